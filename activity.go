@@ -0,0 +1,157 @@
+package stockfighter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// An AccountProfile represents the trading activity of an account, as seen in
+// the executions of a venue.
+type AccountProfile struct {
+	Account string
+
+	// Fills and shares traded since the profiler started
+	Trades int
+	Volume uint64
+
+	// Fills, and net shares bought (negative when sold), in the window
+	// ending at the last execution
+	WindowTrades int
+	WindowNet    int64
+
+	// Share of the fills of the account in the window where its order was
+	// aggressive (crossed the book), between 0 and 1
+	Aggressiveness float64
+
+	// Largest number of aggressive fills of the account in the window within
+	// the cluster interval of each other
+	MaxCluster int
+}
+
+func (p AccountProfile) String() string {
+	return fmt.Sprintf("%v: %v trades (%v in window, net %+d), %.0f%% aggressive, max cluster %v",
+		p.Account, p.Trades, p.WindowTrades, p.WindowNet, 100*p.Aggressiveness, p.MaxCluster)
+}
+
+type accountFill struct {
+	ts         time.Time
+	shares     int64
+	aggressive bool
+}
+
+type accountActivity struct {
+	trades int
+	volume uint64
+	fills  []accountFill
+}
+
+// An ActivityProfiler aggregates the executions of a venue into per-account
+// activity profiles (trade counts, net direction, clustering of aggressive
+// orders), e.g. to spot the bots moving the market.
+//
+// The client has no executions stream yet: executions are fed with Add as
+// they are received.
+//
+// You can create a new ActivityProfiler using NewActivityProfiler function.
+type ActivityProfiler struct {
+	// Interval within which aggressive fills are clustered together
+	ClusterInterval time.Duration
+
+	mu       sync.Mutex
+	window   time.Duration
+	last     time.Time
+	accounts map[string]*accountActivity
+}
+
+// NewActivityProfiler creates a new ActivityProfiler with profiles over a
+// sliding window of the given duration. This never returns nil.
+func NewActivityProfiler(window time.Duration) *ActivityProfiler {
+	if window <= 0 {
+		panic(fmt.Errorf("Invalid activity window: %v", window))
+	}
+
+	return &ActivityProfiler{
+		ClusterInterval: time.Second,
+		window:          window,
+		accounts:        make(map[string]*accountActivity),
+	}
+}
+
+// Add adds an execution.
+func (profiler *ActivityProfiler) Add(execution *Execution) {
+	profiler.mu.Lock()
+	defer profiler.mu.Unlock()
+
+	activity, ok := profiler.accounts[execution.Account]
+	if !ok {
+		activity = &accountActivity{}
+		profiler.accounts[execution.Account] = activity
+	}
+
+	shares := int64(execution.Filled)
+	if execution.Order.Direction == OrderDirectionSell {
+		shares = -shares
+	}
+	activity.trades++
+	activity.volume += execution.Filled
+	activity.fills = append(activity.fills, accountFill{ts: execution.FilledAt, shares: shares, aggressive: execution.Aggressive()})
+
+	if execution.FilledAt.After(profiler.last) {
+		profiler.last = execution.FilledAt
+	}
+	// fills out of the window are dropped
+	start := profiler.last.Add(-profiler.window)
+	for _, activity := range profiler.accounts {
+		i := 0
+		for i < len(activity.fills) && activity.fills[i].ts.Before(start) {
+			i++
+		}
+		activity.fills = activity.fills[i:]
+	}
+}
+
+// Profiles returns the profiles of the accounts seen, most active in the
+// window first.
+func (profiler *ActivityProfiler) Profiles() []AccountProfile {
+	profiler.mu.Lock()
+	defer profiler.mu.Unlock()
+
+	profiles := make([]AccountProfile, 0, len(profiler.accounts))
+	for account, activity := range profiler.accounts {
+		profile := AccountProfile{Account: account, Trades: activity.trades, Volume: activity.volume, WindowTrades: len(activity.fills)}
+
+		var aggressive []time.Time
+		for _, fill := range activity.fills {
+			profile.WindowNet += fill.shares
+			if fill.aggressive {
+				aggressive = append(aggressive, fill.ts)
+			}
+		}
+		if len(activity.fills) > 0 {
+			profile.Aggressiveness = float64(len(aggressive)) / float64(len(activity.fills))
+		}
+
+		// fills are mostly in timestamp order, but are not required to be
+		sort.Slice(aggressive, func(i, j int) bool { return aggressive[i].Before(aggressive[j]) })
+		for i, j := 0, 0; j < len(aggressive); j++ {
+			for aggressive[j].Sub(aggressive[i]) > profiler.ClusterInterval {
+				i++
+			}
+			if j-i+1 > profile.MaxCluster {
+				profile.MaxCluster = j - i + 1
+			}
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].WindowTrades != profiles[j].WindowTrades {
+			return profiles[i].WindowTrades > profiles[j].WindowTrades
+		}
+		return profiles[i].Account < profiles[j].Account
+	})
+	return profiles
+}
@@ -0,0 +1,36 @@
+package stockfighter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivityProfiler(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	profiler := NewActivityProfiler(time.Minute)
+
+	execution := func(account, direction string, filled uint64, aggressive bool, at time.Duration) *Execution {
+		e := &Execution{Account: account, Order: Order{OrderID: 1, Direction: direction}, StandingID: 2, IncomingID: 3,
+			Filled: filled, FilledAt: ts.Add(at)}
+		if aggressive {
+			e.IncomingID = 1
+		}
+		return e
+	}
+
+	profiler.Add(execution("SLOW", OrderDirectionBuy, 100, false, 0))
+	for i := 0; i < 4; i++ {
+		profiler.Add(execution("BULLDOZER", OrderDirectionBuy, 50, true, 2*time.Minute+time.Duration(i)*200*time.Millisecond))
+	}
+	profiler.Add(execution("BULLDOZER", OrderDirectionSell, 20, false, 2*time.Minute+10*time.Second))
+	profiler.Add(execution("SLOW", OrderDirectionSell, 10, false, 2*time.Minute+20*time.Second))
+
+	assert.Equal(t, []AccountProfile{
+		{Account: "BULLDOZER", Trades: 5, Volume: 220, WindowTrades: 5, WindowNet: 180, Aggressiveness: 0.8, MaxCluster: 4},
+		{Account: "SLOW", Trades: 2, Volume: 110, WindowTrades: 1, WindowNet: -10},
+	}, profiler.Profiles())
+
+	assert.Panics(t, func() { NewActivityProfiler(0) })
+}
@@ -0,0 +1,208 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Anomaly kinds.
+const (
+	// Price jump, compared with the rolling volatility of trade prices
+	AnomalyPriceJump = "price-jump"
+
+	// Trade size, compared with the rolling average trade size
+	AnomalyTradeSize = "trade-size"
+
+	// Burst of trades of the same size in a short time, the print pattern of
+	// a single counterparty working an order
+	AnomalyBurst = "burst"
+)
+
+// An Anomaly represents a statistically unusual trade.
+type Anomaly struct {
+	// Anomaly kind (AnomalyPriceJump, AnomalyTradeSize, or AnomalyBurst)
+	Kind string
+
+	// Trade that raised the anomaly
+	Price     uint64
+	Size      uint64
+	Timestamp time.Time
+
+	// How unusual the trade is: the price change in standard deviations of
+	// the recent price changes, the trade size in multiples of the average
+	// size, or the number of trades in the burst
+	Score float64
+}
+
+func (a Anomaly) String() string {
+	return fmt.Sprintf("%v: %v @ $%.2f (score %.1f)", a.Kind, a.Size, float64(a.Price)/100.0, a.Score)
+}
+
+// An AnomalyDetector watches the trades of a stock for statistically unusual
+// prints: price jumps, abnormal trade sizes, and bursts of same-size trades.
+//
+// Trades are compared with the last Window trades, and no anomaly is raised
+// until Window trades were added. When trades are fed from polled quotes, only
+// the last trade of each quote is seen, so bursts are only detected if quotes
+// are polled faster than trades print.
+//
+// You can create a new AnomalyDetector using NewAnomalyDetector function.
+type AnomalyDetector struct {
+	// Price change (in standard deviations) raising a price jump
+	PriceThreshold float64
+
+	// Trade size (in multiples of the average size) raising a trade size
+	// anomaly
+	SizeThreshold float64
+
+	// Number of trades of the same size within BurstInterval raising a burst
+	BurstCount    int
+	BurstInterval time.Duration
+
+	mu        sync.Mutex
+	window    int
+	returns   []float64
+	sizes     []float64
+	lastPrice uint64
+	lastTrade time.Time
+	burst     []time.Time
+	burstSize uint64
+}
+
+// NewAnomalyDetector creates a new AnomalyDetector comparing trades with the
+// last window trades. This never returns nil.
+func NewAnomalyDetector(window int) *AnomalyDetector {
+	if window < 2 {
+		panic(fmt.Errorf("Invalid anomaly detector window: %v", window))
+	}
+
+	return &AnomalyDetector{
+		PriceThreshold: 4,
+		SizeThreshold:  5,
+		BurstCount:     5,
+		BurstInterval:  time.Second,
+		window:         window,
+	}
+}
+
+// AddTrade adds a trade of size shares at price and returns the anomalies it
+// raises.
+func (d *AnomalyDetector) AddTrade(price, size uint64, ts time.Time) []Anomaly {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var anomalies []Anomaly
+	anomaly := func(kind string, score float64) {
+		anomalies = append(anomalies, Anomaly{Kind: kind, Price: price, Size: size, Timestamp: ts, Score: score})
+	}
+
+	if d.lastPrice > 0 && price > 0 {
+		ret := math.Log(float64(price) / float64(d.lastPrice))
+		if len(d.returns) >= d.window {
+			mean, stddev := meanStddev(d.returns)
+			if stddev > 0 && math.Abs(ret-mean) >= d.PriceThreshold*stddev {
+				anomaly(AnomalyPriceJump, math.Abs(ret-mean)/stddev)
+			}
+		}
+		d.returns = appendWindow(d.returns, ret, d.window)
+	}
+	if price > 0 {
+		d.lastPrice = price
+	}
+
+	if len(d.sizes) >= d.window {
+		mean, _ := meanStddev(d.sizes)
+		if mean > 0 && float64(size) >= d.SizeThreshold*mean {
+			anomaly(AnomalyTradeSize, float64(size)/mean)
+		}
+	}
+	d.sizes = appendWindow(d.sizes, float64(size), d.window)
+
+	if size != d.burstSize {
+		d.burst, d.burstSize = d.burst[:0], size
+	}
+	d.burst = append(d.burst, ts)
+	for len(d.burst) > 0 && ts.Sub(d.burst[0]) > d.BurstInterval {
+		d.burst = d.burst[1:]
+	}
+	// raised once per burst, when it reaches BurstCount trades
+	if len(d.burst) == d.BurstCount {
+		anomaly(AnomalyBurst, float64(len(d.burst)))
+	}
+
+	return anomalies
+}
+
+// AddQuote adds the last trade of a quote, unless it was added already by a
+// previous quote, and returns the anomalies it raises.
+func (d *AnomalyDetector) AddQuote(quote *Quote) []Anomaly {
+	d.mu.Lock()
+	if quote.LastSize == 0 || !quote.LastTradeTime.After(d.lastTrade) {
+		d.mu.Unlock()
+		return nil
+	}
+	d.lastTrade = quote.LastTradeTime
+	d.mu.Unlock()
+
+	return d.AddTrade(quote.LastPrice, quote.LastSize, quote.LastTradeTime)
+}
+
+// Start starts detecting anomalies in the trades of quote updates (e.g. from
+// a QuotePoller) in a new goroutine, and returns the channel anomalies are
+// delivered on. Updates with an error are ignored.
+//
+// The channel is closed when ctx is done or updates is closed.
+func (d *AnomalyDetector) Start(ctx context.Context, updates <-chan QuoteUpdate) <-chan Anomaly {
+	anomalies := make(chan Anomaly)
+
+	go func() {
+		defer close(anomalies)
+
+		for {
+			select {
+			case update, open := <-updates:
+				if !open {
+					return
+				}
+				if update.Err != nil {
+					continue
+				}
+				for _, anomaly := range d.AddQuote(update.Quote) {
+					select {
+					case anomalies <- anomaly:
+					case <-ctx.Done():
+						return
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return anomalies
+}
+
+// appendWindow appends a value to the last n values, dropping the oldest.
+func appendWindow(values []float64, v float64, n int) []float64 {
+	if len(values) >= n {
+		copy(values, values[1:])
+		values = values[:n-1]
+	}
+	return append(values, v)
+}
+
+func meanStddev(values []float64) (mean, stddev float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	for _, v := range values {
+		stddev += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(stddev / float64(len(values)))
+}
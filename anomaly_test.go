@@ -0,0 +1,65 @@
+package stockfighter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnomalyDetector(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	d := NewAnomalyDetector(10)
+
+	// a quiet market: prices oscillating by a cent, various sizes
+	for i := 0; i < 20; i++ {
+		assert.Empty(t, d.AddTrade(uint64(5000+i%2), uint64(10+i%3), ts.Add(time.Duration(i)*time.Minute)))
+	}
+
+	anomalies := d.AddTrade(5500, 11, ts.Add(time.Hour))
+	assert.Len(t, anomalies, 1)
+	assert.Equal(t, AnomalyPriceJump, anomalies[0].Kind)
+	assert.Equal(t, uint64(5500), anomalies[0].Price)
+
+	anomalies = d.AddTrade(5500, 500, ts.Add(2*time.Hour))
+	assert.Len(t, anomalies, 1)
+	assert.Equal(t, AnomalyTradeSize, anomalies[0].Kind)
+	assert.True(t, anomalies[0].Score > 5)
+
+	// the burst is raised once, on its fifth trade
+	var bursts int
+	for i := 0; i < 8; i++ {
+		for _, anomaly := range d.AddTrade(5500, 7, ts.Add(3*time.Hour+time.Duration(i)*100*time.Millisecond)) {
+			if anomaly.Kind == AnomalyBurst {
+				bursts++
+				assert.Equal(t, float64(5), anomaly.Score)
+			}
+		}
+	}
+	assert.Equal(t, 1, bursts)
+
+	assert.Panics(t, func() { NewAnomalyDetector(1) })
+}
+
+func TestAnomalyDetectorStart(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	d := NewAnomalyDetector(2)
+
+	updates := make(chan QuoteUpdate)
+	go func() {
+		defer close(updates)
+		for i, size := range []uint64{10, 10, 10, 10, 100} {
+			quote := &Quote{LastPrice: 5000, LastSize: size, LastTradeTime: ts.Add(time.Duration(i) * time.Minute)}
+			updates <- QuoteUpdate{Stock: testStock, Quote: quote}
+			updates <- QuoteUpdate{Stock: testStock, Quote: quote} // same trade
+		}
+	}()
+
+	var anomalies []Anomaly
+	for anomaly := range d.Start(context.Background(), updates) {
+		anomalies = append(anomalies, anomaly)
+	}
+	assert.Len(t, anomalies, 1)
+	assert.Equal(t, AnomalyTradeSize, anomalies[0].Kind)
+}
@@ -0,0 +1,50 @@
+package stockfighter
+
+import (
+	"context"
+	"time"
+)
+
+// StockfighterAPI is the interface implemented by Client, so that code using
+// the API can be tested without network access (see package
+// stockfightertest).
+//
+// It includes every Client method but Subsystem, WithContext, Usage, Stats,
+// ResetStats, PublishExpvar, Venue, InvalidateStocks, DecodeTickertapeFrame,
+// and DecodeExecutionFrame, which are about the Client itself rather than the
+// API, and the iterators Quotes and Orders, which need Go 1.23.
+type StockfighterAPI interface {
+	Ping() error
+	PingVenue(venue string) error
+	PingRTT() (time.Duration, error)
+	PingVenueRTT(venue string) (time.Duration, error)
+	ListStocks(venue string) ([]StockInfo, error)
+	GetOrderbook(venue, stock string) (*Orderbook, error)
+	StreamOrderbook(venue, stock string, maxEntries int, fn func(entry OrderbookEntry) error) (*Orderbook, error)
+	GetOrderbookInto(venue, stock string, maxEntries int, book *Orderbook) error
+	GetOrderbookDepth(venue, stock string, n int) (*Orderbook, error)
+	GetQuote(venue, stock string) (*Quote, error)
+	PlaceOrder(venue, stock, account string, price, quantity uint64, direction, orderType string) (*Order, error)
+	GetOrder(venue, stock string, orderID int64) (*Order, error)
+	CancelOrder(venue, stock string, orderID int64) (*Order, error)
+	GetAllOrders(venue, account string) ([]Order, error)
+	GetStockOrders(venue, account, stock string) ([]Order, error)
+
+	StartLevel(level string) (*Level, error)
+	RestartLevel(instanceID int64) (*Level, error)
+	ResumeLevel(instanceID int64) (*Level, error)
+	StopLevel(instanceID int64) error
+	GetLevelStatus(instanceID int64) (*LevelStatus, error)
+
+	CancelAllOrders(ctx context.Context, venue, account string) ([]CancelResult, error)
+	PlaceOrders(ctx context.Context, reqs []OrderRequest) []OrderResult
+	GetQuotes(ctx context.Context, venue string, stocks []string) (map[string]*Quote, error)
+	GetMarketSnapshot(ctx context.Context, venue, stock, account string) (*MarketSnapshot, error)
+	PlaceOCO(ctx context.Context, first, second OrderRequest) (*OCOResult, error)
+	ExecSweep(venue, stock, account, direction string, targetQty, limitPrice uint64) (*SweepResult, error)
+	WaitForFill(ctx context.Context, venue, stock string, orderID int64) (*Order, error)
+	MeasureLatency(ctx context.Context, venue string, n int) (*LatencyStats, error)
+	SubscribeQuotes(ctx context.Context, venue string, handler func(Quote) error) error
+}
+
+var _ StockfighterAPI = (*Client)(nil)
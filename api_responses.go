@@ -23,22 +23,12 @@ type apiRespStockOrderbook struct {
 	Timestamp   time.Time        `json:"ts"`
 }
 
-type apiRespNewStockOrder struct {
-	OK               bool            `json:"ok"`
-	Error            string          `json:"error"`
-	VenueSymbol      string          `json:"venue"`
-	StockSymbol      string          `json:"symbol"`
-	Direction        string          `json:"direction"`
-	OriginalQuantity uint64          `json:"originalQty"`
-	Quantity         uint64          `json:"qty"`
-	Price            uint64          `json:"price"`
-	OrderType        string          `json:"orderType"`
-	OrderID          int64           `json:"id"`
-	Account          string          `json:"account"`
-	Timestamp        time.Time       `json:"ts"`
-	Fills            []OrderFillInfo `json:"fills"`
-	TotalFilled      uint64          `json:"totalFilled"`
-	Open             bool            `json:"open"`
+// apiRespOrder is the response of the endpoints returning a single order,
+// which are the same as the orders returned by the endpoints listing orders.
+type apiRespOrder struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	Order
 }
 
 type apiRespStockQuote struct {
@@ -46,10 +36,10 @@ type apiRespStockQuote struct {
 	Error         string    `json:"error"`
 	VenueSymbol   string    `json:"venue"`
 	StockSymbol   string    `json:"symbol"`
-	BidPrice      uint64    `json:"bid"`
+	BidPrice      *uint64   `json:"bid"`
 	BidSize       uint64    `json:"bidSize"`
 	BidDepth      uint64    `json:"bidDepth"`
-	AskPrice      uint64    `json:"ask"`
+	AskPrice      *uint64   `json:"ask"`
 	AskSize       uint64    `json:"askSize"`
 	AskDepth      uint64    `json:"askDepth"`
 	LastPrice     uint64    `json:"last"`
@@ -58,27 +48,46 @@ type apiRespStockQuote struct {
 	QuoteTime     time.Time `json:"quoteTime"`
 }
 
-type apiRespStockOrderStatus struct {
-	OK               bool            `json:"ok"`
-	Error            string          `json:"error"`
-	VenueSymbol      string          `json:"venue"`
-	StockSymbol      string          `json:"symbol"`
-	Direction        string          `json:"direction"`
-	OriginalQuantity uint64          `json:"originalQty"`
-	Quantity         uint64          `json:"qty"`
-	Price            uint64          `json:"price"`
-	OrderType        string          `json:"orderType"`
-	OrderID          int64           `json:"id"`
-	Account          string          `json:"account"`
-	Timestamp        time.Time       `json:"ts"`
-	Fills            []OrderFillInfo `json:"fills"`
-	TotalFilled      uint64          `json:"totalFilled"`
-	Open             bool            `json:"open"`
-}
-
 type apiRespAllOrdersStatus struct {
 	OK          bool    `json:"ok"`
 	Error       string  `json:"error"`
 	VenueSymbol string  `json:"venue"`
 	Orders      []Order `json:"orders"`
 }
+
+type apiRespLevel struct {
+	OK                   bool              `json:"ok"`
+	Error                string            `json:"error"`
+	InstanceID           int64             `json:"instanceId"`
+	Account              string            `json:"account"`
+	Venues               []string          `json:"venues"`
+	Tickers              []string          `json:"tickers"`
+	Instructions         map[string]string `json:"instructions"`
+	SecondsPerTradingDay int               `json:"secondsPerTradingDay"`
+}
+
+type apiRespLevelStatus struct {
+	OK         bool   `json:"ok"`
+	Error      string `json:"error"`
+	InstanceID int64  `json:"id"`
+	Done       bool   `json:"done"`
+	State      string `json:"state"`
+	Details    struct {
+		TradingDay       int `json:"tradingDay"`
+		EndOfTheWorldDay int `json:"endOfTheWorldDay"`
+	} `json:"details"`
+	Flash apiRespLevelFlash `json:"flash"`
+}
+
+// apiRespLevelFlash is the message shown to the player, keyed by severity
+// ("info", "warning", "success", or "danger").
+type apiRespLevelFlash map[string]string
+
+func (flash apiRespLevelFlash) message() string {
+	for _, severity := range []string{"danger", "warning", "success", "info"} {
+		if msg, ok := flash[severity]; ok {
+			return msg
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package stockfighter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	var quote apiRespStockQuote
+	assert.Nil(t, decodeResponse(JSONCodec{}, 200, []byte(`{"ok": true, "ask": 5100, "extra": {"nested": [1, 2]}}`), &quote))
+	assert.Equal(t, uint64(5100), *quote.AskPrice)
+	assert.Nil(t, quote.BidPrice)
+
+	for _, payload := range []string{``, `null`, `[]`, `{}`, `{"ok": "yes"}`, `{"ok": true, "bid": -1}`, `{"ok": true, "bid": "5000"}`, `<html>Bad Gateway</html>`} {
+		err := decodeResponse(JSONCodec{}, 502, []byte(payload), &quote)
+		if assert.IsType(t, &ErrorDecode{}, err, payload) {
+			assert.Equal(t, 502, err.(*ErrorDecode).StatusCode)
+			assert.Equal(t, payload, string(err.(*ErrorDecode).Payload))
+			assert.Contains(t, err.Error(), "HTTP 502")
+		}
+	}
+
+	err := decodeResponse(JSONCodec{}, 200, bytes.Repeat([]byte("x"), 1000), &quote)
+	assert.Len(t, err.Error(), len(`Cannot decode API response (HTTP 200): invalid character 'x' looking for beginning of value (payload: "")`)+maxErrorPayload)
+}
+
+// fuzzDecode fuzzes the decoding of responses into the type returned by
+// newRespBody, seeded with the fixtures of the given endpoints.
+func fuzzDecode(f *testing.F, newRespBody func() interface{}, fixtures ...string) {
+	for _, fixture := range append(fixtures, "error_unauthorized", "error_venue_not_found") {
+		data, err := os.ReadFile(filepath.Join("testdata", "fixtures", fixture+".json"))
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte(`{"ok": true, "unexpected": null}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		err := decodeResponse(JSONCodec{}, 200, data, newRespBody())
+		if err == nil {
+			return
+		}
+
+		decodeErr, ok := err.(*ErrorDecode)
+		if !ok {
+			t.Fatalf("unexpected error type %T: %v", err, err)
+		}
+		if !bytes.Equal(decodeErr.Payload, data) {
+			t.Fatalf("payload %q, want %q", decodeErr.Payload, data)
+		}
+		_ = decodeErr.Error()
+	})
+}
+
+func FuzzDecodeHeartbeat(f *testing.F) {
+	fuzzDecode(f, func() interface{} { return &apiRespHeartbeat{} }, "heartbeat", "venue_heartbeat")
+}
+
+func FuzzDecodeStocks(f *testing.F) {
+	fuzzDecode(f, func() interface{} { return &apiRespStocks{} }, "stocks")
+}
+
+func FuzzDecodeOrderbook(f *testing.F) {
+	fuzzDecode(f, func() interface{} { return &apiRespStockOrderbook{} }, "orderbook")
+}
+
+func FuzzDecodeQuote(f *testing.F) {
+	fuzzDecode(f, func() interface{} { return &apiRespStockQuote{} }, "quote")
+}
+
+func FuzzDecodeOrder(f *testing.F) {
+	fuzzDecode(f, func() interface{} { return &apiRespOrder{} }, "order", "canceled_order")
+}
+
+func FuzzDecodeOrders(f *testing.F) {
+	fuzzDecode(f, func() interface{} { return &apiRespAllOrdersStatus{} }, "orders")
+}
+
+func FuzzDecodeLevel(f *testing.F) {
+	fuzzDecode(f, func() interface{} { return &apiRespLevel{} })
+}
+
+func FuzzDecodeLevelStatus(f *testing.F) {
+	fuzzDecode(f, func() interface{} { return &apiRespLevelStatus{} })
+}
@@ -0,0 +1,204 @@
+package stockfighter
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// DefaultConcurrency is the default maximum number of API requests batch
+// methods such as CancelAllOrders and PlaceOrders make concurrently.
+const DefaultConcurrency = 4
+
+// WithConcurrency sets the maximum number of API requests batch methods make
+// concurrently (DefaultConcurrency by default).
+func WithConcurrency(n int) ClientOption {
+	return func(client *Client) {
+		if n > 0 {
+			client.concurrency = n
+		}
+	}
+}
+
+// parallel calls fn for every index in [0, n), with at most limit calls
+// running at once, and returns the errors positionally. Indexes not started
+// yet when ctx is done get ctx.Err() without fn being called.
+//
+// Client methods panic on invalid arguments, which the caller could not
+// recover from since fn runs in its own goroutine: a panic of fn with an
+// error is returned as the error of its index instead (see panicError).
+func parallel(ctx context.Context, limit, n int, fn func(i int) error) []error {
+	if limit <= 0 {
+		limit = DefaultConcurrency
+	}
+
+	errs := make([]error, n)
+	sem := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		if ctx.Err() != nil {
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = panicError(r)
+				}
+			}()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// panicError returns the error a client method panicked with, given the value
+// recovered from the panic. It panics again with other values, and runtime
+// errors, which are bugs rather than invalid arguments.
+func panicError(r interface{}) error {
+	err, ok := r.(error)
+	if _, bug := r.(runtime.Error); !ok || bug {
+		panic(r)
+	}
+	return err
+}
+
+// A CancelResult represents the result of canceling one order in
+// CancelAllOrders.
+type CancelResult struct {
+	// Stock symbol and ID of the order
+	Stock   string
+	OrderID int64
+
+	// Order status returned by CancelOrder, if successful
+	Order *Order
+
+	// Error returned by CancelOrder, if any
+	Err error
+}
+
+// CancelAllOrders cancels every open order of an account on a venue
+// concurrently (see WithConcurrency). It returns one result per open order, in
+// the order GetAllOrders returned them. In dry-run mode (see WithDryRun), only
+// the dry-run orders are canceled.
+//
+// An error is only returned if the open orders could not be listed. Requests
+// are made with ctx (see Client.WithContext): when it is done, cancellations
+// in flight fail with its error, and orders not canceled yet get ctx.Err() as
+// their result error.
+func (client *Client) CancelAllOrders(ctx context.Context, venue, account string) ([]CancelResult, error) {
+	client = client.WithContext(ctx)
+	orders, err := client.GetAllOrders(venue, account)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []CancelResult
+	for _, order := range orders {
+		if order.Open && (client.dryRun == nil || order.OrderID < 0) {
+			results = append(results, CancelResult{Stock: order.Symbol, OrderID: order.OrderID})
+		}
+	}
+
+	errs := parallel(ctx, client.concurrency, len(results), func(i int) error {
+		var err error
+		results[i].Order, err = client.CancelOrder(venue, results[i].Stock, results[i].OrderID)
+		return err
+	})
+	for i, err := range errs {
+		results[i].Err = err
+	}
+
+	return results, nil
+}
+
+// An OrderResult represents the result of placing one order in PlaceOrders.
+//
+// Either Order or Err is set.
+type OrderResult struct {
+	// Order status returned by PlaceOrder
+	Order *Order
+
+	// Error returned by PlaceOrder, the error PlaceOrder panicked with for
+	// an invalid request, or ctx.Err() if the order was not placed before ctx
+	// was done
+	Err error
+}
+
+// PlaceOrders places orders concurrently (see WithConcurrency) and returns one
+// result per order request, in the same order. Invalid requests, which
+// PlaceOrder panics on, only fail their own result.
+//
+// Requests are made with ctx (see Client.WithContext): when it is done,
+// placements in flight fail with its error.
+func (client *Client) PlaceOrders(ctx context.Context, reqs []OrderRequest) []OrderResult {
+	client = client.WithContext(ctx)
+	results := make([]OrderResult, len(reqs))
+	errs := parallel(ctx, client.concurrency, len(reqs), func(i int) error {
+		var err error
+		results[i].Order, err = client.placeOrderRequest(reqs[i])
+		return err
+	})
+	for i, err := range errs {
+		results[i].Err = err
+	}
+
+	return results
+}
+
+// GetQuotes returns the quotes of several stocks of a venue, fetched
+// concurrently (see WithConcurrency). Duplicate stocks are fetched once.
+//
+// If some quotes could not be fetched, GetQuotes returns the others along with
+// an *ErrorPartial holding the error of each failed stock, including invalid
+// symbols, which GetQuote panics on.
+//
+// Requests are made with ctx (see Client.WithContext): when it is done,
+// requests in flight fail with its error.
+func (client *Client) GetQuotes(ctx context.Context, venue string, stocks []string) (map[string]*Quote, error) {
+	client = client.WithContext(ctx)
+	seen := make(map[string]bool, len(stocks))
+	var unique []string
+	for _, stock := range stocks {
+		if !seen[stock] {
+			seen[stock] = true
+			unique = append(unique, stock)
+		}
+	}
+
+	quotes := make([]*Quote, len(unique))
+	errs := parallel(ctx, client.concurrency, len(unique), func(i int) error {
+		var err error
+		quotes[i], err = client.GetQuote(venue, unique[i])
+		return err
+	})
+
+	result := make(map[string]*Quote, len(unique))
+	partial := &ErrorPartial{Errors: make(map[string]error)}
+	for i, stock := range unique {
+		if errs[i] != nil {
+			partial.Errors[stock] = errs[i]
+			continue
+		}
+		result[stock] = quotes[i]
+	}
+
+	if len(partial.Errors) > 0 {
+		return result, partial
+	}
+	return result, nil
+}
@@ -0,0 +1,231 @@
+package stockfighter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallel(t *testing.T) {
+	var running, maxRunning int32
+	errOdd := errors.New("odd")
+
+	errs := parallel(context.Background(), 2, 6, func(i int) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+
+		if i%2 == 1 {
+			return errOdd
+		}
+		return nil
+	})
+	assert.Equal(t, []error{nil, errOdd, nil, errOdd, nil, errOdd}, errs)
+	assert.True(t, maxRunning <= 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs = parallel(ctx, 2, 2, func(i int) error {
+		t.Error("called after ctx is done")
+		return nil
+	})
+	assert.Equal(t, []error{context.Canceled, context.Canceled}, errs)
+
+	// invalid argument panics are returned as errors
+	errInvalid := errors.New("Invalid stock symbol: ")
+	errs = parallel(context.Background(), 2, 2, func(i int) error {
+		if i == 1 {
+			panic(errInvalid)
+		}
+		return nil
+	})
+	assert.Equal(t, []error{nil, errInvalid}, errs)
+}
+
+func TestCancelAllOrders(t *testing.T) {
+	var mu sync.Mutex
+	var canceled []string
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"ok": true, "orders": [
+				{"symbol": "FOOBAR", "id": 1, "open": true},
+				{"symbol": "FOOBAR", "id": 2, "open": false},
+				{"symbol": "BARBAZ", "id": 3, "open": true}
+			]}`))
+			return
+		}
+
+		mu.Lock()
+		canceled = append(canceled, r.URL.Path)
+		mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, "/3") {
+			w.Write([]byte(`{"ok": false, "error": "already closed"}`))
+			return
+		}
+		w.Write([]byte(`{"ok": true, "id": 1, "open": false}`))
+	})
+
+	results, err := client.CancelAllOrders(context.Background(), testVenue, testAccount)
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+
+	assert.Equal(t, "FOOBAR", results[0].Stock)
+	assert.Nil(t, results[0].Err)
+	assert.False(t, results[0].Order.Open)
+
+	assert.Equal(t, int64(3), results[1].OrderID)
+	assert.Nil(t, results[1].Order)
+	assert.Equal(t, "already closed", results[1].Err.Error())
+
+	assert.ElementsMatch(t, []string{"/venues/TESTEX/stocks/FOOBAR/orders/1", "/venues/TESTEX/stocks/BARBAZ/orders/3"}, canceled)
+}
+
+func TestCancelAllOrdersContext(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"ok": true, "orders": [{"symbol": "FOOBAR", "id": 1, "open": true}]}`))
+			return
+		}
+		<-release
+		w.Write([]byte(`{"ok": true, "id": 1, "open": false}`))
+	})
+	defer close(release)
+
+	// cancellations in flight are aborted when ctx is done
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	results, err := client.CancelAllOrders(ctx, testVenue, testAccount)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.True(t, errors.Is(results[0].Err, context.DeadlineExceeded))
+
+	// invalid symbols make the listing panic, before any concurrent request
+	assert.Panics(t, func() { client.CancelAllOrders(context.Background(), "", testAccount) })
+}
+
+func TestPlaceOrders(t *testing.T) {
+	var running, maxRunning int32
+	release := make(chan struct{})
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		if n > atomic.LoadInt32(&maxRunning) {
+			atomic.StoreInt32(&maxRunning, n)
+		}
+		<-release
+
+		var req OrderRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Price == 1 {
+			w.Write([]byte(`{"ok": false, "error": "invalid price"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"ok": true, "id": %d, "price": %d}`, req.Price, req.Price)
+	})
+	client := NewClient(testApiKey, WithBaseURL(server.transport.baseURL), WithConcurrency(2))
+
+	reqs := make([]OrderRequest, 5)
+	for i := range reqs {
+		reqs[i] = OrderRequest{Venue: testVenue, Stock: testStock, Account: testAccount, Price: uint64(i + 1), Quantity: 1, Direction: OrderDirectionBuy, OrderType: OrderTypeLimit}
+	}
+
+	go func() {
+		for range reqs {
+			release <- struct{}{}
+		}
+	}()
+	results := client.PlaceOrders(context.Background(), reqs)
+
+	assert.Len(t, results, 5)
+	assert.Equal(t, "invalid price", results[0].Err.Error())
+	for i := 1; i < len(results); i++ {
+		assert.Nil(t, results[i].Err)
+		assert.Equal(t, uint64(i+1), results[i].Order.Price)
+	}
+	assert.True(t, maxRunning <= 2)
+}
+
+func TestPlaceOrdersInvalid(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var req OrderRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Price == 2 {
+			<-release
+		}
+		fmt.Fprintf(w, `{"ok": true, "id": %d, "price": %d}`, req.Price, req.Price)
+	})
+	defer close(release)
+
+	reqs := []OrderRequest{
+		{Venue: testVenue, Stock: testStock, Account: testAccount, Price: 1, Quantity: 1, Direction: OrderDirectionBuy, OrderType: OrderTypeLimit},
+		{Venue: testVenue, Stock: testStock, Account: testAccount, Price: 2, Quantity: 1, Direction: OrderDirectionBuy, OrderType: OrderTypeLimit},
+		{Venue: testVenue, Stock: "FOO BAR", Account: testAccount, Price: 3, Quantity: 1, Direction: OrderDirectionBuy, OrderType: OrderTypeLimit},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	results := client.PlaceOrders(ctx, reqs)
+
+	assert.Nil(t, results[0].Err)
+	assert.Equal(t, uint64(1), results[0].Order.Price)
+	assert.True(t, errors.Is(results[1].Err, context.DeadlineExceeded))
+	assert.Nil(t, results[2].Order)
+	assert.Equal(t, "Invalid stock symbol: FOO BAR", results[2].Err.Error())
+}
+
+func TestGetQuotes(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/NOPE/") {
+			w.WriteHeader(404)
+			w.Write([]byte(`{"ok": false, "error": "no such stock"}`))
+			return
+		}
+		w.Write([]byte(`{"ok": true, "bid": 100}`))
+	})
+
+	quotes, err := client.GetQuotes(context.Background(), testVenue, []string{testStock, "NOPE", "BARBAZ", testStock})
+	assert.Len(t, quotes, 2)
+	assert.Equal(t, uint64(100), quotes[testStock].BidPrice)
+	assert.Equal(t, uint64(100), quotes["BARBAZ"].BidPrice)
+
+	partial, ok := err.(*ErrorPartial)
+	assert.True(t, ok)
+	assert.Len(t, partial.Errors, 1)
+	assert.IsType(t, &ErrorStockNotFound{}, partial.Errors["NOPE"])
+	assert.Equal(t, "Some requests failed: NOPE: Stock not found: NOPE (venue: TESTEX)", err.Error())
+
+	// invalid symbols fail alone
+	quotes, err = client.GetQuotes(context.Background(), testVenue, []string{testStock, "FOO/BAR"})
+	assert.Len(t, quotes, 1)
+	assert.Equal(t, "Invalid stock symbol: FOO/BAR", err.(*ErrorPartial).Errors["FOO/BAR"].Error())
+}
+
+func TestGetQuotesContext(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"ok": true, "bid": 100}`))
+	})
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	quotes, err := client.GetQuotes(ctx, testVenue, []string{testStock})
+	assert.Empty(t, quotes)
+	assert.True(t, errors.Is(err.(*ErrorPartial).Errors[testStock], context.DeadlineExceeded))
+}
@@ -0,0 +1,182 @@
+package stockfighter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Blotter event kinds.
+const (
+	// An order was seen for the first time, or its state changed
+	BlotterEventOrder = "order"
+
+	// An order was filled, in full or in part
+	BlotterEventFill = "fill"
+)
+
+// Blotter export formats.
+const (
+	BlotterFormatCSV   = "csv"
+	BlotterFormatJSONL = "jsonl"
+)
+
+// A BlotterEvent represents an order event or a fill recorded in a blotter.
+type BlotterEvent struct {
+	// Event time: the time the order event was recorded, or the fill
+	// timestamp
+	Time time.Time `json:"time"`
+
+	// Event kind (BlotterEventOrder or BlotterEventFill)
+	Kind string `json:"kind"`
+
+	// Order the event is about
+	Venue     string `json:"venue"`
+	Symbol    string `json:"symbol"`
+	OrderID   int64  `json:"id"`
+	Direction string `json:"direction"`
+	OrderType string `json:"orderType"`
+
+	// Order state after the event (see Order.State)
+	State string `json:"state"`
+
+	// Order price and quantity for order events, fill price and quantity for
+	// fills
+	Price    uint64 `json:"price"`
+	Quantity uint64 `json:"qty"`
+}
+
+// A Blotter is an append-only log of the order events and fills of a trading
+// session, for post-mortem analysis. Order statuses are recorded as they are
+// retrieved, e.g. by polling, and the blotter only logs what changed since
+// the previous status of each order.
+//
+// A Blotter is safe for concurrent use.
+//
+// You can create a new Blotter using NewBlotter function.
+type Blotter struct {
+	// OnEvent, if set, is called with each event as it is logged, e.g. to
+	// persist it. It is called with the blotter locked, so it must not use
+	// the blotter.
+	OnEvent func(BlotterEvent)
+
+	mu     sync.Mutex
+	events []BlotterEvent
+	orders map[orderKey]*Order
+
+	// now returns the current time (time.Now, but for tests)
+	now func() time.Time
+}
+
+// orderKey identifies an order. Order IDs are only unique within a venue.
+type orderKey struct {
+	venue   string
+	orderID int64
+}
+
+// NewBlotter creates a new, empty Blotter. This never returns nil.
+func NewBlotter() *Blotter {
+	return &Blotter{orders: make(map[orderKey]*Order), now: time.Now}
+}
+
+// RecordOrder records a status of an order. An order event is logged if the
+// order was not recorded before or if it changed (see Order.Changed), along
+// with a fill event for each new fill.
+func (blotter *Blotter) RecordOrder(order *Order) {
+	blotter.mu.Lock()
+	defer blotter.mu.Unlock()
+
+	key := orderKey{order.Venue, order.OrderID}
+	prev := blotter.orders[key]
+	if !order.Changed(prev) {
+		return
+	}
+
+	event := BlotterEvent{
+		Time:      blotter.now(),
+		Kind:      BlotterEventOrder,
+		Venue:     order.Venue,
+		Symbol:    order.Symbol,
+		OrderID:   order.OrderID,
+		Direction: order.Direction,
+		OrderType: order.OrderType,
+		State:     order.State(),
+		Price:     order.Price,
+		Quantity:  order.OriginalQuantity,
+	}
+	blotter.log(event)
+
+	for _, fill := range order.NewFillsSince(prev) {
+		event.Time, event.Kind, event.Price, event.Quantity = fill.Timestamp, BlotterEventFill, fill.Price, fill.Quantity
+		blotter.log(event)
+	}
+
+	status := *order
+	blotter.orders[key] = &status
+}
+
+func (blotter *Blotter) log(event BlotterEvent) {
+	blotter.events = append(blotter.events, event)
+	if blotter.OnEvent != nil {
+		blotter.OnEvent(event)
+	}
+}
+
+// Events returns the events recorded for a stock (or all stocks if stock is
+// empty) between from and to (inclusive, and unbounded if zero), in the order
+// they were recorded.
+func (blotter *Blotter) Events(stock string, from, to time.Time) []BlotterEvent {
+	blotter.mu.Lock()
+	defer blotter.mu.Unlock()
+
+	var events []BlotterEvent
+	for _, event := range blotter.events {
+		if stock != "" && event.Symbol != stock {
+			continue
+		}
+		if (!from.IsZero() && event.Time.Before(from)) || (!to.IsZero() && event.Time.After(to)) {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// blotterCSVHeader is the header line of CSV blotter exports.
+var blotterCSVHeader = []string{"time", "kind", "venue", "symbol", "id", "direction", "orderType", "state", "price", "qty"}
+
+// WriteBlotter writes blotter events in CSV (with a header line) or JSON
+// lines format.
+func WriteBlotter(w io.Writer, format string, events []BlotterEvent) error {
+	switch format {
+	case BlotterFormatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(blotterCSVHeader)
+		for _, event := range events {
+			cw.Write([]string{
+				event.Time.Format(time.RFC3339Nano), event.Kind, event.Venue, event.Symbol,
+				strconv.FormatInt(event.OrderID, 10), event.Direction, event.OrderType, event.State,
+				strconv.FormatUint(event.Price, 10), strconv.FormatUint(event.Quantity, 10),
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+
+	case BlotterFormatJSONL:
+		bw := bufio.NewWriter(w)
+		encoder := json.NewEncoder(bw)
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				return err
+			}
+		}
+		return bw.Flush()
+	}
+
+	return fmt.Errorf("Invalid blotter format: %v", format)
+}
@@ -0,0 +1,76 @@
+package stockfighter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlotter(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	now := ts
+	blotter := NewBlotter()
+	blotter.now = func() time.Time { return now }
+	var logged []BlotterEvent
+	blotter.OnEvent = func(event BlotterEvent) { logged = append(logged, event) }
+
+	order := &Order{Venue: testVenue, Symbol: testStock, OrderID: 1, Direction: OrderDirectionBuy, OrderType: OrderTypeLimit,
+		Price: 5000, OriginalQuantity: 10, Quantity: 10, Open: true}
+	blotter.RecordOrder(order)
+	blotter.RecordOrder(order) // unchanged
+
+	now = ts.Add(time.Minute)
+	filled := *order
+	filled.Quantity, filled.TotalFilled = 6, 4
+	filled.Fills = []OrderFillInfo{{Price: 4990, Quantity: 4, Timestamp: ts.Add(30 * time.Second)}}
+	blotter.RecordOrder(&filled)
+
+	blotter.RecordOrder(&Order{Venue: testVenue, Symbol: "BAR", OrderID: 2, Direction: OrderDirectionSell, OrderType: OrderTypeMarket,
+		OriginalQuantity: 5})
+
+	events := blotter.Events("", time.Time{}, time.Time{})
+	assert.Len(t, events, 4)
+	assert.Equal(t, events, logged)
+	assert.Equal(t, BlotterEvent{Time: ts, Kind: BlotterEventOrder, Venue: testVenue, Symbol: testStock, OrderID: 1,
+		Direction: OrderDirectionBuy, OrderType: OrderTypeLimit, State: OrderStateNew, Price: 5000, Quantity: 10}, events[0])
+	assert.Equal(t, OrderStatePartiallyFilled, events[1].State)
+	assert.Equal(t, BlotterEvent{Time: ts.Add(30 * time.Second), Kind: BlotterEventFill, Venue: testVenue, Symbol: testStock, OrderID: 1,
+		Direction: OrderDirectionBuy, OrderType: OrderTypeLimit, State: OrderStatePartiallyFilled, Price: 4990, Quantity: 4}, events[2])
+	assert.Equal(t, OrderStateExpired, events[3].State)
+
+	assert.Len(t, blotter.Events(testStock, time.Time{}, time.Time{}), 3)
+	assert.Equal(t, events[1:3], blotter.Events(testStock, ts.Add(time.Second), ts.Add(time.Minute)))
+	assert.Empty(t, blotter.Events("BAZ", time.Time{}, time.Time{}))
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteBlotter(&buf, BlotterFormatCSV, events[2:3]))
+	assert.Equal(t, "time,kind,venue,symbol,id,direction,orderType,state,price,qty\n"+
+		"2015-12-04T09:02:46Z,fill,TESTEX,FOOBAR,1,buy,limit,partially-filled,4990,4\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, WriteBlotter(&buf, BlotterFormatJSONL, events[3:]))
+	assert.Equal(t, `{"time":"2015-12-04T09:03:16Z","kind":"order","venue":"TESTEX","symbol":"BAR","id":2,"direction":"sell",`+
+		`"orderType":"market","state":"expired","price":0,"qty":5}`+"\n", buf.String())
+
+	assert.NotNil(t, WriteBlotter(&buf, "xml", events))
+}
+
+func TestBlotterVenues(t *testing.T) {
+	blotter := NewBlotter()
+	order := &Order{Venue: testVenue, Symbol: testStock, OrderID: 1, OriginalQuantity: 10, Quantity: 10, Open: true}
+	blotter.RecordOrder(order)
+
+	// order IDs are only unique within a venue
+	other := *order
+	other.Venue = "OTHEREX"
+	blotter.RecordOrder(&other)
+	blotter.RecordOrder(order)
+	blotter.RecordOrder(&other)
+
+	events := blotter.Events("", time.Time{}, time.Time{})
+	assert.Len(t, events, 2)
+	assert.Equal(t, "OTHEREX", events[1].Venue)
+	assert.Equal(t, OrderStateNew, events[1].State)
+}
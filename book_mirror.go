@@ -0,0 +1,224 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A BookMirror maintains an approximate local copy of the orderbook of a
+// stock. Full orderbook snapshots are applied periodically, and quotes (which
+// carry the size at the best bid and ask) keep the inside market up to date in
+// between, so reads never hit the API.
+//
+// Quotes and snapshots can be fed by hand with ApplyQuote and ApplySnapshot,
+// or by Run, which polls both.
+//
+// You can create a new BookMirror using NewBookMirror function.
+type BookMirror struct {
+	client *Client
+	venue  string
+	stock  string
+
+	// Quote polling interval (DefaultPollInterval if not positive)
+	Interval time.Duration
+
+	// Orderbook snapshot polling interval (10 times DefaultPollInterval if
+	// not positive)
+	SnapshotInterval time.Duration
+
+	mu      sync.RWMutex
+	bids    map[uint64]uint64
+	asks    map[uint64]uint64
+	updated time.Time
+}
+
+// defaultSnapshotInterval is the default snapshot interval of a BookMirror.
+const defaultSnapshotInterval = 10 * DefaultPollInterval
+
+// NewBookMirror creates a new, empty BookMirror for a stock. This never
+// returns nil.
+func NewBookMirror(client *Client, venue, stock string) *BookMirror {
+	venue = strings.TrimSpace(venue)
+	if !validSymbol(venue) {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+
+	stock = strings.TrimSpace(stock)
+	if !validSymbol(stock) {
+		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
+	}
+
+	return &BookMirror{
+		client:           client,
+		venue:            venue,
+		stock:            stock,
+		Interval:         DefaultPollInterval,
+		SnapshotInterval: defaultSnapshotInterval,
+		bids:             make(map[uint64]uint64),
+		asks:             make(map[uint64]uint64),
+	}
+}
+
+// Run keeps the mirror up to date until ctx is done or an API call fails.
+func (mirror *BookMirror) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	snapshot := func() error {
+		orderbook, err := mirror.client.GetOrderbook(mirror.venue, mirror.stock)
+		if err == nil {
+			mirror.ApplySnapshot(orderbook)
+		}
+		return err
+	}
+	if err := snapshot(); err != nil {
+		return err
+	}
+
+	interval := mirror.SnapshotInterval
+	if interval <= 0 {
+		interval = defaultSnapshotInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	updates := NewQuotePoller(mirror.client, mirror.venue, []string{mirror.stock}, mirror.Interval).Start(ctx)
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return ctx.Err()
+			}
+			if update.Err != nil {
+				return update.Err
+			}
+			mirror.ApplyQuote(update.Quote)
+		case <-ticker.C:
+			if err := snapshot(); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ApplySnapshot replaces the mirrored orderbook with a full snapshot.
+func (mirror *BookMirror) ApplySnapshot(orderbook *Orderbook) {
+	mirror.mu.Lock()
+	defer mirror.mu.Unlock()
+
+	mirror.bids = orderbookLevels(orderbook, true)
+	mirror.asks = orderbookLevels(orderbook, false)
+	mirror.updated = orderbook.Timestamp
+}
+
+// ApplyQuote updates the inside market from a quote: levels better than the
+// quoted best price are removed, and the quoted size is set at the best
+// price. Quotes older than the last update are ignored.
+func (mirror *BookMirror) ApplyQuote(quote *Quote) {
+	mirror.mu.Lock()
+	defer mirror.mu.Unlock()
+
+	if quote.QuoteTime.Before(mirror.updated) {
+		return
+	}
+	mirror.updated = quote.QuoteTime
+
+	applyQuoteSide(mirror.bids, quote.HasBid, quote.BidPrice, quote.BidSize, quote.BidDepth, true)
+	applyQuoteSide(mirror.asks, quote.HasAsk, quote.AskPrice, quote.AskSize, quote.AskDepth, false)
+}
+
+func applyQuoteSide(levels map[uint64]uint64, quoted bool, price, size, depth uint64, isBuy bool) {
+	if !quoted {
+		if depth == 0 {
+			// empty side
+			for p := range levels {
+				delete(levels, p)
+			}
+		}
+		return
+	}
+
+	for p := range levels {
+		if (isBuy && p > price) || (!isBuy && p < price) {
+			delete(levels, p)
+		}
+	}
+	if size > 0 {
+		levels[price] = size
+	} else {
+		delete(levels, price)
+	}
+}
+
+// BestBid returns the best bid price and the quantity at it. It returns false
+// if there are no bids.
+func (mirror *BookMirror) BestBid() (price, quantity uint64, ok bool) {
+	mirror.mu.RLock()
+	defer mirror.mu.RUnlock()
+
+	return bestLevel(mirror.bids, true)
+}
+
+// BestAsk returns the best ask price and the quantity at it. It returns false
+// if there are no asks.
+func (mirror *BookMirror) BestAsk() (price, quantity uint64, ok bool) {
+	mirror.mu.RLock()
+	defer mirror.mu.RUnlock()
+
+	return bestLevel(mirror.asks, false)
+}
+
+func bestLevel(levels map[uint64]uint64, isBuy bool) (price, quantity uint64, ok bool) {
+	for p, qty := range levels {
+		if !ok || (isBuy && p > price) || (!isBuy && p < price) {
+			price, quantity, ok = p, qty, true
+		}
+	}
+	return price, quantity, ok
+}
+
+// DepthAt returns the quantity resting at a price on one side of the book.
+func (mirror *BookMirror) DepthAt(price uint64, isBuy bool) uint64 {
+	mirror.mu.RLock()
+	defer mirror.mu.RUnlock()
+
+	if isBuy {
+		return mirror.bids[price]
+	}
+	return mirror.asks[price]
+}
+
+// Orderbook returns a copy of the mirrored orderbook, with a single entry per
+// price level, best prices first.
+func (mirror *BookMirror) Orderbook() *Orderbook {
+	mirror.mu.RLock()
+	defer mirror.mu.RUnlock()
+
+	return &Orderbook{
+		Venue:     mirror.venue,
+		Symbol:    mirror.stock,
+		Bids:      levelEntries(mirror.bids, true),
+		Asks:      levelEntries(mirror.asks, false),
+		Timestamp: mirror.updated,
+	}
+}
+
+func levelEntries(levels map[uint64]uint64, isBuy bool) []OrderbookEntry {
+	entries := make([]OrderbookEntry, 0, len(levels))
+	for price, qty := range levels {
+		entries = append(entries, OrderbookEntry{Price: price, Quantity: qty, IsBuy: isBuy})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if isBuy {
+			return entries[i].Price > entries[j].Price
+		}
+		return entries[i].Price < entries[j].Price
+	})
+	return entries
+}
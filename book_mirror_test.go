@@ -0,0 +1,70 @@
+package stockfighter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookMirror(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	mirror := NewBookMirror(NewClient(testApiKey), testVenue, testStock)
+
+	_, _, ok := mirror.BestBid()
+	assert.False(t, ok)
+
+	mirror.ApplySnapshot(&Orderbook{
+		Bids:      []OrderbookEntry{{Price: 100, Quantity: 10, IsBuy: true}, {Price: 99, Quantity: 5, IsBuy: true}},
+		Asks:      []OrderbookEntry{{Price: 105, Quantity: 7}, {Price: 105, Quantity: 3}, {Price: 106, Quantity: 1}},
+		Timestamp: ts,
+	})
+	price, qty, ok := mirror.BestAsk()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(105), price)
+	assert.Equal(t, uint64(10), qty)
+
+	// the best ask was lifted, and a better bid showed up
+	mirror.ApplyQuote(&Quote{HasBid: true, BidPrice: 101, BidSize: 2, BidDepth: 17, HasAsk: true, AskPrice: 106, AskSize: 1, AskDepth: 1, QuoteTime: ts.Add(time.Second)})
+	price, qty, _ = mirror.BestBid()
+	assert.Equal(t, uint64(101), price)
+	assert.Equal(t, uint64(2), qty)
+	assert.Equal(t, uint64(10), mirror.DepthAt(100, true))
+	assert.Zero(t, mirror.DepthAt(105, false))
+
+	// stale quotes are ignored
+	mirror.ApplyQuote(&Quote{QuoteTime: ts})
+	assert.Equal(t, &Orderbook{
+		Venue:  testVenue,
+		Symbol: testStock,
+		Bids: []OrderbookEntry{
+			{Price: 101, Quantity: 2, IsBuy: true}, {Price: 100, Quantity: 10, IsBuy: true}, {Price: 99, Quantity: 5, IsBuy: true},
+		},
+		Asks:      []OrderbookEntry{{Price: 106, Quantity: 1}},
+		Timestamp: ts.Add(time.Second),
+	}, mirror.Orderbook())
+
+	// no asks left
+	mirror.ApplyQuote(&Quote{HasBid: true, BidPrice: 101, BidSize: 2, BidDepth: 17, QuoteTime: ts.Add(2 * time.Second)})
+	_, _, ok = mirror.BestAsk()
+	assert.False(t, ok)
+}
+
+func TestBookMirrorRunDefaultInterval(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true, "bids": [{"price": 100, "qty": 10, "isBuy": true}]}`))
+	})
+	mirror := NewBookMirror(client, testVenue, testStock)
+	mirror.Interval = 0
+	mirror.SnapshotInterval = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, mirror.Run(ctx))
+	price, quantity, ok := mirror.BestBid()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(100), price)
+	assert.Equal(t, uint64(10), quantity)
+}
@@ -0,0 +1,180 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// A Candle represents an OHLCV bar: the trades of an interval summarized as
+// open, high, low, and close prices and total volume.
+type Candle struct {
+	// Start of the interval
+	Start time.Time `json:"start"`
+
+	// Prices (in cents)
+	Open  uint64 `json:"open"`
+	High  uint64 `json:"high"`
+	Low   uint64 `json:"low"`
+	Close uint64 `json:"close"`
+
+	// Traded quantity
+	Volume uint64 `json:"volume"`
+}
+
+func (c Candle) String() string {
+	return fmt.Sprintf("%v O %.2f H %.2f L %.2f C %.2f V %v", c.Start.Format(time.RFC3339),
+		float64(c.Open)/100.0, float64(c.High)/100.0, float64(c.Low)/100.0, float64(c.Close)/100.0, c.Volume)
+}
+
+// Candles aggregates trades into candles of a fixed interval. Intervals
+// without any trade produce no candle.
+//
+// Trades can be added by hand with AddTrade and AddQuote, or consumed from a
+// QuotePoller with Start.
+//
+// You can create a new Candles using NewCandles function.
+type Candles struct {
+	interval time.Duration
+
+	mu        sync.Mutex
+	candles   []Candle
+	current   *Candle
+	lastTrade time.Time
+}
+
+// NewCandles creates a new Candles aggregating trades into candles of the
+// given interval (e.g. time.Second or time.Minute). This never returns nil.
+func NewCandles(interval time.Duration) *Candles {
+	if interval <= 0 {
+		panic(fmt.Errorf("Invalid candle interval: %v", interval))
+	}
+
+	return &Candles{interval: interval}
+}
+
+// AddTrade adds a trade of size shares at price. It returns the candle
+// completed by the trade, if the trade starts a new interval.
+func (c *Candles) AddTrade(price, size uint64, ts time.Time) (Candle, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	start := ts.Truncate(c.interval)
+	var completed Candle
+	var ok bool
+	if c.current != nil && start.After(c.current.Start) {
+		completed, ok = c.complete()
+	}
+
+	if c.current == nil {
+		c.current = &Candle{Start: start, Open: price, High: price, Low: price}
+	}
+	if price > c.current.High {
+		c.current.High = price
+	}
+	if price < c.current.Low {
+		c.current.Low = price
+	}
+	c.current.Close = price
+	c.current.Volume += size
+
+	return completed, ok
+}
+
+// AddQuote adds the last trade of a quote, unless it was added already by a
+// previous quote.
+func (c *Candles) AddQuote(quote *Quote) (Candle, bool) {
+	c.mu.Lock()
+	if quote.LastSize == 0 || !quote.LastTradeTime.After(c.lastTrade) {
+		c.mu.Unlock()
+		return Candle{}, false
+	}
+	c.lastTrade = quote.LastTradeTime
+	c.mu.Unlock()
+
+	return c.AddTrade(quote.LastPrice, quote.LastSize, quote.LastTradeTime)
+}
+
+// Flush completes the current candle if its interval ended before now, and
+// returns it.
+func (c *Candles) Flush(now time.Time) (Candle, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current == nil || now.Before(c.current.Start.Add(c.interval)) {
+		return Candle{}, false
+	}
+	return c.complete()
+}
+
+func (c *Candles) complete() (Candle, bool) {
+	completed := *c.current
+	c.candles = append(c.candles, completed)
+	c.current = nil
+	return completed, true
+}
+
+// Candles returns the completed candles, oldest first.
+func (c *Candles) Candles() []Candle {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Candle(nil), c.candles...)
+}
+
+// Current returns the candle of the current interval. It returns false if no
+// trade was added since the last candle completed.
+func (c *Candles) Current() (Candle, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current == nil {
+		return Candle{}, false
+	}
+	return *c.current, true
+}
+
+// Start consumes quote updates (e.g. from a QuotePoller) in a new goroutine
+// and returns the channel completed candles are delivered on. Update errors
+// are ignored.
+//
+// The channel is closed when ctx is done or updates is closed.
+func (c *Candles) Start(ctx context.Context, updates <-chan QuoteUpdate) <-chan Candle {
+	candles := make(chan Candle)
+
+	go func() {
+		defer close(candles)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			var candle Candle
+			var ok bool
+			select {
+			case update, open := <-updates:
+				if !open {
+					return
+				}
+				if update.Err == nil {
+					candle, ok = c.AddQuote(update.Quote)
+				}
+			case now := <-ticker.C:
+				candle, ok = c.Flush(now)
+			case <-ctx.Done():
+				return
+			}
+
+			if ok {
+				select {
+				case candles <- candle:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return candles
+}
@@ -0,0 +1,42 @@
+package stockfighter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCandles(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 0, 0, time.UTC)
+	candles := NewCandles(time.Minute)
+
+	_, ok := candles.AddTrade(100, 10, ts.Add(5*time.Second))
+	assert.False(t, ok)
+	candles.AddTrade(103, 5, ts.Add(10*time.Second))
+	candles.AddTrade(98, 1, ts.Add(20*time.Second))
+	candles.AddTrade(99, 4, ts.Add(50*time.Second))
+
+	current, ok := candles.Current()
+	assert.True(t, ok)
+	assert.Equal(t, Candle{Start: ts, Open: 100, High: 103, Low: 98, Close: 99, Volume: 20}, current)
+
+	// the first trade of the next interval completes the candle
+	completed, ok := candles.AddQuote(&Quote{LastPrice: 101, LastSize: 3, LastTradeTime: ts.Add(2 * time.Minute)})
+	assert.True(t, ok)
+	assert.Equal(t, current, completed)
+
+	// the same last trade is not added twice
+	_, ok = candles.AddQuote(&Quote{LastPrice: 101, LastSize: 3, LastTradeTime: ts.Add(2 * time.Minute)})
+	assert.False(t, ok)
+
+	_, ok = candles.Flush(ts.Add(2*time.Minute + 59*time.Second))
+	assert.False(t, ok)
+	completed, ok = candles.Flush(ts.Add(3 * time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, Candle{Start: ts.Add(2 * time.Minute), Open: 101, High: 101, Low: 101, Close: 101, Volume: 3}, completed)
+
+	assert.Len(t, candles.Candles(), 2)
+	_, ok = candles.Current()
+	assert.False(t, ok)
+}
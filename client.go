@@ -1,52 +1,135 @@
 package stockfighter
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 // Client represents a client object you can use Stockfighter APIs.
 //
 // You can create a new Client using NewClient function.
 type Client struct {
-	apiKey     string
-	apiBaseURL string
-	httpClient http.Client
+	transport   *transport
+	subsystem   string
+	usage       *usageCounter
+	concurrency int
+	quotes      *quoteCache
+	stocks      *stocksCache
+	dryRun      *dryRun
+	beforeOrder []BeforeOrderHook
+	afterOrder  []AfterOrderHook
+
+	// Context of API requests (context.Background() if nil)
+	ctx context.Context
 }
 
-// NewClient creates a new Client using your API key. This never returns nil.
-func NewClient(apiKey string) *Client {
-	return &Client{
-		apiKey:     apiKey,
-		apiBaseURL: "https://api.stockfighter.io/ob/api",
-		httpClient: http.Client{},
+// DefaultBaseURL is the base URL of the official Stockfighter API.
+const DefaultBaseURL = "https://api.stockfighter.io/ob/api"
+
+// DefaultGMBaseURL is the base URL of the official Stockfighter GM API, which
+// manages level instances.
+const DefaultGMBaseURL = "https://www.stockfighter.io/gm"
+
+// A ClientOption configures a Client created by NewClient.
+type ClientOption func(*Client)
+
+// WithBaseURL sets the API base URL (DefaultBaseURL by default), e.g. to use
+// a Stockfighter clone or a local mock.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(client *Client) {
+		client.transport.baseURL = strings.TrimRight(baseURL, "/")
 	}
 }
 
-func (client *Client) getAPIJson(method, apiPath string, reqBody io.Reader, respBody interface{}) (int, error) {
-	req, err := http.NewRequest(strings.ToUpper(method), client.apiBaseURL+apiPath, reqBody)
-	if err != nil {
-		return 0, err
+// WithGMBaseURL sets the GM API base URL (DefaultGMBaseURL by default).
+func WithGMBaseURL(baseURL string) ClientOption {
+	return func(client *Client) {
+		client.transport.gmBaseURL = strings.TrimRight(baseURL, "/")
 	}
+}
 
-	req.Header.Add("X-Starfighter-Authorization", client.apiKey)
-	if reqBody != nil {
-		req.Header.Add("Content-Type", "application/json")
+// WithHTTPClient sets the HTTP client used to make API requests, e.g. to set
+// timeouts or wrap its transport.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(client *Client) {
+		client.transport.httpClient = *httpClient
 	}
+}
 
-	resp, err := client.httpClient.Do(req)
-	if err != nil {
-		return 0, err
+// WithLogger sets the logger API requests and responses are logged to (at
+// debug level), along with order placements and cancellations (at info
+// level). Nothing is logged by default.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(client *Client) {
+		client.transport.logger = logger
 	}
-	defer resp.Body.Close()
+}
+
+// NewClient creates a new Client using your API key. This never returns nil.
+func NewClient(apiKey string, options ...ClientOption) *Client {
+	client := &Client{
+		transport: &transport{
+			apiKey:     apiKey,
+			baseURL:    DefaultBaseURL,
+			gmBaseURL:  DefaultGMBaseURL,
+			httpClient: http.Client{},
+			stats:      newStatsCounter(),
+			codec:      JSONCodec{},
+		},
+		subsystem:   DefaultSubsystem,
+		usage:       &usageCounter{requests: make(map[string]uint64)},
+		concurrency: DefaultConcurrency,
+		stocks:      newStocksCache(),
+	}
+
+	for _, option := range options {
+		option(client)
+	}
+	client.transport.authHeader = []string{apiKey}[:1:1]
+
+	return client
+}
 
-	decoder := json.NewDecoder(resp.Body)
-	return resp.StatusCode, decoder.Decode(respBody)
+// WithContext returns a client making its API requests with ctx: requests
+// waiting for their turn (see WithRateLimit) or in flight when ctx is done
+// fail with the error of ctx. The returned client shares everything else with
+// the original client. This panics if ctx is nil.
+//
+//     ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//     defer cancel()
+//     quote, err := client.WithContext(ctx).GetQuote(venue, stock)
+func (client *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		panic(errors.New("Invalid context: nil"))
+	}
+
+	derived := *client
+	derived.ctx = ctx
+	return &derived
+}
+
+// call makes an API request accounted to the subsystem of the client.
+func (client *Client) call(method, apiPath string, reqBody, respBody interface{}) (*apiResponse, error) {
+	client.usage.add(client.subsystem)
+	return client.transport.do(apiRequest{ctx: client.ctx, method: method, path: apiPath, body: reqBody, subsystem: client.subsystem}, respBody)
+}
+
+func (client *Client) logInfo(msg string, args ...interface{}) {
+	client.transport.logInfo(msg, args...)
+}
+
+// validSymbol reports whether a venue or stock symbol, or an account or level
+// name, can be used in an API path: it must not be empty, nor contain slashes
+// or whitespace.
+func validSymbol(symbol string) bool {
+	return symbol != "" && !strings.ContainsRune(symbol, '/') && strings.IndexFunc(symbol, unicode.IsSpace) < 0
 }
 
 // Ping checks if the API is up.
@@ -57,7 +140,7 @@ func (client *Client) getAPIJson(method, apiPath string, reqBody io.Reader, resp
 //     GET https://api.stockfighter.io/ob/api/heartbeat
 func (client *Client) Ping() error {
 	var resp apiRespHeartbeat
-	_, err := client.getAPIJson("GET", "/heartbeat", nil, &resp)
+	_, err := client.call("GET", "/heartbeat", nil, &resp)
 	if err != nil {
 		return err
 	}
@@ -77,18 +160,18 @@ func (client *Client) Ping() error {
 //     GET https://api.stockfighter.io/ob/api/venues/:venue/heartbeat
 func (client *Client) PingVenue(venue string) error {
 	venue = strings.TrimSpace(venue)
-	if venue == "" {
+	if !validSymbol(venue) {
 		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
 	}
 
 	var resp apiRespHeartbeat
-	status, err := client.getAPIJson("GET", "/venues/"+venue+"/heartbeat", nil, &resp)
+	reply, err := client.call("GET", "/venues/"+url.PathEscape(venue)+"/heartbeat", nil, &resp)
 	switch {
 	case err != nil:
 		return err
-	case status == 500: // timeout
+	case reply.statusCode == 500: // timeout
 		return &ErrorAPITimeout{}
-	case status == 404: // venue not found
+	case reply.statusCode == 404: // venue not found
 		return &ErrorVenueNotFound{VenueSymbol: venue}
 	}
 
@@ -101,28 +184,36 @@ func (client *Client) PingVenue(venue string) error {
 
 // ListStocks lists the stocks available for trading on a venue.
 //
+// Stock lists are cached (see WithStocksTTL and InvalidateStocks), so
+// ListStocks can be called freely.
+//
 // Stockfighter API:
 //     GET https://api.stockfighter.io/ob/api/venues/:venue/stocks
 func (client *Client) ListStocks(venue string) ([]StockInfo, error) {
 	venue = strings.TrimSpace(venue)
-	if venue == "" {
+	if !validSymbol(venue) {
 		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
 	}
 
+	if stocks, ok := client.stocks.get(venue); ok {
+		return stocks, nil
+	}
+
 	var resp apiRespStocks
-	status, err := client.getAPIJson("GET", "/venues/"+venue+"/stocks", nil, &resp)
+	reply, err := client.call("GET", "/venues/"+url.PathEscape(venue)+"/stocks", nil, &resp)
 	switch {
 	case err != nil:
 		return nil, err
-	case status == 401: // unauthorized
+	case reply.statusCode == 401: // unauthorized
 		return nil, &ErrorUnauthorized{}
-	case status == 404: // venue not found
+	case reply.statusCode == 404: // venue not found
 		return nil, &ErrorVenueNotFound{VenueSymbol: venue}
 	}
 
 	if !resp.OK {
 		return nil, errors.New(resp.Error)
 	}
+	client.stocks.put(venue, resp.Stocks)
 
 	return resp.Stocks, nil
 }
@@ -133,24 +224,24 @@ func (client *Client) ListStocks(venue string) ([]StockInfo, error) {
 //     GET https://api.stockfighter.io/ob/api/venues/:venue/stocks/:stock
 func (client *Client) GetOrderbook(venue, stock string) (*Orderbook, error) {
 	venue = strings.TrimSpace(venue)
-	if venue == "" {
+	if !validSymbol(venue) {
 		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
 	}
 
 	stock = strings.TrimSpace(stock)
-	if stock == "" {
+	if !validSymbol(stock) {
 		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
 	}
 
 	var resp apiRespStockOrderbook
-	status, err := client.getAPIJson("GET", "/venues/"+venue+"/stocks/"+stock, nil, &resp)
+	reply, err := client.call("GET", "/venues/"+url.PathEscape(venue)+"/stocks/"+url.PathEscape(stock), nil, &resp)
 	switch {
 	case err != nil:
 		return nil, err
-	case status == 401: // unauthorized
+	case reply.statusCode == 401: // unauthorized
 		return nil, &ErrorUnauthorized{}
-	case status == 404: // venue not found
-		return nil, &ErrorVenueNotFound{VenueSymbol: venue}
+	case reply.statusCode == 404: // venue or stock not found
+		return nil, notFoundError(venue, stock, resp.Error)
 	}
 
 	if !resp.OK {
@@ -158,6 +249,8 @@ func (client *Client) GetOrderbook(venue, stock string) (*Orderbook, error) {
 	}
 
 	return &Orderbook{
+		Venue:     resp.VenueSymbol,
+		Symbol:    resp.StockSymbol,
 		Bids:      resp.Bids,
 		Asks:      resp.Asks,
 		Timestamp: resp.Timestamp,
@@ -170,58 +263,95 @@ func (client *Client) GetOrderbook(venue, stock string) (*Orderbook, error) {
 //     POST https://api.stockfighter.io/ob/api/venues/:venue/stocks/:stock/orders
 func (client *Client) PlaceOrder(venue, stock, account string, price, quantity uint64, direction, orderType string) (*Order, error) {
 	venue = strings.TrimSpace(venue)
-	if venue == "" {
+	if !validSymbol(venue) {
 		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
 	}
 
 	stock = strings.TrimSpace(stock)
-	if stock == "" {
+	if !validSymbol(stock) {
 		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
 	}
 
 	account = strings.TrimSpace(account)
-	if account == "" {
+	if !validSymbol(account) {
 		panic(fmt.Errorf("Invalid account name: %v", account))
 	}
 
-	reqBody := strings.NewReader(fmt.Sprintf(`{
-			"account": "%s",
-			"venue": "%s",
-			"stock": "%s",
-			"price": %d,
-			"qty": %d,
-			"direction": "%s",
-			"orderType": "%s"
-		}`, account, venue, stock, price, quantity, direction, orderType))
+	reqBody := OrderRequest{
+		Venue:     venue,
+		Stock:     stock,
+		Account:   account,
+		Price:     price,
+		Quantity:  quantity,
+		Direction: direction,
+		OrderType: orderType,
+	}
+	if err := validateOrder(&reqBody); err != nil {
+		return nil, err
+	}
+	if err := client.runBeforeOrder(&reqBody); err != nil {
+		return nil, err
+	}
+
+	order, err := client.placeOrder(reqBody)
+	client.runAfterOrder(reqBody, order, err)
+	return order, err
+}
+
+// placeOrder places an order that was validated.
+func (client *Client) placeOrder(reqBody OrderRequest) (*Order, error) {
+	if client.dryRun != nil {
+		return client.placeDryRunOrder(reqBody)
+	}
 
-	var resp apiRespNewStockOrder
-	status, err := client.getAPIJson("POST", "/venues/"+venue+"/stocks/"+stock+"/orders", reqBody, &resp)
+	venue, stock := reqBody.Venue, reqBody.Stock
+	var resp apiRespOrder
+	reply, err := client.call("POST", "/venues/"+url.PathEscape(venue)+"/stocks/"+url.PathEscape(stock)+"/orders", reqBody, &resp)
 	switch {
 	case err != nil:
 		return nil, err
-	case status == 401: // unauthorized
+	case reply.statusCode == 401: // unauthorized
 		return nil, &ErrorUnauthorized{}
-	case status == 404: // stock not found
-		return nil, &ErrorStockNotFound{VenueSymbol: venue, StockSymbol: stock}
+	case reply.statusCode == 404: // venue or stock not found
+		return nil, notFoundError(venue, stock, resp.Error)
 	}
 
 	if !resp.OK {
 		return nil, errors.New(resp.Error)
 	}
 
-	return &Order{
-		Direction:        resp.Direction,
-		OriginalQuantity: resp.OriginalQuantity,
-		Quantity:         resp.Quantity,
-		Price:            resp.Price,
-		OrderType:        resp.OrderType,
-		OrderID:          resp.OrderID,
-		Account:          resp.Account,
-		Timestamp:        resp.Timestamp,
-		Fills:            resp.Fills,
-		TotalFilled:      resp.TotalFilled,
-		Open:             resp.Open,
-	}, nil
+	client.logInfo("stockfighter: order placed", "venue", venue, "stock", stock, "account", reqBody.Account, "id", resp.OrderID,
+		"direction", resp.Direction, "orderType", resp.OrderType, "price", resp.Price, "qty", resp.OriginalQuantity, "filled", resp.TotalFilled)
+
+	return &resp.Order, nil
+}
+
+// validateOrder checks an order before it is sent, so that obviously invalid
+// orders do not use up the rate limit. The price of market orders is ignored
+// by the API, and is zeroed.
+func validateOrder(order *OrderRequest) error {
+	if order.Quantity == 0 {
+		return &ErrorInvalidOrder{Reason: "zero quantity"}
+	}
+
+	switch order.Direction {
+	case OrderDirectionBuy, OrderDirectionSell:
+	default:
+		return &ErrorInvalidOrder{Reason: fmt.Sprintf("unknown direction %q", order.Direction)}
+	}
+
+	switch order.OrderType {
+	case OrderTypeMarket:
+		order.Price = 0
+	case OrderTypeLimit, OrderTypeFillOrKill, OrderTypeImmediateOrCancel:
+		if order.Price == 0 {
+			return &ErrorInvalidOrder{Reason: order.OrderType + " order without a price"}
+		}
+	default:
+		return &ErrorInvalidOrder{Reason: fmt.Sprintf("unknown order type %q", order.OrderType)}
+	}
+
+	return nil
 }
 
 // GetQuote returns a quick look at the most recent trade information for a stock.
@@ -230,42 +360,56 @@ func (client *Client) PlaceOrder(venue, stock, account string, price, quantity u
 //     GET https://api.stockfighter.io/ob/api/venues/:venue/stocks/:stock/quote
 func (client *Client) GetQuote(venue, stock string) (*Quote, error) {
 	venue = strings.TrimSpace(venue)
-	if venue == "" {
+	if !validSymbol(venue) {
 		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
 	}
 
 	stock = strings.TrimSpace(stock)
-	if stock == "" {
+	if !validSymbol(stock) {
 		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
 	}
 
+	if quote, ok := client.quotes.get(venue, stock); ok {
+		return quote, nil
+	}
+
 	var resp apiRespStockQuote
-	status, err := client.getAPIJson("GET", "/venues/"+venue+"/stocks/"+stock+"/quote", nil, &resp)
+	reply, err := client.call("GET", "/venues/"+url.PathEscape(venue)+"/stocks/"+url.PathEscape(stock)+"/quote", nil, &resp)
 	switch {
 	case err != nil:
 		return nil, err
-	case status == 401: // unauthorized
+	case reply.statusCode == 401: // unauthorized
 		return nil, &ErrorUnauthorized{}
-	case status == 404: // venue or stock not found
-		return nil, &ErrorStockNotFound{VenueSymbol: venue, StockSymbol: stock}
+	case reply.statusCode == 404: // venue or stock not found
+		return nil, notFoundError(venue, stock, resp.Error)
 	}
 
 	if !resp.OK {
 		return nil, errors.New(resp.Error)
 	}
 
-	return &Quote{
-		BidPrice:      resp.BidPrice,
+	quote := &Quote{
+		Venue:         resp.VenueSymbol,
+		Symbol:        resp.StockSymbol,
 		BidSize:       resp.BidSize,
 		BidDepth:      resp.BidDepth,
-		AskPrice:      resp.AskPrice,
 		AskSize:       resp.AskSize,
 		AskDepth:      resp.AskDepth,
 		LastPrice:     resp.LastPrice,
 		LastSize:      resp.LastSize,
 		LastTradeTime: resp.LastTradeTime,
 		QuoteTime:     resp.QuoteTime,
-	}, nil
+	}
+	// bid and ask are absent when there are no bids or asks
+	if resp.BidPrice != nil {
+		quote.HasBid, quote.BidPrice = true, *resp.BidPrice
+	}
+	if resp.AskPrice != nil {
+		quote.HasAsk, quote.AskPrice = true, *resp.AskPrice
+	}
+	client.quotes.put(venue, stock, quote)
+
+	return quote, nil
 }
 
 // GetOrder returns a status of an existing order.
@@ -274,42 +418,36 @@ func (client *Client) GetQuote(venue, stock string) (*Quote, error) {
 //     GET https://api.stockfighter.io/ob/api/venues/:venue/stocks/:stock/orders/:id
 func (client *Client) GetOrder(venue, stock string, orderID int64) (*Order, error) {
 	venue = strings.TrimSpace(venue)
-	if venue == "" {
+	if !validSymbol(venue) {
 		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
 	}
 
 	stock = strings.TrimSpace(stock)
-	if stock == "" {
+	if !validSymbol(stock) {
 		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
 	}
 
-	var resp apiRespStockOrderStatus
-	status, err := client.getAPIJson("GET", "/venues/"+venue+"/stocks/"+stock+"/orders/"+strconv.FormatInt(orderID, 10), nil, &resp)
+	if client.dryRun != nil {
+		if order, ok := client.getDryRunOrder(venue, stock, orderID); ok {
+			return order, nil
+		}
+	}
+
+	var resp apiRespOrder
+	reply, err := client.call("GET", "/venues/"+url.PathEscape(venue)+"/stocks/"+url.PathEscape(stock)+"/orders/"+strconv.FormatInt(orderID, 10), nil, &resp)
 	switch {
 	case err != nil:
 		return nil, err
-	case status == 401: // unauthorized
+	case reply.statusCode == 401: // unauthorized
 		return nil, &ErrorUnauthorized{}
-		//case status == 404: // venue, stock, or order ID not found
+		//case reply.statusCode == 404: // venue, stock, or order ID not found
 	}
 
 	if !resp.OK {
 		return nil, errors.New(resp.Error)
 	}
 
-	return &Order{
-		Direction:        resp.Direction,
-		OriginalQuantity: resp.OriginalQuantity,
-		Quantity:         resp.Quantity,
-		Price:            resp.Price,
-		OrderType:        resp.OrderType,
-		OrderID:          resp.OrderID,
-		Account:          resp.Account,
-		Timestamp:        resp.Timestamp,
-		Fills:            resp.Fills,
-		TotalFilled:      resp.TotalFilled,
-		Open:             resp.Open,
-	}, nil
+	return &resp.Order, nil
 }
 
 // CancelOrder cancels an order.
@@ -318,43 +456,37 @@ func (client *Client) GetOrder(venue, stock string, orderID int64) (*Order, erro
 //     DELETE https://api.stockfighter.io/ob/api/venues/:venue/stocks/:stock/orders/:order
 func (client *Client) CancelOrder(venue, stock string, orderID int64) (*Order, error) {
 	venue = strings.TrimSpace(venue)
-	if venue == "" {
+	if !validSymbol(venue) {
 		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
 	}
 
 	stock = strings.TrimSpace(stock)
-	if stock == "" {
+	if !validSymbol(stock) {
 		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
 	}
 
-	var resp apiRespStockOrderStatus
-	status, err := client.getAPIJson("DELETE", "/venues/"+venue+"/stocks/"+stock+"/orders/"+strconv.FormatInt(orderID, 10), nil, &resp)
+	if client.dryRun != nil {
+		return client.cancelDryRunOrder(venue, stock, orderID)
+	}
+
+	var resp apiRespOrder
+	reply, err := client.call("DELETE", "/venues/"+url.PathEscape(venue)+"/stocks/"+url.PathEscape(stock)+"/orders/"+strconv.FormatInt(orderID, 10), nil, &resp)
 	switch {
 	case err != nil:
 		return nil, err
-	case status == 401: // unauthorized
+	case reply.statusCode == 401: // unauthorized
 		return nil, &ErrorUnauthorized{}
-	case status == 404: // stock not found
-		return nil, &ErrorStockNotFound{VenueSymbol: venue, StockSymbol: stock}
+	case reply.statusCode == 404: // venue or stock not found
+		return nil, notFoundError(venue, stock, resp.Error)
 	}
 
 	if !resp.OK {
 		return nil, errors.New(resp.Error)
 	}
 
-	return &Order{
-		Direction:        resp.Direction,
-		OriginalQuantity: resp.OriginalQuantity,
-		Quantity:         resp.Quantity,
-		Price:            resp.Price,
-		OrderType:        resp.OrderType,
-		OrderID:          resp.OrderID,
-		Account:          resp.Account,
-		Timestamp:        resp.Timestamp,
-		Fills:            resp.Fills,
-		TotalFilled:      resp.TotalFilled,
-		Open:             resp.Open,
-	}, nil
+	client.logInfo("stockfighter: order canceled", "venue", venue, "stock", stock, "id", resp.OrderID, "filled", resp.TotalFilled)
+
+	return &resp.Order, nil
 }
 
 // GetAllOrders returns status of all stock orders in the venue.
@@ -363,23 +495,23 @@ func (client *Client) CancelOrder(venue, stock string, orderID int64) (*Order, e
 //     GET https://api.stockfighter.io/ob/api/venues/:venue/accounts/:account/orders
 func (client *Client) GetAllOrders(venue, account string) ([]Order, error) {
 	venue = strings.TrimSpace(venue)
-	if venue == "" {
+	if !validSymbol(venue) {
 		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
 	}
 
 	account = strings.TrimSpace(account)
-	if account == "" {
+	if !validSymbol(account) {
 		panic(fmt.Errorf("Invalid account name: %v", account))
 	}
 
 	var resp apiRespAllOrdersStatus
-	status, err := client.getAPIJson("GET", "/venues/"+venue+"/accounts/"+account+"/orders", nil, &resp)
+	reply, err := client.call("GET", "/venues/"+url.PathEscape(venue)+"/accounts/"+url.PathEscape(account)+"/orders", nil, &resp)
 	switch {
 	case err != nil:
 		return nil, err
-	case status == 401: // unauthorized
+	case reply.statusCode == 401: // unauthorized
 		return nil, &ErrorUnauthorized{}
-	case status == 404: // venue not found
+	case reply.statusCode == 404: // venue not found
 		return nil, &ErrorVenueNotFound{VenueSymbol: venue}
 	}
 
@@ -387,6 +519,9 @@ func (client *Client) GetAllOrders(venue, account string) ([]Order, error) {
 		return nil, errors.New(resp.Error)
 	}
 
+	if client.dryRun != nil {
+		return append(resp.Orders, client.listDryRunOrders(venue, account, "")...), nil
+	}
 	return resp.Orders, nil
 }
 
@@ -396,34 +531,37 @@ func (client *Client) GetAllOrders(venue, account string) ([]Order, error) {
 //     GET https://api.stockfighter.io/ob/api/venues/:venue/accounts/:account/stocks/:stock/orders
 func (client *Client) GetStockOrders(venue, account, stock string) ([]Order, error) {
 	venue = strings.TrimSpace(venue)
-	if venue == "" {
+	if !validSymbol(venue) {
 		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
 	}
 
 	account = strings.TrimSpace(account)
-	if account == "" {
+	if !validSymbol(account) {
 		panic(fmt.Errorf("Invalid account name: %v", account))
 	}
 
 	stock = strings.TrimSpace(stock)
-	if stock == "" {
+	if !validSymbol(stock) {
 		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
 	}
 
 	var resp apiRespAllOrdersStatus
-	status, err := client.getAPIJson("GET", "/venues/"+venue+"/accounts/"+account+"/stocks/"+stock+"/orders", nil, &resp)
+	reply, err := client.call("GET", "/venues/"+url.PathEscape(venue)+"/accounts/"+url.PathEscape(account)+"/stocks/"+url.PathEscape(stock)+"/orders", nil, &resp)
 	switch {
 	case err != nil:
 		return nil, err
-	case status == 401: // unauthorized
+	case reply.statusCode == 401: // unauthorized
 		return nil, &ErrorUnauthorized{}
-	case status == 404: // venue not found
-		return nil, &ErrorVenueNotFound{VenueSymbol: venue}
+	case reply.statusCode == 404: // venue or stock not found
+		return nil, notFoundError(venue, stock, resp.Error)
 	}
 
 	if !resp.OK {
 		return nil, errors.New(resp.Error)
 	}
 
+	if client.dryRun != nil {
+		return append(resp.Orders, client.listDryRunOrders(venue, account, stock)...), nil
+	}
 	return resp.Orders, nil
 }
@@ -1,12 +1,16 @@
 package stockfighter
 
 import (
-	"testing"
-
-	"fmt"
-
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
 	"os"
-	"strings"
+	"path/filepath"
+	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -16,241 +20,280 @@ const (
 	testStock   = "FOOBAR"
 	testAccount = "EXB123456"
 
-	testPrice    = uint64(5264)
-	testQuantity = uint64(4625)
-
 	testVenueNE = "NOEXIST"
 	testStockNE = "NOEXIST"
 )
 
-var (
-	testApiKey   = ""
-	testApiKeyNE = "INVALID_API_KEY"
-)
+var testApiKey = "TEST_API_KEY"
 
-func TestPing(t *testing.T) {
-	client := NewClient(testApiKey)
+// Golden files in testdata/golden are the JSON encoding of what API calls
+// return for the fixtures in testdata/fixtures. Run go test -update to
+// rewrite them after a deliberate change.
+var update = flag.Bool("update", false, "update golden files")
 
-	assert.Nil(t, client.Ping())
+// An endpointTest is an API call made against a server replying with a
+// fixture.
+type endpointTest struct {
+	name string
 
-	assert.Nil(t, client.PingVenue(testVenue))
+	// Expected request
+	method string
+	path   string
 
-	// 404: venue not found
-	err := client.PingVenue(testVenueNE)
-	_, ok := err.(*ErrorVenueNotFound)
-	assert.True(t, ok)
-}
+	// Reply
+	status  int
+	fixture string
 
-func TestListStocks(t *testing.T) {
-	client := NewClient(testApiKey)
+	call func(client *Client) (interface{}, error)
 
-	stocks, err := client.ListStocks(testVenue)
-	assert.Nil(t, err)
-	var testStockFound = false
-	for _, s := range stocks {
-		if s.Symbol == testStock {
-			testStockFound = true
-			break
+	// Expected error (an error of the same type, or a generic error with the
+	// same message), or nil if the result is checked against the golden file
+	// named after the test
+	err error
+}
+
+func TestEndpoints(t *testing.T) {
+	ping := func(client *Client) (interface{}, error) {
+		return nil, client.Ping()
+	}
+	pingVenue := func(venue string) func(client *Client) (interface{}, error) {
+		return func(client *Client) (interface{}, error) {
+			return nil, client.PingVenue(venue)
+		}
+	}
+	listStocks := func(venue string) func(client *Client) (interface{}, error) {
+		return func(client *Client) (interface{}, error) {
+			return client.ListStocks(venue)
 		}
 	}
-	assert.True(t, testStockFound)
+	getOrderbook := func(venue, stock string) func(client *Client) (interface{}, error) {
+		return func(client *Client) (interface{}, error) {
+			return client.GetOrderbook(venue, stock)
+		}
+	}
+	getQuote := func(venue, stock string) func(client *Client) (interface{}, error) {
+		return func(client *Client) (interface{}, error) {
+			return client.GetQuote(venue, stock)
+		}
+	}
+	placeOrder := func(venue, stock, orderType string) func(client *Client) (interface{}, error) {
+		return func(client *Client) (interface{}, error) {
+			return client.PlaceOrder(venue, stock, testAccount, 5100, 100, OrderDirectionBuy, orderType)
+		}
+	}
+	getOrder := func(venue, stock string) func(client *Client) (interface{}, error) {
+		return func(client *Client) (interface{}, error) {
+			return client.GetOrder(venue, stock, 12345)
+		}
+	}
+	cancelOrder := func(venue, stock string) func(client *Client) (interface{}, error) {
+		return func(client *Client) (interface{}, error) {
+			return client.CancelOrder(venue, stock, 12345)
+		}
+	}
+	getAllOrders := func(venue string) func(client *Client) (interface{}, error) {
+		return func(client *Client) (interface{}, error) {
+			return client.GetAllOrders(venue, testAccount)
+		}
+	}
+	getStockOrders := func(venue, stock string) func(client *Client) (interface{}, error) {
+		return func(client *Client) (interface{}, error) {
+			return client.GetStockOrders(venue, testAccount, stock)
+		}
+	}
+
+	venueNotFound := &ErrorVenueNotFound{VenueSymbol: testVenueNE}
+	stockNotFound := &ErrorStockNotFound{VenueSymbol: testVenue, StockSymbol: testStockNE}
+
+	tests := []endpointTest{
+		{"heartbeat", "GET", "/heartbeat", 200, "heartbeat", ping, nil},
+
+		{"venue_heartbeat", "GET", "/venues/TESTEX/heartbeat", 200, "venue_heartbeat", pingVenue(testVenue), nil},
+		{"venue_heartbeat_not_found", "GET", "/venues/NOEXIST/heartbeat", 404, "error_venue_not_found", pingVenue(testVenueNE), venueNotFound},
+		{"venue_heartbeat_timeout", "GET", "/venues/TESTEX/heartbeat", 500, "error_timeout", pingVenue(testVenue), &ErrorAPITimeout{}},
+
+		{"stocks", "GET", "/venues/TESTEX/stocks", 200, "stocks", listStocks(testVenue), nil},
+		{"stocks_unauthorized", "GET", "/venues/TESTEX/stocks", 401, "error_unauthorized", listStocks(testVenue), &ErrorUnauthorized{}},
+		{"stocks_venue_not_found", "GET", "/venues/NOEXIST/stocks", 404, "error_venue_not_found", listStocks(testVenueNE), venueNotFound},
+
+		{"orderbook", "GET", "/venues/TESTEX/stocks/FOOBAR", 200, "orderbook", getOrderbook(testVenue, testStock), nil},
+		{"orderbook_unauthorized", "GET", "/venues/TESTEX/stocks/FOOBAR", 401, "error_unauthorized", getOrderbook(testVenue, testStock), &ErrorUnauthorized{}},
+		{"orderbook_venue_not_found", "GET", "/venues/NOEXIST/stocks/FOOBAR", 404, "error_venue_not_found", getOrderbook(testVenueNE, testStock), venueNotFound},
+		{"orderbook_stock_not_found", "GET", "/venues/TESTEX/stocks/NOEXIST", 404, "error_stock_not_found", getOrderbook(testVenue, testStockNE), stockNotFound},
+
+		{"quote", "GET", "/venues/TESTEX/stocks/FOOBAR/quote", 200, "quote", getQuote(testVenue, testStock), nil},
+		{"quote_no_bids", "GET", "/venues/TESTEX/stocks/FOOBAR/quote", 200, "quote_no_bids", getQuote(testVenue, testStock), nil},
+		{"quote_unauthorized", "GET", "/venues/TESTEX/stocks/FOOBAR/quote", 401, "error_unauthorized", getQuote(testVenue, testStock), &ErrorUnauthorized{}},
+		{"quote_stock_not_found", "GET", "/venues/TESTEX/stocks/NOEXIST/quote", 404, "error_stock_not_found", getQuote(testVenue, testStockNE), stockNotFound},
+		{"quote_venue_not_found", "GET", "/venues/NOEXIST/stocks/FOOBAR/quote", 404, "error_venue_not_found", getQuote(testVenueNE, testStock), venueNotFound},
+
+		{"place_order", "POST", "/venues/TESTEX/stocks/FOOBAR/orders", 200, "order", placeOrder(testVenue, testStock, OrderTypeLimit), nil},
+		{"place_order_unauthorized", "POST", "/venues/TESTEX/stocks/FOOBAR/orders", 401, "error_unauthorized", placeOrder(testVenue, testStock, OrderTypeLimit), &ErrorUnauthorized{}},
+		{"place_order_stock_not_found", "POST", "/venues/TESTEX/stocks/NOEXIST/orders", 404, "error_stock_not_found", placeOrder(testVenue, testStockNE, OrderTypeLimit), stockNotFound},
+		{"place_order_venue_not_found", "POST", "/venues/NOEXIST/stocks/FOOBAR/orders", 404, "error_venue_not_found", placeOrder(testVenueNE, testStock, OrderTypeLimit), venueNotFound},
+
+		{"order_status", "GET", "/venues/TESTEX/stocks/FOOBAR/orders/12345", 200, "order", getOrder(testVenue, testStock), nil},
+		{"order_status_unauthorized", "GET", "/venues/TESTEX/stocks/FOOBAR/orders/12345", 401, "error_unauthorized", getOrder(testVenue, testStock), &ErrorUnauthorized{}},
+		{"order_status_stock_not_found", "GET", "/venues/TESTEX/stocks/NOEXIST/orders/12345", 404, "error_stock_not_found", getOrder(testVenue, testStockNE), apiError("No stock exists with symbol NOEXIST on venue TESTEX")},
+
+		{"cancel_order", "DELETE", "/venues/TESTEX/stocks/FOOBAR/orders/12345", 200, "canceled_order", cancelOrder(testVenue, testStock), nil},
+		{"cancel_order_unauthorized", "DELETE", "/venues/TESTEX/stocks/FOOBAR/orders/12345", 401, "error_unauthorized", cancelOrder(testVenue, testStock), &ErrorUnauthorized{}},
+		{"cancel_order_stock_not_found", "DELETE", "/venues/TESTEX/stocks/NOEXIST/orders/12345", 404, "error_stock_not_found", cancelOrder(testVenue, testStockNE), stockNotFound},
+		{"cancel_order_venue_not_found", "DELETE", "/venues/NOEXIST/stocks/FOOBAR/orders/12345", 404, "error_venue_not_found", cancelOrder(testVenueNE, testStock), venueNotFound},
+
+		{"account_orders", "GET", "/venues/TESTEX/accounts/EXB123456/orders", 200, "orders", getAllOrders(testVenue), nil},
+		{"account_orders_unauthorized", "GET", "/venues/TESTEX/accounts/EXB123456/orders", 401, "error_unauthorized", getAllOrders(testVenue), &ErrorUnauthorized{}},
+		{"account_orders_venue_not_found", "GET", "/venues/NOEXIST/accounts/EXB123456/orders", 404, "error_venue_not_found", getAllOrders(testVenueNE), venueNotFound},
+
+		{"account_stock_orders", "GET", "/venues/TESTEX/accounts/EXB123456/stocks/FOOBAR/orders", 200, "orders", getStockOrders(testVenue, testStock), nil},
+		{"account_stock_orders_unauthorized", "GET", "/venues/TESTEX/accounts/EXB123456/stocks/FOOBAR/orders", 401, "error_unauthorized", getStockOrders(testVenue, testStock), &ErrorUnauthorized{}},
+		{"account_stock_orders_venue_not_found", "GET", "/venues/NOEXIST/accounts/EXB123456/stocks/FOOBAR/orders", 404, "error_venue_not_found", getStockOrders(testVenueNE, testStock), venueNotFound},
+		{"account_stock_orders_stock_not_found", "GET", "/venues/TESTEX/accounts/EXB123456/stocks/NOEXIST/orders", 404, "error_stock_not_found", getStockOrders(testVenue, testStockNE), stockNotFound},
+	}
 
-	// 404: venue not found
-	_, err = client.ListStocks(testVenueNE)
-	_, ok := err.(*ErrorVenueNotFound)
-	assert.True(t, ok)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testEndpoint(t, tt)
+		})
+	}
 }
 
-func TestGetOrderbook(t *testing.T) {
-	client := NewClient(testApiKey)
+func testEndpoint(t *testing.T, tt endpointTest) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "fixtures", tt.fixture+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	orderbook, err := client.GetOrderbook(testVenue, testStock)
-	assert.Nil(t, err)
-	assert.NotNil(t, orderbook)
-	assert.NotNil(t, orderbook.Bids)
-	assert.NotNil(t, orderbook.Asks)
-
-	// 404: venue not found
-	_, err = client.GetOrderbook(testVenueNE, testStock)
-	_, ok := err.(*ErrorVenueNotFound)
-	assert.True(t, ok)
-
-	// 500: "No venue exists with the symbol XXXX"
-	_, err = client.GetOrderbook(testVenue, testStockNE)
-	assert.NotNil(t, err)
-}
+	var method, path, apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, apiKey = r.Method, r.URL.Path, r.Header.Get("X-Starfighter-Authorization")
+		w.WriteHeader(tt.status)
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	result, err := tt.call(NewClient(testApiKey, WithBaseURL(server.URL)))
+	assert.Equal(t, tt.method, method)
+	assert.Equal(t, tt.path, path)
+	assert.Equal(t, testApiKey, apiKey)
+
+	if tt.err != nil {
+		if _, ok := tt.err.(*genericError); ok {
+			assert.EqualError(t, err, tt.err.Error())
+		} else {
+			assert.IsType(t, tt.err, err)
+			assert.Equal(t, tt.err, err)
+		}
+		return
+	}
+	if !assert.Nil(t, err) || result == nil {
+		return
+	}
 
-func TestGetAllOrders(t *testing.T) {
-	client := NewClient(testApiKey)
+	got, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
 
-	orders, err := client.GetAllOrders(testVenue, testAccount)
-	assert.Nil(t, err)
-	assert.NotNil(t, orders)
-
-	// 404: venue not found
-	_, err = client.GetAllOrders(testVenueNE, testStock)
-	_, ok := err.(*ErrorVenueNotFound)
-	assert.True(t, ok)
-
-	// 401: unauthorized
-	clientNE := NewClient(testApiKeyNE)
-	_, err = clientNE.GetAllOrders(testVenueNE, testStock)
-	_, ok = err.(*ErrorUnauthorized)
-	assert.True(t, ok)
+	golden := filepath.Join("testdata", "golden", tt.name+".json")
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(want), string(got))
 }
 
-func TestGetStockOrders(t *testing.T) {
-	client := NewClient(testApiKey)
+// A genericError is an error expected to be returned as errors.New(message).
+type genericError struct {
+	message string
+}
 
-	orders, err := client.GetStockOrders(testVenue, testAccount, testStock)
-	assert.Nil(t, err)
-	assert.NotNil(t, orders)
-
-	// 404: venue not found
-	_, err = client.GetStockOrders(testVenueNE, testAccount, testStock)
-	_, ok := err.(*ErrorVenueNotFound)
-	assert.True(t, ok)
-
-	// 401: unauthorized
-	clientNE := NewClient(testApiKeyNE)
-	_, err = clientNE.GetStockOrders(testVenueNE, testAccount, testStock)
-	_, ok = err.(*ErrorUnauthorized)
-	assert.True(t, ok)
+func apiError(message string) error {
+	return &genericError{message: message}
 }
 
-func TestGetQuote(t *testing.T) {
-	client := NewClient(testApiKey)
+func (e *genericError) Error() string {
+	return e.message
+}
+
+func TestPlaceOrderValidation(t *testing.T) {
+	var body OrderRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+	client := NewClient(testApiKey, WithBaseURL(server.URL))
+
+	tests := []struct {
+		price, qty           uint64
+		direction, orderType string
+		err                  error
+	}{
+		{5100, 0, OrderDirectionBuy, OrderTypeLimit, &ErrorInvalidOrder{Reason: "zero quantity"}},
+		{5100, 10, "short", OrderTypeLimit, &ErrorInvalidOrder{Reason: `unknown direction "short"`}},
+		{5100, 10, OrderDirectionBuy, "stop", &ErrorInvalidOrder{Reason: `unknown order type "stop"`}},
+		{0, 10, OrderDirectionBuy, OrderTypeLimit, &ErrorInvalidOrder{Reason: "limit order without a price"}},
+		{0, 10, OrderDirectionSell, OrderTypeImmediateOrCancel, &ErrorInvalidOrder{Reason: "immediate-or-cancel order without a price"}},
+	}
+	for _, tt := range tests {
+		_, err := client.PlaceOrder(testVenue, testStock, testAccount, tt.price, tt.qty, tt.direction, tt.orderType)
+		assert.Equal(t, tt.err, err)
+	}
+	assert.Zero(t, body.Quantity, "invalid orders must not be sent")
 
-	quote, err := client.GetQuote(testVenue, testStock)
+	// the price of market orders is zeroed
+	_, err := client.PlaceOrder(testVenue, testStock, testAccount, 5100, 10, OrderDirectionSell, OrderTypeMarket)
 	assert.Nil(t, err)
-	assert.NotNil(t, quote)
-
-	// 404: venue or stock not found (this API returns 404 for both errors)
-	_, err = client.GetQuote(testVenueNE, testStock)
-	_, ok := err.(*ErrorStockNotFound)
-	assert.True(t, ok)
-	_, err = client.GetQuote(testVenue, testStockNE)
-	_, ok = err.(*ErrorStockNotFound)
-	assert.True(t, ok)
+	assert.Equal(t, uint64(10), body.Quantity)
+	assert.Zero(t, body.Price)
 }
 
-func TestOrderStuffs(t *testing.T) {
+func TestInvalidSymbols(t *testing.T) {
 	client := NewClient(testApiKey)
+	for _, symbol := range []string{"", " ", "TEST EX", "TEST\tEX", "TESTEX/..", "../heartbeat"} {
+		assert.Panics(t, func() { client.PingVenue(symbol) }, symbol)
+		assert.Panics(t, func() { client.GetQuote(testVenue, symbol) }, symbol)
+		assert.Panics(t, func() { client.GetAllOrders(testVenue, symbol) }, symbol)
+	}
+}
 
-	// BUY
-	buyOrder, err := client.PlaceOrder(testVenue, testStock, testAccount, testPrice, testQuantity, OrderDirectionBuy, OrderTypeLimit)
-	assert.Nil(t, err)
-	assert.NotNil(t, buyOrder)
-	assert.Equal(t, testAccount, buyOrder.Account)
-	assert.Equal(t, OrderDirectionBuy, buyOrder.Direction)
-	assert.Equal(t, OrderTypeLimit, buyOrder.OrderType)
-	assert.NotNil(t, buyOrder.Fills)
-	assert.Equal(t, testQuantity, buyOrder.OriginalQuantity)
-	assert.Equal(t, testPrice, buyOrder.Price)
-	assert.NotZero(t, buyOrder.OrderID)
-
-	// BUY: check status
-	buyOrderStatus, err := client.GetOrder(testVenue, testStock, buyOrder.OrderID)
-	assert.Nil(t, err)
-	assert.NotNil(t, buyOrderStatus)
-	assert.Equal(t, testAccount, buyOrderStatus.Account)
-	assert.Equal(t, OrderDirectionBuy, buyOrderStatus.Direction)
-	assert.Equal(t, OrderTypeLimit, buyOrderStatus.OrderType)
-	assert.NotNil(t, buyOrderStatus.Fills)
-	assert.Equal(t, testQuantity, buyOrderStatus.OriginalQuantity)
-	assert.Equal(t, testPrice, buyOrderStatus.Price)
-	assert.Equal(t, buyOrder.OrderID, buyOrderStatus.OrderID)
-
-	// BUY: cancel
-	buyOrderCancel, err := client.CancelOrder(testVenue, testStock, buyOrder.OrderID)
-	assert.Nil(t, err)
-	assert.NotNil(t, buyOrderCancel)
-	assert.Equal(t, testAccount, buyOrderCancel.Account)
-	assert.Equal(t, OrderDirectionBuy, buyOrderCancel.Direction)
-	assert.Equal(t, OrderTypeLimit, buyOrderCancel.OrderType)
-	assert.NotNil(t, buyOrderCancel.Fills)
-	assert.Equal(t, testQuantity, buyOrderCancel.OriginalQuantity)
-	assert.Equal(t, testPrice, buyOrderCancel.Price)
-	assert.Equal(t, buyOrder.OrderID, buyOrderCancel.OrderID)
-	assert.False(t, buyOrderCancel.Open)
-
-	// SELL
-	sellOrder, err := client.PlaceOrder(testVenue, testStock, testAccount, testPrice, testQuantity, OrderDirectionSell, OrderTypeLimit)
-	assert.Nil(t, err)
-	assert.NotNil(t, sellOrder)
-	assert.Equal(t, testAccount, sellOrder.Account)
-	assert.Equal(t, OrderDirectionSell, sellOrder.Direction)
-	assert.Equal(t, OrderTypeLimit, sellOrder.OrderType)
-	assert.NotNil(t, sellOrder.Fills)
-	assert.Equal(t, testQuantity, sellOrder.OriginalQuantity)
-	assert.Equal(t, testPrice, sellOrder.Price)
-	assert.NotZero(t, sellOrder.OrderID)
-
-	// SELL: check status
-	sellOrderStatus, err := client.GetOrder(testVenue, testStock, sellOrder.OrderID)
-	assert.Nil(t, err)
-	assert.NotNil(t, sellOrderStatus)
-	assert.Equal(t, testAccount, sellOrderStatus.Account)
-	assert.Equal(t, OrderDirectionSell, sellOrderStatus.Direction)
-	assert.Equal(t, OrderTypeLimit, sellOrderStatus.OrderType)
-	assert.NotNil(t, sellOrderStatus.Fills)
-	assert.Equal(t, testQuantity, sellOrderStatus.OriginalQuantity)
-	assert.Equal(t, testPrice, sellOrderStatus.Price)
-	assert.Equal(t, sellOrder.OrderID, sellOrderStatus.OrderID)
-
-	// SELL: cancel
-	sellOrderCancel, err := client.CancelOrder(testVenue, testStock, sellOrder.OrderID)
+func TestPathEscaping(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(testApiKey, WithBaseURL(server.URL))
+	_, err := client.GetQuote("TEST?EX", "FOO#BAR")
 	assert.Nil(t, err)
-	assert.NotNil(t, sellOrderCancel)
-	assert.Equal(t, testAccount, sellOrderCancel.Account)
-	assert.Equal(t, OrderDirectionSell, sellOrderCancel.Direction)
-	assert.Equal(t, OrderTypeLimit, sellOrderCancel.OrderType)
-	assert.NotNil(t, sellOrderCancel.Fills)
-	assert.Equal(t, testQuantity, sellOrderCancel.OriginalQuantity)
-	assert.Equal(t, testPrice, sellOrderCancel.Price)
-	assert.Equal(t, sellOrder.OrderID, sellOrderCancel.OrderID)
-	assert.False(t, sellOrderCancel.Open)
-
-	// invalid order
-	_, err = client.PlaceOrder(testVenue, testStock, testAccount, testPrice, 0, OrderDirectionBuy, OrderTypeLimit)
-	assert.NotNil(t, err)
-	_, err = client.PlaceOrder(testVenue, testStock, testAccount, testPrice, testPrice, "invaliddirection", OrderTypeLimit)
-	assert.NotNil(t, err)
-	_, err = client.PlaceOrder(testVenue, testStock, testAccount, testPrice, testPrice, OrderDirectionSell, "invalidtype")
-	assert.NotNil(t, err)
-
-	// checking with invalid params
-	_, err = client.GetOrder(testVenue, testStock, 0)
-	assert.NotNil(t, err)
-	_, err = client.GetOrder(testVenueNE, testStock, sellOrder.OrderID)
-	assert.NotNil(t, err)
-	_, err = client.GetOrder(testVenue, testStockNE, sellOrder.OrderID)
-	assert.NotNil(t, err)
-
-	// cancelling with invalid params
-	_, err = client.CancelOrder(testVenue, testStock, 0)
-	assert.NotNil(t, err)
-	_, err = client.CancelOrder(testVenueNE, testStock, sellOrder.OrderID)
-	assert.NotNil(t, err)
-	_, err = client.CancelOrder(testVenue, testStockNE, sellOrder.OrderID)
-	assert.NotNil(t, err)
-
-	// 401: unauthorized
-	clientNE := NewClient(testApiKeyNE)
-	_, err = clientNE.PlaceOrder(testVenue, testStock, testAccount, testPrice, testPrice, OrderDirectionBuy, OrderTypeLimit)
-	_, ok := err.(*ErrorUnauthorized)
-	assert.True(t, ok)
-	_, err = clientNE.GetOrder(testVenue, testStock, sellOrder.OrderID)
-	_, ok = err.(*ErrorUnauthorized)
-	assert.True(t, ok)
-	_, err = clientNE.CancelOrder(testVenue, testStock, sellOrder.OrderID)
-	_, ok = err.(*ErrorUnauthorized)
-	assert.True(t, ok)
+	assert.Equal(t, "/venues/TEST%3FEX/stocks/FOO%23BAR/quote", path)
 }
 
-func init() {
-	testApiKey = strings.TrimSpace(os.Getenv("API_KEY"))
-	if testApiKey == "" {
-		panic(fmt.Errorf("API key ($API_KEY) missing"))
-	}
+func TestWithContext(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"ok": true}`))
+	})
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := client.WithContext(ctx).Ping()
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	_, err = client.WithContext(ctx).StartLevel("first_steps")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// the original client is unaffected
+	assert.Nil(t, client.ctx)
+	assert.Panics(t, func() { client.WithContext(nil) })
 }
@@ -0,0 +1,227 @@
+// Command sf-exporter exports the market and the trading activity of an
+// account as Prometheus metrics, so that a level run can be watched in
+// Grafana.
+//
+//     sf-exporter [-listen ADDR] [-interval DURATION] [-base-url URL] [-stocks STOCK,...] -account ACCOUNT VENUE...
+//
+// Every interval, it polls the quotes of the stocks of the venues (all the
+// stocks of each venue by default) and the orders of the account, and exports
+// them on /metrics along with the metrics of package metrics:
+//
+//     stockfighter_quote_cents{venue,stock,side}     bid, ask, and last price
+//     stockfighter_position_shares{venue,stock}      position
+//     stockfighter_cash_cents{venue,stock}           cash from fills
+//     stockfighter_nav_cents{venue,stock}            net asset value at the last price
+//     stockfighter_open_orders{venue,stock}          open orders
+//     stockfighter_fills_total{venue,stock,direction}
+//
+// Fill rates are derived from stockfighter_fills_total and
+// stockfighter_filled_shares_total, e.g. rate(stockfighter_fills_total[1m]).
+//
+// The API key is read from $STOCKFIGHTER_API_KEY.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gpk.io/stockfighter"
+	"gpk.io/stockfighter/metrics"
+)
+
+// exporter polls the API and updates the metrics.
+type exporter struct {
+	client  *stockfighter.Client
+	metrics *metrics.Metrics
+	account string
+	venues  []string
+	stocks  []string
+
+	quotes     *prometheus.GaugeVec
+	cash       *prometheus.GaugeVec
+	nav        *prometheus.GaugeVec
+	openOrders *prometheus.GaugeVec
+}
+
+func newExporter(reg prometheus.Registerer, client *stockfighter.Client, m *metrics.Metrics, account string, venues, stocks []string) *exporter {
+	e := &exporter{
+		client:  client,
+		metrics: m,
+		account: account,
+		venues:  venues,
+		stocks:  stocks,
+		quotes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Name:      "quote_cents",
+			Help:      "Quoted bid, ask, and last price by venue and stock.",
+		}, []string{"venue", "stock", "side"}),
+		cash: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Name:      "cash_cents",
+			Help:      "Cash received from sales minus cash spent on purchases by venue and stock.",
+		}, []string{"venue", "stock"}),
+		nav: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Name:      "nav_cents",
+			Help:      "Net asset value at the last price by venue and stock.",
+		}, []string{"venue", "stock"}),
+		openOrders: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Name:      "open_orders",
+			Help:      "Open orders by venue and stock.",
+		}, []string{"venue", "stock"}),
+	}
+
+	reg.MustRegister(e.quotes, e.cash, e.nav, e.openOrders)
+	return e
+}
+
+// poll updates the metrics of every venue. Errors are logged, and the venue
+// is polled again at the next interval.
+func (e *exporter) poll() {
+	for _, venue := range e.venues {
+		if err := e.pollVenue(venue); err != nil {
+			log.Printf("sf-exporter: %v: %v", venue, err)
+		}
+	}
+}
+
+func (e *exporter) pollVenue(venue string) error {
+	stocks := e.stocks
+	if len(stocks) == 0 {
+		infos, err := e.client.ListStocks(venue)
+		if err != nil {
+			return err
+		}
+		for _, info := range infos {
+			stocks = append(stocks, info.Symbol)
+		}
+	}
+
+	orders, err := e.client.GetAllOrders(venue, e.account)
+	if err != nil {
+		return err
+	}
+	byStock := make(map[string][]stockfighter.Order)
+	for i := range orders {
+		order := &orders[i]
+		byStock[order.Symbol] = append(byStock[order.Symbol], *order)
+		e.metrics.ObserveOrder(venue, order.Symbol, order)
+	}
+
+	for _, stock := range stocks {
+		quote, err := e.client.GetQuote(venue, stock)
+		if err != nil {
+			return err
+		}
+		if quote.HasBid {
+			e.quotes.WithLabelValues(venue, stock, "bid").Set(float64(quote.BidPrice))
+		}
+		if quote.HasAsk {
+			e.quotes.WithLabelValues(venue, stock, "ask").Set(float64(quote.AskPrice))
+		}
+		e.quotes.WithLabelValues(venue, stock, "last").Set(float64(quote.LastPrice))
+
+		position := stockfighter.PositionFromOrders(byStock[stock])
+		var open int
+		for _, order := range byStock[stock] {
+			if order.Open {
+				open++
+			}
+		}
+		e.metrics.SetPosition(venue, stock, position.Shares)
+		e.cash.WithLabelValues(venue, stock).Set(float64(position.Cash))
+		e.nav.WithLabelValues(venue, stock).Set(float64(position.NAV(quote.LastPrice)))
+		e.openOrders.WithLabelValues(venue, stock).Set(float64(open))
+	}
+
+	return nil
+}
+
+func main() {
+	listen := flag.String("listen", ":9101", "address to serve metrics on")
+	interval := flag.Duration("interval", 5*time.Second, "polling interval")
+	baseURL := flag.String("base-url", "", "API base URL (default "+stockfighter.DefaultBaseURL+")")
+	account := flag.String("account", "", "trading account")
+	stocks := flag.String("stocks", "", "comma-separated stocks to export (default all the stocks of each venue)")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: sf-exporter [-listen ADDR] [-interval DURATION] [-base-url URL] [-stocks STOCK,...] -account ACCOUNT VENUE...")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	flag.Parse()
+	if *account == "" || flag.NArg() == 0 {
+		flag.Usage()
+	}
+
+	if err := run(*listen, *interval, *baseURL, *account, flag.Args(), splitList(*stocks)); err != nil {
+		fmt.Fprintln(os.Stderr, "sf-exporter:", err)
+		os.Exit(1)
+	}
+}
+
+func run(listen string, interval time.Duration, baseURL, account string, venues, stocks []string) error {
+	apiKey := strings.TrimSpace(os.Getenv("STOCKFIGHTER_API_KEY"))
+	if apiKey == "" {
+		return errors.New("API key missing: set $STOCKFIGHTER_API_KEY")
+	}
+
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+	options := []stockfighter.ClientOption{stockfighter.WithHTTPClient(&http.Client{Transport: m.RoundTripper(nil)})}
+	if baseURL != "" {
+		options = append(options, stockfighter.WithBaseURL(baseURL))
+	}
+	client := stockfighter.NewClient(apiKey, options...)
+	reg.MustRegister(metrics.UsageCollector(client))
+	e := newExporter(reg, client, m, account, venues, stocks)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: listen, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			e.poll()
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// splitList splits a comma-separated list, ignoring empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+	"gpk.io/stockfighter/metrics"
+)
+
+func TestExporterPoll(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/venues/TESTEX/stocks":
+			w.Write([]byte(`{"ok": true, "symbols": [{"name": "Foreign Owned Occluded Bridge Architecture Resources", "symbol": "FOOBAR"}]}`))
+		case "/venues/TESTEX/stocks/FOOBAR/quote":
+			w.Write([]byte(`{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR", "bid": 5000, "ask": 5100, "last": 5100}`))
+		case "/venues/TESTEX/accounts/EXB123456/orders":
+			w.Write([]byte(`{"ok": true, "venue": "TESTEX", "orders": [
+				{"symbol": "FOOBAR", "venue": "TESTEX", "direction": "buy", "originalQty": 100, "qty": 20, "price": 5100, "orderType": "limit",
+				 "id": 1, "account": "EXB123456", "fills": [{"price": 5050, "qty": 80}], "totalFilled": 80, "open": true},
+				{"symbol": "FOOBAR", "venue": "TESTEX", "direction": "sell", "originalQty": 30, "qty": 0, "price": 5200, "orderType": "limit",
+				 "id": 2, "account": "EXB123456", "fills": [{"price": 5200, "qty": 30}], "totalFilled": 30, "open": false}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer api.Close()
+
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+	client := stockfighter.NewClient("KEY", stockfighter.WithBaseURL(api.URL))
+	e := newExporter(reg, client, m, "EXB123456", []string{"TESTEX"}, nil)
+
+	// fills are counted once however many times orders are polled
+	e.poll()
+	e.poll()
+
+	assert.Equal(t, float64(5000), testutil.ToFloat64(e.quotes.WithLabelValues("TESTEX", "FOOBAR", "bid")))
+	assert.Equal(t, float64(5100), testutil.ToFloat64(e.quotes.WithLabelValues("TESTEX", "FOOBAR", "ask")))
+	assert.Equal(t, float64(5100), testutil.ToFloat64(e.quotes.WithLabelValues("TESTEX", "FOOBAR", "last")))
+	assert.Equal(t, float64(-80*5050+30*5200), testutil.ToFloat64(e.cash.WithLabelValues("TESTEX", "FOOBAR")))
+	assert.Equal(t, float64(-80*5050+30*5200+50*5100), testutil.ToFloat64(e.nav.WithLabelValues("TESTEX", "FOOBAR")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(e.openOrders.WithLabelValues("TESTEX", "FOOBAR")))
+
+	// position_shares, plus one series per direction for fills_total and
+	// filled_shares_total
+	assert.Equal(t, 5, testutil.CollectAndCount(reg, "stockfighter_position_shares", "stockfighter_fills_total", "stockfighter_filled_shares_total"))
+}
+
+func TestSplitList(t *testing.T) {
+	assert.Equal(t, []string{"FOOBAR", "BAR"}, splitList(" FOOBAR,,BAR ,"))
+	assert.Equal(t, 0, len(splitList("")))
+}
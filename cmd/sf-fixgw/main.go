@@ -0,0 +1,55 @@
+// Command sf-fixgw serves a FIX 4.2 gateway to the Stockfighter API (see
+// package fixgw), trading an account.
+//
+//     sf-fixgw [-listen ADDR] [-comp-id ID] [-poll DURATION] [-base-url URL] VENUE ACCOUNT
+//
+// FIX clients log on with the gateway CompID as their TargetCompID. The API
+// key is read from $STOCKFIGHTER_API_KEY.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"gpk.io/stockfighter"
+	"gpk.io/stockfighter/fixgw"
+)
+
+func main() {
+	listen := flag.String("listen", "localhost:9878", "address to listen on")
+	compID := flag.String("comp-id", fixgw.DefaultCompID, "CompID of the gateway (empty accepts any)")
+	poll := flag.Duration("poll", 500*time.Millisecond, "interval between polls of open orders and market data")
+	baseURL := flag.String("base-url", "", "API base URL")
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: sf-fixgw [flags] VENUE ACCOUNT")
+		os.Exit(2)
+	}
+	apiKey := strings.TrimSpace(os.Getenv("STOCKFIGHTER_API_KEY"))
+	if apiKey == "" {
+		fmt.Fprintln(os.Stderr, "sf-fixgw: API key missing: set $STOCKFIGHTER_API_KEY")
+		os.Exit(1)
+	}
+
+	var options []stockfighter.ClientOption
+	if *baseURL != "" {
+		options = append(options, stockfighter.WithBaseURL(strings.TrimRight(*baseURL, "/")))
+	}
+	gw := fixgw.New(stockfighter.NewClient(apiKey, options...), flag.Arg(0), flag.Arg(1))
+	gw.CompID = *compID
+	gw.PollInterval = *poll
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("sf-fixgw: listening on %v", *listen)
+	log.Fatal(gw.Serve(context.Background(), listener))
+}
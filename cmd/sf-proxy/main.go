@@ -0,0 +1,52 @@
+// Command sf-proxy serves a local proxy of the Stockfighter API (see package
+// proxy), so that local scripts share the API key and rate budget.
+//
+//     sf-proxy [-listen ADDR] [-cache-ttl DURATION] [-interval DURATION] [-base-url URL] [-gm-url URL]
+//
+// Scripts use http://ADDR/ob/api and http://ADDR/gm as their base URLs, e.g.
+// with the stockfighter command:
+//
+//     stockfighter -base-url http://localhost:8080/ob/api -gm-url http://localhost:8080/gm quote TESTEX FOOBAR
+//
+// The API key is read from $STOCKFIGHTER_API_KEY.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"gpk.io/stockfighter/proxy"
+)
+
+func main() {
+	listen := flag.String("listen", "localhost:8080", "address to listen on")
+	cacheTTL := flag.Duration("cache-ttl", 250*time.Millisecond, "time-to-live of cached GET responses (0 disables caching)")
+	interval := flag.Duration("interval", 0, "minimum interval between API requests")
+	baseURL := flag.String("base-url", "", "API base URL to forward to")
+	gmBaseURL := flag.String("gm-url", "", "GM API base URL to forward to")
+	flag.Parse()
+
+	apiKey := strings.TrimSpace(os.Getenv("STOCKFIGHTER_API_KEY"))
+	if apiKey == "" {
+		fmt.Fprintln(os.Stderr, "sf-proxy: API key missing: set $STOCKFIGHTER_API_KEY")
+		os.Exit(1)
+	}
+
+	p := proxy.New(apiKey)
+	p.CacheTTL = *cacheTTL
+	p.Interval = *interval
+	if *baseURL != "" {
+		p.BaseURL = strings.TrimRight(*baseURL, "/")
+	}
+	if *gmBaseURL != "" {
+		p.GMBaseURL = strings.TrimRight(*gmBaseURL, "/")
+	}
+
+	log.Printf("sf-proxy: listening on %v", *listen)
+	log.Fatal(http.ListenAndServe(*listen, p))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gpk.io/stockfighter"
+)
+
+func runBooks(client *stockfighter.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("books", flag.ContinueOnError)
+	diff := flags.String("diff", "", "book states to compare with, as printed by another build")
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if *diff == "" {
+		return books(client, f, out)
+	}
+
+	golden, err := os.ReadFile(*diff)
+	if err != nil {
+		return err
+	}
+	var states bytes.Buffer
+	if err := books(client, f, &states); err != nil {
+		return err
+	}
+	return diffBooks(golden, states.Bytes(), out)
+}
+
+// books replays the tickertape frames of a stream journal through the
+// decoding and book mirroring pipeline, with a BookMirror per stock, and
+// prints the mirrored orderbook after each frame, as JSON lines.
+//
+// Book states printed by builds of two versions of the package, from the
+// same journal, can then be compared with diffBooks to catch regressions.
+func books(client *stockfighter.Client, in io.Reader, out io.Writer) error {
+	mirrors := make(map[string]*stockfighter.BookMirror)
+	encoder := json.NewEncoder(out)
+	return stockfighter.ReplayJournal(context.Background(), in, 0, func(frame *stockfighter.JournalFrame) error {
+		if !strings.HasSuffix(frame.Stream, "/tickertape") && !strings.Contains(frame.Stream, "/tickertape/") {
+			return nil
+		}
+
+		quote, err := client.DecodeTickertapeFrame(frame.Data)
+		if err != nil {
+			return err
+		}
+		key := quote.Venue + "/" + quote.Symbol
+		mirror, ok := mirrors[key]
+		if !ok {
+			mirror = stockfighter.NewBookMirror(client, quote.Venue, quote.Symbol)
+			mirrors[key] = mirror
+		}
+		mirror.ApplyQuote(quote)
+		return encoder.Encode(mirror.Orderbook())
+	})
+}
+
+// diffBooks prints the book states which differ between want and got, as
+// printed by books, and fails if any does.
+func diffBooks(want, got []byte, out io.Writer) error {
+	wantLines, gotLines := bookLines(want), bookLines(got)
+	n := len(wantLines)
+	if len(gotLines) > n {
+		n = len(gotLines)
+	}
+
+	diffs := 0
+	for i := 0; i < n; i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		diffs++
+		fmt.Fprintf(out, "state %d:\n- %v\n+ %v\n", i+1, w, g)
+	}
+	if diffs > 0 {
+		return fmt.Errorf("%d of %d book states differ", diffs, n)
+	}
+	return nil
+}
+
+func bookLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1<<24)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
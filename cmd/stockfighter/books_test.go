@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func TestBooks(t *testing.T) {
+	var journal bytes.Buffer
+	recorder := stockfighter.NewStreamJournal(&journal)
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	recorder.Record("/ws/EXB123456/venues/TESTEX/tickertape", ts,
+		[]byte(`{"ok":true,"quote":{"symbol":"FOOBAR","venue":"TESTEX","bid":5000,"bidSize":10,"bidDepth":10,"quoteTime":"2015-12-04T09:02:16Z"}}`))
+	recorder.Record("/ws/EXB123456/venues/TESTEX/executions", ts, []byte(`{"ok":true}`))
+	recorder.Record("/ws/EXB123456/venues/TESTEX/tickertape/stocks/FOOBAR", ts,
+		[]byte(`{"ok":true,"quote":{"symbol":"FOOBAR","venue":"TESTEX","bid":5000,"bidSize":10,"bidDepth":10,`+
+			`"ask":5100,"askSize":5,"askDepth":5,"quoteTime":"2015-12-04T09:02:17Z"}}`))
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "journal")
+	assert.Nil(t, os.WriteFile(path, journal.Bytes(), 0644))
+
+	var out bytes.Buffer
+	client := stockfighter.NewClient("")
+	assert.Nil(t, runBooks(client, []string{path}, &out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"bids":[{"price":5000,"qty":10,"isBuy":true}],"asks":[]`)
+	assert.Contains(t, lines[1], `"asks":[{"price":5100,"qty":5,"isBuy":false}]`)
+
+	golden := filepath.Join(dir, "golden")
+	assert.Nil(t, os.WriteFile(golden, out.Bytes(), 0644))
+	out.Reset()
+	assert.Nil(t, runBooks(client, []string{"-diff", golden, path}, &out))
+	assert.Equal(t, "", out.String())
+
+	// a regression in the second state
+	assert.Nil(t, os.WriteFile(golden, []byte(lines[0]+"\n"+strings.Replace(lines[1], "5100", "5200", 1)+"\n"), 0644))
+	err := runBooks(client, []string{"-diff", golden, path}, &out)
+	assert.EqualError(t, err, "1 of 2 book states differ")
+	assert.True(t, strings.HasPrefix(out.String(), "state 2:\n- "), out.String())
+
+	assert.NotNil(t, runBooks(client, []string{filepath.Join(dir, "missing")}, &out))
+	assert.NotNil(t, runBooks(client, nil, &out))
+}
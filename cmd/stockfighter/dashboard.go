@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+func runDashboard(client *stockfighter.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("watch dashboard", flag.ContinueOnError)
+	account := flags.String("account", defaults.account, "trading account")
+	interval := flags.Duration("interval", stockfighter.DefaultPollInterval, "polling interval")
+	fills := flags.Int("fills", 10, "fills shown in the blotter")
+	args, err := parseVenueArgs(flags, args, 2)
+	if err != nil {
+		return err
+	}
+	if *account == "" {
+		return errors.New("-account is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return watchDashboard(ctx, client, args[0], args[1], *account, *interval, *fills, out)
+}
+
+// watchDashboard redraws the dashboard of an account for a stock every
+// interval, until ctx is done.
+func watchDashboard(ctx context.Context, client *stockfighter.Client, venue, stock, account string, interval time.Duration, fills int, out io.Writer) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		quote, err := client.GetQuote(venue, stock)
+		if err != nil {
+			return err
+		}
+		orders, err := client.GetStockOrders(venue, account, stock)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(out, clearScreen)
+		printDashboard(out, venue, stock, account, quote, orders, fills)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// printDashboard prints the quote, position, open orders, and latest fills of
+// an account for a stock, one pane after the other.
+func printDashboard(out io.Writer, venue, stock, account string, quote *stockfighter.Quote, orders []stockfighter.Order, fills int) {
+	fmt.Fprintf(out, "%v on %v, account %v at %v\n", stock, venue, account, quote.QuoteTime.Format("15:04:05.000"))
+
+	fmt.Fprintln(out, "\nQUOTE")
+	fmt.Fprintf(out, "  bid %v  ask %v  last %v x %v\n", quoteSide(quote.HasBid, quote.BidPrice, quote.BidSize),
+		quoteSide(quote.HasAsk, quote.AskPrice, quote.AskSize), price(quote.LastPrice), quote.LastSize)
+
+	position := stockfighter.PositionFromOrders(orders)
+	fmt.Fprintln(out, "\nPOSITION")
+	fmt.Fprintf(out, "  shares %v  cash %v  NAV %v\n", position.Shares, money(position.Cash), money(position.NAV(quote.LastPrice)))
+
+	fmt.Fprintln(out, "\nOPEN ORDERS")
+	for _, order := range orders {
+		if order.Open {
+			fmt.Fprintf(out, "  #%v %v %v %v @ %v: filled %v/%v\n", order.OrderID, order.Direction, order.OriginalQuantity,
+				order.OrderType, price(order.Price), order.TotalFilled, order.OriginalQuantity)
+		}
+	}
+
+	type blotterFill struct {
+		stockfighter.OrderFillInfo
+		order *stockfighter.Order
+	}
+	var blotter []blotterFill
+	for i := range orders {
+		for _, fill := range orders[i].Fills {
+			blotter = append(blotter, blotterFill{OrderFillInfo: fill, order: &orders[i]})
+		}
+	}
+	// newest first
+	sort.SliceStable(blotter, func(i, j int) bool {
+		return blotter[i].Timestamp.After(blotter[j].Timestamp)
+	})
+	if len(blotter) > fills {
+		blotter = blotter[:fills]
+	}
+
+	fmt.Fprintln(out, "\nFILLS")
+	for _, fill := range blotter {
+		fmt.Fprintf(out, "  %v %v %v @ %v (#%v)\n", fill.Timestamp.Format("15:04:05.000"), fill.order.Direction,
+			fill.Quantity, price(fill.Price), fill.order.OrderID)
+	}
+}
+
+// money formats a signed amount in cents as dollars.
+func money(cents int64) string {
+	if cents < 0 {
+		return "-" + price(uint64(-cents))
+	}
+	return price(uint64(cents))
+}
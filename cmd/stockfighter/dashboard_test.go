@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchDashboard(t *testing.T) {
+	client := newTestClient(t, `{"ok": true, "bid": 5000, "bidSize": 10, "ask": 5100, "askSize": 5, "last": 5050, "lastSize": 3,
+		"quoteTime": "2015-12-04T09:02:16Z", "orders": [
+			{"id": 1, "direction": "buy", "originalQty": 10, "orderType": "limit", "price": 5000, "totalFilled": 10, "open": false,
+				"fills": [{"price": 5000, "qty": 10, "ts": "2015-12-04T09:01:00Z"}]},
+			{"id": 2, "direction": "sell", "originalQty": 8, "orderType": "limit", "price": 5100, "totalFilled": 2, "open": true,
+				"fills": [{"price": 5100, "qty": 2, "ts": "2015-12-04T09:02:00Z"}]}]}`)
+
+	var out bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Nil(t, watchDashboard(ctx, client, "TESTEX", "FOOBAR", "EXB123456", time.Hour, 1, &out))
+	assert.Equal(t, clearScreen+"FOOBAR on TESTEX, account EXB123456 at 09:02:16.000\n"+
+		"\nQUOTE\n  bid $50.00 x 10  ask $51.00 x 5  last $50.50 x 3\n"+
+		"\nPOSITION\n  shares 8  cash -$398.00  NAV $6.00\n"+
+		"\nOPEN ORDERS\n  #2 sell 8 limit @ $51.00: filled 2/8\n"+
+		"\nFILLS\n  09:02:00.000 sell 2 @ $51.00 (#2)\n", out.String())
+
+	assert.EqualError(t, runWatch(client, []string{"dashboard", "TESTEX", "FOOBAR"}, &out), "-account is required")
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gpk.io/stockfighter"
+)
+
+func runLevel(client *stockfighter.Client, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("expected start, restart, resume, stop, or status")
+	}
+
+	switch args[0] {
+	case "start":
+		return runStartLevel(client, args[1:], out)
+	case "restart":
+		return runLevelInstance(client, "restart", client.RestartLevel, args[1:], out)
+	case "resume":
+		return runLevelInstance(client, "resume", client.ResumeLevel, args[1:], out)
+	case "stop":
+		return runStopLevel(client, args[1:], out)
+	case "status":
+		return runLevelStatus(client, args[1:], out)
+	}
+
+	return fmt.Errorf("unknown level command %q", args[0])
+}
+
+// levelFlags returns the flags of a level command.
+func levelFlags(name string) (flags *flag.FlagSet, asJSON, asEnv *bool) {
+	flags = flag.NewFlagSet("level "+name, flag.ContinueOnError)
+	asJSON = flags.Bool("json", false, "print JSON")
+	asEnv = flags.Bool("env", false, "print shell variable assignments")
+	return flags, asJSON, asEnv
+}
+
+func runStartLevel(client *stockfighter.Client, args []string, out io.Writer) error {
+	flags, asJSON, asEnv := levelFlags("start")
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	level, err := client.StartLevel(args[0])
+	if err != nil {
+		return err
+	}
+
+	return printLevel(out, level, *asJSON, *asEnv)
+}
+
+func runLevelInstance(client *stockfighter.Client, name string, fn func(instanceID int64) (*stockfighter.Level, error), args []string, out io.Writer) error {
+	flags, asJSON, asEnv := levelFlags(name)
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	instanceID, err := parseInstanceID(args[0])
+	if err != nil {
+		return err
+	}
+
+	level, err := fn(instanceID)
+	if err != nil {
+		return err
+	}
+
+	return printLevel(out, level, *asJSON, *asEnv)
+}
+
+func runStopLevel(client *stockfighter.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("level stop", flag.ContinueOnError)
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	instanceID, err := parseInstanceID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := client.StopLevel(instanceID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "instance %v stopped\n", instanceID)
+	return nil
+}
+
+func runLevelStatus(client *stockfighter.Client, args []string, out io.Writer) error {
+	flags, asJSON, asEnv := levelFlags("status")
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	instanceID, err := parseInstanceID(args[0])
+	if err != nil {
+		return err
+	}
+
+	status, err := client.GetLevelStatus(instanceID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *asJSON:
+		return printJSON(out, status)
+	case *asEnv:
+		printEnv(out, "INSTANCE_ID", strconv.FormatInt(status.InstanceID, 10))
+		printEnv(out, "STATE", status.State)
+		printEnv(out, "DONE", strconv.FormatBool(status.Done))
+		printEnv(out, "TRADING_DAY", strconv.Itoa(status.TradingDay))
+		return nil
+	}
+
+	fmt.Fprintf(out, "instance %v: %v, day %v/%v\n", status.InstanceID, status.State, status.TradingDay, status.EndOfTheWorldDay)
+	if status.Flash != "" {
+		fmt.Fprintln(out, status.Flash)
+	}
+	return nil
+}
+
+func parseInstanceID(arg string) (int64, error) {
+	instanceID, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid instance ID: %v", arg)
+	}
+	return instanceID, nil
+}
+
+// printLevel prints a level instance. The -env output can be sourced into
+// shell scripts:
+//
+//     eval "$(stockfighter level start -env first_steps)"
+//     stockfighter quote $VENUE $STOCK
+func printLevel(out io.Writer, level *stockfighter.Level, asJSON, asEnv bool) error {
+	switch {
+	case asJSON:
+		return printJSON(out, level)
+	case asEnv:
+		printEnv(out, "INSTANCE_ID", strconv.FormatInt(level.InstanceID, 10))
+		printEnv(out, "ACCOUNT", level.Account)
+		printEnv(out, "VENUES", strings.Join(level.Venues, " "))
+		printEnv(out, "TICKERS", strings.Join(level.Tickers, " "))
+		// the first venue and stock, for the common single-stock levels
+		if len(level.Venues) > 0 {
+			printEnv(out, "VENUE", level.Venues[0])
+		}
+		if len(level.Tickers) > 0 {
+			printEnv(out, "STOCK", level.Tickers[0])
+		}
+		return nil
+	}
+
+	fmt.Fprintf(out, "instance %v\n", level.InstanceID)
+	fmt.Fprintf(out, "account  %v\n", level.Account)
+	fmt.Fprintf(out, "venues   %v\n", strings.Join(level.Venues, " "))
+	fmt.Fprintf(out, "tickers  %v\n", strings.Join(level.Tickers, " "))
+	return nil
+}
+
+// printEnv prints a shell variable assignment, quoting the value.
+func printEnv(out io.Writer, name, value string) {
+	fmt.Fprintf(out, "export %v='%v'\n", name, strings.ReplaceAll(value, "'", `'\''`))
+}
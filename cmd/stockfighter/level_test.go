@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func TestLevel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true, "instanceId": 1234, "account": "EXB123456", "venues": ["TESTEX"], "tickers": ["FOOBAR", "BAZ"],
+			"id": 1234, "state": "open", "details": {"tradingDay": 3, "endOfTheWorldDay": 100}}`))
+	}))
+	t.Cleanup(server.Close)
+	client := stockfighter.NewClient("KEY", stockfighter.WithGMBaseURL(server.URL))
+
+	var out bytes.Buffer
+	assert.Nil(t, runLevel(client, []string{"start", "-env", "first_steps"}, &out))
+	assert.Equal(t, "export INSTANCE_ID='1234'\nexport ACCOUNT='EXB123456'\nexport VENUES='TESTEX'\nexport TICKERS='FOOBAR BAZ'\n"+
+		"export VENUE='TESTEX'\nexport STOCK='FOOBAR'\n", out.String())
+
+	out.Reset()
+	assert.Nil(t, runLevel(client, []string{"resume", "1234"}, &out))
+	assert.Equal(t, "instance 1234\naccount  EXB123456\nvenues   TESTEX\ntickers  FOOBAR BAZ\n", out.String())
+
+	out.Reset()
+	assert.Nil(t, runLevel(client, []string{"status", "1234"}, &out))
+	assert.Equal(t, "instance 1234: open, day 3/100\n", out.String())
+
+	out.Reset()
+	assert.Nil(t, runLevel(client, []string{"stop", "1234"}, &out))
+	assert.Equal(t, "instance 1234 stopped\n", out.String())
+
+	assert.EqualError(t, runLevel(client, []string{"restart", "x"}, &out), "invalid instance ID: x")
+	assert.NotNil(t, runLevel(client, []string{"pause", "1234"}, &out))
+}
+
+func TestPrintEnv(t *testing.T) {
+	var out bytes.Buffer
+	printEnv(&out, "ACCOUNT", "it's")
+	assert.Equal(t, `export ACCOUNT='it'\''s'`+"\n", out.String())
+}
@@ -0,0 +1,227 @@
+// Command stockfighter is a command line client for the Stockfighter API.
+//
+//     stockfighter [-profile NAME] [-base-url URL] [-gm-url URL] [-dry-run] COMMAND [ARGS...]
+//
+// Commands:
+//
+//     quote [-json] [VENUE] STOCK    print the quote of a stock
+//     book [-json] [VENUE] STOCK     print the orderbook of a stock
+//
+//     order buy|sell [-json] -account ACCOUNT -price CENTS -qty N [-type TYPE] [VENUE] STOCK
+//         place an order (a limit order by default)
+//     order status [-json] [VENUE] STOCK ID
+//         print the status of an order
+//     order cancel [-json] [VENUE] STOCK ID
+//         cancel an order
+//
+//     watch book [-interval DURATION] [-depth N] [VENUE] STOCK
+//         continuously display the price ladder of a stock
+//     watch dashboard [-interval DURATION] [-fills N] -account ACCOUNT [VENUE] STOCK
+//         continuously display the quote, position, open orders, and fills of
+//         an account for a stock
+//
+//     record [-format json|csv] [-o FILE|-out DIR] [-interval DURATION] [-duration DURATION] [VENUE [STOCK]]
+//         record the quotes of a stock, or of all stocks of a venue, until
+//         interrupted or for DURATION
+//     replay [-json] [-speed X] FILE
+//         replay a JSON recording at X times the recorded pace
+//     books [-diff FILE] JOURNAL
+//         print the book states mirrored from the tickertape frames of a
+//         stream journal (see stockfighter.StreamJournal), or with -diff,
+//         those differing from the states printed by another build to FILE
+//
+//     level start [-json|-env] LEVEL
+//         start a level instance, e.g. first_steps
+//     level restart|resume [-json|-env] ID
+//         restart or resume a level instance
+//     level stop ID
+//         stop a level instance
+//     level status [-json|-env] ID
+//         print the status of a level instance
+//
+// The -env flag of level commands prints shell variable assignments (e.g.
+// ACCOUNT, VENUE, and STOCK) to be evaluated by shell scripts.
+//
+// With -dry-run, orders are not placed nor canceled but simulated against the
+// live orderbook (see stockfighter.WithDryRun), e.g. to try out scripts
+// safely.
+//
+// The API key and settings are read from the profile named by -profile, or
+// else $STOCKFIGHTER_PROFILE or "default", of the config file at
+// ~/.stockfighter/config (see stockfighter.Config), with the API key from
+// $STOCKFIGHTER_API_KEY if set. The account and venue of the profile are the
+// defaults of -account and VENUE, which may then be omitted.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gpk.io/stockfighter"
+)
+
+// A command is a stockfighter subcommand.
+type command struct {
+	name  string
+	usage string
+	run   func(client *stockfighter.Client, args []string, out io.Writer) error
+}
+
+var commands = []command{
+	{"quote", "quote [-json] [VENUE] STOCK", runQuote},
+	{"book", "book [-json] [VENUE] STOCK", runBook},
+	{"order", "order buy|sell [-json] -account ACCOUNT -price CENTS -qty N [-type TYPE] [VENUE] STOCK\n" +
+		"  order status|cancel [-json] [VENUE] STOCK ID", runOrder},
+	{"watch", "watch book [-interval DURATION] [-depth N] [VENUE] STOCK\n" +
+		"  watch dashboard [-interval DURATION] [-fills N] -account ACCOUNT [VENUE] STOCK", runWatch},
+	{"record", "record [-format json|csv] [-o FILE|-out DIR] [-interval DURATION] [-duration DURATION] [VENUE [STOCK]]", runRecord},
+	{"replay", "replay [-json] [-speed X] FILE", runReplay},
+	{"books", "books [-diff FILE] JOURNAL", runBooks},
+	{"level", "level start [-json|-env] LEVEL\n" +
+		"  level restart|resume|status [-json|-env] ID\n" +
+		"  level stop ID", runLevel},
+}
+
+// defaults are the default account and venue of commands, from the profile.
+var defaults struct {
+	account string
+	venue   string
+}
+
+// newClient creates the client with the settings of a profile of the config
+// file, overridden by options, and sets the defaults of commands from the
+// profile.
+func newClient(name string, options []stockfighter.ClientOption) (*stockfighter.Client, error) {
+	if name == "" {
+		name = os.Getenv("STOCKFIGHTER_PROFILE")
+	}
+	if name == "" {
+		name = stockfighter.DefaultProfile
+	}
+
+	config := &stockfighter.Config{}
+	if path := stockfighter.DefaultConfigPath(); path != "" {
+		var err error
+		if config, err = stockfighter.LoadConfig(path); err != nil {
+			return nil, err
+		}
+	}
+
+	profile, err := config.Profile(name)
+	if err != nil {
+		return nil, err
+	}
+	profileOptions, err := profile.Options()
+	if err != nil {
+		return nil, fmt.Errorf("profile %v: %v", name, err)
+	}
+
+	defaults.account, defaults.venue = profile.Account, profile.Venue
+	return stockfighter.NewClient(profile.APIKey, append(profileOptions, options...)...), nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: stockfighter [-profile NAME] [-base-url URL] [-gm-url URL] [-dry-run] COMMAND [ARGS...]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintln(os.Stderr, "  "+cmd.usage)
+	}
+	os.Exit(2)
+}
+
+func main() {
+	profile := flag.String("profile", "", "profile of ~/.stockfighter/config to use (default $STOCKFIGHTER_PROFILE or default)")
+	baseURL := flag.String("base-url", "", "API base URL (default "+stockfighter.DefaultBaseURL+")")
+	gmBaseURL := flag.String("gm-url", "", "GM API base URL (default "+stockfighter.DefaultGMBaseURL+")")
+	dryRun := flag.Bool("dry-run", false, "do not place nor cancel orders, only simulate them")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+	}
+
+	name, args := flag.Arg(0), flag.Args()[1:]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		var options []stockfighter.ClientOption
+		if *baseURL != "" {
+			options = append(options, stockfighter.WithBaseURL(*baseURL))
+		}
+		if *gmBaseURL != "" {
+			options = append(options, stockfighter.WithGMBaseURL(*gmBaseURL))
+		}
+		if *dryRun {
+			options = append(options, stockfighter.WithDryRun())
+		}
+
+		client, err := newClient(*profile, options)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "stockfighter:", err)
+			os.Exit(1)
+		}
+
+		if err := cmd.run(client, args, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "stockfighter %v: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "stockfighter: unknown command %q\n", name)
+	usage()
+}
+
+// parseArgs parses the flags of a command, and checks it got exactly n
+// positional arguments.
+func parseArgs(flags *flag.FlagSet, args []string, n int) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() != n {
+		return nil, fmt.Errorf("expected %d arguments, got %d", n, flags.NArg())
+	}
+	return flags.Args(), nil
+}
+
+// parseVenueArgs is parseArgs for commands whose first argument is VENUE,
+// which may be omitted if the profile has a default venue.
+func parseVenueArgs(flags *flag.FlagSet, args []string, n int) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	args = flags.Args()
+	if len(args) == n-1 && defaults.venue != "" {
+		args = append([]string{defaults.venue}, args...)
+	}
+	if len(args) != n {
+		return nil, fmt.Errorf("expected %d arguments, got %d", n, flags.NArg())
+	}
+	return args, nil
+}
+
+// printJSON writes v as indented JSON.
+func printJSON(out io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// quoteSide formats the best price and size of a side of a quote.
+func quoteSide(quoted bool, cents, size uint64) string {
+	if !quoted {
+		return "none"
+	}
+	return fmt.Sprintf("%v x %v", price(cents), size)
+}
+
+// price formats a price in cents as dollars.
+func price(cents uint64) string {
+	return fmt.Sprintf("$%.2f", float64(cents)/100.0)
+}
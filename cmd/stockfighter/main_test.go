@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "0123456789abcdef", r.Header.Get("X-Starfighter-Authorization"))
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"ok": true, "id": 42, "direction": "buy", "originalQty": 10, "orderType": "limit", "price": 5000, "open": true}`))
+	}))
+	defer server.Close()
+
+	home := t.TempDir()
+	assert.Nil(t, os.Mkdir(filepath.Join(home, ".stockfighter"), 0700))
+	assert.Nil(t, os.WriteFile(filepath.Join(home, ".stockfighter", "config"), []byte(`
+api_key = "0123456789abcdef"
+
+[testex]
+base_url = "`+server.URL+`"
+account = "EXB123456"
+venue = "TESTEX"
+`), 0600))
+	t.Setenv("HOME", home)
+	t.Setenv("STOCKFIGHTER_API_KEY", "")
+	t.Setenv("STOCKFIGHTER_PROFILE", "testex")
+	defer func() { defaults.account, defaults.venue = "", "" }()
+
+	client, err := newClient("", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "EXB123456", defaults.account)
+	assert.Equal(t, "TESTEX", defaults.venue)
+
+	// the account and venue of the profile are the defaults of commands
+	var out bytes.Buffer
+	assert.Nil(t, runOrder(client, []string{"buy", "-price", "5000", "-qty", "10", "FOOBAR"}, &out))
+	assert.Nil(t, runOrder(client, []string{"status", "FOOBAR", "42"}, &out))
+	assert.Nil(t, runOrder(client, []string{"status", "OTHEREX", "FOOBAR", "42"}, &out))
+	assert.Equal(t, []string{"/venues/TESTEX/stocks/FOOBAR/orders", "/venues/TESTEX/stocks/FOOBAR/orders/42",
+		"/venues/OTHEREX/stocks/FOOBAR/orders/42"}, paths)
+
+	_, err = newClient("unknown", nil)
+	assert.EqualError(t, err, "Unknown profile: unknown")
+}
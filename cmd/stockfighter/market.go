@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+func runQuote(client *stockfighter.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("quote", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print JSON")
+	args, err := parseVenueArgs(flags, args, 2)
+	if err != nil {
+		return err
+	}
+
+	venue, stock := args[0], args[1]
+	quote, err := client.GetQuote(venue, stock)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(out, quote)
+	}
+
+	fmt.Fprintf(out, "%v on %v at %v\n", stock, venue, quote.QuoteTime.Format(time.RFC3339))
+	fmt.Fprintf(out, "bid   %v (depth %v)\n", quoteSide(quote.HasBid, quote.BidPrice, quote.BidSize), quote.BidDepth)
+	fmt.Fprintf(out, "ask   %v (depth %v)\n", quoteSide(quote.HasAsk, quote.AskPrice, quote.AskSize), quote.AskDepth)
+	fmt.Fprintf(out, "last  %v x %v at %v\n", price(quote.LastPrice), quote.LastSize, quote.LastTradeTime.Format(time.RFC3339))
+	return nil
+}
+
+func runBook(client *stockfighter.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("book", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print JSON")
+	args, err := parseVenueArgs(flags, args, 2)
+	if err != nil {
+		return err
+	}
+
+	venue, stock := args[0], args[1]
+	orderbook, err := client.GetOrderbook(venue, stock)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(out, orderbook)
+	}
+
+	// asks are printed best price last, right above the bids
+	fmt.Fprintf(out, "%v on %v at %v\n", stock, venue, orderbook.Timestamp.Format(time.RFC3339))
+	for i := len(orderbook.Asks) - 1; i >= 0; i-- {
+		fmt.Fprintln(out, orderbook.Asks[i])
+	}
+	fmt.Fprintln(out, "----")
+	for _, entry := range orderbook.Bids {
+		fmt.Fprintln(out, entry)
+	}
+	return nil
+}
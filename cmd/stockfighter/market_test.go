@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func newTestClient(t *testing.T, body string) *stockfighter.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return stockfighter.NewClient("KEY", stockfighter.WithBaseURL(server.URL))
+}
+
+func TestQuote(t *testing.T) {
+	client := newTestClient(t, `{"ok": true, "bid": 5000, "bidSize": 10, "ask": 5100, "last": 5050, "quoteTime": "2015-12-04T09:02:16Z"}`)
+
+	var out bytes.Buffer
+	assert.Nil(t, runQuote(client, []string{"TESTEX", "FOOBAR"}, &out))
+	assert.Contains(t, out.String(), "FOOBAR on TESTEX at 2015-12-04T09:02:16Z\n")
+	assert.Contains(t, out.String(), "bid   $50.00 x 10 (depth 0)\n")
+
+	out.Reset()
+	assert.Nil(t, runQuote(client, []string{"-json", "TESTEX", "FOOBAR"}, &out))
+	assert.Contains(t, out.String(), `"bid": 5000,`)
+
+	assert.NotNil(t, runQuote(client, []string{"TESTEX"}, &out))
+}
+
+func TestBook(t *testing.T) {
+	client := newTestClient(t, `{"ok": true,
+		"bids": [{"price": 5000, "qty": 10, "isBuy": true}],
+		"asks": [{"price": 5100, "qty": 5}, {"price": 5200, "qty": 7}]}`)
+
+	var out bytes.Buffer
+	assert.Nil(t, runBook(client, []string{"TESTEX", "FOOBAR"}, &out))
+	assert.Contains(t, out.String(), "SELL $52.00 x 7\nSELL $51.00 x 5\n----\nBUY  $50.00 x 10\n")
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"gpk.io/stockfighter"
+)
+
+func runOrder(client *stockfighter.Client, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("expected buy, sell, status, or cancel")
+	}
+
+	switch args[0] {
+	case "buy":
+		return runPlaceOrder(client, stockfighter.OrderDirectionBuy, args[1:], out)
+	case "sell":
+		return runPlaceOrder(client, stockfighter.OrderDirectionSell, args[1:], out)
+	case "status":
+		return runOrderStatus(client, client.GetOrder, args[1:], out)
+	case "cancel":
+		return runOrderStatus(client, client.CancelOrder, args[1:], out)
+	}
+
+	return fmt.Errorf("unknown order command %q", args[0])
+}
+
+func runPlaceOrder(client *stockfighter.Client, direction string, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("order "+direction, flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print JSON")
+	account := flags.String("account", defaults.account, "trading account")
+	price := flags.Uint64("price", 0, "limit price, in cents")
+	quantity := flags.Uint64("qty", 0, "quantity")
+	orderType := flags.String("type", stockfighter.OrderTypeLimit, "order type")
+	args, err := parseVenueArgs(flags, args, 2)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *account == "":
+		return errors.New("-account is required")
+	case *quantity == 0:
+		return errors.New("-qty is required")
+	case *price == 0 && *orderType != stockfighter.OrderTypeMarket:
+		return errors.New("-price is required")
+	}
+
+	order, err := client.PlaceOrder(args[0], args[1], *account, *price, *quantity, direction, *orderType)
+	if err != nil {
+		return err
+	}
+
+	return printOrder(out, args[1], order, *asJSON)
+}
+
+func runOrderStatus(client *stockfighter.Client, fn func(venue, stock string, orderID int64) (*stockfighter.Order, error), args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("order", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print JSON")
+	args, err := parseVenueArgs(flags, args, 3)
+	if err != nil {
+		return err
+	}
+
+	orderID, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID: %v", args[2])
+	}
+
+	order, err := fn(args[0], args[1], orderID)
+	if err != nil {
+		return err
+	}
+
+	return printOrder(out, args[1], order, *asJSON)
+}
+
+func printOrder(out io.Writer, stock string, order *stockfighter.Order, asJSON bool) error {
+	if asJSON {
+		return printJSON(out, order)
+	}
+
+	fmt.Fprintf(out, "#%v %v %v %v %v @ %v: filled %v/%v, %v\n", order.OrderID, order.Direction, order.OriginalQuantity,
+		stock, order.OrderType, price(order.Price), order.TotalFilled, order.OriginalQuantity, order.State())
+	for _, fill := range order.Fills {
+		fmt.Fprintf(out, "  fill %v @ %v at %v\n", fill.Quantity, price(fill.Price), fill.Timestamp.Format("15:04:05.000"))
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrder(t *testing.T) {
+	client := newTestClient(t, `{"ok": true, "id": 42, "direction": "buy", "originalQty": 10, "orderType": "limit", "price": 5000,
+		"totalFilled": 4, "open": true, "fills": [{"price": 4990, "qty": 4, "ts": "2015-12-04T09:02:16.5Z"}]}`)
+
+	var out bytes.Buffer
+	assert.Nil(t, runOrder(client, []string{"buy", "-account", "EXB123456", "-price", "5000", "-qty", "10", "TESTEX", "FOOBAR"}, &out))
+	assert.Equal(t, "#42 buy 10 FOOBAR limit @ $50.00: filled 4/10, partially-filled\n  fill 4 @ $49.90 at 09:02:16.500\n", out.String())
+
+	out.Reset()
+	assert.Nil(t, runOrder(client, []string{"cancel", "-json", "TESTEX", "FOOBAR", "42"}, &out))
+	assert.Contains(t, out.String(), `"id": 42,`)
+
+	assert.EqualError(t, runOrder(client, []string{"sell", "-price", "5000", "-qty", "10", "TESTEX", "FOOBAR"}, &out), "-account is required")
+	assert.EqualError(t, runOrder(client, []string{"status", "TESTEX", "FOOBAR", "x"}, &out), "invalid order ID: x")
+	assert.NotNil(t, runOrder(client, []string{"amend"}, &out))
+}
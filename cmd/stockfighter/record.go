@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// Recording formats.
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+)
+
+// csvHeader is the header line of CSV recordings.
+var csvHeader = []string{"venue", "symbol", "quoteTime", "bid", "bidSize", "bidDepth", "ask", "askSize", "askDepth", "last", "lastSize", "lastTrade"}
+
+func runRecord(client *stockfighter.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("record", flag.ContinueOnError)
+	format := flags.String("format", formatJSON, "output format (json or csv)")
+	output := flags.String("o", "", "output file (default stdout)")
+	dir := flags.String("out", "", "output directory, in which a file named after the venue, stock, and start time is created")
+	interval := flags.Duration("interval", stockfighter.DefaultPollInterval, "polling interval")
+	duration := flags.Duration("duration", 0, "recording duration (default until interrupted)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+	if len(args) == 0 && defaults.venue != "" {
+		args = []string{defaults.venue}
+	}
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("expected 1 or 2 arguments, got %d", flags.NArg())
+	}
+	if *format != formatJSON && *format != formatCSV {
+		return fmt.Errorf("invalid format %q", *format)
+	}
+	if *output != "" && *dir != "" {
+		return errors.New("-o and -out are mutually exclusive")
+	}
+	if *duration < 0 {
+		return errors.New("-duration must not be negative")
+	}
+
+	venue, stock := args[0], ""
+	if len(args) == 2 {
+		stock = args[1]
+	}
+	path := *output
+	if *dir != "" {
+		if err := os.MkdirAll(*dir, 0755); err != nil {
+			return err
+		}
+		path = filepath.Join(*dir, recordingName(venue, stock, *format, time.Now()))
+	}
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	return record(ctx, client, venue, stock, *format, *interval, out)
+}
+
+// recordingName returns the name of the file of a recording started at
+// start, e.g. TESTEX-FOOBAR-20151204T090216Z.json.
+func recordingName(venue, stock, format string, start time.Time) string {
+	name := venue
+	if stock != "" {
+		name += "-" + stock
+	}
+	return name + "-" + start.UTC().Format("20060102T150405Z") + "." + format
+}
+
+// record records the quotes of a stock, or of all stocks of the venue if
+// stock is empty, until ctx is done.
+func record(ctx context.Context, client *stockfighter.Client, venue, stock, format string, interval time.Duration, out io.Writer) error {
+	stocks := []string{stock}
+	if stock == "" {
+		infos, err := client.ListStocks(venue)
+		if err != nil {
+			return err
+		}
+		stocks = stocks[:0]
+		for _, info := range infos {
+			stocks = append(stocks, info.Symbol)
+		}
+	}
+
+	write := stockfighter.NewQuoteRecorder(out).Record
+	if format == formatCSV {
+		w := csv.NewWriter(out)
+		if err := w.Write(csvHeader); err != nil {
+			return err
+		}
+		write = func(venue, stock string, quote *stockfighter.Quote) error {
+			w.Write(csvRecord(venue, stock, quote))
+			w.Flush()
+			return w.Error()
+		}
+	}
+
+	for update := range stockfighter.NewQuotePoller(client, venue, stocks, interval).Start(ctx) {
+		if update.Err != nil {
+			return update.Err
+		}
+		if err := write(venue, update.Stock, update.Quote); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func csvRecord(venue, stock string, quote *stockfighter.Quote) []string {
+	u := func(v uint64) string { return strconv.FormatUint(v, 10) }
+	// the best price of an empty side is left empty
+	bid, ask := "", ""
+	if quote.HasBid {
+		bid = u(quote.BidPrice)
+	}
+	if quote.HasAsk {
+		ask = u(quote.AskPrice)
+	}
+	return []string{
+		venue, stock, quote.QuoteTime.Format(time.RFC3339Nano),
+		bid, u(quote.BidSize), u(quote.BidDepth),
+		ask, u(quote.AskSize), u(quote.AskDepth),
+		u(quote.LastPrice), u(quote.LastSize), quote.LastTradeTime.Format(time.RFC3339Nano),
+	}
+}
+
+func runReplay(client *stockfighter.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print JSON")
+	speed := flags.Float64("speed", 1, "playback speed (0 to replay without delays)")
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	if *speed < 0 {
+		return errors.New("-speed must not be negative")
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return replay(ctx, f, *speed, *asJSON, out)
+}
+
+// replay prints the quotes of a JSON recording, waiting between quotes for
+// the time elapsed between them when recorded, divided by speed.
+func replay(ctx context.Context, in io.Reader, speed float64, asJSON bool, out io.Writer) error {
+	replayer := stockfighter.NewQuoteReplayer(in)
+	recorder := stockfighter.NewQuoteRecorder(out)
+
+	var last time.Time
+	for {
+		quote, err := replayer.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if speed > 0 && !last.IsZero() && quote.QuoteTime.After(last) {
+			timer := time.NewTimer(time.Duration(float64(quote.QuoteTime.Sub(last)) / speed))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			}
+		}
+		last = quote.QuoteTime
+
+		if asJSON {
+			if err := recorder.Record(quote.Venue, quote.Stock, &quote.Quote); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Fprintf(out, "%v %v %v: bid %v, ask %v, last %v\n", quote.QuoteTime.Format("15:04:05.000"), quote.Venue, quote.Stock,
+			quoteSide(quote.HasBid, quote.BidPrice, quote.BidSize), quoteSide(quote.HasAsk, quote.AskPrice, quote.AskSize), price(quote.LastPrice))
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord(t *testing.T) {
+	client := newTestClient(t, `{"ok": true, "symbols": [{"symbol": "FOOBAR"}], "bid": 5000, "bidSize": 10, "quoteTime": "2015-12-04T09:02:16Z"}`)
+
+	var out bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Nil(t, record(ctx, client, "TESTEX", "", formatJSON, time.Hour, &out))
+	assert.Equal(t, 1, strings.Count(out.String(), "\n"))
+	assert.Contains(t, out.String(), `{"venue":"TESTEX","symbol":"FOOBAR","bid":5000,`)
+
+	out.Reset()
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Nil(t, record(ctx, client, "TESTEX", "FOOBAR", formatCSV, time.Hour, &out))
+	assert.Equal(t, "venue,symbol,quoteTime,bid,bidSize,bidDepth,ask,askSize,askDepth,last,lastSize,lastTrade\n"+
+		"TESTEX,FOOBAR,2015-12-04T09:02:16Z,5000,10,0,,0,0,0,0,0001-01-01T00:00:00Z\n", out.String())
+
+	assert.EqualError(t, runRecord(client, []string{"-format", "xml", "TESTEX"}, &out), `invalid format "xml"`)
+}
+
+func TestRunRecordDuration(t *testing.T) {
+	client := newTestClient(t, `{"ok": true, "bid": 5000, "bidSize": 10, "quoteTime": "2015-12-04T09:02:16Z"}`)
+	dir := filepath.Join(t.TempDir(), "data")
+
+	start := time.Now()
+	assert.Nil(t, runRecord(client, []string{"-out", dir, "-duration", "50ms", "-interval", "1h", "TESTEX", "FOOBAR"}, nil))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	files, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(files))
+	assert.True(t, strings.HasPrefix(files[0].Name(), "TESTEX-FOOBAR-"))
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `{"venue":"TESTEX","symbol":"FOOBAR","bid":5000,`)
+
+	assert.EqualError(t, runRecord(client, []string{"-o", "x", "-out", dir, "TESTEX"}, nil), "-o and -out are mutually exclusive")
+}
+
+func TestRecordingName(t *testing.T) {
+	start := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	assert.Equal(t, "TESTEX-FOOBAR-20151204T090216Z.json", recordingName("TESTEX", "FOOBAR", formatJSON, start))
+	assert.Equal(t, "TESTEX-20151204T090216Z.csv", recordingName("TESTEX", "", formatCSV, start))
+}
+
+func TestReplay(t *testing.T) {
+	recording := `{"venue":"TESTEX","symbol":"FOOBAR","bid":5000,"bidSize":10,"quoteTime":"2015-12-04T09:02:16Z"}
+{"venue":"TESTEX","symbol":"FOOBAR","ask":5100,"askSize":5,"quoteTime":"2015-12-04T09:02:16.05Z"}
+`
+
+	var out bytes.Buffer
+	start := time.Now()
+	assert.Nil(t, replay(context.Background(), strings.NewReader(recording), 1, false, &out))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	assert.Equal(t, "09:02:16.000 TESTEX FOOBAR: bid $50.00 x 10, ask none, last $0.00\n"+
+		"09:02:16.050 TESTEX FOOBAR: bid none, ask $51.00 x 5, last $0.00\n", out.String())
+
+	out.Reset()
+	assert.Nil(t, replay(context.Background(), strings.NewReader(recording), 0, true, &out))
+	assert.Contains(t, out.String(), `"ask":5100,"askSize":5,`)
+
+	assert.NotNil(t, replay(context.Background(), strings.NewReader("{"), 0, false, &out))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\x1b[H\x1b[2J"
+
+func runWatch(client *stockfighter.Client, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("expected book or dashboard")
+	}
+
+	switch args[0] {
+	case "book":
+		return runWatchBook(client, args[1:], out)
+	case "dashboard":
+		return runDashboard(client, args[1:], out)
+	}
+
+	return fmt.Errorf("unknown watch command %q", args[0])
+}
+
+func runWatchBook(client *stockfighter.Client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("watch book", flag.ContinueOnError)
+	interval := flags.Duration("interval", stockfighter.DefaultPollInterval, "polling interval")
+	depth := flags.Int("depth", 10, "price levels shown on each side")
+	args, err := parseVenueArgs(flags, args, 2)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return watchBook(ctx, client, args[0], args[1], *interval, *depth, out)
+}
+
+// watchBook redraws the price ladder of a stock every time its orderbook or
+// quote changes, until ctx is done.
+func watchBook(ctx context.Context, client *stockfighter.Client, venue, stock string, interval time.Duration, depth int, out io.Writer) error {
+	deltas := stockfighter.NewOrderbookWatcher(client, venue, stock, interval).Start(ctx)
+	quotes := stockfighter.NewQuotePoller(client, venue, []string{stock}, interval).Start(ctx)
+
+	var orderbook *stockfighter.Orderbook
+	var quote *stockfighter.Quote
+	for deltas != nil || quotes != nil {
+		select {
+		case delta, ok := <-deltas:
+			if !ok {
+				deltas = nil
+				continue
+			}
+			if delta.Err != nil {
+				return delta.Err
+			}
+			orderbook = delta.Orderbook
+		case update, ok := <-quotes:
+			if !ok {
+				quotes = nil
+				continue
+			}
+			if update.Err != nil {
+				return update.Err
+			}
+			quote = update.Quote
+		}
+
+		fmt.Fprint(out, clearScreen)
+		printLadder(out, venue, stock, orderbook, quote, depth)
+	}
+
+	return nil
+}
+
+// printLadder prints a price ladder: asks above bids, best prices in the
+// middle, with the spread and last trade. Either orderbook or quote may be
+// nil if not received yet.
+func printLadder(out io.Writer, venue, stock string, orderbook *stockfighter.Orderbook, quote *stockfighter.Quote, depth int) {
+	var bids, asks []ladderLevel
+	if orderbook != nil {
+		fmt.Fprintf(out, "%v on %v at %v\n\n", stock, venue, orderbook.Timestamp.Format("15:04:05.000"))
+		bids, asks = ladderLevels(orderbook.Bids, depth), ladderLevels(orderbook.Asks, depth)
+	} else {
+		fmt.Fprintf(out, "%v on %v\n\n", stock, venue)
+	}
+
+	fmt.Fprintf(out, "%10v %10v %-10v\n", "BID", "PRICE", "ASK")
+	for i := len(asks) - 1; i >= 0; i-- {
+		fmt.Fprintf(out, "%10v %10v %-10v\n", "", price(asks[i].price), asks[i].qty)
+	}
+	if len(bids) > 0 && len(asks) > 0 && asks[0].price > bids[0].price {
+		fmt.Fprintf(out, "%10v %10v\n", "spread", price(asks[0].price-bids[0].price))
+	} else {
+		fmt.Fprintf(out, "%10v %10v\n", "spread", "-")
+	}
+	for _, level := range bids {
+		fmt.Fprintf(out, "%10v %10v\n", level.qty, price(level.price))
+	}
+
+	if quote != nil && !quote.LastTradeTime.IsZero() {
+		fmt.Fprintf(out, "\nlast %v x %v at %v\n", price(quote.LastPrice), quote.LastSize, quote.LastTradeTime.Format("15:04:05.000"))
+	}
+}
+
+// A ladderLevel is the total quantity at a price.
+type ladderLevel struct {
+	price uint64
+	qty   uint64
+}
+
+// ladderLevels aggregates orderbook entries by price, keeping the first depth
+// levels. Entries are expected best price first, as returned by the API.
+func ladderLevels(entries []stockfighter.OrderbookEntry, depth int) []ladderLevel {
+	var levels []ladderLevel
+	for _, entry := range entries {
+		if n := len(levels); n > 0 && levels[n-1].price == entry.Price {
+			levels[n-1].qty += entry.Quantity
+			continue
+		}
+		levels = append(levels, ladderLevel{price: entry.Price, qty: entry.Quantity})
+	}
+
+	if len(levels) > depth {
+		levels = levels[:depth]
+	}
+	return levels
+}
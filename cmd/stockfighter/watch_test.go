@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func TestPrintLadder(t *testing.T) {
+	orderbook := &stockfighter.Orderbook{
+		Bids: []stockfighter.OrderbookEntry{{Price: 5000, Quantity: 10, IsBuy: true}, {Price: 5000, Quantity: 5, IsBuy: true}, {Price: 4900, Quantity: 1, IsBuy: true}},
+		Asks: []stockfighter.OrderbookEntry{{Price: 5100, Quantity: 5}, {Price: 5200, Quantity: 7}, {Price: 5300, Quantity: 9}},
+	}
+	quote := &stockfighter.Quote{LastPrice: 5050, LastSize: 3, LastTradeTime: time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)}
+
+	var out bytes.Buffer
+	printLadder(&out, "TESTEX", "FOOBAR", orderbook, quote, 2)
+	assert.Equal(t, "FOOBAR on TESTEX at 00:00:00.000\n\n"+
+		"       BID      PRICE ASK       \n"+
+		"               $52.00 7         \n"+
+		"               $51.00 5         \n"+
+		"    spread      $1.00\n"+
+		"        15     $50.00\n"+
+		"         1     $49.00\n"+
+		"\nlast $50.50 x 3 at 09:02:16.000\n", out.String())
+
+	out.Reset()
+	printLadder(&out, "TESTEX", "FOOBAR", nil, nil, 2)
+	assert.Contains(t, out.String(), "    spread          -\n")
+}
+
+func TestWatchBook(t *testing.T) {
+	client := newTestClient(t, `{"ok": true, "bids": [{"price": 5000, "qty": 10, "isBuy": true}], "bid": 5000, "quoteTime": "2015-12-04T09:02:16Z"}`)
+
+	var out bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Nil(t, watchBook(ctx, client, "TESTEX", "FOOBAR", time.Hour, 10, &out))
+	assert.Contains(t, out.String(), clearScreen+"FOOBAR on TESTEX")
+	assert.Contains(t, out.String(), "        10     $50.00\n")
+
+	assert.NotNil(t, runWatch(client, []string{"quote"}, &out))
+}
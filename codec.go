@@ -0,0 +1,46 @@
+package stockfighter
+
+import "encoding/json"
+
+// A Codec encodes API request bodies and decodes API responses and stream
+// frames, so that encoding/json can be swapped for a faster implementation,
+// e.g. jsoniter or sonic, when decoding is the bottleneck:
+//
+//    type jsoniterCodec struct{}
+//
+//    func (jsoniterCodec) Marshal(v interface{}) ([]byte, error) {
+//        return jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(v)
+//    }
+//
+//    func (jsoniterCodec) Unmarshal(data []byte, v interface{}) error {
+//        return jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(data, v)
+//    }
+//
+// Codecs must honor json struct tags and the json.Marshaler and
+// json.Unmarshaler implementations of the package types.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec is the Codec of package encoding/json, used by default.
+type JSONCodec struct{}
+
+// Marshal implements Codec.
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements Codec.
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// WithCodec sets the codec API requests and responses, and stream frames
+// decoded with Client.DecodeTickertapeFrame and Client.DecodeExecutionFrame,
+// are encoded and decoded with (JSONCodec by default).
+func WithCodec(codec Codec) ClientOption {
+	return func(client *Client) {
+		client.transport.codec = codec
+	}
+}
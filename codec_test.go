@@ -0,0 +1,50 @@
+package stockfighter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingCodec is a JSONCodec counting its calls.
+type countingCodec struct {
+	JSONCodec
+	marshals, unmarshals int
+}
+
+func (codec *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	codec.marshals++
+	return codec.JSONCodec.Marshal(v)
+}
+
+func (codec *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	codec.unmarshals++
+	return codec.JSONCodec.Unmarshal(data, v)
+}
+
+func TestWithCodec(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true, "id": 1}`))
+	})
+	codec := &countingCodec{}
+	client := NewClient(testApiKey, WithBaseURL(server.transport.baseURL), WithCodec(codec))
+
+	order, err := client.PlaceOrder(testVenue, testStock, testAccount, 100, 10, OrderDirectionBuy, OrderTypeLimit)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), order.OrderID)
+	assert.Equal(t, 1, codec.marshals)
+	// the envelope, then the body
+	assert.Equal(t, 2, codec.unmarshals)
+
+	quote, err := client.DecodeTickertapeFrame([]byte(`{"ok": true, "quote": {"symbol": "FOOBAR", "bid": 100}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), quote.BidPrice)
+	execution, err := client.DecodeExecutionFrame([]byte(`{"ok": true, "account": "EXB123456", "filled": 10}`))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), execution.Filled)
+	assert.Equal(t, 6, codec.unmarshals)
+
+	_, err = client.DecodeTickertapeFrame([]byte(`{}`))
+	assert.IsType(t, &ErrorDecode{}, err)
+}
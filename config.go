@@ -0,0 +1,553 @@
+package stockfighter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultProfile is the name of the profile used when none is selected.
+const DefaultProfile = "default"
+
+// KeyringService is the service name API keys are stored under in the OS
+// keyring.
+const KeyringService = "stockfighter"
+
+// A Profile represents the settings of a client in a config file.
+type Profile struct {
+	Name string
+
+	APIKey    string
+	BaseURL   string
+	GMBaseURL string
+
+	// Default account and venue, for bots to use
+	Account string
+	Venue   string
+
+	// Whether the API key is read from the OS keyring, under KeyringService
+	// and the name of the profile, when it is not set
+	Keyring bool
+
+	// Maximum number of API requests per second (0 for no limit)
+	RateLimit int
+
+	// Level API requests are logged at to stderr (see WithLogger), e.g.
+	// "debug" or "info", or empty to log nothing
+	LogLevel string
+
+	// Risk policy orders must satisfy (see RiskPolicy), or empty for none
+	RiskPolicy string
+}
+
+// Options returns the client options applying the settings of the profile:
+// base URLs, rate limit, logging, and risk policy, if set. It fails if the log
+// level or the risk policy is invalid.
+func (profile *Profile) Options() ([]ClientOption, error) {
+	var options []ClientOption
+	if profile.BaseURL != "" {
+		options = append(options, WithBaseURL(profile.BaseURL))
+	}
+	if profile.GMBaseURL != "" {
+		options = append(options, WithGMBaseURL(profile.GMBaseURL))
+	}
+	if profile.RateLimit > 0 {
+		options = append(options, WithRateLimit(profile.RateLimit, time.Second))
+	}
+	if profile.LogLevel != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(profile.LogLevel)); err != nil {
+			return nil, fmt.Errorf("invalid log level: %v", profile.LogLevel)
+		}
+		options = append(options, WithLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))))
+	}
+	if profile.RiskPolicy != "" {
+		policy, err := ParseRiskPolicy(profile.RiskPolicy)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, WithRiskPolicy(policy))
+	}
+	return options, nil
+}
+
+// A Config represents a config file: a set of profiles, keyed by name.
+//
+// Config files are written in TOML, with a table per profile and string keys
+// (api_key, base_url, gm_base_url, account, venue, log_level, and
+// risk_policy), an integer
+// key (rate_limit), and a boolean key (keyring). Keys before any table belong
+// to the default profile, and are inherited by the other profiles, except
+// api_key for the profiles setting keyring:
+//
+//    api_key = "0123456789abcdef"
+//
+//    [testex]
+//    base_url = "http://localhost:8080/ob/api"
+//    account = "EXB123456"
+//    venue = "TESTEX"
+//    rate_limit = 10
+//    log_level = "debug"
+//
+//    [live]
+//    keyring = true
+//    risk_policy = "abs(position) <= 1000 && open_orders <= 20"
+//
+// Config files can also be written in YAML, with a mapping per profile in
+// place of tables:
+//
+//    api_key: 0123456789abcdef
+//
+//    testex:
+//      base_url: http://localhost:8080/ob/api
+//      rate_limit: 10
+//
+// Only the subset of TOML and YAML needed for profiles is supported. The
+// format is told from the first key, by whether it is followed by "=" or ":".
+type Config struct {
+	Profiles map[string]*Profile
+}
+
+// DefaultConfigPath returns the path of the config file,
+// .stockfighter/config in the home directory of the user, or an empty string
+// if the home directory is unknown.
+func DefaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".stockfighter", "config")
+}
+
+// LoadConfig reads a config file. A missing file is an empty config.
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{Profiles: make(map[string]*Profile)}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	config, err := ParseConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	return config, nil
+}
+
+// ParseConfig parses the content of a config file.
+func ParseConfig(r io.Reader) (*Config, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	parse := parseTOML
+	if isYAML(lines) {
+		parse = parseYAML
+	}
+	names, entries, err := parse(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	// top-level entries are applied first to every profile, so that the
+	// profiles inherit them
+	var inherited, own []configEntry
+	for _, entry := range entries {
+		if entry.profile == "" {
+			inherited = append(inherited, entry)
+		}
+	}
+	config := &Config{Profiles: make(map[string]*Profile)}
+	for _, name := range append([]string{DefaultProfile}, names...) {
+		own = own[:0]
+		for _, entry := range entries {
+			if entry.profile == name {
+				own = append(own, entry)
+			}
+		}
+
+		profile := &Profile{Name: name}
+		for _, entry := range append(inherited, own...) {
+			if err := profile.set(entry.key, entry.value); err != nil {
+				return nil, fmt.Errorf("line %v: %v", entry.line, err)
+			}
+		}
+		if profile.Keyring && name != DefaultProfile && !hasKey(own, "api_key") {
+			profile.APIKey = ""
+		}
+		config.Profiles[name] = profile
+	}
+
+	if p := config.Profiles[DefaultProfile]; *p == (Profile{Name: DefaultProfile}) {
+		delete(config.Profiles, DefaultProfile)
+	}
+	return config, nil
+}
+
+// A configEntry is a key set in a config file, in a profile or, if profile
+// is empty, at the top level.
+type configEntry struct {
+	profile string
+	key     string
+	value   string // as accepted by Profile.set
+	line    int
+}
+
+func hasKey(entries []configEntry, key string) bool {
+	for _, entry := range entries {
+		if entry.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// profileKeys tells whether the values of the keys of a profile are strings.
+var profileKeys = map[string]bool{
+	"api_key":     true,
+	"base_url":    true,
+	"gm_base_url": true,
+	"account":     true,
+	"venue":       true,
+	"log_level":   true,
+	"risk_policy": true,
+	"keyring":     false,
+	"rate_limit":  false,
+}
+
+// isYAML reports whether config file lines are YAML rather than TOML, from
+// their first key.
+func isYAML(lines []string) bool {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || line[0] == '#':
+			continue
+		case line == "---":
+			return true
+		case line[0] == '[':
+			return false
+		}
+		colon, equal := strings.IndexByte(line, ':'), strings.IndexByte(line, '=')
+		return colon >= 0 && (equal < 0 || colon < equal)
+	}
+	return false
+}
+
+// parseTOML parses the lines of a TOML config file into the names of its
+// profiles and its entries.
+func parseTOML(lines []string) (names []string, entries []configEntry, err error) {
+	profile := ""
+	for i, line := range lines {
+		n := i + 1
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			name := strings.TrimSpace(strings.TrimSuffix(stripComment(line[1:]), "]"))
+			if !strings.HasSuffix(stripComment(line), "]") || name == "" {
+				return nil, nil, fmt.Errorf("line %v: invalid table: %v", n, line)
+			}
+			profile = name
+			names = append(names, name)
+			continue
+		}
+
+		i := strings.IndexByte(line, '=')
+		if i < 0 {
+			return nil, nil, fmt.Errorf("line %v: expected key = value: %v", n, line)
+		}
+		key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		if profileKeys[key] {
+			s, err := parseTOMLString(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %v: invalid string for %v: %v", n, key, value)
+			}
+			value = s
+		} else {
+			value = stripComment(value)
+		}
+		entries = append(entries, configEntry{profile: profile, key: key, value: value, line: n})
+	}
+	return names, entries, nil
+}
+
+// parseYAML parses the lines of a YAML config file into the names of its
+// profiles and its entries: top-level keys with a scalar value, and mappings
+// of keys with scalar values.
+func parseYAML(lines []string) (names []string, entries []configEntry, err error) {
+	profile, indent := "", 0
+	for i, line := range lines {
+		n := i + 1
+		content := strings.TrimLeft(line, " ")
+		if content == "" || content[0] == '#' || (line == "---" && len(entries) == 0 && len(names) == 0) {
+			continue
+		}
+		if content[0] == '\t' {
+			return nil, nil, fmt.Errorf("line %v: tabs are not allowed for indentation", n)
+		}
+
+		depth := len(line) - len(content)
+		switch {
+		case depth == 0:
+			profile = ""
+		case profile == "" || (indent != 0 && depth != indent):
+			return nil, nil, fmt.Errorf("line %v: unexpected indentation: %v", n, line)
+		default:
+			indent = depth
+		}
+
+		i := strings.Index(content, ": ")
+		if strings.HasSuffix(content, ":") {
+			i = len(content) - 1
+		} else if j := strings.Index(content, ":\t"); i < 0 || (j >= 0 && j < i) {
+			i = j
+		}
+		if i <= 0 {
+			return nil, nil, fmt.Errorf("line %v: expected key: value: %v", n, line)
+		}
+		key, value := content[:i], strings.TrimSpace(content[i+1:])
+
+		if value == "" || value[0] == '#' {
+			if _, ok := profileKeys[key]; ok || depth > 0 {
+				return nil, nil, fmt.Errorf("line %v: missing value for %v", n, key)
+			}
+			profile, indent = key, 0
+			names = append(names, key)
+			continue
+		}
+
+		s, quoted, err := parseYAMLScalar(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %v: invalid value for %v: %v", n, key, value)
+		}
+		if quoted && !profileKeys[key] {
+			// a quoted number or boolean is a string, which set rejects
+			s = value
+		}
+		entries = append(entries, configEntry{profile: profile, key: key, value: s, line: n})
+	}
+	return names, entries, nil
+}
+
+// parseYAMLScalar parses a plain, single-quoted, or double-quoted YAML
+// scalar, followed by an optional comment. Flow collections are not
+// supported.
+func parseYAMLScalar(value string) (s string, quoted bool, err error) {
+	switch value[0] {
+	case '\'':
+		for i := 1; i < len(value); i++ {
+			if value[i] != '\'' {
+				continue
+			}
+			if i+1 < len(value) && value[i+1] == '\'' {
+				i++
+				continue
+			}
+			if rest := value[i+1:]; rest != "" && !strings.HasPrefix(strings.TrimSpace(rest), "#") {
+				break
+			}
+			return strings.ReplaceAll(value[1:i], "''", "'"), true, nil
+		}
+		return "", false, errors.New("invalid single-quoted scalar")
+
+	case '"':
+		prefix, err := strconv.QuotedPrefix(value)
+		if err != nil || !strings.HasPrefix(strings.TrimSpace(value[len(prefix):])+"#", "#") {
+			return "", false, errors.New("invalid double-quoted scalar")
+		}
+		s, err := strconv.Unquote(prefix)
+		return s, true, err
+
+	case '[', '{', '&', '*', '!', '|', '>', '@', '`':
+		return "", false, errors.New("unsupported scalar")
+	}
+
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value), false, nil
+}
+
+// set sets a key of the profile to a value, already unquoted for string keys.
+func (profile *Profile) set(key, value string) error {
+	switch key {
+	case "keyring":
+		switch value {
+		case "true":
+			profile.Keyring = true
+		case "false":
+			profile.Keyring = false
+		default:
+			return fmt.Errorf("invalid boolean for %v: %v", key, value)
+		}
+		return nil
+
+	case "rate_limit":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid rate limit: %v", value)
+		}
+		profile.RateLimit = n
+		return nil
+	}
+
+	var field *string
+	switch key {
+	case "api_key":
+		field = &profile.APIKey
+	case "base_url":
+		field = &profile.BaseURL
+	case "gm_base_url":
+		field = &profile.GMBaseURL
+	case "account":
+		field = &profile.Account
+	case "venue":
+		field = &profile.Venue
+	case "log_level":
+		field = &profile.LogLevel
+	case "risk_policy":
+		field = &profile.RiskPolicy
+	default:
+		return fmt.Errorf("unknown key: %v", key)
+	}
+
+	if key == "log_level" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid log level: %v", value)
+		}
+	}
+	if key == "risk_policy" {
+		if _, err := ParseRiskPolicy(value); err != nil {
+			return err
+		}
+	}
+	*field = value
+	return nil
+}
+
+// parseTOMLString parses a basic ("...") or literal ('...') TOML string,
+// followed by an optional comment.
+func parseTOMLString(value string) (string, error) {
+	if strings.HasPrefix(value, "'") {
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 || stripComment(value[end+2:]) != "" {
+			return "", errors.New("invalid literal string")
+		}
+		return value[1 : end+1], nil
+	}
+
+	prefix, err := strconv.QuotedPrefix(value)
+	if err != nil || !strings.HasPrefix(prefix, `"`) || stripComment(value[len(prefix):]) != "" {
+		return "", errors.New("invalid string")
+	}
+	return strconv.Unquote(prefix)
+}
+
+// stripComment removes a trailing comment and surrounding whitespace.
+func stripComment(s string) string {
+	if i := strings.IndexByte(s, '#'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+// Profile returns a profile of the config, with the API key from
+// $STOCKFIGHTER_API_KEY if set, or else from the OS keyring if the profile
+// uses it and has no API key. An unknown profile is empty, unless it is not
+// the default profile.
+func (config *Config) Profile(name string) (*Profile, error) {
+	profile, ok := config.Profiles[name]
+	if !ok && name != DefaultProfile {
+		return nil, fmt.Errorf("Unknown profile: %v", name)
+	}
+
+	p := Profile{Name: name}
+	if ok {
+		p = *profile
+	}
+	if apiKey := strings.TrimSpace(os.Getenv("STOCKFIGHTER_API_KEY")); apiKey != "" {
+		p.APIKey = apiKey
+	} else if p.APIKey == "" && p.Keyring {
+		apiKey, err := keyringLookup(KeyringService, name)
+		if err != nil {
+			return nil, fmt.Errorf("keyring: %v", err)
+		}
+		p.APIKey = apiKey
+	}
+
+	if p.APIKey == "" {
+		return nil, &ErrorAPIKeyMissing{Profile: name}
+	}
+	return &p, nil
+}
+
+// keyringLookup reads a secret from the OS keyring, with the security tool
+// on macOS and secret-tool (libsecret) elsewhere.
+var keyringLookup = func(service, user string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", user, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "user", user)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// NewClientFromEnv creates a new Client with the settings of the profile
+// named by $STOCKFIGHTER_PROFILE, or DefaultProfile. See NewClientFromProfile.
+func NewClientFromEnv(options ...ClientOption) (*Client, error) {
+	name := os.Getenv("STOCKFIGHTER_PROFILE")
+	if name == "" {
+		name = DefaultProfile
+	}
+	return NewClientFromProfile(name, options...)
+}
+
+// NewClientFromProfile creates a new Client with the settings of a profile of
+// the config file at DefaultConfigPath. The API key is taken from
+// $STOCKFIGHTER_API_KEY if set (see Config.Profile). Options are applied after
+// those of the profile.
+func NewClientFromProfile(name string, options ...ClientOption) (*Client, error) {
+	config := &Config{Profiles: make(map[string]*Profile)}
+	if path := DefaultConfigPath(); path != "" {
+		var err error
+		if config, err = LoadConfig(path); err != nil {
+			return nil, err
+		}
+	}
+
+	profile, err := config.Profile(name)
+	if err != nil {
+		return nil, err
+	}
+	profileOptions, err := profile.Options()
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(profile.APIKey, append(profileOptions, options...)...), nil
+}
@@ -0,0 +1,236 @@
+package stockfighter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfig = `
+# default profile
+api_key = "0123456789abcdef"
+
+[testex]
+api_key = 'fedcba9876543210' # literal string
+base_url = "http://localhost:8080/ob/api"
+account = "EXB123456"
+venue = "TESTEX"
+rate_limit = 10
+log_level = "debug"
+
+[live]
+keyring = true
+risk_policy = "abs(position) <= 1000 && open_orders <= 20"
+`
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig(strings.NewReader(testConfig))
+	assert.Nil(t, err)
+	assert.Len(t, config.Profiles, 3)
+	assert.Equal(t, &Profile{Name: DefaultProfile, APIKey: "0123456789abcdef"}, config.Profiles[DefaultProfile])
+	assert.Equal(t, &Profile{
+		Name:      "testex",
+		APIKey:    "fedcba9876543210",
+		BaseURL:   "http://localhost:8080/ob/api",
+		Account:   "EXB123456",
+		Venue:     "TESTEX",
+		RateLimit: 10,
+		LogLevel:  "debug",
+	}, config.Profiles["testex"])
+	// profiles inherit top-level keys, but api_key when using the keyring
+	assert.Equal(t, &Profile{Name: "live", Keyring: true, RiskPolicy: "abs(position) <= 1000 && open_orders <= 20"},
+		config.Profiles["live"])
+
+	options, err := config.Profiles["testex"].Options()
+	assert.Nil(t, err)
+	assert.Len(t, options, 3)
+	options, err = config.Profiles["live"].Options()
+	assert.Nil(t, err)
+	assert.Len(t, options, 1)
+	options, err = (&Profile{Name: "other"}).Options()
+	assert.Nil(t, err)
+	assert.Len(t, options, 0)
+	_, err = (&Profile{LogLevel: "verbose"}).Options()
+	assert.EqualError(t, err, "invalid log level: verbose")
+	_, err = (&Profile{RiskPolicy: "position"}).Options()
+	assert.EqualError(t, err, `invalid risk policy "position": not a boolean expression`)
+
+	// no default profile without default settings
+	config, err = ParseConfig(strings.NewReader("[live]\nkeyring = true\n"))
+	assert.Nil(t, err)
+	assert.Len(t, config.Profiles, 1)
+
+	config, err = ParseConfig(strings.NewReader(`
+api_key = "0123456789abcdef"
+rate_limit = 5
+
+[testex]
+base_url = "http://localhost:8080/ob/api"
+rate_limit = 10
+
+[other]
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, &Profile{Name: "testex", APIKey: "0123456789abcdef", BaseURL: "http://localhost:8080/ob/api", RateLimit: 10},
+		config.Profiles["testex"])
+	assert.Equal(t, &Profile{Name: "other", APIKey: "0123456789abcdef", RateLimit: 5}, config.Profiles["other"])
+
+	for _, s := range []string{
+		"api_key",
+		"api_key = 0123",
+		`api_key = "0123" trailing`,
+		"api_key = '0123",
+		"keyring = yes",
+		"rate_limit = -1",
+		`rate_limit = "10"`,
+		`log_level = "verbose"`,
+		`risk_policy = "abs(position) <="`,
+		`secret = "0123"`,
+		"[testex",
+		"[]",
+	} {
+		_, err := ParseConfig(strings.NewReader(s))
+		assert.NotNil(t, err, s)
+	}
+	_, err = ParseConfig(strings.NewReader("\n\nkeyring = 1"))
+	assert.EqualError(t, err, "line 3: invalid boolean for keyring: 1")
+}
+
+func TestParseConfigYAML(t *testing.T) {
+	config, err := ParseConfig(strings.NewReader(`---
+# default profile
+api_key: 0123456789abcdef
+
+testex:
+  api_key: 'fedcba9876543210' # single-quoted
+  base_url: "http://localhost:8080/ob/api"
+  account: EXB123456
+  venue: TESTEX
+  rate_limit: 10
+  log_level: debug
+
+live:
+  keyring: true
+
+other:
+  venue: OTHEREX
+`))
+	assert.Nil(t, err)
+	assert.Len(t, config.Profiles, 4)
+	assert.Equal(t, &Profile{Name: DefaultProfile, APIKey: "0123456789abcdef"}, config.Profiles[DefaultProfile])
+	assert.Equal(t, &Profile{
+		Name:      "testex",
+		APIKey:    "fedcba9876543210",
+		BaseURL:   "http://localhost:8080/ob/api",
+		Account:   "EXB123456",
+		Venue:     "TESTEX",
+		RateLimit: 10,
+		LogLevel:  "debug",
+	}, config.Profiles["testex"])
+	assert.Equal(t, &Profile{Name: "live", Keyring: true}, config.Profiles["live"])
+	assert.Equal(t, &Profile{Name: "other", APIKey: "0123456789abcdef", Venue: "OTHEREX"}, config.Profiles["other"])
+
+	for _, s := range []string{
+		"api_key:",
+		"api_key: '0123",
+		`api_key: "0123" trailing`,
+		"api_key: [0123]",
+		"keyring: yes",
+		`keyring: "true"`,
+		"rate_limit: -1",
+		"secret: 0123",
+		"testex:\n  venue: TESTEX\n    account: EXB123456",
+		"testex:\n\tvenue: TESTEX",
+		"  venue: TESTEX",
+		"testex:\n  venue:",
+	} {
+		_, err := ParseConfig(strings.NewReader(s))
+		assert.NotNil(t, err, s)
+	}
+	_, err = ParseConfig(strings.NewReader("testex:\n  rate_limit: fast"))
+	assert.EqualError(t, err, "line 2: invalid rate limit: fast")
+}
+
+func TestConfigProfile(t *testing.T) {
+	t.Setenv("STOCKFIGHTER_API_KEY", "")
+	lookup := keyringLookup
+	defer func() { keyringLookup = lookup }()
+	keyringLookup = func(service, user string) (string, error) {
+		if user == "live" {
+			return "keyring-" + service, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	config, err := ParseConfig(strings.NewReader(testConfig + "\n[missing]\nkeyring = true\n[nokey]\n"))
+	assert.Nil(t, err)
+
+	profile, err := config.Profile("live")
+	assert.Nil(t, err)
+	assert.Equal(t, "keyring-stockfighter", profile.APIKey)
+	assert.Equal(t, "", config.Profiles["live"].APIKey)
+
+	_, err = config.Profile("missing")
+	assert.EqualError(t, err, "keyring: not found")
+	profile, err = config.Profile("nokey")
+	assert.Nil(t, err)
+	assert.Equal(t, "0123456789abcdef", profile.APIKey)
+	nokey, err := ParseConfig(strings.NewReader("[nokey]\n"))
+	assert.Nil(t, err)
+	_, err = nokey.Profile("nokey")
+	assert.IsType(t, &ErrorAPIKeyMissing{}, err)
+	_, err = config.Profile("unknown")
+	assert.EqualError(t, err, "Unknown profile: unknown")
+
+	t.Setenv("STOCKFIGHTER_API_KEY", "fromenv")
+	profile, err = config.Profile("testex")
+	assert.Nil(t, err)
+	assert.Equal(t, "fromenv", profile.APIKey)
+	assert.Equal(t, "TESTEX", profile.Venue)
+
+	// the default profile need not be in the config file
+	profile, err = (&Config{}).Profile(DefaultProfile)
+	assert.Nil(t, err)
+	assert.Equal(t, "fromenv", profile.APIKey)
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	home := t.TempDir()
+	path := filepath.Join(home, ".stockfighter", "config")
+	assert.Nil(t, os.Mkdir(filepath.Dir(path), 0700))
+	assert.Nil(t, os.WriteFile(path, []byte(testConfig), 0600))
+	t.Setenv("HOME", home)
+	t.Setenv("STOCKFIGHTER_API_KEY", "")
+	t.Setenv("STOCKFIGHTER_PROFILE", "testex")
+	assert.Equal(t, path, DefaultConfigPath())
+
+	client, err := NewClientFromEnv()
+	assert.Nil(t, err)
+	assert.Equal(t, "fedcba9876543210", client.transport.apiKey)
+	assert.Equal(t, "http://localhost:8080/ob/api", client.transport.baseURL)
+
+	// options override the profile
+	client, err = NewClientFromEnv(WithBaseURL("http://localhost:9090"))
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost:9090", client.transport.baseURL)
+
+	lookup := keyringLookup
+	defer func() { keyringLookup = lookup }()
+	keyringLookup = func(service, user string) (string, error) {
+		return "", errors.New("not found")
+	}
+	client, err = NewClientFromProfile("live")
+	assert.Nil(t, client)
+	assert.EqualError(t, err, "keyring: not found")
+	_, err = NewClientFromProfile("unknown")
+	assert.EqualError(t, err, "Unknown profile: unknown")
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("STOCKFIGHTER_PROFILE", "")
+	_, err = NewClientFromEnv()
+	assert.EqualError(t, err, "API key missing for profile default: set $STOCKFIGHTER_API_KEY or api_key in the config file")
+}
@@ -0,0 +1,77 @@
+package stockfighter
+
+import (
+	"context"
+	"sync"
+)
+
+// A ConflatedQuotes keeps only the latest quote of each stock of a stream of
+// quote updates, for consumers that only ever need the latest quote: bursts
+// of updates replace each other instead of queueing up.
+//
+//    quotes := ConflateQuotes(ctx, poller.Start(ctx))
+//    for range quotes.Notify {
+//        quote := quotes.Latest(stock)
+//        // ...
+//    }
+//
+// Update errors are skipped.
+type ConflatedQuotes struct {
+	// Channel receiving a value when quotes were updated since the last
+	// value was received, closed when the source is closed or when the
+	// context of the stream is done
+	Notify <-chan struct{}
+
+	mu     sync.Mutex
+	quotes map[string]*Quote
+}
+
+// ConflateQuotes starts conflating a stream of quote updates, e.g. of a
+// QuotePoller, in a new goroutine.
+func ConflateQuotes(ctx context.Context, updates <-chan QuoteUpdate) *ConflatedQuotes {
+	notify := make(chan struct{}, 1)
+	quotes := &ConflatedQuotes{
+		Notify: notify,
+		quotes: make(map[string]*Quote),
+	}
+
+	go func() {
+		defer close(notify)
+
+		for {
+			select {
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+				if update.Err != nil || update.Quote == nil {
+					continue
+				}
+
+				quotes.mu.Lock()
+				quotes.quotes[update.Stock] = update.Quote
+				quotes.mu.Unlock()
+
+				select {
+				case notify <- struct{}{}:
+				default:
+					// a notification is pending already
+				}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return quotes
+}
+
+// Latest returns the latest quote of a stock, or nil if no quote of the stock
+// was received yet.
+func (quotes *ConflatedQuotes) Latest(stock string) *Quote {
+	quotes.mu.Lock()
+	defer quotes.mu.Unlock()
+
+	return quotes.quotes[stock]
+}
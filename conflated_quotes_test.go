@@ -0,0 +1,42 @@
+package stockfighter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConflateQuotes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := make(chan QuoteUpdate)
+	quotes := ConflateQuotes(ctx, updates)
+	assert.Nil(t, quotes.Latest(testStock))
+
+	// a burst of updates is notified once, with the latest quote
+	for bid := uint64(100); bid <= 110; bid++ {
+		updates <- QuoteUpdate{Venue: testVenue, Stock: testStock, Quote: &Quote{BidPrice: bid}}
+	}
+	updates <- QuoteUpdate{Venue: testVenue, Stock: "BARBAZ", Quote: &Quote{BidPrice: 50}}
+	updates <- QuoteUpdate{Venue: testVenue, Stock: testStock, Err: errors.New("poll failed")}
+
+	<-quotes.Notify
+	assert.Equal(t, uint64(110), quotes.Latest(testStock).BidPrice)
+	assert.Equal(t, uint64(50), quotes.Latest("BARBAZ").BidPrice)
+	select {
+	case <-quotes.Notify:
+		t.Error("Notify received a value without update")
+	default:
+	}
+
+	updates <- QuoteUpdate{Venue: testVenue, Stock: testStock, Quote: &Quote{BidPrice: 111}}
+	<-quotes.Notify
+	assert.Equal(t, uint64(111), quotes.Latest(testStock).BidPrice)
+
+	close(updates)
+	_, ok := <-quotes.Notify
+	assert.False(t, ok)
+}
@@ -0,0 +1,257 @@
+/*
+Package conformance checks that a Stockfighter API implementation (the
+official API, a clone, or a local mock) behaves the way package stockfighter
+expects: status codes, response fields, and error formats of every endpoint.
+
+    report := conformance.Run(conformance.Config{
+        BaseURL: stockfighter.DefaultBaseURL,
+        APIKey:  apiKey,
+        Venue:   "TESTEX",
+        Stock:   "FOOBAR",
+        Account: "EXB123456",
+    })
+    fmt.Print(report)
+    if !report.Passed() {
+        os.Exit(1)
+    }
+
+Run places (and cancels) a small limit order on the given account.
+*/
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gpk.io/stockfighter"
+)
+
+// Config describes the API implementation to check.
+type Config struct {
+	// API base URL (e.g. stockfighter.DefaultBaseURL)
+	BaseURL string
+
+	// Valid API key
+	APIKey string
+
+	// Existing venue, stock, and trading account
+	Venue   string
+	Stock   string
+	Account string
+
+	// HTTP client to use (http.DefaultClient if nil)
+	HTTPClient *http.Client
+}
+
+// A Result represents the outcome of a single check.
+type Result struct {
+	// Check name
+	Name string
+
+	// Request the check made
+	Method string
+	Path   string
+
+	// Mismatches found; the check passed if there are none
+	Problems []string
+}
+
+// Passed returns true if the check found no mismatch.
+func (r Result) Passed() bool {
+	return len(r.Problems) == 0
+}
+
+func (r Result) String() string {
+	if r.Passed() {
+		return fmt.Sprintf("PASS %v (%v %v)", r.Name, r.Method, r.Path)
+	}
+
+	return fmt.Sprintf("FAIL %v (%v %v)\n    %v", r.Name, r.Method, r.Path, strings.Join(r.Problems, "\n    "))
+}
+
+// A Report represents the results of all checks.
+type Report struct {
+	Results []Result
+}
+
+// Passed returns true if every check passed.
+func (r *Report) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Report) String() string {
+	var buf bytes.Buffer
+	passed := 0
+	for _, result := range r.Results {
+		fmt.Fprintln(&buf, result)
+		if result.Passed() {
+			passed++
+		}
+	}
+	fmt.Fprintf(&buf, "%v/%v checks passed\n", passed, len(r.Results))
+	return buf.String()
+}
+
+// Nonexistent symbols and key used by the error checks.
+const (
+	missingVenue  = "NOEXIST"
+	missingStock  = "NOEXIST"
+	invalidAPIKey = "INVALID_API_KEY"
+)
+
+type runner struct {
+	config Config
+	report *Report
+}
+
+// Run runs every check against the API described by config and returns the
+// report. This never returns nil.
+func Run(config Config) *Report {
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+
+	r := &runner{config: config, report: &Report{}}
+	venue, stock, account := config.Venue, config.Stock, config.Account
+
+	r.check("API heartbeat", config.APIKey, "GET", "/heartbeat", nil, 200, true)
+	r.check("venue heartbeat", config.APIKey, "GET", "/venues/"+venue+"/heartbeat", nil, 200, true, "venue")
+	r.check("venue heartbeat, unknown venue", config.APIKey, "GET", "/venues/"+missingVenue+"/heartbeat", nil, 404, false)
+
+	stocks := r.check("stocks", config.APIKey, "GET", "/venues/"+venue+"/stocks", nil, 200, true, "symbols")
+	r.checkStockListed(stocks)
+	r.check("stocks, unknown venue", config.APIKey, "GET", "/venues/"+missingVenue+"/stocks", nil, 404, false)
+
+	r.check("orderbook", config.APIKey, "GET", "/venues/"+venue+"/stocks/"+stock, nil, 200, true, "venue", "symbol", "bids", "asks", "ts")
+	r.check("orderbook, unknown venue", config.APIKey, "GET", "/venues/"+missingVenue+"/stocks/"+stock, nil, 404, false)
+
+	r.check("quote", config.APIKey, "GET", "/venues/"+venue+"/stocks/"+stock+"/quote", nil, 200, true, "venue", "symbol", "quoteTime")
+	r.check("quote, unknown stock", config.APIKey, "GET", "/venues/"+venue+"/stocks/"+missingStock+"/quote", nil, 404, false)
+
+	orderFields := []string{"venue", "symbol", "direction", "originalQty", "qty", "price", "orderType", "id", "account", "ts", "fills", "totalFilled", "open"}
+	order := map[string]interface{}{
+		"account":   account,
+		"venue":     venue,
+		"stock":     stock,
+		"price":     1,
+		"qty":       1,
+		"direction": stockfighter.OrderDirectionBuy,
+		"orderType": stockfighter.OrderTypeLimit,
+	}
+	placed := r.check("place order", config.APIKey, "POST", "/venues/"+venue+"/stocks/"+stock+"/orders", order, 200, true, orderFields...)
+	r.check("place order, invalid API key", invalidAPIKey, "POST", "/venues/"+venue+"/stocks/"+stock+"/orders", order, 401, false)
+
+	if id, ok := placed["id"].(float64); ok {
+		orderPath := "/venues/" + venue + "/stocks/" + stock + "/orders/" + strconv.FormatInt(int64(id), 10)
+		r.check("order status", config.APIKey, "GET", orderPath, nil, 200, true, orderFields...)
+		canceled := r.check("cancel order", config.APIKey, "DELETE", orderPath, nil, 200, true, orderFields...)
+		if open, ok := canceled["open"].(bool); ok && open {
+			r.fail("cancel order", "DELETE", orderPath, "canceled order is still open")
+		}
+	}
+
+	r.check("account orders", config.APIKey, "GET", "/venues/"+venue+"/accounts/"+account+"/orders", nil, 200, true, "venue", "orders")
+	r.check("account orders, invalid API key", invalidAPIKey, "GET", "/venues/"+venue+"/accounts/"+account+"/orders", nil, 401, false)
+	r.check("account stock orders", config.APIKey, "GET", "/venues/"+venue+"/accounts/"+account+"/stocks/"+stock+"/orders", nil, 200, true, "venue", "orders")
+
+	return r.report
+}
+
+// check makes a request and compares the response with the expected status,
+// "ok" value, and fields. Responses with "ok": false must carry an "error"
+// string. It returns the decoded response body (nil if it could not be
+// decoded).
+func (r *runner) check(name, apiKey, method, path string, body interface{}, status int, ok bool, fields ...string) map[string]interface{} {
+	result := Result{Name: name, Method: method, Path: path}
+	defer func() {
+		r.report.Results = append(r.report.Results, result)
+	}()
+
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			panic(err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, r.config.BaseURL+path, reqBody)
+	if err != nil {
+		result.Problems = append(result.Problems, err.Error())
+		return nil
+	}
+	req.Header.Set("X-Starfighter-Authorization", apiKey)
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.config.HTTPClient.Do(req)
+	if err != nil {
+		result.Problems = append(result.Problems, err.Error())
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != status {
+		result.Problems = append(result.Problems, fmt.Sprintf("status %v, expected %v", resp.StatusCode, status))
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		result.Problems = append(result.Problems, fmt.Sprintf("content type %q, expected application/json", ct))
+	}
+
+	var decoded map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		result.Problems = append(result.Problems, fmt.Sprintf("invalid JSON body: %v", err))
+		return nil
+	}
+
+	if got, isBool := decoded["ok"].(bool); !isBool {
+		result.Problems = append(result.Problems, `missing boolean field "ok"`)
+	} else if got != ok {
+		result.Problems = append(result.Problems, fmt.Sprintf(`"ok" is %v, expected %v`, got, ok))
+	}
+	if !ok {
+		if msg, isString := decoded["error"].(string); !isString || msg == "" {
+			result.Problems = append(result.Problems, `missing error message field "error"`)
+		}
+	}
+	for _, field := range fields {
+		if _, present := decoded[field]; !present {
+			result.Problems = append(result.Problems, fmt.Sprintf("missing field %q", field))
+		}
+	}
+
+	return decoded
+}
+
+func (r *runner) checkStockListed(stocks map[string]interface{}) {
+	symbols, _ := stocks["symbols"].([]interface{})
+	for _, s := range symbols {
+		if info, ok := s.(map[string]interface{}); ok && info["symbol"] == r.config.Stock {
+			if _, ok := info["name"].(string); !ok {
+				r.fail("stocks", "GET", "/venues/"+r.config.Venue+"/stocks", fmt.Sprintf("stock %v has no name", r.config.Stock))
+			}
+			return
+		}
+	}
+
+	r.fail("stocks", "GET", "/venues/"+r.config.Venue+"/stocks", fmt.Sprintf("stock %v not listed", r.config.Stock))
+}
+
+// fail records a problem found while checking the response of a previous
+// check.
+func (r *runner) fail(name, method, path, problem string) {
+	r.report.Results = append(r.report.Results, Result{Name: name, Method: method, Path: path, Problems: []string{problem}})
+}
@@ -0,0 +1,73 @@
+package conformance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const order = `{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR", "direction": "buy", "originalQty": 1, "qty": 1,
+	"price": 1, "orderType": "limit", "id": 1, "account": "EXB123456", "ts": "2015-12-04T09:02:16Z", "fills": [],
+	"totalFilled": 0, "open": %v}`
+
+func newMockAPI(cancelLeavesOpen bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("X-Starfighter-Authorization") != "KEY" {
+			w.WriteHeader(401)
+			w.Write([]byte(`{"ok": false, "error": "unauthorized"}`))
+			return
+		}
+		if strings.Contains(r.URL.Path, "NOEXIST") {
+			w.WriteHeader(404)
+			w.Write([]byte(`{"ok": false, "error": "not found"}`))
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/heartbeat":
+			w.Write([]byte(`{"ok": true, "error": ""}`))
+		case strings.HasSuffix(r.URL.Path, "/heartbeat"):
+			w.Write([]byte(`{"ok": true, "venue": "TESTEX"}`))
+		case strings.HasSuffix(r.URL.Path, "/stocks"):
+			w.Write([]byte(`{"ok": true, "symbols": [{"name": "Foreign Owned Occluded Bridge Architecture Resources", "symbol": "FOOBAR"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/quote"):
+			w.Write([]byte(`{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR", "quoteTime": "2015-12-04T09:02:16Z"}`))
+		case strings.HasSuffix(r.URL.Path, "/stocks/FOOBAR"):
+			w.Write([]byte(`{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR", "bids": [], "asks": [], "ts": "2015-12-04T09:02:16Z"}`))
+		case strings.Contains(r.URL.Path, "/accounts/"):
+			w.Write([]byte(`{"ok": true, "venue": "TESTEX", "orders": []}`))
+		case r.Method == "DELETE":
+			w.Write([]byte(strings.Replace(order, "%v", map[bool]string{true: "true", false: "false"}[cancelLeavesOpen], 1)))
+		default:
+			w.Write([]byte(strings.Replace(order, "%v", "true", 1)))
+		}
+	}))
+}
+
+func TestRun(t *testing.T) {
+	server := newMockAPI(false)
+	defer server.Close()
+
+	config := Config{BaseURL: server.URL, APIKey: "KEY", Venue: "TESTEX", Stock: "FOOBAR", Account: "EXB123456"}
+	report := Run(config)
+	assert.True(t, report.Passed(), report.String())
+	assert.Len(t, report.Results, 16)
+
+	config.Stock = "BARBAZ"
+	report = Run(config)
+	assert.False(t, report.Passed())
+	assert.Contains(t, report.String(), "stock BARBAZ not listed")
+}
+
+func TestRunOpenAfterCancel(t *testing.T) {
+	server := newMockAPI(true)
+	defer server.Close()
+
+	report := Run(Config{BaseURL: server.URL, APIKey: "KEY", Venue: "TESTEX", Stock: "FOOBAR", Account: "EXB123456"})
+	assert.False(t, report.Passed())
+	assert.Contains(t, report.String(), "canceled order is still open")
+}
@@ -0,0 +1,135 @@
+package stockfighter
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A ConsolidatedEntry represents an entry of a consolidated book, tagged with
+// the venue it comes from.
+type ConsolidatedEntry struct {
+	Venue string
+	OrderbookEntry
+}
+
+// A CrossedMarket represents a bid on a venue higher than an ask on another
+// venue: buying on the ask venue and selling on the bid venue makes a profit.
+type CrossedMarket struct {
+	Ticker string
+
+	BidVenue string
+	BidPrice uint64
+	AskVenue string
+	AskPrice uint64
+
+	// Shares that can be bought and sold at the top of both books
+	Quantity uint64
+}
+
+func (c CrossedMarket) String() string {
+	return fmt.Sprintf("%v crossed: bid $%.2f on %v over ask $%.2f on %v (%v shares)", c.Ticker,
+		float64(c.BidPrice)/100.0, c.BidVenue, float64(c.AskPrice)/100.0, c.AskVenue, c.Quantity)
+}
+
+// A ConsolidatedBook represents the orderbooks of a ticker on several venues
+// merged together.
+//
+// You can create a new ConsolidatedBook using ConsolidateBooks function, or
+// MultiVenue.Orderbook.
+type ConsolidatedBook struct {
+	Ticker string
+
+	// Bids, best price first, and asks, best price first. Ties are in the
+	// order of the books consolidated.
+	Bids []ConsolidatedEntry
+	Asks []ConsolidatedEntry
+
+	// Best bid and ask of each venue, keyed by venue symbol
+	venueBids map[string]OrderbookEntry
+	venueAsks map[string]OrderbookEntry
+	venues    []string
+}
+
+// ConsolidateBooks merges the orderbooks of a ticker on several venues.
+func ConsolidateBooks(books []*Orderbook) *ConsolidatedBook {
+	book := &ConsolidatedBook{venueBids: make(map[string]OrderbookEntry), venueAsks: make(map[string]OrderbookEntry)}
+	for _, b := range books {
+		book.Ticker = b.Symbol
+		book.venues = append(book.venues, b.Venue)
+		for i, entry := range b.Bids {
+			book.Bids = append(book.Bids, ConsolidatedEntry{Venue: b.Venue, OrderbookEntry: entry})
+			if i == 0 {
+				book.venueBids[b.Venue] = entry
+			}
+		}
+		for i, entry := range b.Asks {
+			book.Asks = append(book.Asks, ConsolidatedEntry{Venue: b.Venue, OrderbookEntry: entry})
+			if i == 0 {
+				book.venueAsks[b.Venue] = entry
+			}
+		}
+	}
+
+	sort.SliceStable(book.Bids, func(i, j int) bool { return book.Bids[i].Price > book.Bids[j].Price })
+	sort.SliceStable(book.Asks, func(i, j int) bool { return book.Asks[i].Price < book.Asks[j].Price })
+	return book
+}
+
+// BestBid returns the best bid across venues, if any.
+func (book *ConsolidatedBook) BestBid() (ConsolidatedEntry, bool) {
+	if len(book.Bids) == 0 {
+		return ConsolidatedEntry{}, false
+	}
+	return book.Bids[0], true
+}
+
+// BestAsk returns the best ask across venues, if any.
+func (book *ConsolidatedBook) BestAsk() (ConsolidatedEntry, bool) {
+	if len(book.Asks) == 0 {
+		return ConsolidatedEntry{}, false
+	}
+	return book.Asks[0], true
+}
+
+// Crossed returns the pairs of venues whose markets are crossed, i.e. the
+// best bid of a venue is higher than the best ask of another venue, most
+// profitable first.
+func (book *ConsolidatedBook) Crossed() []CrossedMarket {
+	var crossed []CrossedMarket
+	for _, bidVenue := range book.venues {
+		bid, ok := book.venueBids[bidVenue]
+		if !ok {
+			continue
+		}
+		for _, askVenue := range book.venues {
+			ask, ok := book.venueAsks[askVenue]
+			if !ok || askVenue == bidVenue || bid.Price <= ask.Price {
+				continue
+			}
+			qty := bid.Quantity
+			if ask.Quantity < qty {
+				qty = ask.Quantity
+			}
+			crossed = append(crossed, CrossedMarket{Ticker: book.Ticker, BidVenue: bidVenue, BidPrice: bid.Price,
+				AskVenue: askVenue, AskPrice: ask.Price, Quantity: qty})
+		}
+	}
+
+	sort.SliceStable(crossed, func(i, j int) bool {
+		return (crossed[i].BidPrice-crossed[i].AskPrice)*crossed[i].Quantity > (crossed[j].BidPrice-crossed[j].AskPrice)*crossed[j].Quantity
+	})
+	return crossed
+}
+
+// Orderbook returns the consolidated orderbook of a ticker across venues.
+func (mv *MultiVenue) Orderbook(ticker string) (*ConsolidatedBook, error) {
+	books := make([]*Orderbook, len(mv.venues))
+	for i, venue := range mv.venues {
+		book, err := mv.client.GetOrderbook(venue, ticker)
+		if err != nil {
+			return nil, err
+		}
+		books[i] = book
+	}
+	return ConsolidateBooks(books), nil
+}
@@ -0,0 +1,60 @@
+package stockfighter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsolidateBooks(t *testing.T) {
+	book := ConsolidateBooks([]*Orderbook{
+		{Venue: "AEX", Symbol: testStock,
+			Bids: []OrderbookEntry{{Price: 5000, Quantity: 10, IsBuy: true}, {Price: 4990, Quantity: 20, IsBuy: true}},
+			Asks: []OrderbookEntry{{Price: 5100, Quantity: 10}}},
+		{Venue: "BEX", Symbol: testStock,
+			Bids: []OrderbookEntry{{Price: 4990, Quantity: 5, IsBuy: true}},
+			Asks: []OrderbookEntry{{Price: 4980, Quantity: 30}, {Price: 5000, Quantity: 5}}},
+		{Venue: "CEX", Symbol: testStock,
+			Bids: []OrderbookEntry{{Price: 5200, Quantity: 1, IsBuy: true}}},
+	})
+
+	assert.Equal(t, testStock, book.Ticker)
+	bid, ok := book.BestBid()
+	assert.True(t, ok)
+	assert.Equal(t, ConsolidatedEntry{Venue: "CEX", OrderbookEntry: OrderbookEntry{Price: 5200, Quantity: 1, IsBuy: true}}, bid)
+	ask, _ := book.BestAsk()
+	assert.Equal(t, "BEX", ask.Venue)
+
+	var venues []string
+	for _, entry := range book.Bids {
+		venues = append(venues, entry.Venue)
+	}
+	assert.Equal(t, []string{"CEX", "AEX", "AEX", "BEX"}, venues)
+
+	assert.Equal(t, []CrossedMarket{
+		{Ticker: testStock, BidVenue: "CEX", BidPrice: 5200, AskVenue: "BEX", AskPrice: 4980, Quantity: 1},
+		{Ticker: testStock, BidVenue: "AEX", BidPrice: 5000, AskVenue: "BEX", AskPrice: 4980, Quantity: 10},
+		{Ticker: testStock, BidVenue: "CEX", BidPrice: 5200, AskVenue: "AEX", AskPrice: 5100, Quantity: 1},
+	}, book.Crossed())
+
+	_, ok = ConsolidateBooks(nil).BestBid()
+	assert.False(t, ok)
+}
+
+func TestMultiVenueOrderbook(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		venue := strings.Split(r.URL.Path, "/")[2]
+		fmt.Fprintf(w, `{"ok": true, "venue": "%s", "symbol": "FOOBAR", "bids": [{"price": %d, "qty": 10, "isBuy": true}], "asks": null}`,
+			venue, 5000+len(venue))
+	})
+	mv := NewMultiVenue(client, testAccount, []string{testVenue, "OTHEREX"}, []string{testStock})
+
+	book, err := mv.Orderbook(testStock)
+	assert.Nil(t, err)
+	assert.Len(t, book.Bids, 2)
+	assert.Equal(t, "OTHEREX", book.Bids[0].Venue)
+	assert.Empty(t, book.Crossed())
+}
@@ -0,0 +1,158 @@
+package stockfighter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WithDryRun enables dry-run mode, e.g. to check the order flow of a strategy
+// end-to-end before a scored attempt: PlaceOrder and CancelOrder log and
+// return synthetic orders instead of making API requests, while reads remain
+// live.
+//
+// Dry-run orders are matched against the live orderbook when placed: they
+// fill at the prices of the orders on the other side of the book they cross,
+// without consuming them, and the rest of a limit order stays open without
+// ever filling. They get negative IDs, and GetOrder returns them too.
+// GetAllOrders and GetStockOrders list them after the live orders, so that
+// CancelAllOrders cancels them (it leaves the live orders alone).
+//
+// Dry-run mode is shared by the clients derived with Subsystem.
+func WithDryRun() ClientOption {
+	return func(client *Client) {
+		client.dryRun = &dryRun{orders: make(map[int64]*Order)}
+	}
+}
+
+// dryRun keeps the orders placed in dry-run mode.
+type dryRun struct {
+	mu     sync.Mutex
+	lastID int64
+	orders map[int64]*Order
+}
+
+func (client *Client) placeDryRunOrder(req OrderRequest) (*Order, error) {
+	book, err := client.GetOrderbook(req.Venue, req.Stock)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	order := &Order{
+		Venue:            req.Venue,
+		Symbol:           req.Stock,
+		Direction:        req.Direction,
+		OriginalQuantity: req.Quantity,
+		Price:            req.Price,
+		OrderType:        req.OrderType,
+		Account:          req.Account,
+		Timestamp:        now,
+	}
+	fills := matchOrder(book, req)
+	if req.OrderType == OrderTypeFillOrKill && fillsQuantity(fills) < req.Quantity {
+		fills = nil
+	}
+	for i := range fills {
+		fills[i].Timestamp = now
+	}
+	order.Fills = fills
+	order.TotalFilled = fillsQuantity(fills)
+	if req.OrderType == OrderTypeLimit && order.TotalFilled < req.Quantity {
+		order.Open, order.Quantity = true, req.Quantity-order.TotalFilled
+	}
+
+	dr := client.dryRun
+	dr.mu.Lock()
+	dr.lastID--
+	order.OrderID = dr.lastID
+	dr.orders[order.OrderID] = order
+	result := *order
+	dr.mu.Unlock()
+
+	client.logInfo("stockfighter: dry-run order placed", "venue", req.Venue, "stock", req.Stock, "account", req.Account, "id", result.OrderID,
+		"direction", result.Direction, "orderType", result.OrderType, "price", result.Price, "qty", result.OriginalQuantity, "filled", result.TotalFilled)
+
+	return &result, nil
+}
+
+func (client *Client) cancelDryRunOrder(venue, stock string, orderID int64) (*Order, error) {
+	dr := client.dryRun
+	dr.mu.Lock()
+	order, ok := dr.orders[orderID]
+	if !ok || order.Venue != venue || order.Symbol != stock {
+		dr.mu.Unlock()
+		return nil, fmt.Errorf("Not a dry-run order: %v", orderID)
+	}
+	order.Open, order.Quantity = false, 0
+	result := *order
+	dr.mu.Unlock()
+
+	client.logInfo("stockfighter: dry-run order canceled", "venue", venue, "stock", stock, "id", orderID, "filled", result.TotalFilled)
+
+	return &result, nil
+}
+
+func (client *Client) getDryRunOrder(venue, stock string, orderID int64) (*Order, bool) {
+	dr := client.dryRun
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	order, ok := dr.orders[orderID]
+	if !ok || order.Venue != venue || order.Symbol != stock {
+		return nil, false
+	}
+	result := *order
+	return &result, true
+}
+
+// listDryRunOrders returns the dry-run orders of an account on a venue, for
+// one stock or for every stock if stock is "", in the order they were placed.
+func (client *Client) listDryRunOrders(venue, account, stock string) []Order {
+	dr := client.dryRun
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	var orders []Order
+	for id := int64(-1); id >= dr.lastID; id-- {
+		order := dr.orders[id]
+		if order.Venue == venue && order.Account == account && (stock == "" || order.Symbol == stock) {
+			orders = append(orders, *order)
+		}
+	}
+	return orders
+}
+
+// matchOrder returns the fills an order would get against the orders on the
+// other side of a book, best price first.
+func matchOrder(book *Orderbook, req OrderRequest) []OrderFillInfo {
+	entries := book.Asks
+	crosses := func(price uint64) bool { return price <= req.Price }
+	if req.Direction == OrderDirectionSell {
+		entries = book.Bids
+		crosses = func(price uint64) bool { return price >= req.Price }
+	}
+
+	var fills []OrderFillInfo
+	remaining := req.Quantity
+	for _, entry := range entries {
+		if remaining == 0 || (req.OrderType != OrderTypeMarket && !crosses(entry.Price)) {
+			break
+		}
+		qty := entry.Quantity
+		if qty > remaining {
+			qty = remaining
+		}
+		fills = append(fills, OrderFillInfo{Price: entry.Price, Quantity: qty})
+		remaining -= qty
+	}
+	return fills
+}
+
+func fillsQuantity(fills []OrderFillInfo) uint64 {
+	var qty uint64
+	for _, fill := range fills {
+		qty += fill.Quantity
+	}
+	return qty
+}
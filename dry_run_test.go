@@ -0,0 +1,98 @@
+package stockfighter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// only reads hit the API
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/venues/TESTEX/stocks/FOOBAR", r.URL.Path)
+		w.Write([]byte(`{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR",
+			"bids": [{"price": 4990, "qty": 100, "isBuy": true}, {"price": 4980, "qty": 100, "isBuy": true}],
+			"asks": [{"price": 5010, "qty": 50, "isBuy": false}, {"price": 5020, "qty": 100, "isBuy": false}]}`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(testApiKey, WithBaseURL(server.URL), WithDryRun())
+
+	order, err := client.PlaceOrder(testVenue, testStock, testAccount, 5015, 80, OrderDirectionBuy, OrderTypeLimit)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), order.OrderID)
+	assert.Equal(t, uint64(50), order.TotalFilled)
+	assert.Equal(t, uint64(30), order.Quantity)
+	assert.Equal(t, OrderStatePartiallyFilled, order.State())
+	if assert.Len(t, order.Fills, 1) {
+		assert.Equal(t, uint64(5010), order.Fills[0].Price)
+	}
+
+	order, err = client.CancelOrder(testVenue, testStock, -1)
+	assert.Nil(t, err)
+	assert.Equal(t, OrderStateCanceled, order.State())
+	order, err = client.GetOrder(testVenue, testStock, -1)
+	assert.Nil(t, err)
+	assert.False(t, order.Open)
+	_, err = client.CancelOrder(testVenue, testStock, 42)
+	assert.NotNil(t, err)
+
+	order, _ = client.PlaceOrder(testVenue, testStock, testAccount, 0, 150, OrderDirectionSell, OrderTypeMarket)
+	assert.Equal(t, int64(-2), order.OrderID)
+	assert.Equal(t, uint64(150), order.TotalFilled)
+	assert.Equal(t, []uint64{4990, 4980}, []uint64{order.Fills[0].Price, order.Fills[1].Price})
+
+	order, _ = client.PlaceOrder(testVenue, testStock, testAccount, 5020, 200, OrderDirectionBuy, OrderTypeFillOrKill)
+	assert.Equal(t, uint64(0), order.TotalFilled)
+	assert.Equal(t, OrderStateExpired, order.State())
+
+	order, _ = client.PlaceOrder(testVenue, testStock, testAccount, 5020, 100, OrderDirectionBuy, OrderTypeImmediateOrCancel)
+	assert.Equal(t, uint64(100), order.TotalFilled)
+	assert.Equal(t, OrderStateFilled, order.State())
+}
+
+func TestDryRunCancelAllOrders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// live orders are listed, but never canceled
+		assert.Equal(t, "GET", r.Method)
+		if strings.HasSuffix(r.URL.Path, "/orders") {
+			w.Write([]byte(`{"ok": true, "orders": [{"id": 7, "venue": "TESTEX", "symbol": "FOOBAR", "account": "EXB123456", "open": true}]}`))
+			return
+		}
+		w.Write([]byte(`{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR",
+			"asks": [{"price": 5010, "qty": 50, "isBuy": false}]}`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(testApiKey, WithBaseURL(server.URL), WithDryRun())
+
+	_, err := client.PlaceOrder(testVenue, testStock, testAccount, 5000, 10, OrderDirectionBuy, OrderTypeLimit)
+	assert.Nil(t, err)
+	_, err = client.PlaceOrder(testVenue, testStock, testAccount, 5010, 10, OrderDirectionBuy, OrderTypeImmediateOrCancel)
+	assert.Nil(t, err)
+	_, err = client.PlaceOrder(testVenue, testStock, "OTHER", 5000, 10, OrderDirectionBuy, OrderTypeLimit)
+	assert.Nil(t, err)
+
+	orders, err := client.GetStockOrders(testVenue, testAccount, testStock)
+	assert.Nil(t, err)
+	if assert.Len(t, orders, 3) {
+		assert.Equal(t, []int64{7, -1, -2}, []int64{orders[0].OrderID, orders[1].OrderID, orders[2].OrderID})
+	}
+
+	results, err := client.CancelAllOrders(context.Background(), testVenue, testAccount)
+	assert.Nil(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, int64(-1), results[0].OrderID)
+		assert.Nil(t, results[0].Err)
+		assert.Equal(t, OrderStateCanceled, results[0].Order.State())
+	}
+
+	orders, err = client.GetAllOrders(testVenue, testAccount)
+	assert.Nil(t, err)
+	if assert.Len(t, orders, 3) {
+		assert.False(t, orders[1].Open)
+	}
+}
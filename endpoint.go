@@ -0,0 +1,75 @@
+package stockfighter
+
+import (
+	"strconv"
+	"strings"
+)
+
+// API endpoint names returned by ParseEndpoint.
+const (
+	EndpointHeartbeat          = "heartbeat"
+	EndpointVenueHeartbeat     = "venue_heartbeat"
+	EndpointStocks             = "stocks"
+	EndpointOrderbook          = "orderbook"
+	EndpointQuote              = "quote"
+	EndpointPlaceOrder         = "place_order"
+	EndpointOrderStatus        = "order_status"
+	EndpointCancelOrder        = "cancel_order"
+	EndpointAccountOrders      = "account_orders"
+	EndpointAccountStockOrders = "account_stock_orders"
+	EndpointOther              = "other"
+)
+
+// An Endpoint describes the API endpoint an API request is for, along with
+// the symbols in its path.
+type Endpoint struct {
+	// Endpoint name (one of the Endpoint* constants)
+	Name string
+
+	// Symbols in the request path, if any
+	Venue   string
+	Stock   string
+	Account string
+	OrderID int64
+}
+
+// ParseEndpoint returns the API endpoint of a request from its method and URL
+// path. The path may include the API base URL path (e.g. /ob/api).
+func ParseEndpoint(method, path string) Endpoint {
+	if i := strings.Index(path, "/venues/"); i >= 0 {
+		path = path[i:]
+	} else if strings.HasSuffix(path, "/heartbeat") {
+		return Endpoint{Name: EndpointHeartbeat}
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "venues" {
+		return Endpoint{Name: EndpointOther}
+	}
+	e := Endpoint{Name: EndpointOther, Venue: parts[1]}
+
+	switch {
+	case len(parts) == 3 && parts[2] == "heartbeat":
+		e.Name = EndpointVenueHeartbeat
+	case len(parts) == 3 && parts[2] == "stocks":
+		e.Name = EndpointStocks
+	case len(parts) == 4 && parts[2] == "stocks":
+		e.Name, e.Stock = EndpointOrderbook, parts[3]
+	case len(parts) == 5 && parts[2] == "stocks" && parts[4] == "quote":
+		e.Name, e.Stock = EndpointQuote, parts[3]
+	case len(parts) == 5 && parts[2] == "stocks" && parts[4] == "orders":
+		e.Name, e.Stock = EndpointPlaceOrder, parts[3]
+	case len(parts) == 6 && parts[2] == "stocks" && parts[4] == "orders":
+		e.Name, e.Stock = EndpointOrderStatus, parts[3]
+		if strings.ToUpper(method) == "DELETE" {
+			e.Name = EndpointCancelOrder
+		}
+		e.OrderID, _ = strconv.ParseInt(parts[5], 10, 64)
+	case len(parts) == 5 && parts[2] == "accounts" && parts[4] == "orders":
+		e.Name, e.Account = EndpointAccountOrders, parts[3]
+	case len(parts) == 7 && parts[2] == "accounts" && parts[4] == "stocks" && parts[6] == "orders":
+		e.Name, e.Account, e.Stock = EndpointAccountStockOrders, parts[3], parts[5]
+	}
+
+	return e
+}
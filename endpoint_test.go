@@ -0,0 +1,28 @@
+package stockfighter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	for _, c := range []struct {
+		method, path string
+		endpoint     Endpoint
+	}{
+		{"GET", "/ob/api/heartbeat", Endpoint{Name: EndpointHeartbeat}},
+		{"GET", "/ob/api/venues/TESTEX/heartbeat", Endpoint{Name: EndpointVenueHeartbeat, Venue: "TESTEX"}},
+		{"GET", "/ob/api/venues/TESTEX/stocks", Endpoint{Name: EndpointStocks, Venue: "TESTEX"}},
+		{"GET", "/ob/api/venues/TESTEX/stocks/FOOBAR", Endpoint{Name: EndpointOrderbook, Venue: "TESTEX", Stock: "FOOBAR"}},
+		{"GET", "/ob/api/venues/TESTEX/stocks/FOOBAR/quote", Endpoint{Name: EndpointQuote, Venue: "TESTEX", Stock: "FOOBAR"}},
+		{"POST", "/ob/api/venues/TESTEX/stocks/FOOBAR/orders", Endpoint{Name: EndpointPlaceOrder, Venue: "TESTEX", Stock: "FOOBAR"}},
+		{"GET", "/ob/api/venues/TESTEX/stocks/FOOBAR/orders/42", Endpoint{Name: EndpointOrderStatus, Venue: "TESTEX", Stock: "FOOBAR", OrderID: 42}},
+		{"DELETE", "/ob/api/venues/TESTEX/stocks/FOOBAR/orders/42", Endpoint{Name: EndpointCancelOrder, Venue: "TESTEX", Stock: "FOOBAR", OrderID: 42}},
+		{"GET", "/ob/api/venues/TESTEX/accounts/EXB123456/orders", Endpoint{Name: EndpointAccountOrders, Venue: "TESTEX", Account: "EXB123456"}},
+		{"GET", "/ob/api/venues/TESTEX/accounts/EXB123456/stocks/FOOBAR/orders", Endpoint{Name: EndpointAccountStockOrders, Venue: "TESTEX", Account: "EXB123456", Stock: "FOOBAR"}},
+		{"GET", "/gm/levels", Endpoint{Name: EndpointOther}},
+	} {
+		assert.Equal(t, c.endpoint, ParseEndpoint(c.method, c.path), c.path)
+	}
+}
@@ -1,6 +1,10 @@
 package stockfighter
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 // API timeout error.
 type ErrorAPITimeout struct{}
@@ -34,3 +38,91 @@ type ErrorStockNotFound struct {
 func (e *ErrorStockNotFound) Error() string {
 	return fmt.Sprintf("Stock not found: %v (venue: %v)", e.StockSymbol, e.VenueSymbol)
 }
+
+// notFoundError returns the error for a 404 response of an endpoint whose
+// path has both a venue and a stock, which the API returns when either does
+// not exist. The error message tells them apart, e.g. "No venue exists with
+// the symbol NOEXIST" or "Stock NOEXIST does not trade on venue TESTEX".
+func notFoundError(venue, stock, msg string) error {
+	if strings.HasPrefix(strings.ToLower(msg), "no venue") {
+		return &ErrorVenueNotFound{VenueSymbol: venue}
+	}
+	return &ErrorStockNotFound{VenueSymbol: venue, StockSymbol: stock}
+}
+
+// Order rejected before being sent, e.g. for a zero quantity or an unknown
+// order type.
+type ErrorInvalidOrder struct {
+	Reason string
+}
+
+func (e *ErrorInvalidOrder) Error() string {
+	return "Invalid order: " + e.Reason
+}
+
+// API response that could not be decoded, e.g. malformed JSON or a field of
+// an unexpected type.
+type ErrorDecode struct {
+	// HTTP status code of the response
+	StatusCode int
+
+	// Response body, as received (empty for responses decoded as they are
+	// received, see Client.StreamOrderbook)
+	Payload []byte
+
+	// Decoding error
+	Err error
+}
+
+func (e *ErrorDecode) Error() string {
+	payload := e.Payload
+	if len(payload) > maxErrorPayload {
+		payload = payload[:maxErrorPayload]
+	}
+	return fmt.Sprintf("Cannot decode API response (HTTP %v): %v (payload: %q)", e.StatusCode, e.Err, payload)
+}
+
+func (e *ErrorDecode) Unwrap() error {
+	return e.Err
+}
+
+// maxErrorPayload is the number of bytes of the payload ErrorDecode.Error
+// includes.
+const maxErrorPayload = 256
+
+// Some requests of a batch failed. Errors are keyed by stock symbol.
+type ErrorPartial struct {
+	Errors map[string]error
+}
+
+func (e *ErrorPartial) Error() string {
+	stocks := make([]string, 0, len(e.Errors))
+	for stock := range e.Errors {
+		stocks = append(stocks, stock)
+	}
+	sort.Strings(stocks)
+
+	msgs := make([]string, len(stocks))
+	for i, stock := range stocks {
+		msgs[i] = stock + ": " + e.Errors[stock].Error()
+	}
+	return "Some requests failed: " + strings.Join(msgs, "; ")
+}
+
+// Order dropped from an OrderQueue before being sent, because a newer requote
+// for the same stock and side replaced it.
+type ErrorOrderSuperseded struct{}
+
+func (e *ErrorOrderSuperseded) Error() string {
+	return "Order superseded by a newer requote"
+}
+
+// No API key found for a profile, in the environment, the config file, nor
+// the OS keyring.
+type ErrorAPIKeyMissing struct {
+	Profile string
+}
+
+func (e *ErrorAPIKeyMissing) Error() string {
+	return "API key missing for profile " + e.Profile + ": set $STOCKFIGHTER_API_KEY or api_key in the config file"
+}
@@ -0,0 +1,214 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Kinds of execution stream issues.
+const (
+	// The execution was received already, or is older than the known status
+	// of its order: it is dropped
+	ExecutionIssueDuplicate = "duplicate"
+
+	// The total filled of the order jumped past the fill of the execution:
+	// executions were likely dropped by the stream
+	ExecutionIssueGap = "gap"
+
+	// The orders of the account were fetched after a gap
+	ExecutionIssueReconciled = "reconciled"
+)
+
+// An ExecutionIssue represents a problem with a stream of executions, raised
+// by an ExecutionChecker.
+type ExecutionIssue struct {
+	Kind string
+
+	// Local time the issue was raised at
+	Time time.Time
+
+	Venue   string
+	Account string
+
+	// Order of the execution, and its expected and actual total filled, for
+	// duplicate and gap issues
+	OrderID  int64
+	Expected uint64
+	Got      uint64
+
+	// Orders of the account fetched, or error fetching them, for
+	// reconciliation issues
+	Orders []Order
+	Err    error
+}
+
+func (issue ExecutionIssue) String() string {
+	switch issue.Kind {
+	case ExecutionIssueReconciled:
+		if issue.Err != nil {
+			return fmt.Sprintf("reconciliation of %v/%v failed: %v", issue.Venue, issue.Account, issue.Err)
+		}
+		return fmt.Sprintf("reconciled %v orders of %v/%v", len(issue.Orders), issue.Venue, issue.Account)
+	}
+	return fmt.Sprintf("%v execution of order %v: total filled %v, expected %v", issue.Kind, issue.OrderID, issue.Got, issue.Expected)
+}
+
+// executionOrderKey identifies an order of an account in a venue.
+type executionOrderKey struct {
+	venue, account string
+	orderID        int64
+}
+
+// An ExecutionChecker checks a stream of executions for duplicates and gaps,
+// from the total filled of the order of each execution: it drops duplicate
+// executions, and fetches the orders of the account with the REST API when
+// executions were likely dropped, so that the fills missed are not lost.
+//
+// You can create a new ExecutionChecker using NewExecutionChecker function.
+type ExecutionChecker struct {
+	// Function called with each issue raised, if not nil
+	OnIssue func(ExecutionIssue)
+
+	client *Client
+
+	mu     sync.Mutex
+	filled map[executionOrderKey]uint64
+	issues []ExecutionIssue
+}
+
+// NewExecutionChecker creates a new ExecutionChecker reconciling orders with
+// client, or only raising issues if client is nil. This never returns nil.
+func NewExecutionChecker(client *Client) *ExecutionChecker {
+	return &ExecutionChecker{
+		client: client,
+		filled: make(map[executionOrderKey]uint64),
+	}
+}
+
+// Issues returns the issues raised so far, in order.
+func (checker *ExecutionChecker) Issues() []ExecutionIssue {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	return append([]ExecutionIssue(nil), checker.issues...)
+}
+
+// Check checks an execution against the status of its order known from the
+// previous executions, and returns whether it should be processed, i.e. it
+// is not a duplicate. A gap is reconciled before Check returns.
+//
+// The first execution of an order is expected to be its first fill: track
+// orders placed before the stream was opened with Reconcile.
+func (checker *ExecutionChecker) Check(execution *Execution) bool {
+	key := executionOrderKey{execution.Venue, execution.Account, execution.Order.OrderID}
+	got := execution.Order.TotalFilled
+
+	checker.mu.Lock()
+	prev := checker.filled[key]
+	expected := prev + execution.Filled
+	if got > prev {
+		checker.filled[key] = got
+	}
+	checker.mu.Unlock()
+
+	switch {
+	case got <= prev:
+		checker.raise(ExecutionIssue{
+			Kind:     ExecutionIssueDuplicate,
+			Venue:    execution.Venue,
+			Account:  execution.Account,
+			OrderID:  key.orderID,
+			Expected: expected,
+			Got:      got,
+		})
+		return false
+
+	case got > expected:
+		checker.raise(ExecutionIssue{
+			Kind:     ExecutionIssueGap,
+			Venue:    execution.Venue,
+			Account:  execution.Account,
+			OrderID:  key.orderID,
+			Expected: expected,
+			Got:      got,
+		})
+		if checker.client != nil {
+			checker.Reconcile(execution.Venue, execution.Account)
+		}
+	}
+	return true
+}
+
+// Reconcile fetches the orders of an account in a venue, updates the known
+// status of the orders, and raises a reconciliation issue with the orders.
+// This panics if the checker has no client.
+func (checker *ExecutionChecker) Reconcile(venue, account string) ([]Order, error) {
+	if checker.client == nil {
+		panic(fmt.Errorf("ExecutionChecker has no client"))
+	}
+
+	orders, err := checker.client.GetAllOrders(venue, account)
+	if err == nil {
+		checker.mu.Lock()
+		for _, order := range orders {
+			key := executionOrderKey{venue, account, order.OrderID}
+			if order.TotalFilled > checker.filled[key] {
+				checker.filled[key] = order.TotalFilled
+			}
+		}
+		checker.mu.Unlock()
+	}
+
+	checker.raise(ExecutionIssue{Kind: ExecutionIssueReconciled, Venue: venue, Account: account, Orders: orders, Err: err})
+	return orders, err
+}
+
+// Start starts checking executions in a new goroutine and returns the
+// channel the executions that are not duplicates are forwarded on.
+//
+// Checking stops and the channel is closed when ctx is done or executions is
+// closed.
+func (checker *ExecutionChecker) Start(ctx context.Context, executions <-chan *Execution) <-chan *Execution {
+	out := make(chan *Execution)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case execution, ok := <-executions:
+				if !ok {
+					return
+				}
+				if !checker.Check(execution) {
+					continue
+				}
+
+				select {
+				case out <- execution:
+				case <-ctx.Done():
+					return
+				}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func (checker *ExecutionChecker) raise(issue ExecutionIssue) {
+	issue.Time = time.Now()
+
+	checker.mu.Lock()
+	checker.issues = append(checker.issues, issue)
+	checker.mu.Unlock()
+
+	if checker.OnIssue != nil {
+		checker.OnIssue(issue)
+	}
+}
@@ -0,0 +1,85 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testExecution(orderID int64, filled, totalFilled uint64) *Execution {
+	return &Execution{
+		Account: testAccount,
+		Venue:   testVenue,
+		Symbol:  testStock,
+		Order:   Order{OrderID: orderID, TotalFilled: totalFilled, Open: true},
+		Filled:  filled,
+	}
+}
+
+func TestExecutionChecker(t *testing.T) {
+	var fetches int
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		assert.Equal(t, "/venues/TESTEX/accounts/"+testAccount+"/orders", r.URL.Path)
+		fmt.Fprintf(w, `{"ok": true, "venue": "%s", "orders": [{"id": 1, "totalFilled": 50, "open": true}, {"id": 2, "totalFilled": 10}]}`, testVenue)
+	})
+
+	var raised []string
+	checker := NewExecutionChecker(client)
+	checker.OnIssue = func(issue ExecutionIssue) { raised = append(raised, issue.Kind) }
+
+	assert.True(t, checker.Check(testExecution(1, 10, 10)))
+	assert.True(t, checker.Check(testExecution(1, 5, 15)))
+
+	// the same execution again
+	assert.False(t, checker.Check(testExecution(1, 5, 15)))
+
+	// executions were dropped: the orders are fetched
+	assert.True(t, checker.Check(testExecution(1, 5, 30)))
+	assert.Equal(t, 1, fetches)
+
+	// in-flight executions older than the orders fetched are duplicates
+	assert.False(t, checker.Check(testExecution(1, 10, 40)))
+	assert.False(t, checker.Check(testExecution(2, 10, 10)))
+	assert.True(t, checker.Check(testExecution(1, 10, 60)))
+
+	assert.Equal(t, []string{ExecutionIssueDuplicate, ExecutionIssueGap, ExecutionIssueReconciled, ExecutionIssueDuplicate, ExecutionIssueDuplicate}, raised)
+
+	issues := checker.Issues()
+	if assert.Len(t, issues, 5) {
+		assert.Equal(t, "gap execution of order 1: total filled 30, expected 20", issues[1].String())
+		assert.Equal(t, "reconciled 2 orders of TESTEX/"+testAccount, issues[2].String())
+		assert.Len(t, issues[2].Orders, 2)
+	}
+}
+
+func TestExecutionCheckerStart(t *testing.T) {
+	checker := NewExecutionChecker(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	executions := make(chan *Execution, 4)
+	executions <- testExecution(1, 10, 10)
+	executions <- testExecution(1, 10, 10)
+	executions <- testExecution(1, 10, 30)
+	executions <- testExecution(2, 5, 5)
+	close(executions)
+
+	var filled []uint64
+	for execution := range checker.Start(ctx, executions) {
+		filled = append(filled, execution.Order.TotalFilled)
+	}
+	assert.Equal(t, []uint64{10, 30, 5}, filled)
+
+	// without client gaps are only raised
+	issues := checker.Issues()
+	if assert.Len(t, issues, 2) {
+		assert.Equal(t, ExecutionIssueDuplicate, issues[0].Kind)
+		assert.Equal(t, ExecutionIssueGap, issues[1].Kind)
+	}
+	assert.Panics(t, func() { checker.Reconcile(testVenue, testAccount) })
+}
@@ -0,0 +1,233 @@
+/*
+Package export converts recorded sessions into analysis-friendly tables of
+quotes, trades, orders, and fills, written as CSV files (or Parquet files,
+see package parquetexport) for use in pandas or DuckDB.
+
+A session is made of a quote recording, as written by
+stockfighter.QuoteRecorder, and optionally a blotter, as written by
+stockfighter.WriteBlotter in the JSONL format:
+
+    tables, err := export.ReadRecording(recording)
+    if err != nil {
+        return err
+    }
+    if err := tables.ReadBlotter(blotter); err != nil {
+        return err
+    }
+    err = tables.WriteCSV("out")
+
+Prices are in cents.
+*/
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// A QuoteRow is a row of the quotes table. Bid and ask columns are nil when
+// there are no bids or asks.
+type QuoteRow struct {
+	Time      time.Time `parquet:"time,timestamp"`
+	Venue     string    `parquet:"venue"`
+	Symbol    string    `parquet:"symbol"`
+	Bid       *uint64   `parquet:"bid,optional"`
+	BidSize   *uint64   `parquet:"bid_size,optional"`
+	BidDepth  uint64    `parquet:"bid_depth"`
+	Ask       *uint64   `parquet:"ask,optional"`
+	AskSize   *uint64   `parquet:"ask_size,optional"`
+	AskDepth  uint64    `parquet:"ask_depth"`
+	Last      uint64    `parquet:"last"`
+	LastSize  uint64    `parquet:"last_size"`
+	LastTrade time.Time `parquet:"last_trade,timestamp"`
+}
+
+// A TradeRow is a row of the trades table: a trade seen in the quotes, i.e.
+// a new last trade.
+type TradeRow struct {
+	Time   time.Time `parquet:"time,timestamp"`
+	Venue  string    `parquet:"venue"`
+	Symbol string    `parquet:"symbol"`
+	Price  uint64    `parquet:"price"`
+	Size   uint64    `parquet:"size"`
+}
+
+// An OrderRow is a row of the orders table: an order event of the blotter.
+type OrderRow struct {
+	Time      time.Time `parquet:"time,timestamp"`
+	Venue     string    `parquet:"venue"`
+	Symbol    string    `parquet:"symbol"`
+	OrderID   int64     `parquet:"order_id"`
+	Direction string    `parquet:"direction"`
+	OrderType string    `parquet:"order_type"`
+	State     string    `parquet:"state"`
+	Price     uint64    `parquet:"price"`
+	Quantity  uint64    `parquet:"qty"`
+}
+
+// A FillRow is a row of the fills table: a fill of the blotter.
+type FillRow struct {
+	Time      time.Time `parquet:"time,timestamp"`
+	Venue     string    `parquet:"venue"`
+	Symbol    string    `parquet:"symbol"`
+	OrderID   int64     `parquet:"order_id"`
+	Direction string    `parquet:"direction"`
+	Price     uint64    `parquet:"price"`
+	Quantity  uint64    `parquet:"qty"`
+}
+
+// Tables holds the tables of a session, in the order of the recording and
+// blotter they were read from.
+type Tables struct {
+	Quotes []QuoteRow
+	Trades []TradeRow
+	Orders []OrderRow
+	Fills  []FillRow
+}
+
+// ReadRecording reads a quote recording into the quotes and trades tables.
+func ReadRecording(r io.Reader) (*Tables, error) {
+	tables := &Tables{}
+	lastTrades := make(map[string]time.Time)
+
+	replayer := stockfighter.NewQuoteReplayer(r)
+	for {
+		quote, err := replayer.Next()
+		if errors.Is(err, io.EOF) {
+			return tables, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := QuoteRow{Time: quote.QuoteTime, Venue: quote.Venue, Symbol: quote.Stock, BidDepth: quote.BidDepth, AskDepth: quote.AskDepth,
+			Last: quote.LastPrice, LastSize: quote.LastSize, LastTrade: quote.LastTradeTime}
+		if quote.HasBid {
+			row.Bid, row.BidSize = &quote.BidPrice, &quote.BidSize
+		}
+		if quote.HasAsk {
+			row.Ask, row.AskSize = &quote.AskPrice, &quote.AskSize
+		}
+		tables.Quotes = append(tables.Quotes, row)
+
+		key := quote.Venue + "/" + quote.Stock
+		if !quote.LastTradeTime.IsZero() && !quote.LastTradeTime.Equal(lastTrades[key]) {
+			lastTrades[key] = quote.LastTradeTime
+			tables.Trades = append(tables.Trades, TradeRow{Time: quote.LastTradeTime, Venue: quote.Venue, Symbol: quote.Stock,
+				Price: quote.LastPrice, Size: quote.LastSize})
+		}
+	}
+}
+
+// ReadBlotter reads a blotter in the JSONL format into the orders and fills
+// tables.
+func (tables *Tables) ReadBlotter(r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	for {
+		var event stockfighter.BlotterEvent
+		err := decoder.Decode(&event)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch event.Kind {
+		case stockfighter.BlotterEventOrder:
+			tables.Orders = append(tables.Orders, OrderRow{Time: event.Time, Venue: event.Venue, Symbol: event.Symbol, OrderID: event.OrderID,
+				Direction: event.Direction, OrderType: event.OrderType, State: event.State, Price: event.Price, Quantity: event.Quantity})
+		case stockfighter.BlotterEventFill:
+			tables.Fills = append(tables.Fills, FillRow{Time: event.Time, Venue: event.Venue, Symbol: event.Symbol, OrderID: event.OrderID,
+				Direction: event.Direction, Price: event.Price, Quantity: event.Quantity})
+		}
+	}
+}
+
+// WriteCSV writes the tables to quotes.csv, trades.csv, orders.csv, and
+// fills.csv in dir, which is created if needed. Times are in RFC 3339 format,
+// and missing values are empty.
+func (tables *Tables) WriteCSV(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	files := []struct {
+		name   string
+		header []string
+		n      int
+		row    func(i int) []string
+	}{
+		{"quotes.csv", []string{"time", "venue", "symbol", "bid", "bid_size", "bid_depth", "ask", "ask_size", "ask_depth", "last", "last_size", "last_trade"},
+			len(tables.Quotes), func(i int) []string {
+				q := tables.Quotes[i]
+				return []string{formatTime(q.Time), q.Venue, q.Symbol, formatOptional(q.Bid), formatOptional(q.BidSize), formatUint(q.BidDepth),
+					formatOptional(q.Ask), formatOptional(q.AskSize), formatUint(q.AskDepth), formatUint(q.Last), formatUint(q.LastSize), formatTime(q.LastTrade)}
+			}},
+		{"trades.csv", []string{"time", "venue", "symbol", "price", "size"},
+			len(tables.Trades), func(i int) []string {
+				t := tables.Trades[i]
+				return []string{formatTime(t.Time), t.Venue, t.Symbol, formatUint(t.Price), formatUint(t.Size)}
+			}},
+		{"orders.csv", []string{"time", "venue", "symbol", "order_id", "direction", "order_type", "state", "price", "qty"},
+			len(tables.Orders), func(i int) []string {
+				o := tables.Orders[i]
+				return []string{formatTime(o.Time), o.Venue, o.Symbol, strconv.FormatInt(o.OrderID, 10), o.Direction, o.OrderType, o.State,
+					formatUint(o.Price), formatUint(o.Quantity)}
+			}},
+		{"fills.csv", []string{"time", "venue", "symbol", "order_id", "direction", "price", "qty"},
+			len(tables.Fills), func(i int) []string {
+				f := tables.Fills[i]
+				return []string{formatTime(f.Time), f.Venue, f.Symbol, strconv.FormatInt(f.OrderID, 10), f.Direction, formatUint(f.Price), formatUint(f.Quantity)}
+			}},
+	}
+
+	for _, file := range files {
+		f, err := os.Create(filepath.Join(dir, file.name))
+		if err != nil {
+			return err
+		}
+
+		w := csv.NewWriter(f)
+		w.Write(file.header)
+		for i := 0; i < file.n; i++ {
+			w.Write(file.row(i))
+		}
+		w.Flush()
+
+		err = w.Error()
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func formatUint(v uint64) string {
+	return strconv.FormatUint(v, 10)
+}
+
+func formatOptional(v *uint64) string {
+	if v == nil {
+		return ""
+	}
+	return formatUint(*v)
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func TestExport(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+
+	var recording bytes.Buffer
+	recorder := stockfighter.NewQuoteRecorder(&recording)
+	recorder.Record("TESTEX", "FOOBAR", &stockfighter.Quote{HasBid: true, BidPrice: 5000, BidSize: 10, QuoteTime: ts})
+	recorder.Record("TESTEX", "FOOBAR", &stockfighter.Quote{HasBid: true, BidPrice: 5000, BidSize: 10, LastPrice: 5010, LastSize: 5,
+		LastTradeTime: ts.Add(time.Second), QuoteTime: ts.Add(time.Second)})
+	recorder.Record("TESTEX", "FOOBAR", &stockfighter.Quote{HasAsk: true, AskPrice: 5020, AskSize: 3, LastPrice: 5010, LastSize: 5,
+		LastTradeTime: ts.Add(time.Second), QuoteTime: ts.Add(2 * time.Second)})
+
+	tables, err := ReadRecording(&recording)
+	assert.Nil(t, err)
+	assert.Len(t, tables.Quotes, 3)
+	assert.Nil(t, tables.Quotes[2].Bid)
+	assert.Equal(t, uint64(5020), *tables.Quotes[2].Ask)
+	assert.Equal(t, []TradeRow{{Time: ts.Add(time.Second), Venue: "TESTEX", Symbol: "FOOBAR", Price: 5010, Size: 5}}, tables.Trades)
+
+	var blotter bytes.Buffer
+	assert.Nil(t, stockfighter.WriteBlotter(&blotter, stockfighter.BlotterFormatJSONL, []stockfighter.BlotterEvent{
+		{Time: ts, Kind: stockfighter.BlotterEventOrder, Venue: "TESTEX", Symbol: "FOOBAR", OrderID: 1, Direction: "buy", OrderType: "limit",
+			State: "open", Price: 5010, Quantity: 5},
+		{Time: ts.Add(time.Second), Kind: stockfighter.BlotterEventFill, Venue: "TESTEX", Symbol: "FOOBAR", OrderID: 1, Direction: "buy",
+			OrderType: "limit", State: "closed", Price: 5010, Quantity: 5},
+	}))
+	assert.Nil(t, tables.ReadBlotter(&blotter))
+	assert.Len(t, tables.Orders, 1)
+	assert.Equal(t, []FillRow{{Time: ts.Add(time.Second), Venue: "TESTEX", Symbol: "FOOBAR", OrderID: 1, Direction: "buy", Price: 5010, Quantity: 5}},
+		tables.Fills)
+
+	dir := t.TempDir()
+	assert.Nil(t, tables.WriteCSV(dir))
+	data, err := os.ReadFile(filepath.Join(dir, "quotes.csv"))
+	assert.Nil(t, err)
+	lines := strings.Split(string(data), "\n")
+	assert.Equal(t, "time,venue,symbol,bid,bid_size,bid_depth,ask,ask_size,ask_depth,last,last_size,last_trade", lines[0])
+	assert.Equal(t, "2015-12-04T09:02:16Z,TESTEX,FOOBAR,5000,10,0,,,0,0,0,", lines[1])
+	data, err = os.ReadFile(filepath.Join(dir, "fills.csv"))
+	assert.Nil(t, err)
+	assert.Equal(t, "time,venue,symbol,order_id,direction,price,qty\n2015-12-04T09:02:17Z,TESTEX,FOOBAR,1,buy,5010,5\n", string(data))
+}
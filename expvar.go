@@ -0,0 +1,64 @@
+package stockfighter
+
+import "expvar"
+
+// An ExpvarOption publishes additional variables with Client.PublishExpvar.
+type ExpvarOption func(vars *expvar.Map)
+
+// ExpvarStreamLag publishes the status of a stream watched by a
+// StreamWatchdog, as "stream", so that its lag and stalls are visible.
+func ExpvarStreamLag(watchdog *StreamWatchdog) ExpvarOption {
+	return func(vars *expvar.Map) {
+		vars.Set("stream", expvar.Func(func() interface{} {
+			return watchdog.Status()
+		}))
+	}
+}
+
+// ExpvarOpenOrders publishes the number of open orders per venue, account,
+// and stock, as "open_orders" keyed by "VENUE/ACCOUNT/STOCK", orders
+// returning the open orders, e.g. strategy.Session.OpenOrders.
+func ExpvarOpenOrders(orders func() []Order) ExpvarOption {
+	return func(vars *expvar.Map) {
+		vars.Set("open_orders", expvar.Func(func() interface{} {
+			counts := make(map[string]int)
+			for _, order := range orders() {
+				if order.Open {
+					counts[order.Venue+"/"+order.Account+"/"+order.Symbol]++
+				}
+			}
+			return counts
+		}))
+	}
+}
+
+// PublishExpvar publishes the internals of the client with package expvar,
+// as a map under name: its stats ("stats", see Stats), usage per subsystem
+// ("usage"), and rate limiter state if any ("rate_limit"), along with the
+// variables of the options. Any program serving /debug/vars, e.g. with
+// net/http/pprof or expvar.Handler, then exposes them:
+//
+//    client.PublishExpvar("stockfighter", stockfighter.ExpvarStreamLag(watchdog))
+//
+// Values are computed when the variables are read. This panics if name is
+// already published.
+func (client *Client) PublishExpvar(name string, options ...ExpvarOption) *expvar.Map {
+	vars := new(expvar.Map).Init()
+	vars.Set("stats", expvar.Func(func() interface{} {
+		return client.Stats()
+	}))
+	vars.Set("usage", expvar.Func(func() interface{} {
+		return client.Usage()
+	}))
+	if limiter := client.transport.limiter; limiter != nil {
+		vars.Set("rate_limit", expvar.Func(func() interface{} {
+			return limiter.getState()
+		}))
+	}
+	for _, option := range options {
+		option(vars)
+	}
+
+	expvar.Publish(name, vars)
+	return vars
+}
@@ -0,0 +1,47 @@
+package stockfighter
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true}`))
+	})
+	client := NewClient(testApiKey, WithBaseURL(server.transport.baseURL), WithRateLimit(100, time.Second))
+	assert.Nil(t, client.Ping())
+
+	watchdog := NewStreamWatchdog(time.Second)
+	orders := func() []Order {
+		return []Order{
+			{Venue: testVenue, Account: testAccount, Symbol: testStock, Open: true},
+			{Venue: testVenue, Account: testAccount, Symbol: testStock, Open: true},
+			{Venue: "OTHEREX", Account: testAccount, Symbol: testStock, Open: true},
+			{Venue: testVenue, Account: "OTHER", Symbol: testStock, Open: true},
+			{Venue: testVenue, Account: testAccount, Symbol: "BARBAZ"},
+		}
+	}
+	client.PublishExpvar("stockfighter_test", ExpvarStreamLag(watchdog), ExpvarOpenOrders(orders))
+
+	var vars struct {
+		Stats      Stats
+		Usage      map[string]uint64
+		RateLimit  rateLimitState `json:"rate_limit"`
+		Stream     StreamStatus
+		OpenOrders map[string]int `json:"open_orders"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(expvar.Get("stockfighter_test").String()), &vars))
+	assert.Equal(t, uint64(1), vars.Stats.Requests)
+	assert.Equal(t, map[string]uint64{DefaultSubsystem: 1}, vars.Usage)
+	assert.Equal(t, 10*time.Millisecond, vars.RateLimit.Interval)
+	assert.False(t, vars.Stream.Stalled)
+	assert.Equal(t, map[string]int{"TESTEX/EXB123456/FOOBAR": 2, "OTHEREX/EXB123456/FOOBAR": 1, "TESTEX/OTHER/FOOBAR": 1}, vars.OpenOrders)
+
+	assert.Panics(t, func() { client.PublishExpvar("stockfighter_test") })
+}
@@ -0,0 +1,260 @@
+/*
+Package fanout rebroadcasts the WebSocket streams of the Stockfighter API
+(tickertape and executions) to local subscribers, so that several strategy
+processes share one upstream connection per account and venue.
+
+A Hub serves the same paths as the API, so local processes only swap the base
+URL of their WebSocket streams:
+
+    hub := fanout.New()
+    go http.ListenAndServe("localhost:8081", hub)
+
+    // ws://localhost:8081/ws/EXB123456/venues/TESTEX/tickertape/stocks/FOOBAR
+
+Subscriptions to the stream of a single stock are served from the stream of
+the whole venue, filtered by stock symbol.
+*/
+package fanout
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"gpk.io/stockfighter"
+)
+
+// DefaultBaseURL is the base URL of the WebSocket streams of the official
+// Stockfighter API.
+const DefaultBaseURL = "wss://api.stockfighter.io/ob/api"
+
+// Stream kinds, as found in stream paths.
+const (
+	StreamTickertape = "tickertape"
+	StreamExecutions = "executions"
+)
+
+// A Hub is an http.Handler accepting local WebSocket subscriptions and
+// rebroadcasting the upstream streams to them. An upstream connection is
+// opened with the first subscription to its stream, reopened if it fails,
+// and closed with the last subscription.
+//
+// You can create a new Hub using New function.
+type Hub struct {
+	// Base URL of the upstream streams
+	BaseURL string
+
+	// Dialer upstream connections are opened with
+	Dialer *websocket.Dialer
+
+	// Delay before reopening a failed upstream connection
+	ReconnectDelay time.Duration
+
+	// Messages buffered per subscriber; subscribers falling further behind
+	// are disconnected
+	BufferSize int
+
+	// Journal upstream messages are recorded to, if not nil, to replay the
+	// session offline (see stockfighter.ReplayJournal)
+	Journal *stockfighter.StreamJournal
+
+	upgrader websocket.Upgrader
+
+	mu    sync.Mutex
+	feeds map[string]*feed
+}
+
+// New creates a new Hub rebroadcasting the streams of the official API. This
+// never returns nil.
+func New() *Hub {
+	return &Hub{
+		BaseURL:        DefaultBaseURL,
+		Dialer:         websocket.DefaultDialer,
+		ReconnectDelay: time.Second,
+		BufferSize:     256,
+		upgrader: websocket.Upgrader{
+			// subscribers are local processes, not browsers
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		feeds: make(map[string]*feed),
+	}
+}
+
+// feed is an upstream stream and its subscribers.
+type feed struct {
+	path   string
+	subs   map[*subscriber]bool
+	cancel context.CancelFunc
+}
+
+// subscriber is a local subscription, optionally to a single stock.
+type subscriber struct {
+	stock string
+	send  chan []byte
+}
+
+// parsePath splits a stream path into the path of the upstream stream of the
+// whole venue and the stock, if any:
+//
+//     /ws/:account/venues/:venue/tickertape[/stocks/:stock]
+//     /ws/:account/venues/:venue/executions[/stocks/:stock]
+func parsePath(path string) (upstream, stock string, ok bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 5 && len(parts) != 7 {
+		return "", "", false
+	}
+	if parts[0] != "ws" || parts[2] != "venues" || (parts[4] != StreamTickertape && parts[4] != StreamExecutions) {
+		return "", "", false
+	}
+	if len(parts) == 7 {
+		if parts[5] != "stocks" {
+			return "", "", false
+		}
+		stock = parts[6]
+	}
+	return "/" + strings.Join(parts[:5], "/"), stock, true
+}
+
+// ServeHTTP implements http.Handler.
+func (hub *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	upstream, stock, ok := parsePath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	conn, err := hub.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade replied with an error already
+		return
+	}
+	defer conn.Close()
+
+	sub := &subscriber{stock: stock, send: make(chan []byte, hub.BufferSize)}
+	hub.subscribe(upstream, sub)
+	defer hub.unsubscribe(upstream, sub)
+
+	// subscribers are not expected to send anything: reading detects when
+	// they go away
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				hub.unsubscribe(upstream, sub)
+				return
+			}
+		}
+	}()
+
+	for msg := range sub.send {
+		if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+			return
+		}
+	}
+}
+
+func (hub *Hub) subscribe(path string, sub *subscriber) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	f, ok := hub.feeds[path]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		f = &feed{path: path, subs: make(map[*subscriber]bool), cancel: cancel}
+		hub.feeds[path] = f
+		go hub.run(ctx, f)
+	}
+	f.subs[sub] = true
+}
+
+// unsubscribe removes a subscriber, if not removed already, and closes the
+// upstream stream if it has no more subscribers.
+func (hub *Hub) unsubscribe(path string, sub *subscriber) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	f, ok := hub.feeds[path]
+	if !ok || !f.subs[sub] {
+		return
+	}
+	hub.remove(f, sub)
+}
+
+// remove removes a subscriber of a feed. hub.mu must be held.
+func (hub *Hub) remove(f *feed, sub *subscriber) {
+	delete(f.subs, sub)
+	close(sub.send)
+	if len(f.subs) == 0 {
+		f.cancel()
+		delete(hub.feeds, f.path)
+	}
+}
+
+// run reads an upstream stream and broadcasts its messages until ctx is done,
+// reconnecting as needed.
+func (hub *Hub) run(ctx context.Context, f *feed) {
+	for ctx.Err() == nil {
+		conn, _, err := hub.Dialer.DialContext(ctx, hub.BaseURL+f.path, nil)
+		if err == nil {
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			for {
+				_, msg, err := conn.ReadMessage()
+				if err != nil {
+					break
+				}
+				if hub.Journal != nil {
+					hub.Journal.Record(f.path, time.Now(), msg)
+				}
+				hub.broadcast(f, msg)
+			}
+			stop()
+			conn.Close()
+		}
+
+		select {
+		case <-time.After(hub.ReconnectDelay):
+		case <-ctx.Done():
+		}
+	}
+}
+
+// broadcast sends a message to the subscribers of a feed it is for.
+func (hub *Hub) broadcast(f *feed, msg []byte) {
+	stock := messageStock(msg)
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for sub := range f.subs {
+		if sub.stock != "" && sub.stock != stock {
+			continue
+		}
+		select {
+		case sub.send <- msg:
+		default:
+			// too slow
+			hub.remove(f, sub)
+		}
+	}
+}
+
+// messageStock returns the stock symbol of a tickertape or executions
+// message.
+func messageStock(msg []byte) string {
+	var m struct {
+		Symbol string `json:"symbol"`
+		Quote  struct {
+			Symbol string `json:"symbol"`
+		} `json:"quote"`
+	}
+	if err := json.Unmarshal(msg, &m); err != nil {
+		return ""
+	}
+	if m.Quote.Symbol != "" {
+		return m.Quote.Symbol
+	}
+	return m.Symbol
+}
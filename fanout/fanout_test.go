@@ -0,0 +1,122 @@
+package fanout
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		upstream string
+		stock    string
+		ok       bool
+	}{
+		{"/ws/EXB123456/venues/TESTEX/tickertape", "/ws/EXB123456/venues/TESTEX/tickertape", "", true},
+		{"/ws/EXB123456/venues/TESTEX/tickertape/stocks/FOOBAR", "/ws/EXB123456/venues/TESTEX/tickertape", "FOOBAR", true},
+		{"/ws/EXB123456/venues/TESTEX/executions/stocks/FOOBAR", "/ws/EXB123456/venues/TESTEX/executions", "FOOBAR", true},
+		{"/ws/EXB123456/venues/TESTEX/orderbook", "", "", false},
+		{"/ws/EXB123456/venues/TESTEX/tickertape/symbols/FOOBAR", "", "", false},
+		{"/ob/api/venues/TESTEX/stocks", "", "", false},
+	}
+	for _, test := range tests {
+		upstream, stock, ok := parsePath(test.path)
+		assert.Equal(t, test.upstream, upstream, test.path)
+		assert.Equal(t, test.stock, stock, test.path)
+		assert.Equal(t, test.ok, ok, test.path)
+	}
+}
+
+func TestHub(t *testing.T) {
+	var dials int32
+	messages := make(chan string)
+	upgrader := websocket.Upgrader{}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ws/EXB123456/venues/TESTEX/tickertape", r.URL.Path)
+		atomic.AddInt32(&dials, 1)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for msg := range messages {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return
+			}
+		}
+	}))
+	defer upstream.Close()
+	defer close(messages)
+
+	hub := New()
+	hub.BaseURL = "ws" + strings.TrimPrefix(upstream.URL, "http")
+	server := httptest.NewServer(hub)
+	defer server.Close()
+	base := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/EXB123456/venues/TESTEX/tickertape"
+
+	venue, _, err := websocket.DefaultDialer.Dial(base, nil)
+	assert.Nil(t, err)
+	defer venue.Close()
+	stock, _, err := websocket.DefaultDialer.Dial(base+"/stocks/FOOBAR", nil)
+	assert.Nil(t, err)
+	defer stock.Close()
+
+	// both subscriptions share one upstream connection
+	waitFor(t, func() bool {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		f := hub.feeds["/ws/EXB123456/venues/TESTEX/tickertape"]
+		return f != nil && len(f.subs) == 2
+	})
+	barQuote := `{"ok": true, "quote": {"symbol": "BAR", "venue": "TESTEX", "bid": 100}}`
+	fooQuote := `{"ok": true, "quote": {"symbol": "FOOBAR", "venue": "TESTEX", "bid": 5000}}`
+	messages <- barQuote
+	messages <- fooQuote
+
+	// the subscription to a stock only receives its messages
+	for _, want := range []string{barQuote, fooQuote} {
+		_, msg, err := venue.ReadMessage()
+		assert.Nil(t, err)
+		assert.Equal(t, want, string(msg))
+	}
+	_, msg, err := stock.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, fooQuote, string(msg))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&dials))
+
+	// the upstream connection is closed with the last subscription
+	venue.Close()
+	stock.Close()
+	waitFor(t, func() bool {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		return len(hub.feeds) == 0
+	})
+}
+
+func TestHubNotFound(t *testing.T) {
+	server := httptest.NewServer(New())
+	defer server.Close()
+
+	_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/EXB123456/venues/TESTEX/orderbook", nil)
+	assert.Equal(t, websocket.ErrBadHandshake, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+// waitFor waits up to a second for cond to hold.
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
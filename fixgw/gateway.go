@@ -0,0 +1,570 @@
+/*
+Package fixgw provides a gateway between FIX 4.2 clients and the Stockfighter
+API, so that FIX-based tools and OMS prototypes can trade the game.
+
+The gateway speaks a minimal FIX subset: sessions (Logon, Heartbeat,
+TestRequest, Logout), orders (NewOrderSingle, OrderCancelRequest,
+ExecutionReport, OrderCancelReject), and market data (MarketDataRequest,
+MarketDataSnapshotFullRefresh of the best bid, offer, and last trade).
+Sequence numbers are not persisted nor checked, and there is no resend.
+
+    gw := fixgw.New(client, venue, account)
+    listener, err := net.Listen("tcp", "localhost:9878")
+    if err != nil {
+        return err
+    }
+    err = gw.Serve(ctx, listener)
+
+FIX prices are in dollars; they are rounded to cents. The venue of orders
+and market data requests is the ExDestination field, if any, or the venue of
+the gateway. The API has no executions stream the gateway could use: fills
+and market data are polled.
+*/
+package fixgw
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// DefaultCompID is the default SenderCompID of the gateway.
+const DefaultCompID = "STOCKFIGHTER"
+
+// DefaultPollInterval is the default PollInterval of the gateway.
+const DefaultPollInterval = 500 * time.Millisecond
+
+const sendingTimeFormat = "20060102-15:04:05.000"
+
+// A Gateway serves FIX sessions trading an account with a
+// stockfighter.StockfighterAPI.
+//
+// You can create a new Gateway using New function.
+type Gateway struct {
+	// Venue of the orders and market data requests without ExDestination
+	Venue string
+
+	// Account orders are placed with
+	Account string
+
+	// CompID the gateway expects as TargetCompID of the logons it accepts
+	// ("" accepts any)
+	CompID string
+
+	// Interval between polls of the open orders and market data
+	// subscriptions of a session (DefaultPollInterval if not positive)
+	PollInterval time.Duration
+
+	api stockfighter.StockfighterAPI
+}
+
+// New creates a new Gateway trading the given account on the given venue by
+// default. This never returns nil.
+func New(api stockfighter.StockfighterAPI, venue, account string) *Gateway {
+	venue = strings.TrimSpace(venue)
+	if venue == "" {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+	account = strings.TrimSpace(account)
+	if account == "" {
+		panic(fmt.Errorf("Invalid account symbol: %v", account))
+	}
+
+	return &Gateway{
+		Venue:        venue,
+		Account:      account,
+		CompID:       DefaultCompID,
+		PollInterval: DefaultPollInterval,
+		api:          api,
+	}
+}
+
+// Serve accepts connections on the listener and serves a session on each,
+// until the context is done or the listener fails. The listener is closed
+// when Serve returns.
+func (gw *Gateway) Serve(ctx context.Context, listener net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		go gw.ServeConn(ctx, conn)
+	}
+}
+
+// ServeConn serves a session on the connection, until the client logs out,
+// the context is done, or the connection fails. The connection is closed
+// when ServeConn returns.
+func (gw *Gateway) ServeConn(ctx context.Context, conn io.ReadWriteCloser) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	s := &session{
+		gw:            gw,
+		conn:          conn,
+		orders:        make(map[string]*sessionOrder),
+		subscriptions: make(map[string]*subscription),
+	}
+	reader := NewReader(conn)
+
+	m, err := reader.ReadMessage()
+	if err != nil {
+		return err
+	}
+	if err := s.logon(m); err != nil {
+		return err
+	}
+	go s.poll(ctx)
+
+	for {
+		m, err := reader.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		err = s.handle(m)
+		s.mu.Unlock()
+		if err != nil {
+			if errors.Is(err, errLogout) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+var errLogout = errors.New("logout")
+
+type sessionOrder struct {
+	clOrdID  string
+	cancelID string
+	order    stockfighter.Order
+
+	// fills reported, shares filled and their total price, and whether the
+	// order closing was reported
+	reported int
+	cum      uint64
+	notional uint64
+	done     bool
+}
+
+type subscription struct {
+	venue      string
+	symbols    []string
+	quoteTimes map[string]time.Time
+}
+
+// A session holds the state of a FIX session. Messages are handled and
+// polls are run with the session locked.
+type session struct {
+	gw   *Gateway
+	conn io.ReadWriteCloser
+
+	mu            sync.Mutex
+	senderCompID  string
+	targetCompID  string
+	heartbeat     time.Duration
+	seq           int
+	lastSent      time.Time
+	execID        int64
+	orders        map[string]*sessionOrder
+	subscriptions map[string]*subscription
+}
+
+func (s *session) logon(m Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.senderCompID, s.targetCompID = m.Get(TagTargetCompID), m.Get(TagSenderCompID)
+	if m.Type() != MsgTypeLogon {
+		s.send(NewMessage(MsgTypeLogout).Add(TagText, "Logon expected"))
+		return fmt.Errorf("Invalid FIX logon: %v", m)
+	}
+	if s.gw.CompID != "" && s.senderCompID != s.gw.CompID {
+		s.send(NewMessage(MsgTypeLogout).Add(TagText, "Unknown TargetCompID"))
+		return fmt.Errorf("Invalid FIX logon TargetCompID: %v", s.senderCompID)
+	}
+
+	heartBtInt, err := strconv.Atoi(m.Get(TagHeartBtInt))
+	if err != nil || heartBtInt < 0 {
+		s.send(NewMessage(MsgTypeLogout).Add(TagText, "Invalid HeartBtInt"))
+		return fmt.Errorf("Invalid FIX logon HeartBtInt: %v", m.Get(TagHeartBtInt))
+	}
+	s.heartbeat = time.Duration(heartBtInt) * time.Second
+
+	return s.send(NewMessage(MsgTypeLogon).Add(TagEncryptMethod, 0).Add(TagHeartBtInt, heartBtInt))
+}
+
+func (s *session) handle(m Message) error {
+	switch m.Type() {
+	case MsgTypeHeartbeat:
+		return nil
+	case MsgTypeTestRequest:
+		return s.send(NewMessage(MsgTypeHeartbeat).Add(TagTestReqID, m.Get(TagTestReqID)))
+	case MsgTypeLogout:
+		if err := s.send(NewMessage(MsgTypeLogout)); err != nil {
+			return err
+		}
+		return errLogout
+	case MsgTypeNewOrderSingle:
+		return s.newOrder(m)
+	case MsgTypeOrderCancelRequest:
+		return s.cancelOrder(m)
+	case MsgTypeMarketDataRequest:
+		return s.marketData(m)
+	default:
+		return s.send(NewMessage(MsgTypeReject).Add(TagRefSeqNum, m.Get(TagMsgSeqNum)).Add(TagText, "Unsupported MsgType "+m.Type()))
+	}
+}
+
+// send sends a message, with the header fields of the session.
+func (s *session) send(m Message) error {
+	s.seq++
+	s.lastSent = time.Now()
+	header := Message{m[0]}.Add(TagSenderCompID, s.senderCompID).Add(TagTargetCompID, s.targetCompID).Add(TagMsgSeqNum, s.seq).
+		Add(TagSendingTime, s.lastSent.UTC().Format(sendingTimeFormat))
+	return WriteMessage(s.conn, append(header, m[1:]...))
+}
+
+func (s *session) newOrder(m Message) error {
+	clOrdID := m.Get(TagClOrdID)
+	reject := func(text string) error {
+		return s.send(NewMessage(MsgTypeExecutionReport).Add(TagOrderID, "NONE").Add(TagClOrdID, clOrdID).Add(TagExecID, s.nextExecID()).
+			Add(TagExecTransType, 0).Add(TagExecType, 8).Add(TagOrdStatus, 8).Add(TagSymbol, m.Get(TagSymbol)).Add(TagSide, m.Get(TagSide)).
+			Add(TagLeavesQty, 0).Add(TagCumQty, 0).Add(TagAvgPx, 0).Add(TagText, text))
+	}
+	if clOrdID == "" {
+		return reject("Missing ClOrdID")
+	}
+	if _, ok := s.orders[clOrdID]; ok {
+		return reject("Duplicate ClOrdID")
+	}
+
+	var direction string
+	switch m.Get(TagSide) {
+	case "1":
+		direction = stockfighter.OrderDirectionBuy
+	case "2":
+		direction = stockfighter.OrderDirectionSell
+	default:
+		return reject("Unsupported Side")
+	}
+	quantity, err := strconv.ParseUint(m.Get(TagOrderQty), 10, 64)
+	if err != nil {
+		return reject("Invalid OrderQty")
+	}
+
+	var (
+		orderType string
+		price     uint64
+	)
+	switch m.Get(TagOrdType) {
+	case "1":
+		orderType = stockfighter.OrderTypeMarket
+	case "2":
+		if price, err = parsePrice(m.Get(TagPrice)); err != nil {
+			return reject("Invalid Price")
+		}
+		switch m.Get(TagTimeInForce) {
+		case "", "0", "1":
+			orderType = stockfighter.OrderTypeLimit
+		case "3":
+			orderType = stockfighter.OrderTypeImmediateOrCancel
+		case "4":
+			orderType = stockfighter.OrderTypeFillOrKill
+		default:
+			return reject("Unsupported TimeInForce")
+		}
+	default:
+		return reject("Unsupported OrdType")
+	}
+
+	order, err := s.placeOrder(s.venue(m), m.Get(TagSymbol), price, quantity, direction, orderType)
+	if err != nil {
+		return reject(err.Error())
+	}
+
+	o := &sessionOrder{clOrdID: clOrdID, order: *order}
+	s.orders[clOrdID] = o
+	if err := s.report(o, "0", nil); err != nil {
+		return err
+	}
+	return s.update(o, order)
+}
+
+func (s *session) placeOrder(venue, stock string, price, quantity uint64, direction, orderType string) (order *stockfighter.Order, err error) {
+	// the client panics on invalid symbols
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return s.gw.api.PlaceOrder(venue, stock, s.gw.Account, price, quantity, direction, orderType)
+}
+
+func (s *session) cancelOrder(m Message) error {
+	clOrdID, origClOrdID := m.Get(TagClOrdID), m.Get(TagOrigClOrdID)
+	reject := func(o *sessionOrder, reason int, text string) error {
+		reply := NewMessage(MsgTypeOrderCancelReject)
+		if o != nil {
+			reply = reply.Add(TagOrderID, o.order.OrderID).Add(TagOrdStatus, o.ordStatus())
+		} else {
+			reply = reply.Add(TagOrderID, "NONE").Add(TagOrdStatus, 8)
+		}
+		return s.send(reply.Add(TagClOrdID, clOrdID).Add(TagOrigClOrdID, origClOrdID).Add(TagCxlRejResponseTo, 1).
+			Add(TagCxlRejReason, reason).Add(TagText, text))
+	}
+
+	o, ok := s.orders[origClOrdID]
+	if !ok {
+		return reject(nil, 1, "Unknown order")
+	}
+	if o.done {
+		return reject(o, 0, "Too late to cancel")
+	}
+
+	order, err := s.gw.api.CancelOrder(o.order.Venue, o.order.Symbol, o.order.OrderID)
+	if err != nil {
+		return reject(o, 0, err.Error())
+	}
+	o.cancelID = clOrdID
+	return s.update(o, order)
+}
+
+func (s *session) marketData(m Message) error {
+	mdReqID := m.Get(TagMDReqID)
+	switch m.Get(TagSubscriptionRequestType) {
+	case "0", "1":
+	case "2":
+		delete(s.subscriptions, mdReqID)
+		return nil
+	default:
+		return s.send(NewMessage(MsgTypeMarketDataReject).Add(TagMDReqID, mdReqID).Add(TagText, "Unsupported SubscriptionRequestType"))
+	}
+
+	sub := &subscription{venue: s.venue(m), symbols: m.GetAll(TagSymbol), quoteTimes: make(map[string]time.Time)}
+	if len(sub.symbols) == 0 {
+		return s.send(NewMessage(MsgTypeMarketDataReject).Add(TagMDReqID, mdReqID).Add(TagText, "Missing Symbol"))
+	}
+	for _, symbol := range sub.symbols {
+		quote, err := s.getQuote(sub.venue, symbol)
+		if err != nil {
+			return s.send(NewMessage(MsgTypeMarketDataReject).Add(TagMDReqID, mdReqID).Add(TagText, err.Error()))
+		}
+		sub.quoteTimes[symbol] = quote.QuoteTime
+		if err := s.snapshot(mdReqID, symbol, quote); err != nil {
+			return err
+		}
+	}
+	if m.Get(TagSubscriptionRequestType) == "1" {
+		s.subscriptions[mdReqID] = sub
+	}
+	return nil
+}
+
+func (s *session) getQuote(venue, stock string) (quote *stockfighter.Quote, err error) {
+	// the client panics on invalid symbols
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return s.gw.api.GetQuote(venue, stock)
+}
+
+func (s *session) snapshot(mdReqID, symbol string, quote *stockfighter.Quote) error {
+	var entries Message
+	if quote.HasBid {
+		entries = entries.Add(TagMDEntryType, 0).Add(TagMDEntryPx, formatPrice(quote.BidPrice)).Add(TagMDEntrySize, quote.BidSize)
+	}
+	if quote.HasAsk {
+		entries = entries.Add(TagMDEntryType, 1).Add(TagMDEntryPx, formatPrice(quote.AskPrice)).Add(TagMDEntrySize, quote.AskSize)
+	}
+	if !quote.LastTradeTime.IsZero() {
+		entries = entries.Add(TagMDEntryType, 2).Add(TagMDEntryPx, formatPrice(quote.LastPrice)).Add(TagMDEntrySize, quote.LastSize)
+	}
+	m := NewMessage(MsgTypeMarketDataSnapshot).Add(TagMDReqID, mdReqID).Add(TagSymbol, symbol).Add(TagNoMDEntries, len(entries)/3)
+	return s.send(append(m, entries...))
+}
+
+// pollInterval returns the PollInterval of the gateway, or
+// DefaultPollInterval if it is not positive.
+func (gw *Gateway) pollInterval() time.Duration {
+	if gw.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+	return gw.PollInterval
+}
+
+// poll polls the open orders and market data subscriptions, and sends
+// heartbeats, until the context is done. The connection is closed on
+// errors.
+func (s *session) poll(ctx context.Context) {
+	ticker := time.NewTicker(s.gw.pollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		err := s.pollOnce()
+		s.mu.Unlock()
+		if err != nil {
+			s.conn.Close()
+			return
+		}
+	}
+}
+
+func (s *session) pollOnce() error {
+	for _, o := range s.orders {
+		if o.done {
+			continue
+		}
+		order, err := s.gw.api.GetOrder(o.order.Venue, o.order.Symbol, o.order.OrderID)
+		if err != nil {
+			continue
+		}
+		if err := s.update(o, order); err != nil {
+			return err
+		}
+	}
+
+	for mdReqID, sub := range s.subscriptions {
+		for _, symbol := range sub.symbols {
+			quote, err := s.getQuote(sub.venue, symbol)
+			if err != nil || quote.QuoteTime.Equal(sub.quoteTimes[symbol]) {
+				continue
+			}
+			sub.quoteTimes[symbol] = quote.QuoteTime
+			if err := s.snapshot(mdReqID, symbol, quote); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.heartbeat > 0 && time.Since(s.lastSent) >= s.heartbeat {
+		return s.send(NewMessage(MsgTypeHeartbeat))
+	}
+	return nil
+}
+
+// update reports the new fills of an order, and its closing.
+func (s *session) update(o *sessionOrder, order *stockfighter.Order) error {
+	o.order = *order
+	for o.reported < len(order.Fills) {
+		fill := order.Fills[o.reported]
+		o.reported++
+		o.cum += fill.Quantity
+		o.notional += fill.Price * fill.Quantity
+
+		execType := "1"
+		if o.cum >= order.OriginalQuantity {
+			execType = "2"
+			o.done = true
+		}
+		if err := s.report(o, execType, &fill); err != nil {
+			return err
+		}
+	}
+
+	if !order.Open && !o.done {
+		o.done = true
+		return s.report(o, "4", nil)
+	}
+	return nil
+}
+
+// report sends an execution report of an order, with the given fill if
+// any.
+func (s *session) report(o *sessionOrder, execType string, fill *stockfighter.OrderFillInfo) error {
+	side := "1"
+	if o.order.Direction == stockfighter.OrderDirectionSell {
+		side = "2"
+	}
+	ordType := "2"
+	if o.order.OrderType == stockfighter.OrderTypeMarket {
+		ordType = "1"
+	}
+	var leaves uint64
+	if !o.done && o.cum < o.order.OriginalQuantity {
+		leaves = o.order.OriginalQuantity - o.cum
+	}
+	var avgPx uint64
+	if o.cum > 0 {
+		avgPx = o.notional / o.cum
+	}
+
+	m := NewMessage(MsgTypeExecutionReport).Add(TagOrderID, o.order.OrderID)
+	if execType == "4" && o.cancelID != "" {
+		m = m.Add(TagClOrdID, o.cancelID).Add(TagOrigClOrdID, o.clOrdID)
+	} else {
+		m = m.Add(TagClOrdID, o.clOrdID)
+	}
+	m = m.Add(TagExecID, s.nextExecID()).Add(TagExecTransType, 0).Add(TagExecType, execType).Add(TagOrdStatus, o.ordStatus()).
+		Add(TagSymbol, o.order.Symbol).Add(TagSide, side).Add(TagOrderQty, o.order.OriginalQuantity).Add(TagOrdType, ordType).
+		Add(TagPrice, formatPrice(o.order.Price))
+	transactTime := time.Now()
+	if fill != nil {
+		m = m.Add(TagLastShares, fill.Quantity).Add(TagLastPx, formatPrice(fill.Price))
+		transactTime = fill.Timestamp
+	}
+	m = m.Add(TagLeavesQty, leaves).Add(TagCumQty, o.cum).Add(TagAvgPx, formatPrice(avgPx)).
+		Add(TagTransactTime, transactTime.UTC().Format(sendingTimeFormat))
+	return s.send(m)
+}
+
+func (o *sessionOrder) ordStatus() string {
+	switch {
+	case o.cum >= o.order.OriginalQuantity:
+		return "2"
+	case o.done:
+		return "4"
+	case o.cum > 0:
+		return "1"
+	default:
+		return "0"
+	}
+}
+
+func (s *session) nextExecID() string {
+	s.execID++
+	return strconv.FormatInt(s.execID, 10)
+}
+
+func (s *session) venue(m Message) string {
+	if venue := m.Get(TagExDestination); venue != "" {
+		return venue
+	}
+	return s.gw.Venue
+}
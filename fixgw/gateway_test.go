@@ -0,0 +1,152 @@
+package fixgw
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+	"gpk.io/stockfighter/stockfightertest"
+)
+
+func TestGateway(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+
+	var (
+		mu     sync.Mutex
+		filled bool
+	)
+	api := &stockfightertest.API{
+		PlaceOrderFunc: func(venue, stock, account string, price, quantity uint64, direction, orderType string) (*stockfighter.Order, error) {
+			return &stockfighter.Order{Venue: venue, Symbol: stock, Direction: direction, OriginalQuantity: quantity, Quantity: quantity,
+				Price: price, OrderType: orderType, OrderID: 42, Account: account, Open: true}, nil
+		},
+		GetOrderFunc: func(venue, stock string, orderID int64) (*stockfighter.Order, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			order := &stockfighter.Order{Venue: venue, Symbol: stock, Direction: stockfighter.OrderDirectionBuy, OriginalQuantity: 100,
+				Quantity: 100, Price: 5010, OrderType: stockfighter.OrderTypeLimit, OrderID: orderID, Open: true}
+			if filled {
+				order.Quantity, order.TotalFilled = 60, 40
+				order.Fills = []stockfighter.OrderFillInfo{{Price: 5000, Quantity: 40, Timestamp: ts}}
+			}
+			return order, nil
+		},
+		CancelOrderFunc: func(venue, stock string, orderID int64) (*stockfighter.Order, error) {
+			return &stockfighter.Order{Venue: venue, Symbol: stock, Direction: stockfighter.OrderDirectionBuy, OriginalQuantity: 100,
+				Price: 5010, OrderType: stockfighter.OrderTypeLimit, OrderID: orderID, TotalFilled: 40,
+				Fills: []stockfighter.OrderFillInfo{{Price: 5000, Quantity: 40, Timestamp: ts}}}, nil
+		},
+		GetQuoteFunc: func(venue, stock string) (*stockfighter.Quote, error) {
+			return &stockfighter.Quote{Venue: venue, Symbol: stock, HasBid: true, BidPrice: 5000, BidSize: 10, LastPrice: 5005, LastSize: 3,
+				LastTradeTime: ts, QuoteTime: ts}, nil
+		},
+	}
+
+	gw := New(api, "TESTEX", "EXB123456")
+	gw.PollInterval = 10 * time.Millisecond
+	client, server := net.Pipe()
+	defer client.Close()
+	done := make(chan error)
+	go func() { done <- gw.ServeConn(context.Background(), server) }()
+
+	reader := NewReader(client)
+	roundTrip := func(m Message) Message {
+		assert.Nil(t, WriteMessage(client, m))
+		reply, err := reader.ReadMessage()
+		assert.Nil(t, err)
+		return reply
+	}
+
+	reply := roundTrip(NewMessage(MsgTypeLogon).Add(TagSenderCompID, "CLIENT").Add(TagTargetCompID, DefaultCompID).Add(TagMsgSeqNum, 1).
+		Add(TagEncryptMethod, 0).Add(TagHeartBtInt, 30))
+	assert.Equal(t, MsgTypeLogon, reply.Type())
+	assert.Equal(t, DefaultCompID, reply.Get(TagSenderCompID))
+	assert.Equal(t, "CLIENT", reply.Get(TagTargetCompID))
+	assert.Equal(t, "1", reply.Get(TagMsgSeqNum))
+
+	reply = roundTrip(NewMessage(MsgTypeTestRequest).Add(TagTestReqID, "T1"))
+	assert.Equal(t, MsgTypeHeartbeat, reply.Type())
+	assert.Equal(t, "T1", reply.Get(TagTestReqID))
+
+	// market data snapshot
+	reply = roundTrip(NewMessage(MsgTypeMarketDataRequest).Add(TagMDReqID, "M1").Add(TagSubscriptionRequestType, 0).
+		Add(TagNoRelatedSym, 1).Add(TagSymbol, "FOOBAR"))
+	assert.Equal(t, MsgTypeMarketDataSnapshot, reply.Type())
+	assert.Equal(t, "2", reply.Get(TagNoMDEntries))
+	assert.Equal(t, []string{"0", "2"}, reply.GetAll(TagMDEntryType))
+	assert.Equal(t, []string{"50.00", "50.05"}, reply.GetAll(TagMDEntryPx))
+
+	// rejected order
+	reply = roundTrip(NewMessage(MsgTypeNewOrderSingle).Add(TagClOrdID, "C0").Add(TagSymbol, "FOOBAR").Add(TagSide, 5).
+		Add(TagOrderQty, 100).Add(TagOrdType, 2).Add(TagPrice, "50.10"))
+	assert.Equal(t, "8", reply.Get(TagExecType))
+	assert.Equal(t, "Unsupported Side", reply.Get(TagText))
+
+	// new order, then a partial fill seen by polling
+	reply = roundTrip(NewMessage(MsgTypeNewOrderSingle).Add(TagClOrdID, "C1").Add(TagSymbol, "FOOBAR").Add(TagSide, 1).
+		Add(TagOrderQty, 100).Add(TagOrdType, 2).Add(TagPrice, "50.10").Add(TagTimeInForce, 0))
+	assert.Equal(t, "0", reply.Get(TagExecType))
+	assert.Equal(t, "42", reply.Get(TagOrderID))
+	assert.Equal(t, "100", reply.Get(TagLeavesQty))
+	assert.Equal(t, []interface{}{"TESTEX", "FOOBAR", "EXB123456", uint64(5010), uint64(100), stockfighter.OrderDirectionBuy,
+		stockfighter.OrderTypeLimit}, api.CallsTo("PlaceOrder")[0].Args)
+
+	mu.Lock()
+	filled = true
+	mu.Unlock()
+	reply, err := reader.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, "1", reply.Get(TagExecType))
+	assert.Equal(t, "1", reply.Get(TagOrdStatus))
+	assert.Equal(t, "40", reply.Get(TagLastShares))
+	assert.Equal(t, "50.00", reply.Get(TagLastPx))
+	assert.Equal(t, "60", reply.Get(TagLeavesQty))
+
+	// cancel
+	reply = roundTrip(NewMessage(MsgTypeOrderCancelRequest).Add(TagClOrdID, "C2").Add(TagOrigClOrdID, "C1").Add(TagSymbol, "FOOBAR").
+		Add(TagSide, 1))
+	assert.Equal(t, MsgTypeExecutionReport, reply.Type())
+	assert.Equal(t, "4", reply.Get(TagExecType))
+	assert.Equal(t, "C2", reply.Get(TagClOrdID))
+	assert.Equal(t, "C1", reply.Get(TagOrigClOrdID))
+	assert.Equal(t, "0", reply.Get(TagLeavesQty))
+	assert.Equal(t, "40", reply.Get(TagCumQty))
+
+	reply = roundTrip(NewMessage(MsgTypeOrderCancelRequest).Add(TagClOrdID, "C3").Add(TagOrigClOrdID, "C1"))
+	assert.Equal(t, MsgTypeOrderCancelReject, reply.Type())
+	assert.Equal(t, "Too late to cancel", reply.Get(TagText))
+
+	reply = roundTrip(NewMessage(MsgTypeLogout))
+	assert.Equal(t, MsgTypeLogout, reply.Type())
+	assert.Nil(t, <-done)
+
+	assert.Panics(t, func() { New(api, "", "EXB123456") })
+}
+
+func TestGatewayLogon(t *testing.T) {
+	gw := New(&stockfightertest.API{}, "TESTEX", "EXB123456")
+	client, server := net.Pipe()
+	defer client.Close()
+	done := make(chan error)
+	go func() { done <- gw.ServeConn(context.Background(), server) }()
+
+	assert.Nil(t, WriteMessage(client, NewMessage(MsgTypeLogon).Add(TagSenderCompID, "CLIENT").Add(TagTargetCompID, "OTHER").
+		Add(TagHeartBtInt, 30)))
+	reply, err := NewReader(client).ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, MsgTypeLogout, reply.Type())
+	assert.EqualError(t, <-done, "Invalid FIX logon TargetCompID: OTHER")
+}
+
+func TestGatewayPollInterval(t *testing.T) {
+	gw := New(&stockfightertest.API{}, "TESTEX", "EXB123456")
+	assert.Equal(t, DefaultPollInterval, gw.pollInterval())
+	gw.PollInterval = 0
+	assert.Equal(t, DefaultPollInterval, gw.pollInterval())
+	gw.PollInterval = time.Second
+	assert.Equal(t, time.Second, gw.pollInterval())
+}
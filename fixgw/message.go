@@ -0,0 +1,271 @@
+package fixgw
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// BeginString is the FIX version spoken by the gateway.
+const BeginString = "FIX.4.2"
+
+// FIX tags used by the gateway.
+const (
+	TagAvgPx                   = 6
+	TagBeginString             = 8
+	TagBodyLength              = 9
+	TagCheckSum                = 10
+	TagClOrdID                 = 11
+	TagCumQty                  = 14
+	TagExecID                  = 17
+	TagExecTransType           = 20
+	TagLastPx                  = 31
+	TagLastShares              = 32
+	TagMsgSeqNum               = 34
+	TagMsgType                 = 35
+	TagOrderID                 = 37
+	TagOrderQty                = 38
+	TagOrdStatus               = 39
+	TagOrdType                 = 40
+	TagOrigClOrdID             = 41
+	TagPrice                   = 44
+	TagRefSeqNum               = 45
+	TagSenderCompID            = 49
+	TagSendingTime             = 52
+	TagSide                    = 54
+	TagSymbol                  = 55
+	TagTargetCompID            = 56
+	TagText                    = 58
+	TagTimeInForce             = 59
+	TagTransactTime            = 60
+	TagEncryptMethod           = 98
+	TagExDestination           = 100
+	TagCxlRejReason            = 102
+	TagHeartBtInt              = 108
+	TagTestReqID               = 112
+	TagNoRelatedSym            = 146
+	TagExecType                = 150
+	TagLeavesQty               = 151
+	TagMDReqID                 = 262
+	TagSubscriptionRequestType = 263
+	TagNoMDEntries             = 268
+	TagMDEntryType             = 269
+	TagMDEntryPx               = 270
+	TagMDEntrySize             = 271
+	TagCxlRejResponseTo        = 434
+)
+
+// FIX message types used by the gateway.
+const (
+	MsgTypeHeartbeat          = "0"
+	MsgTypeTestRequest        = "1"
+	MsgTypeReject             = "3"
+	MsgTypeLogout             = "5"
+	MsgTypeExecutionReport    = "8"
+	MsgTypeOrderCancelReject  = "9"
+	MsgTypeLogon              = "A"
+	MsgTypeNewOrderSingle     = "D"
+	MsgTypeOrderCancelRequest = "F"
+	MsgTypeMarketDataRequest  = "V"
+	MsgTypeMarketDataSnapshot = "W"
+	MsgTypeMarketDataReject   = "Y"
+)
+
+const soh = '\x01'
+
+// A Field represents a tag=value field of a FIX message.
+type Field struct {
+	Tag   int
+	Value string
+}
+
+// A Message represents a FIX message, as its fields in order. Repeating
+// groups are kept as is, their fields following the count field.
+//
+// Messages written with WriteMessage need not have the BeginString,
+// BodyLength, and CheckSum fields: they are added.
+type Message []Field
+
+// NewMessage creates a new message of the given type.
+func NewMessage(msgType string) Message {
+	return Message{{TagMsgType, msgType}}
+}
+
+// Type returns the message type.
+func (m Message) Type() string {
+	return m.Get(TagMsgType)
+}
+
+// Get returns the value of the first field with the given tag, or "" if
+// there is none.
+func (m Message) Get(tag int) string {
+	for _, field := range m {
+		if field.Tag == tag {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+// GetAll returns the values of the fields with the given tag, e.g. the
+// symbols of a repeating group.
+func (m Message) GetAll(tag int) []string {
+	var values []string
+	for _, field := range m {
+		if field.Tag == tag {
+			values = append(values, field.Value)
+		}
+	}
+	return values
+}
+
+// Add returns the message with a field appended.
+func (m Message) Add(tag int, value interface{}) Message {
+	return append(m, Field{tag, fmt.Sprint(value)})
+}
+
+func (m Message) String() string {
+	var buf bytes.Buffer
+	for i, field := range m {
+		if i > 0 {
+			buf.WriteByte('|')
+		}
+		fmt.Fprintf(&buf, "%v=%v", field.Tag, field.Value)
+	}
+	return buf.String()
+}
+
+// WriteMessage writes the message to w, with its BeginString, BodyLength,
+// and CheckSum fields.
+func WriteMessage(w io.Writer, m Message) error {
+	var body bytes.Buffer
+	for _, field := range m {
+		switch field.Tag {
+		case TagBeginString, TagBodyLength, TagCheckSum:
+			continue
+		}
+		fmt.Fprintf(&body, "%v=%v%c", field.Tag, field.Value, soh)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v=%v%c%v=%v%c", TagBeginString, BeginString, soh, TagBodyLength, body.Len(), soh)
+	buf.Write(body.Bytes())
+	fmt.Fprintf(&buf, "%v=%03d%c", TagCheckSum, checksum(buf.Bytes()), soh)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// A Reader reads FIX messages from a stream.
+//
+// You can create a new Reader using NewReader function.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader creates a new Reader reading from r. This never returns nil.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// ReadMessage reads the next message, checking its BeginString, BodyLength,
+// and CheckSum fields. ReadMessage returns io.EOF at the end of the stream.
+func (reader *Reader) ReadMessage() (Message, error) {
+	var raw bytes.Buffer
+
+	begin, err := reader.readField(&raw)
+	if err != nil {
+		return nil, err
+	}
+	if begin.Tag != TagBeginString || begin.Value != BeginString {
+		return nil, fmt.Errorf("Invalid FIX BeginString: %v", begin)
+	}
+	length, err := reader.readField(&raw)
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	n, err := strconv.Atoi(length.Value)
+	if length.Tag != TagBodyLength || err != nil || n < 0 {
+		return nil, fmt.Errorf("Invalid FIX BodyLength: %v", length)
+	}
+
+	body := make([]byte, n)
+	if _, err := io.ReadFull(reader.r, body); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	raw.Write(body)
+	sum := checksum(raw.Bytes())
+
+	trailer, err := reader.readField(&raw)
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if trailer.Tag != TagCheckSum || trailer.Value != fmt.Sprintf("%03d", sum) {
+		return nil, fmt.Errorf("Invalid FIX CheckSum: %v (expected %03d)", trailer, sum)
+	}
+
+	m := Message{begin, length}
+	for _, data := range bytes.Split(bytes.TrimSuffix(body, []byte{soh}), []byte{soh}) {
+		field, err := parseField(data)
+		if err != nil {
+			return nil, err
+		}
+		m = append(m, field)
+	}
+	return append(m, trailer), nil
+}
+
+func (reader *Reader) readField(raw *bytes.Buffer) (Field, error) {
+	data, err := reader.r.ReadBytes(soh)
+	if err != nil {
+		if err == io.EOF && len(data) > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+		return Field{}, err
+	}
+	raw.Write(data)
+	return parseField(data[:len(data)-1])
+}
+
+func parseField(data []byte) (Field, error) {
+	i := bytes.IndexByte(data, '=')
+	if i <= 0 {
+		return Field{}, fmt.Errorf("Invalid FIX field: %q", data)
+	}
+	tag, err := strconv.Atoi(string(data[:i]))
+	if err != nil {
+		return Field{}, fmt.Errorf("Invalid FIX field: %q", data)
+	}
+	return Field{tag, string(data[i+1:])}, nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func checksum(data []byte) int {
+	var sum int
+	for _, b := range data {
+		sum += int(b)
+	}
+	return sum % 256
+}
+
+// formatPrice formats a price in cents as a FIX price, in dollars.
+func formatPrice(cents uint64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+// parsePrice parses a FIX price, in dollars, to cents.
+func parsePrice(s string) (uint64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("Invalid FIX price: %q", s)
+	}
+	return uint64(v*100 + 0.5), nil
+}
@@ -0,0 +1,52 @@
+package fixgw
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewMessage(MsgTypeHeartbeat).Add(TagSenderCompID, "CLIENT").Add(TagTestReqID, "T1")
+	assert.Nil(t, WriteMessage(&buf, m))
+	assert.Equal(t, "8=FIX.4.2|9=22|35=0|49=CLIENT|112=T1|10=145|", strings.Replace(buf.String(), "\x01", "|", -1))
+
+	buf.WriteString("8=FIX.4.2\x019=5\x0135=0\x0110=000\x01")
+	reader := NewReader(&buf)
+	m, err := reader.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, MsgTypeHeartbeat, m.Type())
+	assert.Equal(t, "T1", m.Get(TagTestReqID))
+	assert.Equal(t, "", m.Get(TagText))
+	assert.Equal(t, "8=FIX.4.2|9=22|35=0|49=CLIENT|112=T1|10=145", m.String())
+
+	_, err = reader.ReadMessage()
+	assert.EqualError(t, err, "Invalid FIX CheckSum: {10 000} (expected 161)")
+	_, err = reader.ReadMessage()
+	assert.Equal(t, io.EOF, err)
+
+	_, err = NewReader(strings.NewReader("8=FIX.4.2\x019=25\x0135=0")).ReadMessage()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	_, err = NewReader(strings.NewReader("8=FIX.4.4\x01")).ReadMessage()
+	assert.EqualError(t, err, "Invalid FIX BeginString: {8 FIX.4.4}")
+}
+
+func TestPrice(t *testing.T) {
+	assert.Equal(t, "50.05", formatPrice(5005))
+	assert.Equal(t, "0.00", formatPrice(0))
+
+	price, err := parsePrice("50.1")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5010), price)
+	price, err = parsePrice("20.29")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2029), price)
+	_, err = parsePrice("-1")
+	assert.NotNil(t, err)
+	_, err = parsePrice("")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,150 @@
+package stockfighter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// A Level represents a running instance of a level, as returned by the GM
+// API when the instance is started, restarted, or resumed.
+type Level struct {
+	// Instance ID, used to restart, resume, stop, or get the status of the
+	// instance
+	InstanceID int64 `json:"instanceId"`
+
+	// Trading account, venues, and stock symbols of the instance
+	Account string   `json:"account"`
+	Venues  []string `json:"venues"`
+	Tickers []string `json:"tickers"`
+
+	// Level instructions, keyed by title
+	Instructions map[string]string `json:"instructions"`
+
+	// Duration of a trading day, in seconds
+	SecondsPerTradingDay int `json:"secondsPerTradingDay"`
+}
+
+// A LevelStatus represents the status of a level instance.
+type LevelStatus struct {
+	InstanceID int64  `json:"id"`
+	Done       bool   `json:"done"`
+	State      string `json:"state"`
+
+	// Current and last trading days of the instance
+	TradingDay       int `json:"tradingDay"`
+	EndOfTheWorldDay int `json:"endOfTheWorldDay"`
+
+	// Message shown to the player, if any
+	Flash string `json:"flash"`
+}
+
+// callGM makes a GM API request accounted to the subsystem of the client.
+func (client *Client) callGM(method, apiPath string, respBody interface{}) (*apiResponse, error) {
+	client.usage.add(client.subsystem)
+	return client.transport.do(apiRequest{ctx: client.ctx, method: method, path: apiPath, baseURL: client.transport.gmBaseURL, subsystem: client.subsystem}, respBody)
+}
+
+// StartLevel starts a new instance of a level, e.g. "first_steps".
+//
+// Stockfighter GM API:
+//     POST https://www.stockfighter.io/gm/levels/:level
+func (client *Client) StartLevel(level string) (*Level, error) {
+	level = strings.TrimSpace(level)
+	if !validSymbol(level) {
+		panic(fmt.Errorf("Invalid level name: %v", level))
+	}
+
+	return client.levelRequest("/levels/" + url.PathEscape(level))
+}
+
+// RestartLevel restarts a level instance from scratch.
+//
+// Stockfighter GM API:
+//     POST https://www.stockfighter.io/gm/instances/:id/restart
+func (client *Client) RestartLevel(instanceID int64) (*Level, error) {
+	return client.levelRequest("/instances/" + strconv.FormatInt(instanceID, 10) + "/restart")
+}
+
+// ResumeLevel resumes a level instance, e.g. after a disconnection.
+//
+// Stockfighter GM API:
+//     POST https://www.stockfighter.io/gm/instances/:id/resume
+func (client *Client) ResumeLevel(instanceID int64) (*Level, error) {
+	return client.levelRequest("/instances/" + strconv.FormatInt(instanceID, 10) + "/resume")
+}
+
+func (client *Client) levelRequest(apiPath string) (*Level, error) {
+	var resp apiRespLevel
+	reply, err := client.callGM("POST", apiPath, &resp)
+	switch {
+	case err != nil:
+		return nil, err
+	case reply.statusCode == 401: // unauthorized
+		return nil, &ErrorUnauthorized{}
+	}
+
+	if !resp.OK {
+		return nil, errors.New(resp.Error)
+	}
+
+	return &Level{
+		InstanceID:           resp.InstanceID,
+		Account:              resp.Account,
+		Venues:               resp.Venues,
+		Tickers:              resp.Tickers,
+		Instructions:         resp.Instructions,
+		SecondsPerTradingDay: resp.SecondsPerTradingDay,
+	}, nil
+}
+
+// StopLevel stops a level instance.
+//
+// Stockfighter GM API:
+//     POST https://www.stockfighter.io/gm/instances/:id/stop
+func (client *Client) StopLevel(instanceID int64) error {
+	var resp apiRespHeartbeat
+	reply, err := client.callGM("POST", "/instances/"+strconv.FormatInt(instanceID, 10)+"/stop", &resp)
+	switch {
+	case err != nil:
+		return err
+	case reply.statusCode == 401: // unauthorized
+		return &ErrorUnauthorized{}
+	}
+
+	if !resp.OK {
+		return errors.New(resp.Error)
+	}
+
+	return nil
+}
+
+// GetLevelStatus returns the status of a level instance.
+//
+// Stockfighter GM API:
+//     GET https://www.stockfighter.io/gm/instances/:id
+func (client *Client) GetLevelStatus(instanceID int64) (*LevelStatus, error) {
+	var resp apiRespLevelStatus
+	reply, err := client.callGM("GET", "/instances/"+strconv.FormatInt(instanceID, 10), &resp)
+	switch {
+	case err != nil:
+		return nil, err
+	case reply.statusCode == 401: // unauthorized
+		return nil, &ErrorUnauthorized{}
+	}
+
+	if !resp.OK {
+		return nil, errors.New(resp.Error)
+	}
+
+	return &LevelStatus{
+		InstanceID:       resp.InstanceID,
+		Done:             resp.Done,
+		State:            resp.State,
+		TradingDay:       resp.Details.TradingDay,
+		EndOfTheWorldDay: resp.Details.EndOfTheWorldDay,
+		Flash:            resp.Flash.message(),
+	}, nil
+}
@@ -0,0 +1,63 @@
+package stockfighter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevels(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/gm/instances/1234":
+			w.Write([]byte(`{"ok": true, "id": 1234, "done": false, "state": "open",
+				"details": {"tradingDay": 3, "endOfTheWorldDay": 100}, "flash": {"info": "Good luck"}}`))
+		case "/gm/instances/1234/stop":
+			w.Write([]byte(`{"ok": true}`))
+		case "/gm/instances/9999/resume":
+			w.Write([]byte(`{"ok": false, "error": "No such instance"}`))
+		default:
+			w.Write([]byte(`{"ok": true, "instanceId": 1234, "account": "EXB123456", "venues": ["TESTEX"], "tickers": ["FOOBAR"],
+				"instructions": {"Instructions": "Buy 100 shares"}, "secondsPerTradingDay": 5}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(testApiKey, WithGMBaseURL(server.URL+"/gm/"))
+
+	level, err := client.StartLevel("first_steps")
+	assert.Nil(t, err)
+	assert.Equal(t, &Level{
+		InstanceID:           1234,
+		Account:              "EXB123456",
+		Venues:               []string{"TESTEX"},
+		Tickers:              []string{"FOOBAR"},
+		Instructions:         map[string]string{"Instructions": "Buy 100 shares"},
+		SecondsPerTradingDay: 5,
+	}, level)
+
+	_, err = client.RestartLevel(1234)
+	assert.Nil(t, err)
+
+	status, err := client.GetLevelStatus(1234)
+	assert.Nil(t, err)
+	assert.Equal(t, &LevelStatus{InstanceID: 1234, State: "open", TradingDay: 3, EndOfTheWorldDay: 100, Flash: "Good luck"}, status)
+
+	assert.Nil(t, client.StopLevel(1234))
+
+	_, err = client.ResumeLevel(9999)
+	assert.EqualError(t, err, "No such instance")
+
+	assert.Equal(t, []string{
+		"POST /gm/levels/first_steps",
+		"POST /gm/instances/1234/restart",
+		"GET /gm/instances/1234",
+		"POST /gm/instances/1234/stop",
+		"POST /gm/instances/9999/resume",
+	}, requests)
+
+	assert.Panics(t, func() { client.StartLevel(" ") })
+}
@@ -0,0 +1,281 @@
+/*
+Package grpcsvc provides a gRPC façade over a stockfighter.Client, so that
+non-Go components (research scripts, dashboards) can place orders and
+subscribe to quotes through a single authenticated gateway holding the API
+key.
+
+The service is defined in pb/stockfighter.proto; after changing it, run go
+generate in package pb to regenerate its Go code.
+
+    client := stockfighter.NewClient(apiKey)
+    server := grpc.NewServer(grpcsvc.Auth(token)...)
+    grpcsvc.NewServer(client).Register(server)
+    err := server.Serve(listener)
+*/
+package grpcsvc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gpk.io/stockfighter"
+	"gpk.io/stockfighter/grpcsvc/pb"
+)
+
+// A Server implements the Stockfighter gRPC service with a Client.
+//
+// You can create a new Server using NewServer function.
+type Server struct {
+	pb.UnimplementedStockfighterServer
+
+	client *stockfighter.Client
+}
+
+// NewServer creates a new Server making API requests with client, with the
+// context of each call: canceled calls cancel their requests. This never
+// returns nil.
+func NewServer(client *stockfighter.Client) *Server {
+	return &Server{client: client}
+}
+
+// Register registers the service on a gRPC server.
+func (s *Server) Register(server *grpc.Server) {
+	pb.RegisterStockfighterServer(server, s)
+}
+
+// Auth returns the server options requiring every call to carry the given
+// token in an "authorization: Bearer TOKEN" metadata entry.
+func Auth(token string) []grpc.ServerOption {
+	check := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, value := range md.Get("authorization") {
+			given, ok := strings.CutPrefix(value, "Bearer ")
+			if ok && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 {
+				return nil
+			}
+		}
+		return status.Error(codes.Unauthenticated, "invalid or missing token")
+	}
+
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := check(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := check(stream.Context()); err != nil {
+				return err
+			}
+			return handler(srv, stream)
+		}),
+	}
+}
+
+// ListStocks implements pb.StockfighterServer.
+func (s *Server) ListStocks(ctx context.Context, req *pb.VenueRequest) (resp *pb.StockList, err error) {
+	defer recoverInvalid(&err)
+	client := s.client.WithContext(ctx)
+
+	stocks, err := client.ListStocks(req.Venue)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp = &pb.StockList{}
+	for _, stock := range stocks {
+		resp.Stocks = append(resp.Stocks, &pb.StockInfo{Symbol: stock.Symbol, Name: stock.Name})
+	}
+	return resp, nil
+}
+
+// GetQuote implements pb.StockfighterServer.
+func (s *Server) GetQuote(ctx context.Context, req *pb.StockRequest) (resp *pb.Quote, err error) {
+	defer recoverInvalid(&err)
+	client := s.client.WithContext(ctx)
+
+	quote, err := client.GetQuote(req.Venue, req.Stock)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toQuote(quote), nil
+}
+
+// GetOrderbook implements pb.StockfighterServer.
+func (s *Server) GetOrderbook(ctx context.Context, req *pb.StockRequest) (resp *pb.Orderbook, err error) {
+	defer recoverInvalid(&err)
+	client := s.client.WithContext(ctx)
+
+	book, err := client.GetOrderbook(req.Venue, req.Stock)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp = &pb.Orderbook{Venue: book.Venue, Symbol: book.Symbol, Timestamp: toTimestamp(book.Timestamp)}
+	for _, entry := range book.Bids {
+		resp.Bids = append(resp.Bids, &pb.OrderbookEntry{Price: entry.Price, Quantity: entry.Quantity, IsBuy: entry.IsBuy})
+	}
+	for _, entry := range book.Asks {
+		resp.Asks = append(resp.Asks, &pb.OrderbookEntry{Price: entry.Price, Quantity: entry.Quantity, IsBuy: entry.IsBuy})
+	}
+	return resp, nil
+}
+
+// PlaceOrder implements pb.StockfighterServer.
+func (s *Server) PlaceOrder(ctx context.Context, req *pb.OrderRequest) (resp *pb.Order, err error) {
+	defer recoverInvalid(&err)
+	client := s.client.WithContext(ctx)
+
+	order, err := client.PlaceOrder(req.Venue, req.Stock, req.Account, req.Price, req.Quantity, req.Direction, req.OrderType)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toOrder(order), nil
+}
+
+// GetOrder implements pb.StockfighterServer.
+func (s *Server) GetOrder(ctx context.Context, req *pb.OrderIDRequest) (resp *pb.Order, err error) {
+	defer recoverInvalid(&err)
+	client := s.client.WithContext(ctx)
+
+	order, err := client.GetOrder(req.Venue, req.Stock, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toOrder(order), nil
+}
+
+// CancelOrder implements pb.StockfighterServer.
+func (s *Server) CancelOrder(ctx context.Context, req *pb.OrderIDRequest) (resp *pb.Order, err error) {
+	defer recoverInvalid(&err)
+	client := s.client.WithContext(ctx)
+
+	order, err := client.CancelOrder(req.Venue, req.Stock, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toOrder(order), nil
+}
+
+// GetAllOrders implements pb.StockfighterServer.
+func (s *Server) GetAllOrders(ctx context.Context, req *pb.AccountRequest) (resp *pb.OrderList, err error) {
+	defer recoverInvalid(&err)
+	client := s.client.WithContext(ctx)
+
+	orders, err := client.GetAllOrders(req.Venue, req.Account)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp = &pb.OrderList{}
+	for i := range orders {
+		resp.Orders = append(resp.Orders, toOrder(&orders[i]))
+	}
+	return resp, nil
+}
+
+// SubscribeQuotes implements pb.StockfighterServer, with a QuotePoller.
+func (s *Server) SubscribeQuotes(req *pb.SubscribeQuotesRequest, stream pb.Stockfighter_SubscribeQuotesServer) (err error) {
+	defer recoverInvalid(&err)
+
+	interval := time.Duration(req.IntervalMs) * time.Millisecond
+	poller := stockfighter.NewQuotePoller(s.client, req.Venue, req.Stocks, interval)
+	for update := range poller.Start(stream.Context()) {
+		msg := &pb.QuoteUpdate{Venue: update.Venue, Stock: update.Stock}
+		if update.Err != nil {
+			msg.Error = update.Err.Error()
+		} else {
+			msg.Quote = toQuote(update.Quote)
+		}
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+	}
+	return stream.Context().Err()
+}
+
+// recoverInvalid turns the panics of the client on invalid symbols into
+// InvalidArgument errors.
+func recoverInvalid(err *error) {
+	if r := recover(); r != nil {
+		*err = status.Error(codes.InvalidArgument, fmt.Sprint(r))
+	}
+}
+
+// toStatus maps client errors to gRPC status codes.
+func toStatus(err error) error {
+	var (
+		unauthorized  *stockfighter.ErrorUnauthorized
+		venueNotFound *stockfighter.ErrorVenueNotFound
+		stockNotFound *stockfighter.ErrorStockNotFound
+		invalidOrder  *stockfighter.ErrorInvalidOrder
+		timeout       *stockfighter.ErrorAPITimeout
+	)
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.As(err, &unauthorized):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.As(err, &venueNotFound), errors.As(err, &stockNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &invalidOrder):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &timeout):
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toQuote(quote *stockfighter.Quote) *pb.Quote {
+	return &pb.Quote{
+		Venue:         quote.Venue,
+		Symbol:        quote.Symbol,
+		HasBid:        quote.HasBid,
+		BidPrice:      quote.BidPrice,
+		BidSize:       quote.BidSize,
+		BidDepth:      quote.BidDepth,
+		HasAsk:        quote.HasAsk,
+		AskPrice:      quote.AskPrice,
+		AskSize:       quote.AskSize,
+		AskDepth:      quote.AskDepth,
+		LastPrice:     quote.LastPrice,
+		LastSize:      quote.LastSize,
+		LastTradeTime: toTimestamp(quote.LastTradeTime),
+		QuoteTime:     toTimestamp(quote.QuoteTime),
+	}
+}
+
+func toOrder(order *stockfighter.Order) *pb.Order {
+	msg := &pb.Order{
+		Venue:            order.Venue,
+		Symbol:           order.Symbol,
+		Direction:        order.Direction,
+		OriginalQuantity: order.OriginalQuantity,
+		Quantity:         order.Quantity,
+		Price:            order.Price,
+		OrderType:        order.OrderType,
+		Id:               order.OrderID,
+		Account:          order.Account,
+		Timestamp:        toTimestamp(order.Timestamp),
+		TotalFilled:      order.TotalFilled,
+		Open:             order.Open,
+	}
+	for _, fill := range order.Fills {
+		msg.Fills = append(msg.Fills, &pb.OrderFill{Price: fill.Price, Quantity: fill.Quantity, Timestamp: toTimestamp(fill.Timestamp)})
+	}
+	return msg
+}
@@ -0,0 +1,71 @@
+package grpcsvc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gpk.io/stockfighter"
+	"gpk.io/stockfighter/grpcsvc/pb"
+)
+
+func TestServer(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/venues/TESTEX/stocks/FOOBAR/quote":
+			w.Write([]byte(`{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR", "bid": 5000, "bidSize": 10}`))
+		case "/venues/TESTEX/stocks/FOOBAR/orders/42":
+			w.Write([]byte(`{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR", "id": 42, "direction": "buy", "qty": 5, "totalFilled": 5, "fills": [{"price": 5000, "qty": 5}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"ok": false, "error": "Stock NOEXIST does not trade on venue TESTEX"}`))
+		}
+	}))
+	defer api.Close()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(Auth("TOKEN")...)
+	NewServer(stockfighter.NewClient("KEY", stockfighter.WithBaseURL(api.URL))).Register(server)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := pb.NewStockfighterClient(conn)
+
+	// calls without the token are rejected
+	_, err = client.GetQuote(context.Background(), &pb.StockRequest{Venue: "TESTEX", Stock: "FOOBAR"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer TOKEN")
+	quote, err := client.GetQuote(ctx, &pb.StockRequest{Venue: "TESTEX", Stock: "FOOBAR"})
+	assert.Nil(t, err)
+	assert.Equal(t, "FOOBAR", quote.Symbol)
+	assert.True(t, quote.HasBid)
+	assert.Equal(t, uint64(5000), quote.BidPrice)
+	assert.Equal(t, uint64(10), quote.BidSize)
+
+	order, err := client.GetOrder(ctx, &pb.OrderIDRequest{Venue: "TESTEX", Stock: "FOOBAR", Id: 42})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), order.Id)
+	assert.Equal(t, uint64(5), order.TotalFilled)
+	assert.Equal(t, 1, len(order.Fills))
+
+	// client errors and panics are mapped to status codes
+	_, err = client.GetQuote(ctx, &pb.StockRequest{Venue: "TESTEX", Stock: "NOEXIST"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetQuote(ctx, &pb.StockRequest{Venue: "TESTEX", Stock: "FOO BAR"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,5 @@
+// Package pb holds the protobuf messages and gRPC service generated from
+// stockfighter.proto.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative stockfighter.proto
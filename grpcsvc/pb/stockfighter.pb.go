@@ -0,0 +1,1273 @@
+// Protobuf definitions of the Stockfighter gRPC façade, mirroring the types of
+// package stockfighter. Prices are in cents.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        v5.29.3
+// source: stockfighter.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VenueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Venue         string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VenueRequest) Reset() {
+	*x = VenueRequest{}
+	mi := &file_stockfighter_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VenueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VenueRequest) ProtoMessage() {}
+
+func (x *VenueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VenueRequest.ProtoReflect.Descriptor instead.
+func (*VenueRequest) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VenueRequest) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+type StockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Venue         string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	Stock         string                 `protobuf:"bytes,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockRequest) Reset() {
+	*x = StockRequest{}
+	mi := &file_stockfighter_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockRequest) ProtoMessage() {}
+
+func (x *StockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockRequest.ProtoReflect.Descriptor instead.
+func (*StockRequest) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StockRequest) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *StockRequest) GetStock() string {
+	if x != nil {
+		return x.Stock
+	}
+	return ""
+}
+
+type AccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Venue         string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	Account       string                 `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountRequest) Reset() {
+	*x = AccountRequest{}
+	mi := &file_stockfighter_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountRequest) ProtoMessage() {}
+
+func (x *AccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountRequest.ProtoReflect.Descriptor instead.
+func (*AccountRequest) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AccountRequest) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *AccountRequest) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+type OrderIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Venue         string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	Stock         string                 `protobuf:"bytes,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	Id            int64                  `protobuf:"varint,3,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderIDRequest) Reset() {
+	*x = OrderIDRequest{}
+	mi := &file_stockfighter_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderIDRequest) ProtoMessage() {}
+
+func (x *OrderIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderIDRequest.ProtoReflect.Descriptor instead.
+func (*OrderIDRequest) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderIDRequest) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *OrderIDRequest) GetStock() string {
+	if x != nil {
+		return x.Stock
+	}
+	return ""
+}
+
+func (x *OrderIDRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type SubscribeQuotesRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Venue  string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	Stocks []string               `protobuf:"bytes,2,rep,name=stocks,proto3" json:"stocks,omitempty"`
+	// Polling interval in milliseconds (1000 if 0)
+	IntervalMs    int64 `protobuf:"varint,3,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeQuotesRequest) Reset() {
+	*x = SubscribeQuotesRequest{}
+	mi := &file_stockfighter_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeQuotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeQuotesRequest) ProtoMessage() {}
+
+func (x *SubscribeQuotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeQuotesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeQuotesRequest) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SubscribeQuotesRequest) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *SubscribeQuotesRequest) GetStocks() []string {
+	if x != nil {
+		return x.Stocks
+	}
+	return nil
+}
+
+func (x *SubscribeQuotesRequest) GetIntervalMs() int64 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+type StockInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockInfo) Reset() {
+	*x = StockInfo{}
+	mi := &file_stockfighter_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockInfo) ProtoMessage() {}
+
+func (x *StockInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockInfo.ProtoReflect.Descriptor instead.
+func (*StockInfo) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StockInfo) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *StockInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type StockList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stocks        []*StockInfo           `protobuf:"bytes,1,rep,name=stocks,proto3" json:"stocks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockList) Reset() {
+	*x = StockList{}
+	mi := &file_stockfighter_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockList) ProtoMessage() {}
+
+func (x *StockList) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockList.ProtoReflect.Descriptor instead.
+func (*StockList) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StockList) GetStocks() []*StockInfo {
+	if x != nil {
+		return x.Stocks
+	}
+	return nil
+}
+
+type Quote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Venue         string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	HasBid        bool                   `protobuf:"varint,3,opt,name=has_bid,json=hasBid,proto3" json:"has_bid,omitempty"`
+	BidPrice      uint64                 `protobuf:"varint,4,opt,name=bid_price,json=bidPrice,proto3" json:"bid_price,omitempty"`
+	BidSize       uint64                 `protobuf:"varint,5,opt,name=bid_size,json=bidSize,proto3" json:"bid_size,omitempty"`
+	BidDepth      uint64                 `protobuf:"varint,6,opt,name=bid_depth,json=bidDepth,proto3" json:"bid_depth,omitempty"`
+	HasAsk        bool                   `protobuf:"varint,7,opt,name=has_ask,json=hasAsk,proto3" json:"has_ask,omitempty"`
+	AskPrice      uint64                 `protobuf:"varint,8,opt,name=ask_price,json=askPrice,proto3" json:"ask_price,omitempty"`
+	AskSize       uint64                 `protobuf:"varint,9,opt,name=ask_size,json=askSize,proto3" json:"ask_size,omitempty"`
+	AskDepth      uint64                 `protobuf:"varint,10,opt,name=ask_depth,json=askDepth,proto3" json:"ask_depth,omitempty"`
+	LastPrice     uint64                 `protobuf:"varint,11,opt,name=last_price,json=lastPrice,proto3" json:"last_price,omitempty"`
+	LastSize      uint64                 `protobuf:"varint,12,opt,name=last_size,json=lastSize,proto3" json:"last_size,omitempty"`
+	LastTradeTime *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=last_trade_time,json=lastTradeTime,proto3" json:"last_trade_time,omitempty"`
+	QuoteTime     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=quote_time,json=quoteTime,proto3" json:"quote_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quote) Reset() {
+	*x = Quote{}
+	mi := &file_stockfighter_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quote) ProtoMessage() {}
+
+func (x *Quote) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quote.ProtoReflect.Descriptor instead.
+func (*Quote) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Quote) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *Quote) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Quote) GetHasBid() bool {
+	if x != nil {
+		return x.HasBid
+	}
+	return false
+}
+
+func (x *Quote) GetBidPrice() uint64 {
+	if x != nil {
+		return x.BidPrice
+	}
+	return 0
+}
+
+func (x *Quote) GetBidSize() uint64 {
+	if x != nil {
+		return x.BidSize
+	}
+	return 0
+}
+
+func (x *Quote) GetBidDepth() uint64 {
+	if x != nil {
+		return x.BidDepth
+	}
+	return 0
+}
+
+func (x *Quote) GetHasAsk() bool {
+	if x != nil {
+		return x.HasAsk
+	}
+	return false
+}
+
+func (x *Quote) GetAskPrice() uint64 {
+	if x != nil {
+		return x.AskPrice
+	}
+	return 0
+}
+
+func (x *Quote) GetAskSize() uint64 {
+	if x != nil {
+		return x.AskSize
+	}
+	return 0
+}
+
+func (x *Quote) GetAskDepth() uint64 {
+	if x != nil {
+		return x.AskDepth
+	}
+	return 0
+}
+
+func (x *Quote) GetLastPrice() uint64 {
+	if x != nil {
+		return x.LastPrice
+	}
+	return 0
+}
+
+func (x *Quote) GetLastSize() uint64 {
+	if x != nil {
+		return x.LastSize
+	}
+	return 0
+}
+
+func (x *Quote) GetLastTradeTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastTradeTime
+	}
+	return nil
+}
+
+func (x *Quote) GetQuoteTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.QuoteTime
+	}
+	return nil
+}
+
+type QuoteUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Venue string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	Stock string                 `protobuf:"bytes,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	// Set unless the quote could not be polled
+	Quote         *Quote `protobuf:"bytes,3,opt,name=quote,proto3" json:"quote,omitempty"`
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuoteUpdate) Reset() {
+	*x = QuoteUpdate{}
+	mi := &file_stockfighter_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteUpdate) ProtoMessage() {}
+
+func (x *QuoteUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteUpdate.ProtoReflect.Descriptor instead.
+func (*QuoteUpdate) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *QuoteUpdate) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *QuoteUpdate) GetStock() string {
+	if x != nil {
+		return x.Stock
+	}
+	return ""
+}
+
+func (x *QuoteUpdate) GetQuote() *Quote {
+	if x != nil {
+		return x.Quote
+	}
+	return nil
+}
+
+func (x *QuoteUpdate) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type OrderbookEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         uint64                 `protobuf:"varint,1,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      uint64                 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	IsBuy         bool                   `protobuf:"varint,3,opt,name=is_buy,json=isBuy,proto3" json:"is_buy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderbookEntry) Reset() {
+	*x = OrderbookEntry{}
+	mi := &file_stockfighter_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderbookEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderbookEntry) ProtoMessage() {}
+
+func (x *OrderbookEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderbookEntry.ProtoReflect.Descriptor instead.
+func (*OrderbookEntry) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *OrderbookEntry) GetPrice() uint64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *OrderbookEntry) GetQuantity() uint64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderbookEntry) GetIsBuy() bool {
+	if x != nil {
+		return x.IsBuy
+	}
+	return false
+}
+
+type Orderbook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Venue         string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Bids          []*OrderbookEntry      `protobuf:"bytes,3,rep,name=bids,proto3" json:"bids,omitempty"`
+	Asks          []*OrderbookEntry      `protobuf:"bytes,4,rep,name=asks,proto3" json:"asks,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Orderbook) Reset() {
+	*x = Orderbook{}
+	mi := &file_stockfighter_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Orderbook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Orderbook) ProtoMessage() {}
+
+func (x *Orderbook) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Orderbook.ProtoReflect.Descriptor instead.
+func (*Orderbook) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Orderbook) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *Orderbook) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Orderbook) GetBids() []*OrderbookEntry {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *Orderbook) GetAsks() []*OrderbookEntry {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *Orderbook) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type OrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Venue         string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	Stock         string                 `protobuf:"bytes,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	Account       string                 `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
+	Price         uint64                 `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      uint64                 `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Direction     string                 `protobuf:"bytes,6,opt,name=direction,proto3" json:"direction,omitempty"`
+	OrderType     string                 `protobuf:"bytes,7,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderRequest) Reset() {
+	*x = OrderRequest{}
+	mi := &file_stockfighter_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderRequest) ProtoMessage() {}
+
+func (x *OrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderRequest.ProtoReflect.Descriptor instead.
+func (*OrderRequest) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *OrderRequest) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *OrderRequest) GetStock() string {
+	if x != nil {
+		return x.Stock
+	}
+	return ""
+}
+
+func (x *OrderRequest) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *OrderRequest) GetPrice() uint64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *OrderRequest) GetQuantity() uint64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *OrderRequest) GetOrderType() string {
+	if x != nil {
+		return x.OrderType
+	}
+	return ""
+}
+
+type OrderFill struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         uint64                 `protobuf:"varint,1,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      uint64                 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderFill) Reset() {
+	*x = OrderFill{}
+	mi := &file_stockfighter_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderFill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderFill) ProtoMessage() {}
+
+func (x *OrderFill) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderFill.ProtoReflect.Descriptor instead.
+func (*OrderFill) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OrderFill) GetPrice() uint64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *OrderFill) GetQuantity() uint64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderFill) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type Order struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Venue            string                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	Symbol           string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Direction        string                 `protobuf:"bytes,3,opt,name=direction,proto3" json:"direction,omitempty"`
+	OriginalQuantity uint64                 `protobuf:"varint,4,opt,name=original_quantity,json=originalQuantity,proto3" json:"original_quantity,omitempty"`
+	Quantity         uint64                 `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price            uint64                 `protobuf:"varint,6,opt,name=price,proto3" json:"price,omitempty"`
+	OrderType        string                 `protobuf:"bytes,7,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
+	Id               int64                  `protobuf:"varint,8,opt,name=id,proto3" json:"id,omitempty"`
+	Account          string                 `protobuf:"bytes,9,opt,name=account,proto3" json:"account,omitempty"`
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Fills            []*OrderFill           `protobuf:"bytes,11,rep,name=fills,proto3" json:"fills,omitempty"`
+	TotalFilled      uint64                 `protobuf:"varint,12,opt,name=total_filled,json=totalFilled,proto3" json:"total_filled,omitempty"`
+	Open             bool                   `protobuf:"varint,13,opt,name=open,proto3" json:"open,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_stockfighter_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Order) GetVenue() string {
+	if x != nil {
+		return x.Venue
+	}
+	return ""
+}
+
+func (x *Order) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Order) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *Order) GetOriginalQuantity() uint64 {
+	if x != nil {
+		return x.OriginalQuantity
+	}
+	return 0
+}
+
+func (x *Order) GetQuantity() uint64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Order) GetPrice() uint64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Order) GetOrderType() string {
+	if x != nil {
+		return x.OrderType
+	}
+	return ""
+}
+
+func (x *Order) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Order) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *Order) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Order) GetFills() []*OrderFill {
+	if x != nil {
+		return x.Fills
+	}
+	return nil
+}
+
+func (x *Order) GetTotalFilled() uint64 {
+	if x != nil {
+		return x.TotalFilled
+	}
+	return 0
+}
+
+func (x *Order) GetOpen() bool {
+	if x != nil {
+		return x.Open
+	}
+	return false
+}
+
+type OrderList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderList) Reset() {
+	*x = OrderList{}
+	mi := &file_stockfighter_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderList) ProtoMessage() {}
+
+func (x *OrderList) ProtoReflect() protoreflect.Message {
+	mi := &file_stockfighter_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderList.ProtoReflect.Descriptor instead.
+func (*OrderList) Descriptor() ([]byte, []int) {
+	return file_stockfighter_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *OrderList) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+var File_stockfighter_proto protoreflect.FileDescriptor
+
+const file_stockfighter_proto_rawDesc = "" +
+	"\n" +
+	"\x12stockfighter.proto\x12\fstockfighter\x1a\x1fgoogle/protobuf/timestamp.proto\"$\n" +
+	"\fVenueRequest\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\":\n" +
+	"\fStockRequest\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\x12\x14\n" +
+	"\x05stock\x18\x02 \x01(\tR\x05stock\"@\n" +
+	"\x0eAccountRequest\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\x12\x18\n" +
+	"\aaccount\x18\x02 \x01(\tR\aaccount\"L\n" +
+	"\x0eOrderIDRequest\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\x12\x14\n" +
+	"\x05stock\x18\x02 \x01(\tR\x05stock\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\x03R\x02id\"g\n" +
+	"\x16SubscribeQuotesRequest\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\x12\x16\n" +
+	"\x06stocks\x18\x02 \x03(\tR\x06stocks\x12\x1f\n" +
+	"\vinterval_ms\x18\x03 \x01(\x03R\n" +
+	"intervalMs\"7\n" +
+	"\tStockInfo\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"<\n" +
+	"\tStockList\x12/\n" +
+	"\x06stocks\x18\x01 \x03(\v2\x17.stockfighter.StockInfoR\x06stocks\"\xcc\x03\n" +
+	"\x05Quote\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x17\n" +
+	"\ahas_bid\x18\x03 \x01(\bR\x06hasBid\x12\x1b\n" +
+	"\tbid_price\x18\x04 \x01(\x04R\bbidPrice\x12\x19\n" +
+	"\bbid_size\x18\x05 \x01(\x04R\abidSize\x12\x1b\n" +
+	"\tbid_depth\x18\x06 \x01(\x04R\bbidDepth\x12\x17\n" +
+	"\ahas_ask\x18\a \x01(\bR\x06hasAsk\x12\x1b\n" +
+	"\task_price\x18\b \x01(\x04R\baskPrice\x12\x19\n" +
+	"\bask_size\x18\t \x01(\x04R\aaskSize\x12\x1b\n" +
+	"\task_depth\x18\n" +
+	" \x01(\x04R\baskDepth\x12\x1d\n" +
+	"\n" +
+	"last_price\x18\v \x01(\x04R\tlastPrice\x12\x1b\n" +
+	"\tlast_size\x18\f \x01(\x04R\blastSize\x12B\n" +
+	"\x0flast_trade_time\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\rlastTradeTime\x129\n" +
+	"\n" +
+	"quote_time\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tquoteTime\"z\n" +
+	"\vQuoteUpdate\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\x12\x14\n" +
+	"\x05stock\x18\x02 \x01(\tR\x05stock\x12)\n" +
+	"\x05quote\x18\x03 \x01(\v2\x13.stockfighter.QuoteR\x05quote\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"Y\n" +
+	"\x0eOrderbookEntry\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x04R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x04R\bquantity\x12\x15\n" +
+	"\x06is_buy\x18\x03 \x01(\bR\x05isBuy\"\xd7\x01\n" +
+	"\tOrderbook\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x120\n" +
+	"\x04bids\x18\x03 \x03(\v2\x1c.stockfighter.OrderbookEntryR\x04bids\x120\n" +
+	"\x04asks\x18\x04 \x03(\v2\x1c.stockfighter.OrderbookEntryR\x04asks\x128\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xc3\x01\n" +
+	"\fOrderRequest\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\x12\x14\n" +
+	"\x05stock\x18\x02 \x01(\tR\x05stock\x12\x18\n" +
+	"\aaccount\x18\x03 \x01(\tR\aaccount\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x04R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x04R\bquantity\x12\x1c\n" +
+	"\tdirection\x18\x06 \x01(\tR\tdirection\x12\x1d\n" +
+	"\n" +
+	"order_type\x18\a \x01(\tR\torderType\"w\n" +
+	"\tOrderFill\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x04R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x04R\bquantity\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x9b\x03\n" +
+	"\x05Order\x12\x14\n" +
+	"\x05venue\x18\x01 \x01(\tR\x05venue\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x1c\n" +
+	"\tdirection\x18\x03 \x01(\tR\tdirection\x12+\n" +
+	"\x11original_quantity\x18\x04 \x01(\x04R\x10originalQuantity\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x04R\bquantity\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x04R\x05price\x12\x1d\n" +
+	"\n" +
+	"order_type\x18\a \x01(\tR\torderType\x12\x0e\n" +
+	"\x02id\x18\b \x01(\x03R\x02id\x12\x18\n" +
+	"\aaccount\x18\t \x01(\tR\aaccount\x128\n" +
+	"\ttimestamp\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12-\n" +
+	"\x05fills\x18\v \x03(\v2\x17.stockfighter.OrderFillR\x05fills\x12!\n" +
+	"\ftotal_filled\x18\f \x01(\x04R\vtotalFilled\x12\x12\n" +
+	"\x04open\x18\r \x01(\bR\x04open\"8\n" +
+	"\tOrderList\x12+\n" +
+	"\x06orders\x18\x01 \x03(\v2\x13.stockfighter.OrderR\x06orders2\xb0\x04\n" +
+	"\fStockfighter\x12A\n" +
+	"\n" +
+	"ListStocks\x12\x1a.stockfighter.VenueRequest\x1a\x17.stockfighter.StockList\x12;\n" +
+	"\bGetQuote\x12\x1a.stockfighter.StockRequest\x1a\x13.stockfighter.Quote\x12C\n" +
+	"\fGetOrderbook\x12\x1a.stockfighter.StockRequest\x1a\x17.stockfighter.Orderbook\x12=\n" +
+	"\n" +
+	"PlaceOrder\x12\x1a.stockfighter.OrderRequest\x1a\x13.stockfighter.Order\x12=\n" +
+	"\bGetOrder\x12\x1c.stockfighter.OrderIDRequest\x1a\x13.stockfighter.Order\x12@\n" +
+	"\vCancelOrder\x12\x1c.stockfighter.OrderIDRequest\x1a\x13.stockfighter.Order\x12E\n" +
+	"\fGetAllOrders\x12\x1c.stockfighter.AccountRequest\x1a\x17.stockfighter.OrderList\x12T\n" +
+	"\x0fSubscribeQuotes\x12$.stockfighter.SubscribeQuotesRequest\x1a\x19.stockfighter.QuoteUpdate0\x01B Z\x1egpk.io/stockfighter/grpcsvc/pbb\x06proto3"
+
+var (
+	file_stockfighter_proto_rawDescOnce sync.Once
+	file_stockfighter_proto_rawDescData []byte
+)
+
+func file_stockfighter_proto_rawDescGZIP() []byte {
+	file_stockfighter_proto_rawDescOnce.Do(func() {
+		file_stockfighter_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_stockfighter_proto_rawDesc), len(file_stockfighter_proto_rawDesc)))
+	})
+	return file_stockfighter_proto_rawDescData
+}
+
+var file_stockfighter_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_stockfighter_proto_goTypes = []any{
+	(*VenueRequest)(nil),           // 0: stockfighter.VenueRequest
+	(*StockRequest)(nil),           // 1: stockfighter.StockRequest
+	(*AccountRequest)(nil),         // 2: stockfighter.AccountRequest
+	(*OrderIDRequest)(nil),         // 3: stockfighter.OrderIDRequest
+	(*SubscribeQuotesRequest)(nil), // 4: stockfighter.SubscribeQuotesRequest
+	(*StockInfo)(nil),              // 5: stockfighter.StockInfo
+	(*StockList)(nil),              // 6: stockfighter.StockList
+	(*Quote)(nil),                  // 7: stockfighter.Quote
+	(*QuoteUpdate)(nil),            // 8: stockfighter.QuoteUpdate
+	(*OrderbookEntry)(nil),         // 9: stockfighter.OrderbookEntry
+	(*Orderbook)(nil),              // 10: stockfighter.Orderbook
+	(*OrderRequest)(nil),           // 11: stockfighter.OrderRequest
+	(*OrderFill)(nil),              // 12: stockfighter.OrderFill
+	(*Order)(nil),                  // 13: stockfighter.Order
+	(*OrderList)(nil),              // 14: stockfighter.OrderList
+	(*timestamppb.Timestamp)(nil),  // 15: google.protobuf.Timestamp
+}
+var file_stockfighter_proto_depIdxs = []int32{
+	5,  // 0: stockfighter.StockList.stocks:type_name -> stockfighter.StockInfo
+	15, // 1: stockfighter.Quote.last_trade_time:type_name -> google.protobuf.Timestamp
+	15, // 2: stockfighter.Quote.quote_time:type_name -> google.protobuf.Timestamp
+	7,  // 3: stockfighter.QuoteUpdate.quote:type_name -> stockfighter.Quote
+	9,  // 4: stockfighter.Orderbook.bids:type_name -> stockfighter.OrderbookEntry
+	9,  // 5: stockfighter.Orderbook.asks:type_name -> stockfighter.OrderbookEntry
+	15, // 6: stockfighter.Orderbook.timestamp:type_name -> google.protobuf.Timestamp
+	15, // 7: stockfighter.OrderFill.timestamp:type_name -> google.protobuf.Timestamp
+	15, // 8: stockfighter.Order.timestamp:type_name -> google.protobuf.Timestamp
+	12, // 9: stockfighter.Order.fills:type_name -> stockfighter.OrderFill
+	13, // 10: stockfighter.OrderList.orders:type_name -> stockfighter.Order
+	0,  // 11: stockfighter.Stockfighter.ListStocks:input_type -> stockfighter.VenueRequest
+	1,  // 12: stockfighter.Stockfighter.GetQuote:input_type -> stockfighter.StockRequest
+	1,  // 13: stockfighter.Stockfighter.GetOrderbook:input_type -> stockfighter.StockRequest
+	11, // 14: stockfighter.Stockfighter.PlaceOrder:input_type -> stockfighter.OrderRequest
+	3,  // 15: stockfighter.Stockfighter.GetOrder:input_type -> stockfighter.OrderIDRequest
+	3,  // 16: stockfighter.Stockfighter.CancelOrder:input_type -> stockfighter.OrderIDRequest
+	2,  // 17: stockfighter.Stockfighter.GetAllOrders:input_type -> stockfighter.AccountRequest
+	4,  // 18: stockfighter.Stockfighter.SubscribeQuotes:input_type -> stockfighter.SubscribeQuotesRequest
+	6,  // 19: stockfighter.Stockfighter.ListStocks:output_type -> stockfighter.StockList
+	7,  // 20: stockfighter.Stockfighter.GetQuote:output_type -> stockfighter.Quote
+	10, // 21: stockfighter.Stockfighter.GetOrderbook:output_type -> stockfighter.Orderbook
+	13, // 22: stockfighter.Stockfighter.PlaceOrder:output_type -> stockfighter.Order
+	13, // 23: stockfighter.Stockfighter.GetOrder:output_type -> stockfighter.Order
+	13, // 24: stockfighter.Stockfighter.CancelOrder:output_type -> stockfighter.Order
+	14, // 25: stockfighter.Stockfighter.GetAllOrders:output_type -> stockfighter.OrderList
+	8,  // 26: stockfighter.Stockfighter.SubscribeQuotes:output_type -> stockfighter.QuoteUpdate
+	19, // [19:27] is the sub-list for method output_type
+	11, // [11:19] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_stockfighter_proto_init() }
+func file_stockfighter_proto_init() {
+	if File_stockfighter_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_stockfighter_proto_rawDesc), len(file_stockfighter_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_stockfighter_proto_goTypes,
+		DependencyIndexes: file_stockfighter_proto_depIdxs,
+		MessageInfos:      file_stockfighter_proto_msgTypes,
+	}.Build()
+	File_stockfighter_proto = out.File
+	file_stockfighter_proto_goTypes = nil
+	file_stockfighter_proto_depIdxs = nil
+}
@@ -0,0 +1,396 @@
+// Protobuf definitions of the Stockfighter gRPC façade, mirroring the types of
+// package stockfighter. Prices are in cents.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             v5.29.3
+// source: stockfighter.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Stockfighter_ListStocks_FullMethodName      = "/stockfighter.Stockfighter/ListStocks"
+	Stockfighter_GetQuote_FullMethodName        = "/stockfighter.Stockfighter/GetQuote"
+	Stockfighter_GetOrderbook_FullMethodName    = "/stockfighter.Stockfighter/GetOrderbook"
+	Stockfighter_PlaceOrder_FullMethodName      = "/stockfighter.Stockfighter/PlaceOrder"
+	Stockfighter_GetOrder_FullMethodName        = "/stockfighter.Stockfighter/GetOrder"
+	Stockfighter_CancelOrder_FullMethodName     = "/stockfighter.Stockfighter/CancelOrder"
+	Stockfighter_GetAllOrders_FullMethodName    = "/stockfighter.Stockfighter/GetAllOrders"
+	Stockfighter_SubscribeQuotes_FullMethodName = "/stockfighter.Stockfighter/SubscribeQuotes"
+)
+
+// StockfighterClient is the client API for Stockfighter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StockfighterClient interface {
+	ListStocks(ctx context.Context, in *VenueRequest, opts ...grpc.CallOption) (*StockList, error)
+	GetQuote(ctx context.Context, in *StockRequest, opts ...grpc.CallOption) (*Quote, error)
+	GetOrderbook(ctx context.Context, in *StockRequest, opts ...grpc.CallOption) (*Orderbook, error)
+	PlaceOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*Order, error)
+	GetOrder(ctx context.Context, in *OrderIDRequest, opts ...grpc.CallOption) (*Order, error)
+	CancelOrder(ctx context.Context, in *OrderIDRequest, opts ...grpc.CallOption) (*Order, error)
+	GetAllOrders(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*OrderList, error)
+	// Streams the quotes of a set of stocks of a venue as they change.
+	SubscribeQuotes(ctx context.Context, in *SubscribeQuotesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[QuoteUpdate], error)
+}
+
+type stockfighterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStockfighterClient(cc grpc.ClientConnInterface) StockfighterClient {
+	return &stockfighterClient{cc}
+}
+
+func (c *stockfighterClient) ListStocks(ctx context.Context, in *VenueRequest, opts ...grpc.CallOption) (*StockList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StockList)
+	err := c.cc.Invoke(ctx, Stockfighter_ListStocks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockfighterClient) GetQuote(ctx context.Context, in *StockRequest, opts ...grpc.CallOption) (*Quote, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Quote)
+	err := c.cc.Invoke(ctx, Stockfighter_GetQuote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockfighterClient) GetOrderbook(ctx context.Context, in *StockRequest, opts ...grpc.CallOption) (*Orderbook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Orderbook)
+	err := c.cc.Invoke(ctx, Stockfighter_GetOrderbook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockfighterClient) PlaceOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, Stockfighter_PlaceOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockfighterClient) GetOrder(ctx context.Context, in *OrderIDRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, Stockfighter_GetOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockfighterClient) CancelOrder(ctx context.Context, in *OrderIDRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, Stockfighter_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockfighterClient) GetAllOrders(ctx context.Context, in *AccountRequest, opts ...grpc.CallOption) (*OrderList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderList)
+	err := c.cc.Invoke(ctx, Stockfighter_GetAllOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stockfighterClient) SubscribeQuotes(ctx context.Context, in *SubscribeQuotesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[QuoteUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Stockfighter_ServiceDesc.Streams[0], Stockfighter_SubscribeQuotes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeQuotesRequest, QuoteUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Stockfighter_SubscribeQuotesClient = grpc.ServerStreamingClient[QuoteUpdate]
+
+// StockfighterServer is the server API for Stockfighter service.
+// All implementations must embed UnimplementedStockfighterServer
+// for forward compatibility.
+type StockfighterServer interface {
+	ListStocks(context.Context, *VenueRequest) (*StockList, error)
+	GetQuote(context.Context, *StockRequest) (*Quote, error)
+	GetOrderbook(context.Context, *StockRequest) (*Orderbook, error)
+	PlaceOrder(context.Context, *OrderRequest) (*Order, error)
+	GetOrder(context.Context, *OrderIDRequest) (*Order, error)
+	CancelOrder(context.Context, *OrderIDRequest) (*Order, error)
+	GetAllOrders(context.Context, *AccountRequest) (*OrderList, error)
+	// Streams the quotes of a set of stocks of a venue as they change.
+	SubscribeQuotes(*SubscribeQuotesRequest, grpc.ServerStreamingServer[QuoteUpdate]) error
+	mustEmbedUnimplementedStockfighterServer()
+}
+
+// UnimplementedStockfighterServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStockfighterServer struct{}
+
+func (UnimplementedStockfighterServer) ListStocks(context.Context, *VenueRequest) (*StockList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListStocks not implemented")
+}
+func (UnimplementedStockfighterServer) GetQuote(context.Context, *StockRequest) (*Quote, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetQuote not implemented")
+}
+func (UnimplementedStockfighterServer) GetOrderbook(context.Context, *StockRequest) (*Orderbook, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetOrderbook not implemented")
+}
+func (UnimplementedStockfighterServer) PlaceOrder(context.Context, *OrderRequest) (*Order, error) {
+	return nil, status.Error(codes.Unimplemented, "method PlaceOrder not implemented")
+}
+func (UnimplementedStockfighterServer) GetOrder(context.Context, *OrderIDRequest) (*Order, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedStockfighterServer) CancelOrder(context.Context, *OrderIDRequest) (*Order, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedStockfighterServer) GetAllOrders(context.Context, *AccountRequest) (*OrderList, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAllOrders not implemented")
+}
+func (UnimplementedStockfighterServer) SubscribeQuotes(*SubscribeQuotesRequest, grpc.ServerStreamingServer[QuoteUpdate]) error {
+	return status.Error(codes.Unimplemented, "method SubscribeQuotes not implemented")
+}
+func (UnimplementedStockfighterServer) mustEmbedUnimplementedStockfighterServer() {}
+func (UnimplementedStockfighterServer) testEmbeddedByValue()                      {}
+
+// UnsafeStockfighterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StockfighterServer will
+// result in compilation errors.
+type UnsafeStockfighterServer interface {
+	mustEmbedUnimplementedStockfighterServer()
+}
+
+func RegisterStockfighterServer(s grpc.ServiceRegistrar, srv StockfighterServer) {
+	// If the following call panics, it indicates UnimplementedStockfighterServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Stockfighter_ServiceDesc, srv)
+}
+
+func _Stockfighter_ListStocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VenueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockfighterServer).ListStocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stockfighter_ListStocks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockfighterServer).ListStocks(ctx, req.(*VenueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stockfighter_GetQuote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockfighterServer).GetQuote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stockfighter_GetQuote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockfighterServer).GetQuote(ctx, req.(*StockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stockfighter_GetOrderbook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockfighterServer).GetOrderbook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stockfighter_GetOrderbook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockfighterServer).GetOrderbook(ctx, req.(*StockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stockfighter_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockfighterServer).PlaceOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stockfighter_PlaceOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockfighterServer).PlaceOrder(ctx, req.(*OrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stockfighter_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockfighterServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stockfighter_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockfighterServer).GetOrder(ctx, req.(*OrderIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stockfighter_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockfighterServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stockfighter_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockfighterServer).CancelOrder(ctx, req.(*OrderIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stockfighter_GetAllOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StockfighterServer).GetAllOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Stockfighter_GetAllOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StockfighterServer).GetAllOrders(ctx, req.(*AccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Stockfighter_SubscribeQuotes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeQuotesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StockfighterServer).SubscribeQuotes(m, &grpc.GenericServerStream[SubscribeQuotesRequest, QuoteUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Stockfighter_SubscribeQuotesServer = grpc.ServerStreamingServer[QuoteUpdate]
+
+// Stockfighter_ServiceDesc is the grpc.ServiceDesc for Stockfighter service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Stockfighter_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "stockfighter.Stockfighter",
+	HandlerType: (*StockfighterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListStocks",
+			Handler:    _Stockfighter_ListStocks_Handler,
+		},
+		{
+			MethodName: "GetQuote",
+			Handler:    _Stockfighter_GetQuote_Handler,
+		},
+		{
+			MethodName: "GetOrderbook",
+			Handler:    _Stockfighter_GetOrderbook_Handler,
+		},
+		{
+			MethodName: "PlaceOrder",
+			Handler:    _Stockfighter_PlaceOrder_Handler,
+		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _Stockfighter_GetOrder_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _Stockfighter_CancelOrder_Handler,
+		},
+		{
+			MethodName: "GetAllOrders",
+			Handler:    _Stockfighter_GetAllOrders_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeQuotes",
+			Handler:       _Stockfighter_SubscribeQuotes_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "stockfighter.proto",
+}
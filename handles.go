@@ -0,0 +1,173 @@
+package stockfighter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Venue is a handle on a venue, so that the venue symbol is given once
+// instead of in every call.
+//
+//     venue := client.Venue("TESTEX")
+//     account := venue.Account("EXB123456")
+//     orders, err := account.Orders()
+//
+// You can create a new Venue using Client.Venue function.
+type Venue struct {
+	client *Client
+	symbol string
+}
+
+// Venue returns a handle on the venue. This never returns nil.
+func (client *Client) Venue(symbol string) *Venue {
+	symbol = strings.TrimSpace(symbol)
+	if !validSymbol(symbol) {
+		panic(fmt.Errorf("Invalid venue symbol: %v", symbol))
+	}
+
+	return &Venue{client: client, symbol: symbol}
+}
+
+// Symbol returns the venue symbol.
+func (venue *Venue) Symbol() string {
+	return venue.symbol
+}
+
+// Ping checks if the venue is up. See Client.PingVenue.
+func (venue *Venue) Ping() error {
+	return venue.client.PingVenue(venue.symbol)
+}
+
+// Stocks lists the stocks available for trading on the venue. See
+// Client.ListStocks.
+func (venue *Venue) Stocks() ([]StockInfo, error) {
+	return venue.client.ListStocks(venue.symbol)
+}
+
+// Stock returns a handle on a stock of the venue. This never returns nil.
+func (venue *Venue) Stock(symbol string) *Stock {
+	symbol = strings.TrimSpace(symbol)
+	if !validSymbol(symbol) {
+		panic(fmt.Errorf("Invalid stock symbol: %v", symbol))
+	}
+
+	return &Stock{venue: venue, symbol: symbol}
+}
+
+// Account returns a handle on a trading account on the venue. This never
+// returns nil.
+func (venue *Venue) Account(name string) *Account {
+	name = strings.TrimSpace(name)
+	if !validSymbol(name) {
+		panic(fmt.Errorf("Invalid account name: %v", name))
+	}
+
+	return &Account{venue: venue, name: name}
+}
+
+// A Stock is a handle on a stock of a venue, optionally bound to a trading
+// account to place orders with.
+//
+//     foobar := client.Venue("TESTEX").Account("EXB123456").Stock("FOOBAR")
+//     order, err := foobar.Buy(5000, 100, stockfighter.OrderTypeLimit)
+//
+// You can create a new Stock using Venue.Stock or Account.Stock function.
+type Stock struct {
+	venue   *Venue
+	symbol  string
+	account *Account
+}
+
+// Venue returns the venue of the stock.
+func (stock *Stock) Venue() *Venue {
+	return stock.venue
+}
+
+// Symbol returns the stock symbol.
+func (stock *Stock) Symbol() string {
+	return stock.symbol
+}
+
+// Account returns the account the stock is bound to, or nil if it is not
+// bound to any.
+func (stock *Stock) Account() *Account {
+	return stock.account
+}
+
+// Quote returns the most recent quote of the stock. See Client.GetQuote.
+func (stock *Stock) Quote() (*Quote, error) {
+	return stock.venue.client.GetQuote(stock.venue.symbol, stock.symbol)
+}
+
+// Orderbook returns the orderbook of the stock. See Client.GetOrderbook.
+func (stock *Stock) Orderbook() (*Orderbook, error) {
+	return stock.venue.client.GetOrderbook(stock.venue.symbol, stock.symbol)
+}
+
+// Buy places a buy order with the account the stock is bound to. See
+// Client.PlaceOrder.
+//
+// Buy panics if the stock is not bound to an account.
+func (stock *Stock) Buy(price, quantity uint64, orderType string) (*Order, error) {
+	return stock.placeOrder(price, quantity, OrderDirectionBuy, orderType)
+}
+
+// Sell places a sell order with the account the stock is bound to. See
+// Client.PlaceOrder.
+//
+// Sell panics if the stock is not bound to an account.
+func (stock *Stock) Sell(price, quantity uint64, orderType string) (*Order, error) {
+	return stock.placeOrder(price, quantity, OrderDirectionSell, orderType)
+}
+
+func (stock *Stock) placeOrder(price, quantity uint64, direction, orderType string) (*Order, error) {
+	if stock.account == nil {
+		panic(fmt.Errorf("Stock not bound to an account: %v", stock.symbol))
+	}
+
+	return stock.venue.client.PlaceOrder(stock.venue.symbol, stock.symbol, stock.account.name, price, quantity, direction, orderType)
+}
+
+// Orders returns status of all orders of an account for the stock. See
+// Client.GetStockOrders.
+func (stock *Stock) Orders(account *Account) ([]Order, error) {
+	return stock.venue.client.GetStockOrders(stock.venue.symbol, account.name, stock.symbol)
+}
+
+// An Account is a handle on a trading account on a venue.
+//
+// You can create a new Account using Venue.Account function.
+type Account struct {
+	venue *Venue
+	name  string
+}
+
+// Venue returns the venue of the account.
+func (account *Account) Venue() *Venue {
+	return account.venue
+}
+
+// Name returns the account name.
+func (account *Account) Name() string {
+	return account.name
+}
+
+// Stock returns a handle on a stock of the venue bound to the account. This
+// never returns nil.
+func (account *Account) Stock(symbol string) *Stock {
+	stock := account.venue.Stock(symbol)
+	stock.account = account
+	return stock
+}
+
+// Orders returns status of all orders of the account on the venue. See
+// Client.GetAllOrders.
+func (account *Account) Orders() ([]Order, error) {
+	return account.venue.client.GetAllOrders(account.venue.symbol, account.name)
+}
+
+// StockOrders returns status of all orders of the account for a stock. See
+// Client.GetStockOrders.
+func (account *Account) StockOrders(stock *Stock) ([]Order, error) {
+	return account.venue.client.GetStockOrders(account.venue.symbol, account.name, stock.symbol)
+}
@@ -0,0 +1,72 @@
+package stockfighter
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandles(t *testing.T) {
+	var paths []string
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"ok": true, "symbols": [{"symbol": "FOOBAR", "name": "Foobar Corp"}], "orders": []}`))
+	})
+
+	venue := client.Venue(" " + testVenue + " ")
+	assert.Equal(t, testVenue, venue.Symbol())
+
+	stocks, err := venue.Stocks()
+	assert.Nil(t, err)
+	assert.Equal(t, []StockInfo{{Symbol: "FOOBAR", Name: "Foobar Corp"}}, stocks)
+
+	stock := venue.Stock(testStock)
+	assert.Equal(t, venue, stock.Venue())
+
+	account := venue.Account(testAccount)
+	_, err = account.Orders()
+	assert.Nil(t, err)
+	_, err = account.StockOrders(stock)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		"/venues/TESTEX/stocks",
+		"/venues/TESTEX/accounts/EXB123456/orders",
+		"/venues/TESTEX/accounts/EXB123456/stocks/FOOBAR/orders",
+	}, paths)
+
+	assert.Panics(t, func() { venue.Stock(" ") })
+}
+
+func TestStockHandle(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/venues/TESTEX/stocks/FOOBAR/quote":
+			w.Write([]byte(`{"ok": true, "bid": 100, "ask": 110}`))
+		case "/venues/TESTEX/stocks/FOOBAR/orders":
+			var req OrderRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal(t, OrderRequest{Venue: testVenue, Stock: testStock, Account: testAccount, Price: 105, Quantity: 10, Direction: OrderDirectionSell, OrderType: OrderTypeLimit}, req)
+			w.Write([]byte(`{"ok": true, "id": 7, "direction": "sell"}`))
+		default:
+			w.Write([]byte(`{"ok": true}`))
+		}
+	})
+
+	venue := client.Venue(testVenue)
+	foobar := venue.Account(testAccount).Stock(testStock)
+	assert.Equal(t, testAccount, foobar.Account().Name())
+
+	quote, err := foobar.Quote()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(110), quote.AskPrice)
+
+	order, err := foobar.Sell(105, 10, OrderTypeLimit)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), order.OrderID)
+
+	// not bound to an account
+	assert.Panics(t, func() { venue.Stock(testStock).Buy(100, 10, OrderTypeLimit) })
+}
@@ -0,0 +1,242 @@
+/*
+Package indicators implements streaming technical indicators: each indicator
+is fed prices one at a time and exposes its current value.
+
+    sma := indicators.NewSMA(20)
+    for candle := range candles.Start(ctx, poller.Start(ctx)) {
+        indicators.AddCandles(sma, candle)
+        if value, ok := sma.Value(); ok {
+            // ...
+        }
+    }
+
+Prices are in cents, like everywhere in package stockfighter, but as float64
+so indicators can be chained.
+*/
+package indicators
+
+import (
+	"fmt"
+	"math"
+
+	"gpk.io/stockfighter"
+)
+
+// An Indicator is a streaming indicator fed one price at a time.
+type Indicator interface {
+	// Add adds the next price.
+	Add(price float64)
+
+	// Value returns the current value of the indicator. It returns false until
+	// enough prices were added.
+	Value() (float64, bool)
+}
+
+// AddCandles adds the close prices of candles to an indicator.
+func AddCandles(indicator Indicator, candles ...stockfighter.Candle) {
+	for _, candle := range candles {
+		indicator.Add(float64(candle.Close))
+	}
+}
+
+func checkPeriod(period int) {
+	if period <= 0 {
+		panic(fmt.Errorf("Invalid indicator period: %v", period))
+	}
+}
+
+// SMA is a simple moving average over a fixed number of prices.
+type SMA struct {
+	window []float64
+	next   int
+	full   bool
+	sum    float64
+}
+
+// NewSMA creates a new SMA over period prices. This never returns nil.
+func NewSMA(period int) *SMA {
+	checkPeriod(period)
+	return &SMA{window: make([]float64, period)}
+}
+
+// Add adds the next price.
+func (s *SMA) Add(price float64) {
+	s.sum += price - s.window[s.next]
+	s.window[s.next] = price
+	s.next = (s.next + 1) % len(s.window)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+// Value returns the average of the last period prices. It returns false until
+// period prices were added.
+func (s *SMA) Value() (float64, bool) {
+	if !s.full {
+		return 0, false
+	}
+	return s.sum / float64(len(s.window)), true
+}
+
+// EMA is an exponential moving average with smoothing factor 2/(period+1),
+// seeded with the simple average of the first period prices.
+type EMA struct {
+	alpha float64
+	seed  *SMA
+	value float64
+	ready bool
+}
+
+// NewEMA creates a new EMA over period prices. This never returns nil.
+func NewEMA(period int) *EMA {
+	checkPeriod(period)
+	return &EMA{alpha: 2 / float64(period+1), seed: NewSMA(period)}
+}
+
+// Add adds the next price.
+func (e *EMA) Add(price float64) {
+	if e.ready {
+		e.value += e.alpha * (price - e.value)
+		return
+	}
+
+	e.seed.Add(price)
+	e.value, e.ready = e.seed.Value()
+}
+
+// Value returns the current average. It returns false until period prices
+// were added.
+func (e *EMA) Value() (float64, bool) {
+	return e.value, e.ready
+}
+
+// VWAP is the volume-weighted average price of all trades added.
+type VWAP struct {
+	notional float64
+	volume   float64
+}
+
+// NewVWAP creates a new VWAP. This never returns nil.
+func NewVWAP() *VWAP {
+	return &VWAP{}
+}
+
+// AddTrade adds a trade of volume shares at price.
+func (v *VWAP) AddTrade(price, volume float64) {
+	v.notional += price * volume
+	v.volume += volume
+}
+
+// AddCandle adds the volume of a candle at its typical price, (high + low +
+// close) / 3.
+func (v *VWAP) AddCandle(candle stockfighter.Candle) {
+	v.AddTrade(float64(candle.High+candle.Low+candle.Close)/3, float64(candle.Volume))
+}
+
+// Add adds a trade of a single share at price.
+func (v *VWAP) Add(price float64) {
+	v.AddTrade(price, 1)
+}
+
+// Value returns the current VWAP. It returns false until some volume was
+// added.
+func (v *VWAP) Value() (float64, bool) {
+	if v.volume == 0 {
+		return 0, false
+	}
+	return v.notional / v.volume, true
+}
+
+// Bollinger computes Bollinger bands: a simple moving average (the middle
+// band) and bands at a number of standard deviations above and below it.
+type Bollinger struct {
+	sma   *SMA
+	k     float64
+	sumSq float64
+}
+
+// NewBollinger creates new Bollinger bands over period prices at k standard
+// deviations (commonly 20 and 2). This never returns nil.
+func NewBollinger(period int, k float64) *Bollinger {
+	checkPeriod(period)
+	return &Bollinger{sma: NewSMA(period), k: k}
+}
+
+// Add adds the next price.
+func (b *Bollinger) Add(price float64) {
+	old := b.sma.window[b.sma.next]
+	b.sumSq += price*price - old*old
+	b.sma.Add(price)
+}
+
+// Value returns the middle band.
+func (b *Bollinger) Value() (float64, bool) {
+	return b.sma.Value()
+}
+
+// Bands returns the lower, middle, and upper bands. It returns false until
+// period prices were added.
+func (b *Bollinger) Bands() (lower, middle, upper float64, ok bool) {
+	middle, ok = b.sma.Value()
+	if !ok {
+		return 0, 0, 0, false
+	}
+
+	n := float64(len(b.sma.window))
+	variance := math.Max(0, b.sumSq/n-middle*middle)
+	d := b.k * math.Sqrt(variance)
+	return middle - d, middle, middle + d, true
+}
+
+// RSI is the relative strength index, using Wilder's smoothing of average
+// gains and losses.
+type RSI struct {
+	period  int
+	prev    float64
+	n       int
+	avgGain float64
+	avgLoss float64
+}
+
+// NewRSI creates a new RSI over period price changes (commonly 14). This never
+// returns nil.
+func NewRSI(period int) *RSI {
+	checkPeriod(period)
+	return &RSI{period: period}
+}
+
+// Add adds the next price.
+func (r *RSI) Add(price float64) {
+	if r.n == 0 {
+		r.prev = price
+		r.n++
+		return
+	}
+
+	change := price - r.prev
+	r.prev = price
+	gain, loss := math.Max(change, 0), math.Max(-change, 0)
+
+	p := float64(r.period)
+	if r.n <= r.period {
+		// simple average over the first period changes
+		r.avgGain += gain / p
+		r.avgLoss += loss / p
+	} else {
+		r.avgGain = (r.avgGain*(p-1) + gain) / p
+		r.avgLoss = (r.avgLoss*(p-1) + loss) / p
+	}
+	r.n++
+}
+
+// Value returns the current RSI, between 0 and 100. It returns false until
+// period price changes were added.
+func (r *RSI) Value() (float64, bool) {
+	if r.n <= r.period {
+		return 0, false
+	}
+	if r.avgLoss == 0 {
+		return 100, true
+	}
+	return 100 - 100/(1+r.avgGain/r.avgLoss), true
+}
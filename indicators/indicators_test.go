@@ -0,0 +1,98 @@
+package indicators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func add(indicator Indicator, prices ...float64) {
+	for _, price := range prices {
+		indicator.Add(price)
+	}
+}
+
+func TestSMA(t *testing.T) {
+	sma := NewSMA(3)
+	add(sma, 1, 2)
+	_, ok := sma.Value()
+	assert.False(t, ok)
+
+	add(sma, 3)
+	value, ok := sma.Value()
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, value)
+
+	add(sma, 10)
+	value, _ = sma.Value()
+	assert.Equal(t, 5.0, value)
+}
+
+func TestEMA(t *testing.T) {
+	ema := NewEMA(3)
+	add(ema, 1, 2, 3)
+	value, ok := ema.Value()
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, value)
+
+	add(ema, 6)
+	value, _ = ema.Value()
+	assert.Equal(t, 4.0, value)
+}
+
+func TestVWAP(t *testing.T) {
+	vwap := NewVWAP()
+	_, ok := vwap.Value()
+	assert.False(t, ok)
+
+	vwap.AddTrade(100, 1)
+	vwap.AddCandle(stockfighter.Candle{High: 110, Low: 90, Close: 100, Volume: 3})
+	vwap.AddTrade(120, 1)
+	value, ok := vwap.Value()
+	assert.True(t, ok)
+	assert.Equal(t, 104.0, value)
+}
+
+func TestBollinger(t *testing.T) {
+	bollinger := NewBollinger(4, 2)
+	add(bollinger, 2, 4, 4, 4)
+	_, _, _, ok := bollinger.Bands()
+	assert.True(t, ok)
+
+	add(bollinger, 5, 5, 7, 9)
+	lower, middle, upper, ok := bollinger.Bands()
+	assert.True(t, ok)
+	assert.Equal(t, 6.5, middle)
+	assert.InDelta(t, 6.5-2*1.6583123951777, lower, 1e-9)
+	assert.InDelta(t, 6.5+2*1.6583123951777, upper, 1e-9)
+}
+
+func TestRSI(t *testing.T) {
+	rsi := NewRSI(2)
+	add(rsi, 10, 12)
+	_, ok := rsi.Value()
+	assert.False(t, ok)
+
+	add(rsi, 11)
+	value, ok := rsi.Value()
+	assert.True(t, ok)
+	assert.InDelta(t, 100-100/(1+2.0), value, 1e-9)
+
+	add(rsi, 11, 14)
+	value, _ = rsi.Value()
+	assert.InDelta(t, 100-100/(1+1.75/0.125), value, 1e-9)
+
+	allUp := NewRSI(2)
+	add(allUp, 1, 2, 3)
+	value, _ = allUp.Value()
+	assert.Equal(t, 100.0, value)
+}
+
+func TestAddCandles(t *testing.T) {
+	sma := NewSMA(2)
+	AddCandles(sma, stockfighter.Candle{Close: 100}, stockfighter.Candle{Close: 110})
+	value, ok := sma.Value()
+	assert.True(t, ok)
+	assert.Equal(t, 105.0, value)
+}
@@ -0,0 +1,235 @@
+/*
+Package influxsink provides a stockfighter.Sink writing quotes, executions,
+order events, and position samples to InfluxDB, or to any endpoint accepting
+the InfluxDB line protocol (e.g. Telegraf), so that sessions can be charted
+and compared historically.
+
+    sink := influxsink.New(&influxsink.HTTPWriter{
+        URL:   "http://localhost:8086/api/v2/write?org=me&bucket=stockfighter&precision=ns",
+        Token: token,
+    })
+    defer sink.Close()
+    err := stockfighter.PublishQuotes(ctx, sink, poller.Start(ctx))
+
+Points are written to the following measurements, tagged with venue and
+symbol:
+
+    quote       bid, ask, spread, bid_size, ask_size, last, last_size
+    execution   price, filled, standing_id, incoming_id (tagged with account)
+    order       id, price, qty, state (tagged with kind and direction)
+    position    shares, cash, nav
+*/
+package influxsink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// DefaultBatchSize is the default number of points buffered before being
+// written.
+const DefaultBatchSize = 100
+
+// A Sink writes points in the line protocol to a writer, in batches.
+//
+// You can create a new Sink using New function.
+type Sink struct {
+	// Points buffered before being written
+	BatchSize int
+
+	w io.Writer
+
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	points int
+}
+
+var _ stockfighter.Sink = (*Sink)(nil)
+
+// New creates a new Sink writing to w, which is closed with the sink if it
+// is an io.Closer. Each write holds a batch of whole lines. This never
+// returns nil.
+func New(w io.Writer) *Sink {
+	return &Sink{BatchSize: DefaultBatchSize, w: w}
+}
+
+// Publish implements stockfighter.Sink.
+func (sink *Sink) Publish(ctx context.Context, event stockfighter.SinkEvent) error {
+	tags := []string{"venue", event.Venue, "symbol", event.Symbol}
+	var fields []field
+	switch event.Kind {
+	case stockfighter.SinkEventQuote:
+		q := event.Quote
+		if q.HasBid {
+			fields = append(fields, intField("bid", int64(q.BidPrice)), intField("bid_size", int64(q.BidSize)))
+		}
+		if q.HasAsk {
+			fields = append(fields, intField("ask", int64(q.AskPrice)), intField("ask_size", int64(q.AskSize)))
+		}
+		if q.HasBid && q.HasAsk {
+			fields = append(fields, intField("spread", int64(q.AskPrice)-int64(q.BidPrice)))
+		}
+		fields = append(fields, intField("last", int64(q.LastPrice)), intField("last_size", int64(q.LastSize)))
+	case stockfighter.SinkEventExecution:
+		e := event.Execution
+		tags = append(tags, "account", e.Account)
+		fields = append(fields, intField("price", int64(e.Price)), intField("filled", int64(e.Filled)),
+			intField("standing_id", e.StandingID), intField("incoming_id", e.IncomingID))
+	case stockfighter.SinkEventOrder:
+		o := event.Order
+		tags = append(tags, "kind", o.Kind, "direction", o.Direction)
+		fields = append(fields, intField("id", o.OrderID), intField("price", int64(o.Price)), intField("qty", int64(o.Quantity)),
+			stringField("state", o.State))
+	default:
+		return fmt.Errorf("Unknown sink event kind: %v", event.Kind)
+	}
+
+	return sink.write(event.Kind, tags, fields, event.Time)
+}
+
+// WritePosition writes a sample of the position of an account in a stock,
+// with its net asset value at the given price.
+func (sink *Sink) WritePosition(venue, stock string, position stockfighter.Position, price uint64, t time.Time) error {
+	return sink.write("position", []string{"venue", venue, "symbol", stock},
+		[]field{intField("shares", position.Shares), intField("cash", position.Cash), intField("nav", position.NAV(price))}, t)
+}
+
+// Flush writes the points buffered so far.
+func (sink *Sink) Flush() error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	return sink.flush()
+}
+
+// Close implements stockfighter.Sink.
+func (sink *Sink) Close() error {
+	err := sink.Flush()
+	if closer, ok := sink.w.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (sink *Sink) write(measurement string, tags []string, fields []field, t time.Time) error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	sink.buf.WriteString(escape(measurement, ", "))
+	for i := 0; i+1 < len(tags); i += 2 {
+		// empty tag values are not allowed
+		if tags[i+1] != "" {
+			sink.buf.WriteString("," + escape(tags[i], ",= ") + "=" + escape(tags[i+1], ",= "))
+		}
+	}
+	for i, f := range fields {
+		if i == 0 {
+			sink.buf.WriteByte(' ')
+		} else {
+			sink.buf.WriteByte(',')
+		}
+		sink.buf.WriteString(escape(f.key, ",= ") + "=" + f.value)
+	}
+	if !t.IsZero() {
+		sink.buf.WriteString(" " + strconv.FormatInt(t.UnixNano(), 10))
+	}
+	sink.buf.WriteByte('\n')
+	sink.points++
+
+	if sink.points >= sink.BatchSize {
+		return sink.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered points. sink.mu must be held.
+func (sink *Sink) flush() error {
+	if sink.points == 0 {
+		return nil
+	}
+
+	_, err := sink.w.Write(sink.buf.Bytes())
+	sink.buf.Reset()
+	sink.points = 0
+	return err
+}
+
+type field struct {
+	key   string
+	value string
+}
+
+func intField(key string, value int64) field {
+	return field{key: key, value: strconv.FormatInt(value, 10) + "i"}
+}
+
+func stringField(key, value string) field {
+	return field{key: key, value: `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`}
+}
+
+// escape escapes the given special characters with backslashes.
+func escape(s, special string) string {
+	if !strings.ContainsAny(s, special) {
+		return s
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// An HTTPWriter is an io.Writer posting what is written to an InfluxDB write
+// endpoint, e.g. http://localhost:8086/api/v2/write?org=ORG&bucket=BUCKET.
+type HTTPWriter struct {
+	URL string
+
+	// API token, sent in the Authorization header if not empty
+	Token string
+
+	// HTTP client (http.DefaultClient if nil)
+	Client *http.Client
+}
+
+// Write implements io.Writer.
+func (w *HTTPWriter) Write(p []byte) (int, error) {
+	req, err := http.NewRequest("POST", w.URL, bytes.NewReader(p))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if w.Token != "" {
+		req.Header.Set("Authorization", "Token "+w.Token)
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return 0, fmt.Errorf("InfluxDB write failed: %v %v", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return len(p), nil
+}
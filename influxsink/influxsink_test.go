@@ -0,0 +1,59 @@
+package influxsink
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func TestSink(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	var buf bytes.Buffer
+	sink := New(&buf)
+
+	quote := &stockfighter.Quote{Venue: "TESTEX", Symbol: "FOOBAR", HasBid: true, BidPrice: 5000, BidSize: 10,
+		HasAsk: true, AskPrice: 5100, AskSize: 20, LastPrice: 5050, LastSize: 5, QuoteTime: ts}
+	assert.Nil(t, sink.Publish(context.Background(), stockfighter.QuoteEvent(quote)))
+	assert.Nil(t, sink.Publish(context.Background(), stockfighter.OrderEvent(stockfighter.BlotterEvent{Time: ts, Kind: "fill",
+		Venue: "TESTEX", Symbol: "FOOBAR", OrderID: 1, Direction: "buy", State: `"odd" state`, Price: 5000, Quantity: 10})))
+	assert.Nil(t, sink.WritePosition("TEST EX", "FOOBAR", stockfighter.Position{Shares: 10, Cash: -50000}, 5100, ts))
+	assert.Empty(t, buf.String())
+
+	assert.Nil(t, sink.Close())
+	assert.Equal(t, "quote,venue=TESTEX,symbol=FOOBAR bid=5000i,bid_size=10i,ask=5100i,ask_size=20i,spread=100i,last=5050i,last_size=5i 1449219736000000000\n"+
+		`order,venue=TESTEX,symbol=FOOBAR,kind=fill,direction=buy id=1i,price=5000i,qty=10i,state="\"odd\" state" 1449219736000000000`+"\n"+
+		"position,venue=TEST\\ EX,symbol=FOOBAR shares=10i,cash=-50000i,nav=1000i 1449219736000000000\n", buf.String())
+
+	assert.NotNil(t, sink.Publish(context.Background(), stockfighter.SinkEvent{Kind: "other"}))
+}
+
+func TestHTTPWriter(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Token TOKEN", r.Header.Get("Authorization"))
+		body, _ = io.ReadAll(r.Body)
+		if bytes.Contains(body, []byte("bad")) {
+			http.Error(w, "unable to parse", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink := New(&HTTPWriter{URL: server.URL, Token: "TOKEN"})
+	sink.BatchSize = 1
+	assert.Nil(t, sink.WritePosition("TESTEX", "FOOBAR", stockfighter.Position{Shares: 1}, 100, time.Time{}))
+	assert.Equal(t, "position,venue=TESTEX,symbol=FOOBAR shares=1i,cash=0i,nav=100i\n", string(body))
+
+	err := sink.WritePosition("TESTEX", "bad", stockfighter.Position{}, 0, time.Time{})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "400 Bad Request unable to parse")
+	}
+}
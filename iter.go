@@ -0,0 +1,107 @@
+//go:build go1.23
+
+package stockfighter
+
+import (
+	"context"
+	"iter"
+)
+
+// Quotes returns an iterator over the new quotes of the given stocks of a
+// venue (all the stocks of the venue if none), polled every
+// DefaultPollInterval, with the polling errors:
+//
+//     for quote, err := range client.Quotes(ctx, venue, stock) {
+//         if err != nil {
+//             continue
+//         }
+//         if quote.AskPrice < limit {
+//             break
+//         }
+//     }
+//
+// Polling starts when the iteration does, and stops when the loop breaks or
+// ctx is done, which ends the iteration.
+func (client *Client) Quotes(ctx context.Context, venue string, stocks ...string) iter.Seq2[Quote, error] {
+	return func(yield func(Quote, error) bool) {
+		symbols := stocks
+		if len(symbols) == 0 {
+			infos, err := client.ListStocks(venue)
+			if err != nil {
+				yield(Quote{}, err)
+				return
+			}
+			for _, info := range infos {
+				symbols = append(symbols, info.Symbol)
+			}
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		for update := range NewQuotePoller(client, venue, symbols, DefaultPollInterval).Start(ctx) {
+			var quote Quote
+			if update.Quote != nil {
+				quote = *update.Quote
+			}
+			if !yield(quote, update.Err) {
+				return
+			}
+		}
+	}
+}
+
+// Orders returns an iterator over the orders of an account in a venue, or
+// over its orders of a stock if stock is not "". The orders are requested
+// when the iteration starts; an error ends the iteration.
+func (client *Client) Orders(venue, account, stock string) iter.Seq2[Order, error] {
+	return func(yield func(Order, error) bool) {
+		var orders []Order
+		var err error
+		if stock == "" {
+			orders, err = client.GetAllOrders(venue, account)
+		} else {
+			orders, err = client.GetStockOrders(venue, account, stock)
+		}
+		if err != nil {
+			yield(Order{}, err)
+			return
+		}
+
+		for _, order := range orders {
+			if !yield(order, nil) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the updates of the stream, with their
+// errors. Breaking out of the loop stops consuming the stream, but not the
+// stream itself: cancel its context for that.
+func (s *QuoteStream) All() iter.Seq2[Quote, error] {
+	return func(yield func(Quote, error) bool) {
+		for update := range s.C {
+			var quote Quote
+			if update.Quote != nil {
+				quote = *update.Quote
+			}
+			if !yield(quote, update.Err) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the executions of the stream. Breaking out
+// of the loop stops consuming the stream, but not the stream itself: cancel
+// its context for that.
+func (s *ExecutionStream) All() iter.Seq[*Execution] {
+	return func(yield func(*Execution) bool) {
+		for execution := range s.C {
+			if !yield(execution) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+//go:build go1.23
+
+package stockfighter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotesIterator(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/venues/"+testVenue+"/stocks" {
+			fmt.Fprintf(w, `{"ok": true, "symbols": [{"name": "Foobar", "symbol": "%s"}, {"name": "Baz", "symbol": "BAZ"}]}`, testStock)
+			return
+		}
+		fmt.Fprintf(w, `{"ok": true, "symbol": "%s", "venue": "%s", "bid": 100, "quoteTime": "2015-12-04T09:02:16Z"}`, testStock, testVenue)
+	})
+
+	var n int
+	for quote, err := range client.Quotes(context.Background(), testVenue) {
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(100), quote.BidPrice)
+		if n++; n == 2 {
+			break
+		}
+	}
+	assert.Equal(t, 2, n)
+}
+
+func TestOrdersIterator(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/venues/"+testVenue+"/accounts/"+testAccount+"/orders" {
+			fmt.Fprintf(w, `{"ok": true, "venue": "%s", "orders": [{"id": 1}, {"id": 2}, {"id": 3}]}`, testVenue)
+			return
+		}
+		w.WriteHeader(404)
+		fmt.Fprint(w, `{"ok": false, "error": "Stock BAZ not found"}`)
+	})
+
+	var ids []int64
+	for order, err := range client.Orders(testVenue, testAccount, "") {
+		assert.Nil(t, err)
+		ids = append(ids, order.OrderID)
+		if order.OrderID == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int64{1, 2}, ids)
+
+	for _, err := range client.Orders(testVenue, testAccount, "BAZ") {
+		var notFound *ErrorStockNotFound
+		assert.True(t, errors.As(err, &notFound))
+	}
+}
+
+func TestStreamIterators(t *testing.T) {
+	updates := make(chan QuoteUpdate, 2)
+	updates <- QuoteUpdate{Quote: &Quote{BidPrice: 100}}
+	updates <- QuoteUpdate{Err: errors.New("timeout")}
+	close(updates)
+
+	var errs int
+	for _, err := range BufferQuotes(context.Background(), updates, 2, OverflowBlock).All() {
+		if err != nil {
+			errs++
+		}
+	}
+	assert.Equal(t, 1, errs)
+
+	executions := make(chan *Execution, 1)
+	executions <- &Execution{Filled: 10}
+	close(executions)
+	for execution := range BufferExecutions(context.Background(), executions, 1, OverflowBlock).All() {
+		assert.Equal(t, uint64(10), execution.Filled)
+	}
+}
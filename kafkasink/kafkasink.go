@@ -0,0 +1,73 @@
+/*
+Package kafkasink provides a stockfighter.Sink publishing events to Kafka.
+
+Events are published as JSON (see stockfighter.SinkEvent) to one topic per
+event kind, e.g. "stockfighter.quote", keyed by venue and stock so that the
+events of a stock stay in order within a partition:
+
+    sink := kafkasink.New([]string{"localhost:9092"}, "stockfighter")
+    defer sink.Close()
+    err := stockfighter.PublishQuotes(ctx, sink, poller.Start(ctx))
+*/
+package kafkasink
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/segmentio/kafka-go"
+	"gpk.io/stockfighter"
+)
+
+// A Sink publishes events to Kafka topics.
+//
+// You can create a new Sink using New function.
+type Sink struct {
+	writer writer
+	prefix string
+}
+
+// writer is the part of kafka.Writer used by a Sink, which tests fake.
+type writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+var _ stockfighter.Sink = (*Sink)(nil)
+
+// New creates a new Sink publishing to the given brokers, to topics named
+// after the prefix and the event kind. This never returns nil.
+func New(brokers []string, prefix string) *Sink {
+	return &Sink{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Balancer: &kafka.Hash{},
+		},
+		prefix: prefix,
+	}
+}
+
+// Topic returns the topic events of a kind are published to.
+func (sink *Sink) Topic(kind string) string {
+	return sink.prefix + "." + kind
+}
+
+// Publish implements stockfighter.Sink.
+func (sink *Sink) Publish(ctx context.Context, event stockfighter.SinkEvent) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return sink.writer.WriteMessages(ctx, kafka.Message{
+		Topic: sink.Topic(event.Kind),
+		Key:   []byte(event.Venue + "." + event.Symbol),
+		Value: value,
+		Time:  event.Time,
+	})
+}
+
+// Close implements stockfighter.Sink.
+func (sink *Sink) Close() error {
+	return sink.writer.Close()
+}
@@ -0,0 +1,51 @@
+package kafkasink
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+// fakeWriter records the messages written instead of sending them.
+type fakeWriter struct {
+	msgs   []kafka.Message
+	closed bool
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.msgs = append(w.msgs, msgs...)
+	return nil
+}
+
+func (w *fakeWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestSink(t *testing.T) {
+	sink := New([]string{"localhost:9092"}, "stockfighter")
+	w := &fakeWriter{}
+	sink.writer = w
+
+	quoteTime := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	event := stockfighter.QuoteEvent(&stockfighter.Quote{Venue: "TESTEX", Symbol: "FOOBAR", HasBid: true, BidPrice: 5000, QuoteTime: quoteTime})
+	assert.Nil(t, sink.Publish(context.Background(), event))
+	assert.Nil(t, sink.Close())
+
+	assert.True(t, w.closed)
+	assert.Equal(t, 1, len(w.msgs))
+	msg := w.msgs[0]
+	assert.Equal(t, "stockfighter.quote", msg.Topic)
+	assert.Equal(t, "TESTEX.FOOBAR", string(msg.Key))
+	assert.Equal(t, quoteTime, msg.Time)
+
+	var published stockfighter.SinkEvent
+	assert.Nil(t, json.Unmarshal(msg.Value, &published))
+	assert.Equal(t, stockfighter.SinkEventQuote, published.Kind)
+	assert.Equal(t, uint64(5000), published.Quote.BidPrice)
+}
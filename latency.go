@@ -0,0 +1,101 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// PingRTT checks if the API is up like Ping, and returns the round-trip time
+// of the heartbeat request.
+func (client *Client) PingRTT() (time.Duration, error) {
+	start := time.Now()
+	err := client.Ping()
+	return time.Since(start), err
+}
+
+// PingVenueRTT checks if a venue is up like PingVenue, and returns the
+// round-trip time of the heartbeat request.
+func (client *Client) PingVenueRTT(venue string) (time.Duration, error) {
+	start := time.Now()
+	err := client.PingVenue(venue)
+	return time.Since(start), err
+}
+
+// LatencyStats represents round-trip time statistics of API requests.
+type LatencyStats struct {
+	Samples int
+
+	Min time.Duration
+	Avg time.Duration
+	P99 time.Duration
+	Max time.Duration
+
+	// Mean absolute difference between consecutive samples
+	Jitter time.Duration
+}
+
+func (s LatencyStats) String() string {
+	return fmt.Sprintf("%v samples: min %v, avg %v, p99 %v, max %v, jitter %v", s.Samples, s.Min, s.Avg, s.P99, s.Max, s.Jitter)
+}
+
+// MeasureLatency measures the latency of a venue by sending n heartbeat
+// requests one after the other, e.g. to choose polling rates.
+//
+// Requests are made with ctx (see Client.WithContext). The first error
+// returned by a heartbeat, or ctx.Err() if ctx is done before all the
+// requests were sent, stops the measure and is returned.
+func (client *Client) MeasureLatency(ctx context.Context, venue string, n int) (*LatencyStats, error) {
+	if n <= 0 {
+		panic(fmt.Errorf("Invalid number of samples: %v", n))
+	}
+	client = client.WithContext(ctx)
+
+	samples := make([]time.Duration, 0, n)
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		rtt, err := client.PingVenueRTT(venue)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+		samples = append(samples, rtt)
+	}
+
+	return latencyStats(samples), nil
+}
+
+func latencyStats(samples []time.Duration) *LatencyStats {
+	stats := &LatencyStats{Samples: len(samples)}
+
+	var sum, jitter time.Duration
+	for i, rtt := range samples {
+		sum += rtt
+		if i > 0 {
+			d := rtt - samples[i-1]
+			if d < 0 {
+				d = -d
+			}
+			jitter += d
+		}
+	}
+	stats.Avg = sum / time.Duration(len(samples))
+	if len(samples) > 1 {
+		stats.Jitter = jitter / time.Duration(len(samples)-1)
+	}
+
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	stats.Min, stats.Max = sorted[0], sorted[len(sorted)-1]
+	// nearest rank
+	stats.P99 = sorted[int(math.Ceil(0.99*float64(len(sorted))))-1]
+
+	return stats
+}
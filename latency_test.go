@@ -0,0 +1,43 @@
+package stockfighter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasureLatency(t *testing.T) {
+	var requests int
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/venues/TESTEX/heartbeat" {
+			requests++
+		}
+		w.Write([]byte(`{"ok": true, "venue": "TESTEX"}`))
+	})
+
+	stats, err := client.MeasureLatency(context.Background(), testVenue, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, requests)
+	assert.Equal(t, 5, stats.Samples)
+	assert.True(t, stats.Min > 0 && stats.Min <= stats.Avg && stats.Avg <= stats.Max && stats.P99 <= stats.Max)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.MeasureLatency(ctx, testVenue, 5)
+	assert.Equal(t, context.Canceled, err)
+
+	rtt, err := client.PingRTT()
+	assert.Nil(t, err)
+	assert.True(t, rtt > 0)
+
+	assert.Panics(t, func() { client.MeasureLatency(context.Background(), testVenue, 0) })
+}
+
+func TestLatencyStats(t *testing.T) {
+	ms := time.Millisecond
+	stats := latencyStats([]time.Duration{10 * ms, 30 * ms, 20 * ms, 20 * ms})
+	assert.Equal(t, &LatencyStats{Samples: 4, Min: 10 * ms, Avg: 20 * ms, P99: 30 * ms, Max: 30 * ms, Jitter: 10 * ms}, stats)
+}
@@ -0,0 +1,81 @@
+package stockfighter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Ledger export formats.
+const (
+	LedgerFormatBeancount = "beancount"
+	LedgerFormatLedger    = "ledger"
+)
+
+// LedgerRootAccount is the account under which WriteLedger books stock
+// positions (<root>:<venue>:<stock>) and cash (<root>:<venue>:Cash).
+const LedgerRootAccount = "Assets:Stockfighter"
+
+type ledgerFill struct {
+	OrderFillInfo
+	orderID   int64
+	direction string
+}
+
+// WriteLedger writes the fills of the given orders for a stock as plain-text
+// accounting transactions in Beancount or ledger-cli format. Each fill is
+// booked as a transaction moving shares into (or out of) the stock account
+// against the venue cash account, in USD.
+//
+// Fills are written in timestamp order. For Beancount, the accounts used are
+// opened at the date of the first fill.
+func WriteLedger(w io.Writer, format, venue, stock string, orders []Order) error {
+	if format != LedgerFormatBeancount && format != LedgerFormatLedger {
+		return fmt.Errorf("Invalid ledger format: %v", format)
+	}
+
+	var fills []ledgerFill
+	for _, order := range orders {
+		for _, fill := range order.Fills {
+			fills = append(fills, ledgerFill{OrderFillInfo: fill, orderID: order.OrderID, direction: order.Direction})
+		}
+	}
+	sort.SliceStable(fills, func(i, j int) bool {
+		return fills[i].Timestamp.Before(fills[j].Timestamp)
+	})
+
+	stockAccount := LedgerRootAccount + ":" + venue + ":" + stock
+	cashAccount := LedgerRootAccount + ":" + venue + ":Cash"
+
+	bw := bufio.NewWriter(w)
+	if format == LedgerFormatBeancount && len(fills) > 0 {
+		date := fills[0].Timestamp.UTC().Format("2006-01-02")
+		fmt.Fprintf(bw, "%v open %v %v\n", date, stockAccount, stock)
+		fmt.Fprintf(bw, "%v open %v USD\n\n", date, cashAccount)
+	}
+
+	for _, fill := range fills {
+		qty := int64(fill.Quantity)
+		if fill.direction == OrderDirectionSell {
+			qty = -qty
+		}
+		price := fmt.Sprintf("%d.%02d", fill.Price/100, fill.Price%100)
+		title := fmt.Sprintf("%v %v %v @ %v (order %v)", fill.direction, fill.Quantity, stock, venue, fill.orderID)
+
+		switch format {
+		case LedgerFormatBeancount:
+			fmt.Fprintf(bw, "%v * %q\n", fill.Timestamp.UTC().Format("2006-01-02"), title)
+			fmt.Fprintf(bw, "  %v  %d %v @ %v USD\n", stockAccount, qty, stock, price)
+			fmt.Fprintf(bw, "  %v\n\n", cashAccount)
+		case LedgerFormatLedger:
+			fmt.Fprintf(bw, "%v * %v\n", fill.Timestamp.UTC().Format("2006/01/02"), title)
+			fmt.Fprintf(bw, "    ; time: %v\n", fill.Timestamp.UTC().Format(time.RFC3339Nano))
+			fmt.Fprintf(bw, "    %v  %d %v @ $%v\n", stockAccount, qty, stock, price)
+			fmt.Fprintf(bw, "    %v\n\n", cashAccount)
+		}
+	}
+
+	return bw.Flush()
+}
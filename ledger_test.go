@@ -0,0 +1,43 @@
+package stockfighter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteLedger(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	orders := []Order{
+		{OrderID: 2, Direction: OrderDirectionSell, Fills: []OrderFillInfo{{Price: 5301, Quantity: 4, Timestamp: ts.Add(time.Minute)}}},
+		{OrderID: 1, Direction: OrderDirectionBuy, Fills: []OrderFillInfo{{Price: 5264, Quantity: 10, Timestamp: ts}}},
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteLedger(&buf, LedgerFormatBeancount, testVenue, testStock, orders))
+	assert.Equal(t, `2015-12-04 open Assets:Stockfighter:TESTEX:FOOBAR FOOBAR
+2015-12-04 open Assets:Stockfighter:TESTEX:Cash USD
+
+2015-12-04 * "buy 10 FOOBAR @ TESTEX (order 1)"
+  Assets:Stockfighter:TESTEX:FOOBAR  10 FOOBAR @ 52.64 USD
+  Assets:Stockfighter:TESTEX:Cash
+
+2015-12-04 * "sell 4 FOOBAR @ TESTEX (order 2)"
+  Assets:Stockfighter:TESTEX:FOOBAR  -4 FOOBAR @ 53.01 USD
+  Assets:Stockfighter:TESTEX:Cash
+
+`, buf.String())
+
+	buf.Reset()
+	assert.Nil(t, WriteLedger(&buf, LedgerFormatLedger, testVenue, testStock, orders[1:]))
+	assert.Equal(t, `2015/12/04 * buy 10 FOOBAR @ TESTEX (order 1)
+    ; time: 2015-12-04T09:02:16Z
+    Assets:Stockfighter:TESTEX:FOOBAR  10 FOOBAR @ $52.64
+    Assets:Stockfighter:TESTEX:Cash
+
+`, buf.String())
+
+	assert.NotNil(t, WriteLedger(&buf, "csv", testVenue, testStock, orders))
+}
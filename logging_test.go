@@ -0,0 +1,28 @@
+package stockfighter
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true, "id": 42, "direction": "buy", "orderType": "limit", "price": 100, "originalQty": 10}`))
+	})
+	client := NewClient(testApiKey, WithBaseURL(server.transport.baseURL), WithLogger(logger))
+
+	_, err := client.PlaceOrder(testVenue, testStock, testAccount, 100, 10, OrderDirectionBuy, OrderTypeLimit)
+	assert.Nil(t, err)
+
+	logs := buf.String()
+	assert.Contains(t, logs, `level=DEBUG msg="stockfighter: request" method=POST path=/venues/TESTEX/stocks/FOOBAR/orders`)
+	assert.Contains(t, logs, `level=DEBUG msg="stockfighter: response" method=POST path=/venues/TESTEX/stocks/FOOBAR/orders status=200`)
+	assert.Contains(t, logs, `level=INFO msg="stockfighter: order placed" venue=TESTEX stock=FOOBAR account=EXB123456 id=42`)
+}
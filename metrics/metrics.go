@@ -0,0 +1,194 @@
+/*
+Package metrics exposes Prometheus metrics for Stockfighter API usage and
+trading activity.
+
+API requests are instrumented by wrapping the HTTP transport of the client:
+
+    m := metrics.New(prometheus.DefaultRegisterer)
+    client := stockfighter.NewClient(apiKey, stockfighter.WithHTTPClient(&http.Client{
+        Transport: m.RoundTripper(nil),
+    }))
+
+The requests made by each subsystem of a client (see
+stockfighter.Client.Subsystem) are exported by a UsageCollector:
+
+    prometheus.MustRegister(metrics.UsageCollector(client))
+
+Fills and positions are reported by the code that tracks them, using
+ObserveOrder and SetPosition.
+*/
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gpk.io/stockfighter"
+)
+
+// Namespace is the namespace of all metrics.
+const Namespace = "stockfighter"
+
+// Metrics holds the collectors of all metrics.
+//
+// You can create a new Metrics using New function.
+type Metrics struct {
+	requests     *prometheus.CounterVec
+	errors       *prometheus.CounterVec
+	latency      *prometheus.HistogramVec
+	ordersPlaced *prometheus.CounterVec
+	fills        *prometheus.CounterVec
+	filledShares *prometheus.CounterVec
+	positions    *prometheus.GaugeVec
+
+	mu        sync.Mutex
+	seenFills map[orderKey]int
+}
+
+// orderKey identifies an order. Order IDs are only unique within a venue.
+type orderKey struct {
+	venue   string
+	orderID int64
+}
+
+// New creates the metrics and registers them on reg. This never returns nil.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "api_requests_total",
+			Help:      "API requests by endpoint, method, and HTTP status code.",
+		}, []string{"endpoint", "method", "code"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "api_errors_total",
+			Help:      "Failed API requests by endpoint and error class (transport, client, server).",
+		}, []string{"endpoint", "class"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "api_request_duration_seconds",
+			Help:      "API response latency by endpoint.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+		ordersPlaced: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "orders_placed_total",
+			Help:      "Orders placed successfully by venue and stock.",
+		}, []string{"venue", "stock"}),
+		fills: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "fills_total",
+			Help:      "Order fills by venue, stock, and direction.",
+		}, []string{"venue", "stock", "direction"}),
+		filledShares: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "filled_shares_total",
+			Help:      "Filled quantity by venue, stock, and direction.",
+		}, []string{"venue", "stock", "direction"}),
+		positions: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "position_shares",
+			Help:      "Current position by venue and stock.",
+		}, []string{"venue", "stock"}),
+		seenFills: make(map[orderKey]int),
+	}
+
+	reg.MustRegister(m.requests, m.errors, m.latency, m.ordersPlaced, m.fills, m.filledShares, m.positions)
+	return m
+}
+
+// RoundTripper returns an http.RoundTripper instrumenting the API requests
+// made through next (http.DefaultTransport if nil).
+func (m *Metrics) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		endpoint := stockfighter.ParseEndpoint(req.Method, req.URL.Path)
+
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		m.latency.WithLabelValues(endpoint.Name).Observe(time.Since(start).Seconds())
+
+		if err != nil {
+			m.requests.WithLabelValues(endpoint.Name, req.Method, "").Inc()
+			m.errors.WithLabelValues(endpoint.Name, "transport").Inc()
+			return resp, err
+		}
+
+		m.requests.WithLabelValues(endpoint.Name, req.Method, strconv.Itoa(resp.StatusCode)).Inc()
+		switch {
+		case resp.StatusCode >= 500:
+			m.errors.WithLabelValues(endpoint.Name, "server").Inc()
+		case resp.StatusCode >= 400:
+			m.errors.WithLabelValues(endpoint.Name, "client").Inc()
+		case endpoint.Name == stockfighter.EndpointPlaceOrder:
+			m.ordersPlaced.WithLabelValues(endpoint.Venue, endpoint.Stock).Inc()
+		}
+
+		return resp, nil
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// ObserveOrder counts the fills of an order status not observed before. The
+// same order can be observed any number of times as its status is polled.
+func (m *Metrics) ObserveOrder(venue, stock string, order *stockfighter.Order) {
+	key := orderKey{venue, order.OrderID}
+	m.mu.Lock()
+	seen := m.seenFills[key]
+	if seen < len(order.Fills) {
+		m.seenFills[key] = len(order.Fills)
+	}
+	m.mu.Unlock()
+
+	if seen >= len(order.Fills) {
+		return
+	}
+	for _, fill := range order.Fills[seen:] {
+		m.fills.WithLabelValues(venue, stock, order.Direction).Inc()
+		m.filledShares.WithLabelValues(venue, stock, order.Direction).Add(float64(fill.Quantity))
+	}
+}
+
+// SetPosition sets the current position in a stock.
+func (m *Metrics) SetPosition(venue, stock string, position int64) {
+	m.positions.WithLabelValues(venue, stock).Set(float64(position))
+}
+
+// usageDesc describes the metric exported by a UsageCollector.
+var usageDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(Namespace, "", "subsystem_requests_total"),
+	"API requests by subsystem of the client, including failed requests.",
+	[]string{"subsystem"}, nil,
+)
+
+// UsageCollector returns a collector exporting the API requests made so far
+// by client and the clients derived from it, per subsystem (see
+// stockfighter.Client.Usage).
+func UsageCollector(client *stockfighter.Client) prometheus.Collector {
+	return usageCollector{client}
+}
+
+type usageCollector struct {
+	client *stockfighter.Client
+}
+
+func (c usageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- usageDesc
+}
+
+func (c usageCollector) Collect(ch chan<- prometheus.Metric) {
+	for subsystem, n := range c.client.Usage() {
+		ch <- prometheus.MustNewConstMetric(usageDesc, prometheus.CounterValue, float64(n), subsystem)
+	}
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+// gather returns the values of the metrics of a registry by name and label
+// values, sorted by label name, e.g. "stockfighter_fills_total{buy,FOOBAR,TESTEX}".
+func gather(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			name := family.GetName() + "{"
+			for i, label := range metric.GetLabel() {
+				if i > 0 {
+					name += ","
+				}
+				name += label.GetValue()
+			}
+			name += "}"
+
+			switch {
+			case metric.Counter != nil:
+				values[name] = metric.Counter.GetValue()
+			case metric.Gauge != nil:
+				values[name] = metric.Gauge.GetValue()
+			case metric.Histogram != nil:
+				values[name] = float64(metric.Histogram.GetSampleCount())
+			}
+		}
+	}
+	return values
+}
+
+func TestObserveOrder(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := New(reg)
+
+	order := &stockfighter.Order{OrderID: 1, Direction: stockfighter.OrderDirectionBuy, Fills: []stockfighter.OrderFillInfo{{Price: 100, Quantity: 10}}}
+	m.ObserveOrder("TESTEX", "FOOBAR", order)
+	m.ObserveOrder("TESTEX", "FOOBAR", order)
+	order.Fills = append(order.Fills, stockfighter.OrderFillInfo{Price: 101, Quantity: 5})
+	m.ObserveOrder("TESTEX", "FOOBAR", order)
+
+	// order IDs are only unique within a venue
+	m.ObserveOrder("OTHEREX", "FOOBAR", &stockfighter.Order{OrderID: 1, Direction: stockfighter.OrderDirectionBuy, Fills: []stockfighter.OrderFillInfo{{Price: 100, Quantity: 7}}})
+
+	m.SetPosition("TESTEX", "FOOBAR", 15)
+
+	values := gather(t, reg)
+	assert.Equal(t, float64(2), values["stockfighter_fills_total{buy,FOOBAR,TESTEX}"])
+	assert.Equal(t, float64(15), values["stockfighter_filled_shares_total{buy,FOOBAR,TESTEX}"])
+	assert.Equal(t, float64(1), values["stockfighter_fills_total{buy,FOOBAR,OTHEREX}"])
+	assert.Equal(t, float64(7), values["stockfighter_filled_shares_total{buy,FOOBAR,OTHEREX}"])
+	assert.Equal(t, float64(15), values["stockfighter_position_shares{FOOBAR,TESTEX}"])
+}
+
+func TestRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			w.Write([]byte(`{"ok": true, "id": 1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"ok": false, "error": "No venue exists with the symbol NOPE"}`))
+	}))
+	defer server.Close()
+
+	reg := prometheus.NewRegistry()
+	m := New(reg)
+	client := stockfighter.NewClient("KEY", stockfighter.WithBaseURL(server.URL), stockfighter.WithHTTPClient(&http.Client{
+		Transport: m.RoundTripper(nil),
+	}))
+
+	_, err := client.PlaceOrder("TESTEX", "FOOBAR", "EXB123456", 100, 10, stockfighter.OrderDirectionBuy, stockfighter.OrderTypeLimit)
+	assert.Nil(t, err)
+	_, err = client.GetQuote("NOPE", "FOOBAR")
+	assert.NotNil(t, err)
+
+	values := gather(t, reg)
+	assert.Equal(t, float64(1), values["stockfighter_orders_placed_total{FOOBAR,TESTEX}"])
+	assert.Equal(t, float64(1), values["stockfighter_api_requests_total{200,"+stockfighter.EndpointPlaceOrder+",POST}"])
+	assert.Equal(t, float64(1), values["stockfighter_api_requests_total{404,"+stockfighter.EndpointQuote+",GET}"])
+	assert.Equal(t, float64(1), values["stockfighter_api_errors_total{client,"+stockfighter.EndpointQuote+"}"])
+	assert.Equal(t, float64(1), values["stockfighter_api_request_duration_seconds{"+stockfighter.EndpointQuote+"}"])
+}
+
+func TestUsageCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	reg := prometheus.NewRegistry()
+	client := stockfighter.NewClient("KEY", stockfighter.WithBaseURL(server.URL))
+	reg.MustRegister(UsageCollector(client))
+
+	assert.Nil(t, client.Ping())
+	assert.Nil(t, client.Subsystem("poller").Ping())
+	assert.Nil(t, client.Subsystem("poller").Ping())
+
+	values := gather(t, reg)
+	assert.Equal(t, float64(1), values["stockfighter_subsystem_requests_total{default}"])
+	assert.Equal(t, float64(2), values["stockfighter_subsystem_requests_total{poller}"])
+}
@@ -0,0 +1,67 @@
+package stockfighter
+
+import "fmt"
+
+// Microprice returns the mid price of the orderbook weighted by the sizes at
+// the best bid and ask: it leans toward the side with less size, where the
+// next trade is more likely. It returns false if either side is empty.
+//
+// Entries are expected best prices first, as returned by the API.
+func (orderbook *Orderbook) Microprice() (float64, bool) {
+	bid, bidSize := bookLevels(orderbook.Bids, 1)
+	ask, askSize := bookLevels(orderbook.Asks, 1)
+	if bidSize == 0 || askSize == 0 {
+		return 0, false
+	}
+
+	return (float64(bid)*float64(askSize) + float64(ask)*float64(bidSize)) / float64(bidSize+askSize), true
+}
+
+// Imbalance returns the imbalance between the quantities on the best
+// depthLevels price levels of each side of the orderbook, from -1 (all asks)
+// to 1 (all bids). It returns false if the orderbook is empty.
+//
+// Entries are expected best prices first, as returned by the API.
+func (orderbook *Orderbook) Imbalance(depthLevels int) (float64, bool) {
+	if depthLevels <= 0 {
+		panic(fmt.Errorf("Invalid depth levels: %v", depthLevels))
+	}
+
+	_, bidQty := bookLevels(orderbook.Bids, depthLevels)
+	_, askQty := bookLevels(orderbook.Asks, depthLevels)
+	if bidQty+askQty == 0 {
+		return 0, false
+	}
+
+	return (float64(bidQty) - float64(askQty)) / float64(bidQty+askQty), true
+}
+
+// bookLevels returns the best price of one side of an orderbook and the
+// quantity on its best n price levels.
+func bookLevels(entries []OrderbookEntry, n int) (best, quantity uint64) {
+	levels := 0
+	for i, entry := range entries {
+		if i == 0 || entry.Price != entries[i-1].Price {
+			if levels++; levels > n {
+				break
+			}
+		}
+		quantity += entry.Quantity
+	}
+	if len(entries) > 0 {
+		best = entries[0].Price
+	}
+	return best, quantity
+}
+
+// Microprice returns the microprice of the mirrored orderbook (see
+// Orderbook.Microprice), kept up to date by quotes between snapshots.
+func (mirror *BookMirror) Microprice() (float64, bool) {
+	return mirror.Orderbook().Microprice()
+}
+
+// Imbalance returns the imbalance of the mirrored orderbook (see
+// Orderbook.Imbalance), kept up to date by quotes between snapshots.
+func (mirror *BookMirror) Imbalance(depthLevels int) (float64, bool) {
+	return mirror.Orderbook().Imbalance(depthLevels)
+}
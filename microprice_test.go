@@ -0,0 +1,49 @@
+package stockfighter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMicroprice(t *testing.T) {
+	orderbook := &Orderbook{
+		Bids: []OrderbookEntry{{Price: 100, Quantity: 10, IsBuy: true}, {Price: 100, Quantity: 20, IsBuy: true}, {Price: 99, Quantity: 50, IsBuy: true}},
+		Asks: []OrderbookEntry{{Price: 104, Quantity: 10}, {Price: 105, Quantity: 30}},
+	}
+
+	price, ok := orderbook.Microprice()
+	assert.True(t, ok)
+	assert.InDelta(t, 103, price, 1e-9) // (100*10 + 104*30) / 40
+
+	imbalance, ok := orderbook.Imbalance(1)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.5, imbalance, 1e-9) // (30 - 10) / 40
+
+	imbalance, ok = orderbook.Imbalance(2)
+	assert.True(t, ok)
+	assert.InDelta(t, 40.0/120, imbalance, 1e-9) // (80 - 40) / 120
+
+	_, ok = (&Orderbook{Bids: orderbook.Bids}).Microprice()
+	assert.False(t, ok)
+	imbalance, ok = (&Orderbook{Bids: orderbook.Bids}).Imbalance(5)
+	assert.True(t, ok)
+	assert.Equal(t, float64(1), imbalance)
+	_, ok = (&Orderbook{}).Imbalance(5)
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { orderbook.Imbalance(0) })
+}
+
+func TestBookMirrorMicroprice(t *testing.T) {
+	mirror := NewBookMirror(nil, testVenue, testStock)
+	mirror.ApplyQuote(&Quote{HasBid: true, BidPrice: 100, BidSize: 30, BidDepth: 30, HasAsk: true, AskPrice: 104, AskSize: 10, AskDepth: 10})
+
+	price, ok := mirror.Microprice()
+	assert.True(t, ok)
+	assert.InDelta(t, 103, price, 1e-9)
+
+	imbalance, ok := mirror.Imbalance(1)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.5, imbalance, 1e-9)
+}
@@ -0,0 +1,25 @@
+package stockfighter
+
+import "net/http"
+
+// A RoundTripFunc makes an API request and returns its response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// A Middleware wraps the RoundTripFunc making API requests, e.g. to add
+// headers, log, collect metrics, cache responses, or inject faults.
+//
+//     func userAgent(next stockfighter.RoundTripFunc) stockfighter.RoundTripFunc {
+//         return func(req *http.Request) (*http.Response, error) {
+//             req.Header.Set("User-Agent", "mybot/1.0")
+//             return next(req)
+//         }
+//     }
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware adds middlewares to the client. The first middleware added is
+// the outermost one: it sees requests first and responses last.
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(client *Client) {
+		client.transport.middlewares = append(client.transport.middlewares, middlewares...)
+	}
+}
@@ -0,0 +1,43 @@
+package stockfighter
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "outer,inner", r.Header.Get("X-Trace"))
+		w.Write([]byte(`{"ok": true}`))
+	})
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				if h := req.Header.Get("X-Trace"); h != "" {
+					name = h + "," + name
+				}
+				req.Header.Set("X-Trace", name)
+				return next(req)
+			}
+		}
+	}
+
+	client := NewClient(testApiKey, WithBaseURL(server.transport.baseURL), WithMiddleware(trace("outer"), trace("inner")))
+	assert.Nil(t, client.Ping())
+	assert.Equal(t, []string{"outer", "inner"}, calls)
+
+	// fault injection
+	errInjected := errors.New("injected")
+	client = NewClient(testApiKey, WithBaseURL(server.transport.baseURL), WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return nil, errInjected
+		}
+	}))
+	assert.Equal(t, errInjected, client.Ping())
+}
@@ -0,0 +1,201 @@
+package stockfighter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A TradingAccount is an account on a venue, with the client (API key) it is
+// traded with.
+type TradingAccount struct {
+	Client  *Client
+	Venue   string
+	Account string
+}
+
+// An AccountRouter chooses the account an order is placed with, among the
+// accounts of a MultiAccount trading the venue of the order. It returns an
+// empty string to reject the order.
+type AccountRouter func(req OrderRequest, accounts []string) string
+
+// RouteRoundRobin returns an AccountRouter spreading orders evenly across
+// accounts, in turn.
+func RouteRoundRobin() AccountRouter {
+	var mu sync.Mutex
+	next := 0
+	return func(req OrderRequest, accounts []string) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		account := accounts[next%len(accounts)]
+		next++
+		return account
+	}
+}
+
+// RouteByStock returns an AccountRouter placing the orders of each stock with
+// a given account, e.g. one account per strategy. Orders of other stocks are
+// placed with the default account, or rejected if it is empty.
+func RouteByStock(accounts map[string]string, def string) AccountRouter {
+	return func(req OrderRequest, _ []string) string {
+		if account, ok := accounts[req.Stock]; ok {
+			return account
+		}
+		return def
+	}
+}
+
+// A Portfolio represents the consolidated view of several accounts.
+type Portfolio struct {
+	// Positions per stock, added up across accounts
+	Positions map[string]Position
+
+	// Positions per account and stock
+	AccountPositions map[string]map[string]Position
+
+	// Open orders of all the accounts
+	OpenOrders []Order
+}
+
+// PnL returns the profit and loss of the portfolio, in cents: the net asset
+// value of its positions, with shares valued at the given prices per stock.
+func (portfolio *Portfolio) PnL(prices map[string]uint64) int64 {
+	var pnl int64
+	for stock, position := range portfolio.Positions {
+		pnl += position.NAV(prices[stock])
+	}
+	return pnl
+}
+
+// A MultiAccount manages several accounts, possibly with different API keys,
+// as one: it consolidates their positions and open orders, and places orders
+// with the account chosen by an AccountRouter.
+//
+//     accounts := stockfighter.NewMultiAccount(stockfighter.RouteRoundRobin())
+//     accounts.Add(client1, venue, account1)
+//     accounts.Add(client2, venue, account2)
+//     order, err := accounts.PlaceOrder(venue, stock, price, quantity, direction, orderType)
+//
+// You can create a new MultiAccount using NewMultiAccount function.
+type MultiAccount struct {
+	router AccountRouter
+
+	mu       sync.Mutex
+	accounts map[string]TradingAccount
+	order    []string
+}
+
+// NewMultiAccount creates a new MultiAccount routing orders with router.
+// This never returns nil.
+func NewMultiAccount(router AccountRouter) *MultiAccount {
+	return &MultiAccount{
+		router:   router,
+		accounts: make(map[string]TradingAccount),
+	}
+}
+
+// Add adds an account on a venue, traded with client. Adding an account
+// again replaces it.
+func (ma *MultiAccount) Add(client *Client, venue, account string) {
+	venue = strings.TrimSpace(venue)
+	if !validSymbol(venue) {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+
+	account = strings.TrimSpace(account)
+	if !validSymbol(account) {
+		panic(fmt.Errorf("Invalid account name: %v", account))
+	}
+
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+
+	if _, ok := ma.accounts[account]; !ok {
+		ma.order = append(ma.order, account)
+	}
+	ma.accounts[account] = TradingAccount{Client: client, Venue: venue, Account: account}
+}
+
+// Accounts returns the accounts, in the order they were added.
+func (ma *MultiAccount) Accounts() []TradingAccount {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+
+	accounts := make([]TradingAccount, len(ma.order))
+	for i, account := range ma.order {
+		accounts[i] = ma.accounts[account]
+	}
+	return accounts
+}
+
+// Portfolio fetches the orders of every account and returns the consolidated
+// view of the accounts. It fails if the orders of any account cannot be
+// fetched.
+func (ma *MultiAccount) Portfolio() (*Portfolio, error) {
+	portfolio := &Portfolio{
+		Positions:        make(map[string]Position),
+		AccountPositions: make(map[string]map[string]Position),
+	}
+
+	for _, account := range ma.Accounts() {
+		orders, err := account.Client.GetAllOrders(account.Venue, account.Account)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", account.Account, err)
+		}
+
+		positions := make(map[string]Position)
+		for _, order := range orders {
+			position, total := positions[order.Symbol], portfolio.Positions[order.Symbol]
+			for _, fill := range order.Fills {
+				position.Apply(order.Direction, fill)
+				total.Apply(order.Direction, fill)
+			}
+			positions[order.Symbol], portfolio.Positions[order.Symbol] = position, total
+
+			if order.Open {
+				portfolio.OpenOrders = append(portfolio.OpenOrders, order)
+			}
+		}
+		portfolio.AccountPositions[account.Account] = positions
+	}
+
+	sort.SliceStable(portfolio.OpenOrders, func(i, j int) bool {
+		return portfolio.OpenOrders[i].Timestamp.Before(portfolio.OpenOrders[j].Timestamp)
+	})
+	return portfolio, nil
+}
+
+// PlaceOrder places an order on a venue with the account chosen by the
+// router among the accounts trading the venue.
+func (ma *MultiAccount) PlaceOrder(venue, stock string, price, quantity uint64, direction, orderType string) (*Order, error) {
+	req := OrderRequest{
+		Venue:     strings.TrimSpace(venue),
+		Stock:     strings.TrimSpace(stock),
+		Price:     price,
+		Quantity:  quantity,
+		Direction: direction,
+		OrderType: orderType,
+	}
+
+	var candidates []string
+	for _, account := range ma.Accounts() {
+		if account.Venue == req.Venue {
+			candidates = append(candidates, account.Account)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("No account trading venue %v", req.Venue)
+	}
+
+	name := ma.router(req, candidates)
+	ma.mu.Lock()
+	account, ok := ma.accounts[name]
+	ma.mu.Unlock()
+	if !ok || account.Venue != req.Venue {
+		return nil, fmt.Errorf("No account to route %v order for %v to", direction, req.Stock)
+	}
+
+	return account.Client.PlaceOrder(req.Venue, req.Stock, account.Account, price, quantity, direction, orderType)
+}
@@ -0,0 +1,87 @@
+package stockfighter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newAccountTestServer serves the orders of an account, and records the
+// orders placed with it.
+func newAccountTestServer(t *testing.T, account, orders string, placed *[]string) *Client {
+	return newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			*placed = append(*placed, account+" "+r.URL.Path)
+			fmt.Fprintf(w, `{"ok": true, "id": 1, "account": "%s"}`, account)
+			return
+		}
+		assert.Equal(t, "/venues/TESTEX/accounts/"+account+"/orders", r.URL.Path)
+		fmt.Fprintf(w, `{"ok": true, "venue": "TESTEX", "orders": [%s]}`, orders)
+	})
+}
+
+func TestMultiAccount(t *testing.T) {
+	var placed []string
+	client1 := newAccountTestServer(t, "ACC1", `
+		{"id": 1, "symbol": "FOOBAR", "direction": "buy", "fills": [{"price": 100, "qty": 10}], "ts": "2015-12-04T09:00:00Z"},
+		{"id": 2, "symbol": "FOOBAR", "direction": "sell", "open": true, "ts": "2015-12-04T09:00:02Z"}`, &placed)
+	client2 := newAccountTestServer(t, "ACC2", `
+		{"id": 3, "symbol": "FOOBAR", "direction": "sell", "fills": [{"price": 110, "qty": 4}], "open": true, "ts": "2015-12-04T09:00:01Z"},
+		{"id": 4, "symbol": "BARBAZ", "direction": "buy", "fills": [{"price": 50, "qty": 2}], "ts": "2015-12-04T09:00:03Z"}`, &placed)
+
+	accounts := NewMultiAccount(RouteRoundRobin())
+	accounts.Add(client1, testVenue, "ACC1")
+	accounts.Add(client2, testVenue, "ACC2")
+	accounts.Add(client2, "OTHEREX", "ACC3")
+	assert.Len(t, accounts.Accounts(), 3)
+	assert.Equal(t, "ACC1", accounts.Accounts()[0].Account)
+
+	accounts = NewMultiAccount(RouteRoundRobin())
+	accounts.Add(client1, testVenue, "ACC1")
+	accounts.Add(client2, testVenue, "ACC2")
+
+	portfolio, err := accounts.Portfolio()
+	assert.Nil(t, err)
+	assert.Equal(t, Position{Shares: 6, Cash: -560}, portfolio.Positions["FOOBAR"])
+	assert.Equal(t, Position{Shares: 2, Cash: -100}, portfolio.Positions["BARBAZ"])
+	assert.Equal(t, Position{Shares: -4, Cash: 440}, portfolio.AccountPositions["ACC2"]["FOOBAR"])
+	if assert.Len(t, portfolio.OpenOrders, 2) {
+		assert.Equal(t, int64(3), portfolio.OpenOrders[0].OrderID)
+		assert.Equal(t, int64(2), portfolio.OpenOrders[1].OrderID)
+	}
+	assert.Equal(t, int64(-560+6*105-100+2*50), portfolio.PnL(map[string]uint64{"FOOBAR": 105, "BARBAZ": 50}))
+
+	for i := 0; i < 3; i++ {
+		order, err := accounts.PlaceOrder(testVenue, testStock, 100, 10, OrderDirectionBuy, OrderTypeLimit)
+		assert.Nil(t, err)
+		assert.NotNil(t, order)
+	}
+	assert.Equal(t, []string{
+		"ACC1 /venues/TESTEX/stocks/FOOBAR/orders",
+		"ACC2 /venues/TESTEX/stocks/FOOBAR/orders",
+		"ACC1 /venues/TESTEX/stocks/FOOBAR/orders",
+	}, placed)
+
+	_, err = accounts.PlaceOrder("OTHEREX", testStock, 100, 10, OrderDirectionBuy, OrderTypeLimit)
+	assert.EqualError(t, err, "No account trading venue OTHEREX")
+
+	assert.Panics(t, func() { accounts.Add(client1, testVenue, "") })
+}
+
+func TestRouteByStock(t *testing.T) {
+	var placed []string
+	client := newAccountTestServer(t, "ACC1", "", &placed)
+
+	accounts := NewMultiAccount(RouteByStock(map[string]string{"BARBAZ": "ACC1"}, ""))
+	accounts.Add(client, testVenue, "ACC1")
+
+	_, err := accounts.PlaceOrder(testVenue, "BARBAZ", 100, 10, OrderDirectionSell, OrderTypeLimit)
+	assert.Nil(t, err)
+	_, err = accounts.PlaceOrder(testVenue, testStock, 100, 10, OrderDirectionSell, OrderTypeLimit)
+	assert.EqualError(t, err, "No account to route sell order for FOOBAR to")
+	assert.Equal(t, 1, len(placed))
+	assert.True(t, strings.HasPrefix(placed[0], "ACC1 "))
+}
@@ -0,0 +1,190 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An AggregateQuote represents the quotes of a ticker across venues.
+type AggregateQuote struct {
+	Ticker string
+
+	// Whether there are any bids, and the best bid price and its venue
+	HasBid   bool
+	BidPrice uint64
+	BidVenue string
+
+	// Whether there are any asks, and the best ask price and its venue
+	HasAsk   bool
+	AskPrice uint64
+	AskVenue string
+
+	// Last quote of the ticker on each venue, keyed by venue symbol
+	Quotes map[string]*Quote
+}
+
+// A MultiVenue is a helper for levels spanning several venues: it polls the
+// quotes of a set of tickers on every venue, aggregates quotes and positions
+// per ticker, and routes orders to the venue with the best price.
+//
+//     mv := stockfighter.NewMultiVenue(client, level.Account, level.Venues, level.Tickers)
+//     for update := range mv.Start(ctx, time.Second) {
+//         quote := mv.Quote(update.Stock)
+//         // ...
+//     }
+//
+// You can create a new MultiVenue using NewMultiVenue function.
+type MultiVenue struct {
+	client  *Client
+	account string
+	venues  []string
+	tickers []string
+
+	mu     sync.Mutex
+	quotes map[string]map[string]*Quote
+}
+
+// NewMultiVenue creates a new MultiVenue trading tickers for an account on
+// several venues. This never returns nil.
+func NewMultiVenue(client *Client, account string, venues, tickers []string) *MultiVenue {
+	account = strings.TrimSpace(account)
+	if !validSymbol(account) {
+		panic(fmt.Errorf("Invalid account name: %v", account))
+	}
+
+	symbols := make([]string, len(venues))
+	for i, venue := range venues {
+		symbols[i] = strings.TrimSpace(venue)
+		if !validSymbol(symbols[i]) {
+			panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+		}
+	}
+
+	return &MultiVenue{
+		client:  client,
+		account: account,
+		venues:  symbols,
+		tickers: tickers,
+		quotes:  make(map[string]map[string]*Quote),
+	}
+}
+
+// Venues returns the venue symbols.
+func (mv *MultiVenue) Venues() []string {
+	return mv.venues
+}
+
+// Venue returns a handle on the account on a venue, e.g. to trade on a given
+// venue rather than the best one. This never returns nil.
+func (mv *MultiVenue) Venue(symbol string) *Account {
+	return mv.client.Venue(symbol).Account(mv.account)
+}
+
+// Start starts polling the quotes of the tickers on every venue every
+// interval, and returns the channel the updates of all the venues are
+// delivered on. Quote keeps up with the updates delivered.
+//
+// Polling stops and the channel is closed when ctx is done.
+func (mv *MultiVenue) Start(ctx context.Context, interval time.Duration) <-chan QuoteUpdate {
+	updates := make(chan QuoteUpdate)
+
+	var wg sync.WaitGroup
+	for _, venue := range mv.venues {
+		wg.Add(1)
+		go func(venueUpdates <-chan QuoteUpdate) {
+			defer wg.Done()
+			for update := range venueUpdates {
+				if update.Err == nil {
+					mv.update(update.Venue, update.Stock, update.Quote)
+				}
+				select {
+				case updates <- update:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(NewQuotePoller(mv.client, venue, mv.tickers, interval).Start(ctx))
+	}
+
+	go func() {
+		wg.Wait()
+		close(updates)
+	}()
+
+	return updates
+}
+
+func (mv *MultiVenue) update(venue, ticker string, quote *Quote) {
+	mv.mu.Lock()
+	defer mv.mu.Unlock()
+
+	quotes, ok := mv.quotes[ticker]
+	if !ok {
+		quotes = make(map[string]*Quote)
+		mv.quotes[ticker] = quotes
+	}
+	quotes[venue] = quote
+}
+
+// Quote returns the last quotes of a ticker across venues, with the best bid
+// and ask.
+func (mv *MultiVenue) Quote(ticker string) AggregateQuote {
+	mv.mu.Lock()
+	defer mv.mu.Unlock()
+
+	aggregate := AggregateQuote{Ticker: ticker, Quotes: make(map[string]*Quote)}
+	// venues in order, so that ties go to the first venue
+	for _, venue := range mv.venues {
+		quote, ok := mv.quotes[ticker][venue]
+		if !ok {
+			continue
+		}
+		aggregate.Quotes[venue] = quote
+		if quote.HasBid && (!aggregate.HasBid || quote.BidPrice > aggregate.BidPrice) {
+			aggregate.HasBid, aggregate.BidPrice, aggregate.BidVenue = true, quote.BidPrice, venue
+		}
+		if quote.HasAsk && (!aggregate.HasAsk || quote.AskPrice < aggregate.AskPrice) {
+			aggregate.HasAsk, aggregate.AskPrice, aggregate.AskVenue = true, quote.AskPrice, venue
+		}
+	}
+	return aggregate
+}
+
+// Positions returns the positions of the account per ticker, added up across
+// venues, from the orders of the account on every venue.
+func (mv *MultiVenue) Positions() (map[string]Position, error) {
+	positions := make(map[string]Position)
+	for _, venue := range mv.venues {
+		orders, err := mv.client.GetAllOrders(venue, mv.account)
+		if err != nil {
+			return nil, err
+		}
+		for _, order := range orders {
+			position := positions[order.Symbol]
+			for _, fill := range order.Fills {
+				position.Apply(order.Direction, fill)
+			}
+			positions[order.Symbol] = position
+		}
+	}
+	return positions, nil
+}
+
+// Route places an order for a ticker on the venue with the best price on the
+// other side of the market (the best ask for a buy order, the best bid for a
+// sell order), as last seen by Start.
+func (mv *MultiVenue) Route(ticker string, price, quantity uint64, direction, orderType string) (*Order, error) {
+	quote := mv.Quote(ticker)
+	venue := quote.AskVenue
+	if direction == OrderDirectionSell {
+		venue = quote.BidVenue
+	}
+	if venue == "" {
+		return nil, fmt.Errorf("No venue to route %v order for %v to", direction, ticker)
+	}
+
+	return mv.client.PlaceOrder(venue, ticker, mv.account, price, quantity, direction, orderType)
+}
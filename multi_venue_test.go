@@ -0,0 +1,61 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiVenue(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		venue := strings.Split(r.URL.Path, "/")[2]
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/quote"):
+			bid, ask := 5000, 5100
+			if venue == "OTHEREX" {
+				bid, ask = 4990, 5050
+			}
+			fmt.Fprintf(w, `{"ok": true, "venue": "%s", "symbol": "FOOBAR", "bid": %d, "ask": %d}`, venue, bid, ask)
+		case strings.HasSuffix(r.URL.Path, "/accounts/EXB123456/orders"):
+			fmt.Fprintf(w, `{"ok": true, "venue": "%s", "orders": [{"symbol": "FOOBAR", "direction": "buy", "fills": [{"price": 5000, "qty": 10}]}]}`, venue)
+		case r.Method == "POST":
+			fmt.Fprintf(w, `{"ok": true, "venue": "%s", "symbol": "FOOBAR", "id": 1}`, venue)
+		}
+	})
+	mv := NewMultiVenue(client, testAccount, []string{testVenue, "OTHEREX"}, []string{testStock})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := mv.Start(ctx, time.Hour)
+	venues := map[string]bool{}
+	for len(venues) < 2 {
+		update := <-updates
+		venues[update.Venue] = true
+	}
+	cancel()
+	for range updates {
+	}
+
+	quote := mv.Quote(testStock)
+	assert.Equal(t, uint64(5000), quote.BidPrice)
+	assert.Equal(t, testVenue, quote.BidVenue)
+	assert.Equal(t, uint64(5050), quote.AskPrice)
+	assert.Equal(t, "OTHEREX", quote.AskVenue)
+	assert.Len(t, quote.Quotes, 2)
+
+	order, err := mv.Route(testStock, 5050, 10, OrderDirectionBuy, OrderTypeLimit)
+	assert.Nil(t, err)
+	assert.Equal(t, "OTHEREX", order.Venue)
+	_, err = mv.Route("NOPE", 5050, 10, OrderDirectionBuy, OrderTypeLimit)
+	assert.NotNil(t, err)
+
+	positions, err := mv.Positions()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]Position{testStock: {Shares: 20, Cash: -100000}}, positions)
+
+	assert.Panics(t, func() { NewMultiVenue(client, testAccount, []string{""}, nil) })
+}
@@ -0,0 +1,70 @@
+/*
+Package natssink provides a stockfighter.Sink publishing events to NATS.
+
+Events are published as JSON (see stockfighter.SinkEvent) to subjects named
+after the event kind, venue, and stock, e.g.
+"stockfighter.quote.TESTEX.FOOBAR", so that subscribers can use wildcards
+such as "stockfighter.*.TESTEX.>":
+
+    sink, err := natssink.Connect(nats.DefaultURL, "stockfighter")
+    if err != nil {
+        return err
+    }
+    defer sink.Close()
+*/
+package natssink
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/nats-io/nats.go"
+	"gpk.io/stockfighter"
+)
+
+// A Sink publishes events to NATS subjects.
+//
+// You can create a new Sink using Connect or New function.
+type Sink struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+var _ stockfighter.Sink = (*Sink)(nil)
+
+// Connect connects to a NATS server and creates a new Sink publishing to
+// subjects named after the prefix.
+func Connect(url, prefix string, options ...nats.Option) (*Sink, error) {
+	conn, err := nats.Connect(url, options...)
+	if err != nil {
+		return nil, err
+	}
+	return New(conn, prefix), nil
+}
+
+// New creates a new Sink publishing with conn to subjects named after the
+// prefix. The connection is drained and closed with the sink. This never
+// returns nil.
+func New(conn *nats.Conn, prefix string) *Sink {
+	return &Sink{conn: conn, prefix: prefix}
+}
+
+// Subject returns the subject an event is published to.
+func (sink *Sink) Subject(event stockfighter.SinkEvent) string {
+	return sink.prefix + "." + event.Kind + "." + event.Venue + "." + event.Symbol
+}
+
+// Publish implements stockfighter.Sink. Messages are buffered by the NATS
+// client, so ctx is not used.
+func (sink *Sink) Publish(ctx context.Context, event stockfighter.SinkEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return sink.conn.Publish(sink.Subject(event), data)
+}
+
+// Close implements stockfighter.Sink.
+func (sink *Sink) Close() error {
+	return sink.conn.Drain()
+}
@@ -0,0 +1,90 @@
+package natssink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+// message is a message published to the fake server.
+type message struct {
+	subject string
+	data    []byte
+}
+
+// serve speaks enough of the NATS protocol to a single client to accept its
+// messages, which it sends to msgs.
+func serve(t *testing.T, listener net.Listener, msgs chan<- message) {
+	defer close(msgs)
+
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	fmt.Fprint(conn, "INFO {\"server_id\":\"test\",\"version\":\"2.10.0\",\"proto\":1,\"max_payload\":1048576}\r\n")
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case "PUB":
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			assert.Nil(t, err)
+			data := make([]byte, size+2)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return
+			}
+			msgs <- message{subject: fields[1], data: data[:size]}
+		}
+	}
+}
+
+func TestSink(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	msgs := make(chan message, 10)
+	go serve(t, listener, msgs)
+
+	sink, err := Connect("nats://"+listener.Addr().String(), "stockfighter")
+	assert.Nil(t, err)
+
+	event := stockfighter.QuoteEvent(&stockfighter.Quote{Venue: "TESTEX", Symbol: "FOOBAR", HasBid: true, BidPrice: 5000,
+		QuoteTime: time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)})
+	assert.Equal(t, "stockfighter.quote.TESTEX.FOOBAR", sink.Subject(event))
+	assert.Nil(t, sink.Publish(context.Background(), event))
+
+	// closing flushes the buffered messages
+	assert.Nil(t, sink.Close())
+
+	select {
+	case msg := <-msgs:
+		assert.Equal(t, "stockfighter.quote.TESTEX.FOOBAR", msg.subject)
+		var published stockfighter.SinkEvent
+		assert.Nil(t, json.Unmarshal(msg.data, &published))
+		assert.Equal(t, stockfighter.SinkEventQuote, published.Kind)
+		assert.Equal(t, uint64(5000), published.Quote.BidPrice)
+	case <-time.After(time.Second):
+		t.Fatal("message not published")
+	}
+}
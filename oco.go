@@ -0,0 +1,147 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// An OCOResult represents the outcome of PlaceOCO.
+type OCOResult struct {
+	// Last known status of each leg
+	First  *Order
+	Second *Order
+
+	// Set if both legs had fills before the other leg could be canceled
+	BothFilled bool
+}
+
+// PlaceOCO places a one-cancels-other order pair: two orders such that as soon
+// as one of them fills (even partially) or closes, the other is canceled.
+//
+// Both requests are checked before either leg is placed: PlaceOCO panics like
+// PlaceOrder if one of them has invalid symbols, and returns an
+// *ErrorInvalidOrder if one of them is invalid.
+//
+// PlaceOCO polls both orders and returns once one leg was canceled; the other
+// leg may still be working. Placements and polls are made with ctx (see
+// Client.WithContext). If ctx is done first, both legs are canceled and
+// ctx.Err() is returned.
+//
+// Fills of the canceled leg can race with its cancellation. In that case both
+// legs are canceled, BothFilled is set, and the caller is left with the
+// combined fills of both legs.
+func (client *Client) PlaceOCO(ctx context.Context, first, second OrderRequest) (*OCOResult, error) {
+	for _, req := range []OrderRequest{first, second} {
+		checkOrderSymbols(req)
+		if err := validateOrder(&req); err != nil {
+			return nil, err
+		}
+	}
+
+	// cancellations are made with client, so that they are still made once
+	// ctx is done
+	polling := client.WithContext(ctx)
+	a, err := polling.placeOrderRequest(first)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := polling.placeOrderRequest(second)
+	if err != nil {
+		if canceled, cancelErr := client.CancelOrder(first.Venue, first.Stock, a.OrderID); cancelErr == nil {
+			a = canceled
+		}
+		return &OCOResult{First: a}, err
+	}
+
+	result := &OCOResult{First: a, Second: b}
+
+	ticker := time.NewTicker(DefaultPollInterval)
+	defer ticker.Stop()
+
+	for {
+		aDone := result.First.TotalFilled > 0 || !result.First.Open
+		bDone := result.Second.TotalFilled > 0 || !result.Second.Open
+		switch {
+		case aDone && bDone:
+			return result, client.cancelOCO(result, first, second, true, true)
+		case aDone:
+			return result, client.cancelOCO(result, first, second, false, true)
+		case bDone:
+			return result, client.cancelOCO(result, first, second, true, false)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if err := client.cancelOCO(result, first, second, true, true); err != nil {
+				return result, err
+			}
+			return result, ctx.Err()
+		}
+
+		if result.First, err = polling.GetOrder(first.Venue, first.Stock, a.OrderID); err != nil {
+			result.First = a
+		} else if result.Second, err = polling.GetOrder(second.Venue, second.Stock, b.OrderID); err != nil {
+			result.Second = b
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				// the poll was canceled
+				if err := client.cancelOCO(result, first, second, true, true); err != nil {
+					return result, err
+				}
+				return result, ctx.Err()
+			}
+			return result, err
+		}
+		a, b = result.First, result.Second
+	}
+}
+
+// cancelOCO cancels the requested open legs of an OCO pair. If canceling one
+// leg reveals fills on it while the other leg has fills too, the other leg is
+// canceled as well.
+func (client *Client) cancelOCO(result *OCOResult, first, second OrderRequest, cancelFirst, cancelSecond bool) error {
+	if cancelSecond && result.Second.Open {
+		status, err := client.CancelOrder(second.Venue, second.Stock, result.Second.OrderID)
+		if err != nil {
+			return err
+		}
+		result.Second = status
+	}
+
+	if cancelFirst && result.First.Open {
+		status, err := client.CancelOrder(first.Venue, first.Stock, result.First.OrderID)
+		if err != nil {
+			return err
+		}
+		result.First = status
+	}
+
+	result.BothFilled = result.First.TotalFilled > 0 && result.Second.TotalFilled > 0
+	if result.BothFilled && (result.First.Open || result.Second.Open) {
+		return client.cancelOCO(result, first, second, true, true)
+	}
+
+	return nil
+}
+
+// checkOrderSymbols panics like PlaceOrder if the symbols of req are invalid.
+func checkOrderSymbols(req OrderRequest) {
+	if venue := strings.TrimSpace(req.Venue); !validSymbol(venue) {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+	if stock := strings.TrimSpace(req.Stock); !validSymbol(stock) {
+		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
+	}
+	if account := strings.TrimSpace(req.Account); !validSymbol(account) {
+		panic(fmt.Errorf("Invalid account name: %v", account))
+	}
+}
+
+func (client *Client) placeOrderRequest(req OrderRequest) (*Order, error) {
+	return client.PlaceOrder(req.Venue, req.Stock, req.Account, req.Price, req.Quantity, req.Direction, req.OrderType)
+}
@@ -0,0 +1,66 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceOCO(t *testing.T) {
+	var mu sync.Mutex
+	var nextID int
+	var canceled []string
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == "POST":
+			nextID++
+			fmt.Fprintf(w, `{"ok": true, "id": %d, "open": true}`, nextID)
+		case r.Method == "DELETE":
+			canceled = append(canceled, r.URL.Path)
+			w.Write([]byte(`{"ok": true, "id": 2, "open": false}`))
+		case strings.HasSuffix(r.URL.Path, "/orders/1"):
+			w.Write([]byte(`{"ok": true, "id": 1, "open": true, "totalFilled": 5, "fills": [{"price": 100, "qty": 5}]}`))
+		default:
+			w.Write([]byte(`{"ok": true, "id": 2, "open": true}`))
+		}
+	})
+
+	takeProfit := OrderRequest{Venue: testVenue, Stock: testStock, Account: testAccount, Price: 100, Quantity: 10, Direction: OrderDirectionSell, OrderType: OrderTypeLimit}
+	bid := OrderRequest{Venue: testVenue, Stock: testStock, Account: testAccount, Price: 90, Quantity: 10, Direction: OrderDirectionBuy, OrderType: OrderTypeLimit}
+
+	result, err := client.PlaceOCO(context.Background(), takeProfit, bid)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), result.First.TotalFilled)
+	assert.True(t, result.First.Open)
+	assert.False(t, result.Second.Open)
+	assert.False(t, result.BothFilled)
+	assert.Equal(t, []string{"/venues/TESTEX/stocks/FOOBAR/orders/2"}, canceled)
+}
+
+func TestPlaceOCOInvalid(t *testing.T) {
+	var placed int
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		placed++
+		w.Write([]byte(`{"ok": true, "id": 1, "open": true}`))
+	})
+
+	valid := OrderRequest{Venue: testVenue, Stock: testStock, Account: testAccount, Price: 100, Quantity: 10, Direction: OrderDirectionSell, OrderType: OrderTypeLimit}
+	badSymbol := valid
+	badSymbol.Stock = "FOO BAR"
+	badQuantity := valid
+	badQuantity.Quantity = 0
+
+	// neither leg is placed if the second one is invalid
+	assert.Panics(t, func() { client.PlaceOCO(context.Background(), valid, badSymbol) })
+	_, err := client.PlaceOCO(context.Background(), valid, badQuantity)
+	assert.IsType(t, &ErrorInvalidOrder{}, err)
+	assert.Equal(t, 0, placed)
+}
@@ -0,0 +1,255 @@
+/*
+Package optimize tunes strategy parameters by running backtests across a
+grid, or a random sample, of parameter values in parallel, and ranking the
+results.
+
+This module has no backtester of its own: a Backtest is any function running
+a strategy with the given parameters and measuring how it did, e.g. against
+a quote recording (see stockfighter.QuoteReplayer).
+
+    opt := optimize.New(func(ctx context.Context, params optimize.Params) (optimize.Result, error) {
+        return runQuoter(ctx, recording, params["spread"], params["size"])
+    })
+    opt.Objective = optimize.ByRiskAdjustedPnL
+    trials, err := opt.Grid(ctx, optimize.Steps("spread", 10, 100, 10), optimize.Values("size", 50, 100, 200))
+    if err != nil {
+        return err
+    }
+    optimize.WriteReport(os.Stdout, trials[:10])
+*/
+package optimize
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// Params holds the values of the parameters of a backtest, by name.
+type Params map[string]float64
+
+func (p Params) String() string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%v=%v", name, p[name])
+	}
+	return strings.Join(parts, " ")
+}
+
+// A Range represents the values a parameter is tried with.
+type Range struct {
+	Name   string
+	Values []float64
+}
+
+// Values returns a Range of the given values.
+func Values(name string, values ...float64) Range {
+	return Range{Name: name, Values: values}
+}
+
+// Steps returns a Range of the values from from to to, both inclusive, by
+// step. This panics if step is not positive.
+func Steps(name string, from, to, step float64) Range {
+	if step <= 0 {
+		panic(fmt.Errorf("Invalid step: %v", step))
+	}
+
+	r := Range{Name: name}
+	// steps are counted rather than accumulated to avoid rounding drift
+	for i := 0; from+float64(i)*step <= to+step/1e9; i++ {
+		r.Values = append(r.Values, from+float64(i)*step)
+	}
+	return r
+}
+
+// A Result represents how a backtest did.
+type Result struct {
+	// Profit and loss, and maximum drawdown (peak to trough of the P&L), in
+	// cents
+	PnL         int64
+	MaxDrawdown int64
+
+	// Orders placed, and orders filled at least partially
+	Orders int
+	Filled int
+}
+
+// FillRate returns the share of the orders filled, between 0 and 1.
+func (r Result) FillRate() float64 {
+	if r.Orders == 0 {
+		return 0
+	}
+	return float64(r.Filled) / float64(r.Orders)
+}
+
+// A Backtest runs a strategy with the given parameters. It must be safe for
+// concurrent use.
+type Backtest func(ctx context.Context, params Params) (Result, error)
+
+// An Objective scores results: the higher, the better.
+type Objective func(Result) float64
+
+// ByPnL scores results by P&L.
+func ByPnL(r Result) float64 {
+	return float64(r.PnL)
+}
+
+// ByRiskAdjustedPnL scores results by P&L over maximum drawdown (counting
+// drawdowns under a dollar as a dollar).
+func ByRiskAdjustedPnL(r Result) float64 {
+	drawdown := r.MaxDrawdown
+	if drawdown < 100 {
+		drawdown = 100
+	}
+	return float64(r.PnL) / float64(drawdown)
+}
+
+// ByFillRate scores results by fill rate.
+func ByFillRate(r Result) float64 {
+	return r.FillRate()
+}
+
+// A Trial represents a backtest run with a set of parameters.
+type Trial struct {
+	Params Params
+	Result Result
+	Score  float64
+
+	// Backtest error, if any
+	Err error
+}
+
+// An Optimizer runs backtests across parameter sets.
+//
+// You can create a new Optimizer using New function.
+type Optimizer struct {
+	// Objective trials are ranked by
+	Objective Objective
+
+	// Maximum number of backtests run concurrently
+	Parallelism int
+
+	backtest Backtest
+}
+
+// New creates a new Optimizer running the given backtest, ranking trials by
+// P&L. This never returns nil.
+func New(backtest Backtest) *Optimizer {
+	return &Optimizer{
+		Objective:   ByPnL,
+		Parallelism: runtime.GOMAXPROCS(0),
+		backtest:    backtest,
+	}
+}
+
+// Grid runs the backtest with every combination of the values of the
+// ranges, and returns the trials ranked by score, best first; failed trials
+// come last.
+//
+// Grid returns early with ctx.Err() if the context is done.
+func (opt *Optimizer) Grid(ctx context.Context, ranges ...Range) ([]Trial, error) {
+	paramSets := []Params{{}}
+	for _, r := range ranges {
+		var product []Params
+		for _, params := range paramSets {
+			for _, value := range r.Values {
+				p := make(Params, len(params)+1)
+				for name, v := range params {
+					p[name] = v
+				}
+				p[r.Name] = value
+				product = append(product, p)
+			}
+		}
+		paramSets = product
+	}
+	return opt.Run(ctx, paramSets)
+}
+
+// Random runs the backtest with n parameter sets, each value picked at
+// random from its range with rng, and returns the trials ranked as by Grid.
+func (opt *Optimizer) Random(ctx context.Context, n int, rng *rand.Rand, ranges ...Range) ([]Trial, error) {
+	paramSets := make([]Params, n)
+	for i := range paramSets {
+		paramSets[i] = make(Params, len(ranges))
+		for _, r := range ranges {
+			paramSets[i][r.Name] = r.Values[rng.Intn(len(r.Values))]
+		}
+	}
+	return opt.Run(ctx, paramSets)
+}
+
+// Run runs the backtest with the given parameter sets, and returns the
+// trials ranked as by Grid.
+func (opt *Optimizer) Run(ctx context.Context, paramSets []Params) ([]Trial, error) {
+	parallelism := opt.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	trials := make([]Trial, len(paramSets))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result, err := opt.backtest(ctx, paramSets[i])
+				trials[i] = Trial{Params: paramSets[i], Result: result, Err: err}
+				if err == nil {
+					trials[i].Score = opt.Objective(result)
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := range paramSets {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(trials, func(i, j int) bool {
+		if (trials[i].Err == nil) != (trials[j].Err == nil) {
+			return trials[i].Err == nil
+		}
+		return trials[i].Score > trials[j].Score
+	})
+	return trials, nil
+}
+
+// WriteReport writes the trials as a table, in their order.
+func WriteReport(w io.Writer, trials []Trial) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "rank\tscore\tP&L\tmax drawdown\tfill rate\tparameters\t")
+	for i, trial := range trials {
+		if trial.Err != nil {
+			fmt.Fprintf(tw, "%v\t-\t-\t-\t-\t%v: %v\t\n", i+1, trial.Params, trial.Err)
+			continue
+		}
+		fmt.Fprintf(tw, "%v\t%.4g\t$%.2f\t$%.2f\t%.0f%%\t%v\t\n", i+1, trial.Score, float64(trial.Result.PnL)/100.0,
+			float64(trial.Result.MaxDrawdown)/100.0, 100*trial.Result.FillRate(), trial.Params)
+	}
+	return tw.Flush()
+}
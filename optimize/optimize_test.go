@@ -0,0 +1,76 @@
+package optimize
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// backtest has a P&L peaking at spread 30, and fills less as the spread
+// widens.
+func backtest(ctx context.Context, params Params) (Result, error) {
+	spread := params["spread"]
+	if spread == 0 {
+		return Result{}, errors.New("no spread")
+	}
+	d := spread - 30
+	return Result{PnL: int64(10000 - d*d*params["size"]), MaxDrawdown: int64(params["size"] * 10), Orders: 100,
+		Filled: int(100 - spread)}, nil
+}
+
+func TestOptimizerGrid(t *testing.T) {
+	var runs int32
+	opt := New(func(ctx context.Context, params Params) (Result, error) {
+		atomic.AddInt32(&runs, 1)
+		return backtest(ctx, params)
+	})
+	opt.Parallelism = 3
+
+	trials, err := opt.Grid(context.Background(), Steps("spread", 0, 50, 10), Values("size", 1, 2))
+	assert.Nil(t, err)
+	assert.Equal(t, int32(12), runs)
+	assert.Len(t, trials, 12)
+	assert.Equal(t, Params{"spread": 30, "size": 1}, trials[0].Params)
+	assert.Equal(t, float64(10000), trials[0].Score)
+	assert.Equal(t, 0.7, trials[0].Result.FillRate())
+	assert.NotNil(t, trials[11].Err)
+
+	opt.Objective = ByFillRate
+	trials, err = opt.Grid(context.Background(), Steps("spread", 10, 50, 10), Values("size", 1))
+	assert.Nil(t, err)
+	assert.Equal(t, Params{"spread": 10, "size": 1}, trials[0].Params)
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteReport(&buf, trials[:1]))
+	assert.Equal(t, "  rank  score     P&L  max drawdown  fill rate        parameters\n"+
+		"     1    0.9  $96.00         $0.10        90%  size=1 spread=10\n", buf.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = opt.Grid(ctx, Steps("spread", 10, 50, 10))
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestOptimizerRandom(t *testing.T) {
+	opt := New(backtest)
+	opt.Objective = ByRiskAdjustedPnL
+
+	trials, err := opt.Random(context.Background(), 20, rand.New(rand.NewSource(1)), Steps("spread", 10, 50, 10), Values("size", 10, 20))
+	assert.Nil(t, err)
+	assert.Len(t, trials, 20)
+	for i := 1; i < len(trials); i++ {
+		assert.True(t, trials[i-1].Score >= trials[i].Score)
+	}
+}
+
+func TestSteps(t *testing.T) {
+	assert.Equal(t, []float64{0.1, 0.2, 0.30000000000000004}, Steps("x", 0.1, 0.3, 0.1).Values)
+	assert.Equal(t, Range{Name: "x"}, Steps("x", 1, 0, 1))
+	assert.Panics(t, func() { Steps("x", 0, 1, 0) })
+	assert.Equal(t, "a=1 b=2", Params{"b": 2, "a": 1}.String())
+}
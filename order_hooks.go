@@ -0,0 +1,93 @@
+package stockfighter
+
+import "fmt"
+
+// A BeforeOrderHook is called by PlaceOrder before an order is sent, e.g. for
+// risk checks, logging, or throttling. It can modify the order, or veto it by
+// returning an error, which PlaceOrder then returns.
+//
+//     func maxQuantity(req *stockfighter.OrderRequest) error {
+//         if req.Quantity > 1000 {
+//             return &stockfighter.ErrorInvalidOrder{Reason: "quantity over 1000"}
+//         }
+//         return nil
+//     }
+type BeforeOrderHook func(req *OrderRequest) error
+
+// An AfterOrderHook is called by PlaceOrder with an order sent and its result,
+// e.g. for accounting. Exactly one of order and err is set.
+type AfterOrderHook func(req OrderRequest, order *Order, err error)
+
+// WithBeforeOrder adds hooks called before orders are sent. Hooks are called
+// in the order they are added, until one vetoes the order.
+func WithBeforeOrder(hooks ...BeforeOrderHook) ClientOption {
+	return func(client *Client) {
+		client.beforeOrder = append(client.beforeOrder, hooks...)
+	}
+}
+
+// WithAfterOrder adds hooks called after orders are sent. Hooks are called in
+// the order they are added. Orders vetoed or found invalid before being sent
+// are not passed to them.
+func WithAfterOrder(hooks ...AfterOrderHook) ClientOption {
+	return func(client *Client) {
+		client.afterOrder = append(client.afterOrder, hooks...)
+	}
+}
+
+// runBeforeOrder calls the before-order hooks of the client, and validates the
+// order again if a hook modified it.
+func (client *Client) runBeforeOrder(req *OrderRequest) error {
+	if len(client.beforeOrder) == 0 {
+		return nil
+	}
+
+	for _, hook := range client.beforeOrder {
+		if err := hook(req); err != nil {
+			return err
+		}
+	}
+	return validateOrder(req)
+}
+
+// runAfterOrder calls the after-order hooks of the client.
+func (client *Client) runAfterOrder(req OrderRequest, order *Order, err error) {
+	for _, hook := range client.afterOrder {
+		hook(req, order, err)
+	}
+}
+
+// PriceBand returns a before-order hook vetoing limit orders priced too far
+// from the last trade of their stock: buying above factor times the last
+// price, or selling below the last price divided by factor. Orders of stocks
+// not traded yet are not checked.
+//
+// The hook gets a quote for every order: see WithQuoteCache.
+func PriceBand(api StockfighterAPI, factor float64) BeforeOrderHook {
+	if factor < 1 {
+		panic(fmt.Errorf("Invalid price band factor: %v", factor))
+	}
+
+	return func(req *OrderRequest) error {
+		if req.OrderType == OrderTypeMarket {
+			return nil
+		}
+
+		quote, err := api.GetQuote(req.Venue, req.Stock)
+		if err != nil {
+			return err
+		}
+		if quote.LastPrice == 0 {
+			return nil
+		}
+
+		last := float64(quote.LastPrice)
+		switch {
+		case req.Direction == OrderDirectionBuy && float64(req.Price) > factor*last:
+			return &ErrorInvalidOrder{Reason: fmt.Sprintf("bid of %v over %v times the last price of %v", req.Price, factor, quote.LastPrice)}
+		case req.Direction == OrderDirectionSell && float64(req.Price) < last/factor:
+			return &ErrorInvalidOrder{Reason: fmt.Sprintf("ask of %v under the last price of %v divided by %v", req.Price, quote.LastPrice, factor)}
+		}
+		return nil
+	}
+}
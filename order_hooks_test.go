@@ -0,0 +1,60 @@
+package stockfighter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderHooks(t *testing.T) {
+	var sent []OrderRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR", "last": 5000}`))
+			return
+		}
+		var req OrderRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		sent = append(sent, req)
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "id": len(sent), "qty": req.Quantity, "price": req.Price, "open": true})
+	}))
+	t.Cleanup(server.Close)
+
+	var calls []string
+	var after []*Order
+	quotes := NewClient(testApiKey, WithBaseURL(server.URL))
+	client := NewClient(testApiKey, WithBaseURL(server.URL),
+		WithBeforeOrder(func(req *OrderRequest) error {
+			calls = append(calls, "halve")
+			req.Quantity /= 2
+			return nil
+		}, PriceBand(quotes, 2)),
+		WithAfterOrder(func(req OrderRequest, order *Order, err error) {
+			assert.Nil(t, err)
+			after = append(after, order)
+		}))
+
+	order, err := client.PlaceOrder(testVenue, testStock, testAccount, 9000, 100, OrderDirectionBuy, OrderTypeLimit)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(50), order.Quantity)
+	assert.Equal(t, []*Order{order}, after)
+
+	// vetoed by the price band
+	_, err = client.PlaceOrder(testVenue, testStock, testAccount, 10001, 100, OrderDirectionBuy, OrderTypeLimit)
+	assert.IsType(t, &ErrorInvalidOrder{}, err)
+	_, err = client.PlaceOrder(testVenue, testStock, testAccount, 2499, 100, OrderDirectionSell, OrderTypeLimit)
+	assert.IsType(t, &ErrorInvalidOrder{}, err)
+
+	// invalid once modified by a hook
+	_, err = client.PlaceOrder(testVenue, testStock, testAccount, 5000, 1, OrderDirectionBuy, OrderTypeLimit)
+	assert.IsType(t, &ErrorInvalidOrder{}, err)
+
+	assert.Len(t, sent, 1)
+	assert.Len(t, after, 1)
+	assert.Equal(t, []string{"halve", "halve", "halve", "halve"}, calls)
+
+	assert.Panics(t, func() { PriceBand(client, 0.5) })
+}
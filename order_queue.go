@@ -0,0 +1,232 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type queuedOrder struct {
+	req     OrderRequest
+	requote bool
+	result  chan OrderResult
+}
+
+type queuedCancel struct {
+	venue, stock string
+	orderID      int64
+	result       chan CancelResult
+}
+
+// An OrderQueue queues order placements and cancellations, and sends them at
+// most once per interval, so that bursty strategies degrade gracefully
+// instead of getting throttled by the API.
+//
+// Cancellations are sent before placements, which are sent in the order they
+// were queued. A requote takes the place of the pending requote for the same
+// venue, stock, account, and direction, if any, which then fails with
+// ErrorOrderSuperseded. Invalid requests, which API methods panic on, fail
+// with the error of the panic instead of crashing the goroutine of Run.
+//
+// You can create a new OrderQueue using NewOrderQueue function.
+type OrderQueue struct {
+	api      StockfighterAPI
+	interval time.Duration
+
+	mu      sync.Mutex
+	cancels []*queuedCancel
+	orders  []*queuedOrder
+	notify  chan struct{}
+
+	// Error the queue stopped with, once Run returned
+	stopped error
+}
+
+// NewOrderQueue creates a new OrderQueue sending requests with api at most
+// once per interval (0 for no limit). This never returns nil.
+func NewOrderQueue(api StockfighterAPI, interval time.Duration) *OrderQueue {
+	if interval < 0 {
+		panic(fmt.Errorf("Invalid order queue interval: %v", interval))
+	}
+
+	return &OrderQueue{
+		api:      api,
+		interval: interval,
+		notify:   make(chan struct{}, 1),
+	}
+}
+
+// Place queues an order placement, and returns the channel its result is
+// delivered on.
+func (queue *OrderQueue) Place(req OrderRequest) <-chan OrderResult {
+	return queue.queueOrder(req, false)
+}
+
+// Requote queues an order placement replacing the pending requote for the
+// same venue, stock, account, and direction, and returns the channel its
+// result is delivered on.
+func (queue *OrderQueue) Requote(req OrderRequest) <-chan OrderResult {
+	return queue.queueOrder(req, true)
+}
+
+// Cancel queues an order cancellation, and returns the channel its result is
+// delivered on.
+func (queue *OrderQueue) Cancel(venue, stock string, orderID int64) <-chan CancelResult {
+	c := &queuedCancel{venue: venue, stock: stock, orderID: orderID, result: make(chan CancelResult, 1)}
+
+	queue.mu.Lock()
+	if queue.stopped != nil {
+		c.result <- CancelResult{Stock: stock, OrderID: orderID, Err: queue.stopped}
+	} else {
+		queue.cancels = append(queue.cancels, c)
+	}
+	queue.mu.Unlock()
+	queue.signal()
+
+	return c.result
+}
+
+// Pending returns the number of requests queued and not sent yet.
+func (queue *OrderQueue) Pending() int {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	return len(queue.cancels) + len(queue.orders)
+}
+
+func (queue *OrderQueue) queueOrder(req OrderRequest, requote bool) <-chan OrderResult {
+	o := &queuedOrder{req: req, requote: requote, result: make(chan OrderResult, 1)}
+
+	queue.mu.Lock()
+	if queue.stopped != nil {
+		o.result <- OrderResult{Err: queue.stopped}
+		queue.mu.Unlock()
+		return o.result
+	}
+
+	replaced := false
+	if requote {
+		for i, pending := range queue.orders {
+			if pending.requote && sameSide(pending.req, req) {
+				pending.result <- OrderResult{Err: &ErrorOrderSuperseded{}}
+				queue.orders[i] = o
+				replaced = true
+				break
+			}
+		}
+	}
+	if !replaced {
+		queue.orders = append(queue.orders, o)
+	}
+	queue.mu.Unlock()
+	queue.signal()
+
+	return o.result
+}
+
+func (queue *OrderQueue) signal() {
+	select {
+	case queue.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends the queued requests until ctx is done. The requests still queued
+// then fail with ctx.Err(), and so do the requests queued afterwards, right
+// away.
+func (queue *OrderQueue) Run(ctx context.Context) {
+	defer func() { queue.drain(ctx.Err()) }()
+
+	for {
+		c, o := queue.next()
+		switch {
+		case c != nil:
+			c.result <- queue.cancel(c)
+		case o != nil:
+			o.result <- queue.place(o)
+		default:
+			select {
+			case <-queue.notify:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if queue.interval > 0 {
+			timer := time.NewTimer(queue.interval)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// cancel sends a queued cancellation.
+func (queue *OrderQueue) cancel(c *queuedCancel) (result CancelResult) {
+	result = CancelResult{Stock: c.stock, OrderID: c.orderID}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Err = panicError(r)
+		}
+	}()
+
+	result.Order, result.Err = queue.api.CancelOrder(c.venue, c.stock, c.orderID)
+	return result
+}
+
+// place sends a queued order placement.
+func (queue *OrderQueue) place(o *queuedOrder) (result OrderResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = OrderResult{Err: panicError(r)}
+		}
+	}()
+
+	result.Order, result.Err = queue.api.PlaceOrder(o.req.Venue, o.req.Stock, o.req.Account, o.req.Price, o.req.Quantity, o.req.Direction, o.req.OrderType)
+	return result
+}
+
+// next pops the next request to send, cancellations first.
+func (queue *OrderQueue) next() (*queuedCancel, *queuedOrder) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	if len(queue.cancels) > 0 {
+		c := queue.cancels[0]
+		queue.cancels = queue.cancels[1:]
+		return c, nil
+	}
+	if len(queue.orders) > 0 {
+		o := queue.orders[0]
+		queue.orders = queue.orders[1:]
+		return nil, o
+	}
+	return nil, nil
+}
+
+// drain fails the requests still queued, and those queued afterwards, with
+// err.
+func (queue *OrderQueue) drain(err error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	queue.stopped = err
+	for _, c := range queue.cancels {
+		c.result <- CancelResult{Stock: c.stock, OrderID: c.orderID, Err: err}
+	}
+	for _, o := range queue.orders {
+		o.result <- OrderResult{Err: err}
+	}
+	queue.cancels, queue.orders = nil, nil
+}
+
+func sameSide(a, b OrderRequest) bool {
+	return a.Venue == b.Venue && a.Stock == b.Stock && a.Account == b.Account && a.Direction == b.Direction
+}
@@ -0,0 +1,90 @@
+package stockfighter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderQueue(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var req OrderRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		sent = append(sent, r.Method+" "+req.Direction)
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "id": 1, "price": req.Price})
+	})
+
+	order := func(direction string, price uint64) OrderRequest {
+		return OrderRequest{Venue: testVenue, Stock: testStock, Account: testAccount, Price: price, Quantity: 10, Direction: direction, OrderType: OrderTypeLimit}
+	}
+	queue := NewOrderQueue(client, 0)
+	placed := queue.Place(order(OrderDirectionBuy, 4000))
+	superseded := queue.Requote(order(OrderDirectionBuy, 5000))
+	canceled := queue.Cancel(testVenue, testStock, 1)
+	ask := queue.Requote(order(OrderDirectionSell, 5200))
+	bid := queue.Requote(order(OrderDirectionBuy, 5010))
+	assert.Equal(t, 4, queue.Pending())
+	assert.IsType(t, &ErrorOrderSuperseded{}, (<-superseded).Err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		queue.Run(ctx)
+		close(done)
+	}()
+
+	assert.Nil(t, (<-canceled).Err)
+	assert.Equal(t, uint64(4000), (<-placed).Order.Price)
+	assert.Equal(t, uint64(5200), (<-ask).Order.Price)
+	assert.Equal(t, uint64(5010), (<-bid).Order.Price)
+	// the requote took the place of the one it superseded
+	mu.Lock()
+	assert.Equal(t, []string{"DELETE ", "POST buy", "POST buy", "POST sell"}, sent)
+	mu.Unlock()
+	cancel()
+	<-done
+
+	// requests still queued fail when the queue stops
+	queue = NewOrderQueue(client, time.Hour)
+	first := queue.Place(order(OrderDirectionBuy, 4000))
+	second := queue.Place(order(OrderDirectionBuy, 4000))
+	ctx, cancel = context.WithCancel(context.Background())
+	done = make(chan struct{})
+	go func() {
+		queue.Run(ctx)
+		close(done)
+	}()
+	assert.Nil(t, (<-first).Err)
+	cancel()
+	<-done
+	assert.Equal(t, context.Canceled, (<-second).Err)
+
+	// requests queued once the queue stopped fail right away
+	assert.Equal(t, context.Canceled, (<-queue.Place(order(OrderDirectionBuy, 4000))).Err)
+	assert.Equal(t, context.Canceled, (<-queue.Requote(order(OrderDirectionBuy, 4000))).Err)
+	assert.Equal(t, context.Canceled, (<-queue.Cancel(testVenue, testStock, 1)).Err)
+	assert.Equal(t, 0, queue.Pending())
+
+	// invalid requests fail instead of crashing Run
+	queue = NewOrderQueue(client, 0)
+	invalid := queue.Place(order("hold", 4000))
+	invalidCancel := queue.Cancel(testVenue, "FOO BAR", 1)
+	valid := queue.Place(order(OrderDirectionSell, 4000))
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+	assert.Equal(t, "Invalid stock symbol: FOO BAR", (<-invalidCancel).Err.Error())
+	assert.NotNil(t, (<-invalid).Err)
+	assert.Nil(t, (<-valid).Err)
+
+	assert.Panics(t, func() { NewOrderQueue(client, -1) })
+}
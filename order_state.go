@@ -0,0 +1,66 @@
+package stockfighter
+
+// Order states (see Order.State).
+const (
+	// Open and not filled yet
+	OrderStateNew = "new"
+
+	// Open and filled in part
+	OrderStatePartiallyFilled = "partially-filled"
+
+	// Filled in full
+	OrderStateFilled = "filled"
+
+	// Closed before being filled in full, by a cancellation
+	OrderStateCanceled = "canceled"
+
+	// Closed before being filled in full, because the order type does not
+	// let it rest on the book (market, fill-or-kill, and immediate-or-cancel
+	// orders)
+	OrderStateExpired = "expired"
+)
+
+// State returns the state of the order in its lifecycle, derived from
+// whether it is open, how much of it was filled, and its type.
+func (order *Order) State() string {
+	switch {
+	case order.OriginalQuantity > 0 && order.TotalFilled >= order.OriginalQuantity:
+		return OrderStateFilled
+	case order.Open && order.TotalFilled == 0:
+		return OrderStateNew
+	case order.Open:
+		return OrderStatePartiallyFilled
+	}
+
+	switch order.OrderType {
+	case OrderTypeMarket, OrderTypeFillOrKill, OrderTypeImmediateOrCancel:
+		return OrderStateExpired
+	}
+	return OrderStateCanceled
+}
+
+// Changed reports whether the order changed since a previous status of the
+// same order, i.e. whether it got new fills or was closed. Any status is a
+// change from a nil previous status.
+func (order *Order) Changed(prev *Order) bool {
+	if prev == nil {
+		return true
+	}
+	return order.Open != prev.Open || order.TotalFilled != prev.TotalFilled || len(order.Fills) != len(prev.Fills)
+}
+
+// NewFillsSince returns the fills of the order not in a previous status of
+// the same order, so that fills are accounted once when polling the order.
+// All the fills are new since a nil previous status.
+//
+// Fills are only ever appended to an order, so the new fills are those past
+// the fills of the previous status.
+func (order *Order) NewFillsSince(prev *Order) []OrderFillInfo {
+	if prev == nil {
+		return order.Fills
+	}
+	if len(prev.Fills) >= len(order.Fills) {
+		return nil
+	}
+	return order.Fills[len(prev.Fills):]
+}
@@ -0,0 +1,53 @@
+package stockfighter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderState(t *testing.T) {
+	tests := []struct {
+		order Order
+		state string
+	}{
+		{Order{OrderType: OrderTypeLimit, OriginalQuantity: 10, Quantity: 10, Open: true}, OrderStateNew},
+		{Order{OrderType: OrderTypeLimit, OriginalQuantity: 10, Quantity: 6, TotalFilled: 4, Open: true}, OrderStatePartiallyFilled},
+		{Order{OrderType: OrderTypeLimit, OriginalQuantity: 10, TotalFilled: 10}, OrderStateFilled},
+		{Order{OrderType: OrderTypeLimit, OriginalQuantity: 10, TotalFilled: 4}, OrderStateCanceled},
+		{Order{OrderType: OrderTypeLimit, OriginalQuantity: 10}, OrderStateCanceled},
+		{Order{OrderType: OrderTypeImmediateOrCancel, OriginalQuantity: 10, TotalFilled: 4}, OrderStateExpired},
+		{Order{OrderType: OrderTypeFillOrKill, OriginalQuantity: 10}, OrderStateExpired},
+		{Order{OrderType: OrderTypeMarket, OriginalQuantity: 10, TotalFilled: 10}, OrderStateFilled},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.state, tt.order.State())
+	}
+}
+
+func TestOrderChanged(t *testing.T) {
+	prev := &Order{OriginalQuantity: 10, Quantity: 10, Open: true}
+	assert.True(t, prev.Changed(nil))
+
+	same := *prev
+	assert.False(t, same.Changed(prev))
+
+	filled := &Order{OriginalQuantity: 10, Quantity: 6, TotalFilled: 4, Open: true, Fills: []OrderFillInfo{{Price: 5000, Quantity: 4}}}
+	assert.True(t, filled.Changed(prev))
+
+	canceled := *prev
+	canceled.Open = false
+	assert.True(t, canceled.Changed(prev))
+}
+
+func TestOrderNewFillsSince(t *testing.T) {
+	fills := []OrderFillInfo{{Price: 5000, Quantity: 4}, {Price: 5010, Quantity: 2}, {Price: 5020, Quantity: 1}}
+	prev := &Order{Fills: fills[:1]}
+	order := &Order{Fills: fills}
+
+	assert.Equal(t, fills, order.NewFillsSince(nil))
+	assert.Equal(t, fills[1:], order.NewFillsSince(prev))
+	assert.Empty(t, order.NewFillsSince(order))
+	assert.Empty(t, prev.NewFillsSince(order))
+}
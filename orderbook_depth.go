@@ -0,0 +1,76 @@
+package stockfighter
+
+import "fmt"
+
+// Top returns a copy of the orderbook with only the entries of the best n
+// price levels of each side, e.g. to keep only the inside market of a deep
+// book. The entries are copied, so that the original entries can be freed.
+// This panics if n is not positive.
+//
+// Entries are expected best prices first, as returned by the API.
+func (orderbook *Orderbook) Top(n int) *Orderbook {
+	if n <= 0 {
+		panic(fmt.Errorf("Invalid depth levels: %v", n))
+	}
+
+	top := *orderbook
+	top.Bids = append([]OrderbookEntry(nil), orderbook.Bids[:topEntries(orderbook.Bids, n)]...)
+	top.Asks = append([]OrderbookEntry(nil), orderbook.Asks[:topEntries(orderbook.Asks, n)]...)
+	return &top
+}
+
+// topEntries returns the number of entries of one side of an orderbook on its
+// best n price levels.
+func topEntries(entries []OrderbookEntry, n int) int {
+	levels := 0
+	for i, entry := range entries {
+		if i == 0 || entry.Price != entries[i-1].Price {
+			if levels++; levels > n {
+				return i
+			}
+		}
+	}
+	return len(entries)
+}
+
+// GetOrderbookDepth returns the orderbook for a particular stock like
+// GetOrderbook, with only the entries of the best n price levels of each side.
+// Unlike the maxEntries of StreamOrderbook and GetOrderbookInto, n counts
+// price levels, which may have several entries each. The entries past them
+// are skipped as the response is decoded (see StreamOrderbook), so that they
+// are never kept in memory. This panics if n is not positive.
+//
+// Entries are expected best prices first, as returned by the API.
+//
+// Stockfighter API:
+//     GET https://api.stockfighter.io/ob/api/venues/:venue/stocks/:stock
+func (client *Client) GetOrderbookDepth(venue, stock string, n int) (*Orderbook, error) {
+	if n <= 0 {
+		panic(fmt.Errorf("Invalid depth levels: %v", n))
+	}
+
+	var bids, asks []OrderbookEntry
+	var bidLevels, askLevels int
+	book, err := client.StreamOrderbook(venue, stock, 0, func(entry OrderbookEntry) error {
+		entries, levels := &asks, &askLevels
+		if entry.IsBuy {
+			entries, levels = &bids, &bidLevels
+		}
+
+		if last := len(*entries) - 1; last < 0 || entry.Price != (*entries)[last].Price {
+			if *levels == n {
+				// past the best n levels
+				return nil
+			}
+			*levels++
+		}
+		*entries = append(*entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	book.Bids, book.Asks = bids, asks
+	return book, nil
+}
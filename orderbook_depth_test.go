@@ -0,0 +1,59 @@
+package stockfighter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testDepthOrderbook() *Orderbook {
+	return &Orderbook{
+		Venue:  testVenue,
+		Symbol: testStock,
+		Bids: []OrderbookEntry{
+			{Price: 5000, Quantity: 10, IsBuy: true},
+			{Price: 5000, Quantity: 20, IsBuy: true},
+			{Price: 4990, Quantity: 5, IsBuy: true},
+			{Price: 4980, Quantity: 5, IsBuy: true},
+		},
+		Asks: []OrderbookEntry{
+			{Price: 5100, Quantity: 7},
+		},
+	}
+}
+
+func TestOrderbookTop(t *testing.T) {
+	book := testDepthOrderbook()
+
+	top := book.Top(2)
+	assert.Equal(t, book.Bids[:3], top.Bids)
+	assert.Equal(t, book.Asks, top.Asks)
+	assert.Equal(t, testStock, top.Symbol)
+	assert.True(t, &top.Bids[0] != &book.Bids[0], "entries not copied")
+
+	assert.Len(t, book.Top(1).Bids, 2)
+	assert.Len(t, book.Top(10).Bids, 4)
+	assert.Empty(t, (&Orderbook{}).Top(1).Bids)
+	assert.Panics(t, func() { book.Top(0) })
+}
+
+func TestGetOrderbookDepth(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR", "bids": [
+			{"price": 5000, "qty": 10, "isBuy": true}, {"price": 5000, "qty": 20, "isBuy": true},
+			{"price": 4990, "qty": 5, "isBuy": true}, {"price": 4980, "qty": 5, "isBuy": true}
+		], "asks": [{"price": 5100, "qty": 7, "isBuy": false}]}`))
+	})
+
+	book, err := client.GetOrderbookDepth(testVenue, testStock, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, testDepthOrderbook().Top(2), book)
+
+	book, err = client.GetOrderbookDepth(testVenue, testStock, 1)
+	assert.Nil(t, err)
+	assert.Len(t, book.Bids, 2)
+	assert.Len(t, book.Asks, 1)
+
+	assert.Panics(t, func() { client.GetOrderbookDepth(testVenue, testStock, 0) })
+}
@@ -0,0 +1,204 @@
+package stockfighter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// StreamOrderbook gets the orderbook for a particular stock like GetOrderbook,
+// but decodes the response as it is received and calls fn with each entry
+// instead of keeping the entries, for venues with very deep books. Entries
+// past the first maxEntries of each side are skipped (0 for no limit); note
+// that maxEntries counts entries, not price levels as the n of
+// GetOrderbookDepth does. IsBuy is set from the side the entry is on.
+//
+// It returns the orderbook without its entries. An error returned by fn
+// stops the decoding and is returned.
+//
+// The response is decoded with encoding/json, whatever the codec of the
+// client.
+//
+// Stockfighter API:
+//     GET https://api.stockfighter.io/ob/api/venues/:venue/stocks/:stock
+func (client *Client) StreamOrderbook(venue, stock string, maxEntries int, fn func(entry OrderbookEntry) error) (*Orderbook, error) {
+	venue = strings.TrimSpace(venue)
+	if !validSymbol(venue) {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+
+	stock = strings.TrimSpace(stock)
+	if !validSymbol(stock) {
+		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
+	}
+
+	var resp orderbookStream
+	client.usage.add(client.subsystem)
+	statusCode, err := client.transport.stream(apiRequest{
+		ctx:       client.ctx,
+		method:    "GET",
+		path:      "/venues/" + url.PathEscape(venue) + "/stocks/" + url.PathEscape(stock),
+		subsystem: client.subsystem,
+	}, func(statusCode int, body io.Reader) error {
+		return resp.decode(statusCode, body, maxEntries, fn)
+	})
+	switch {
+	case err != nil:
+		return nil, err
+	case statusCode == 401: // unauthorized
+		return nil, &ErrorUnauthorized{}
+	case statusCode == 404: // venue or stock not found
+		return nil, notFoundError(venue, stock, resp.Error)
+	}
+
+	if !resp.OK {
+		return nil, errors.New(resp.Error)
+	}
+
+	return &resp.Orderbook, nil
+}
+
+// GetOrderbookInto gets the orderbook for a particular stock like
+// GetOrderbook, into book, reusing the capacity of its Bids and Asks, and
+// keeping at most maxEntries entries, not price levels, on each side (0 for
+// no limit). See StreamOrderbook.
+//
+// On error, book is reset to an empty orderbook, still keeping the capacity
+// of its Bids and Asks: their entries may have been overwritten already.
+func (client *Client) GetOrderbookInto(venue, stock string, maxEntries int, book *Orderbook) error {
+	bids, asks := book.Bids[:0], book.Asks[:0]
+	header, err := client.StreamOrderbook(venue, stock, maxEntries, func(entry OrderbookEntry) error {
+		if entry.IsBuy {
+			bids = append(bids, entry)
+		} else {
+			asks = append(asks, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		*book = Orderbook{Bids: bids[:0], Asks: asks[:0]}
+		return err
+	}
+
+	*book = *header
+	book.Bids, book.Asks = bids, asks
+	return nil
+}
+
+// orderbookStream is the response of the orderbook endpoint, decoded as it
+// is received.
+type orderbookStream struct {
+	OK    bool
+	Error string
+	Orderbook
+}
+
+// decode decodes the response body, calling fn with the entries.
+func (resp *orderbookStream) decode(statusCode int, body io.Reader, maxEntries int, fn func(OrderbookEntry) error) error {
+	decodeErr := func(err error) error {
+		return &ErrorDecode{StatusCode: statusCode, Err: err}
+	}
+
+	decoder := json.NewDecoder(body)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return decodeErr(err)
+	}
+
+	hasOK := false
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return decodeErr(err)
+		}
+		key, _ := token.(string)
+
+		// keys are matched case-insensitively, like encoding/json does
+		switch {
+		case strings.EqualFold(key, "ok"):
+			hasOK = true
+			err = decoder.Decode(&resp.OK)
+		case strings.EqualFold(key, "error"):
+			err = decoder.Decode(&resp.Error)
+		case strings.EqualFold(key, "venue"):
+			err = decoder.Decode(&resp.Venue)
+		case strings.EqualFold(key, "symbol"):
+			err = decoder.Decode(&resp.Symbol)
+		case strings.EqualFold(key, "ts"):
+			err = decoder.Decode(&resp.Timestamp)
+		case strings.EqualFold(key, "bids"), strings.EqualFold(key, "asks"):
+			if err := decodeEntries(decoder, strings.EqualFold(key, "bids"), maxEntries, fn); err != nil {
+				var decodeError *ErrorDecode
+				if errors.As(err, &decodeError) {
+					decodeError.StatusCode = statusCode
+				}
+				return err
+			}
+		default:
+			var skipped json.RawMessage
+			err = decoder.Decode(&skipped)
+		}
+		if err != nil {
+			return decodeErr(err)
+		}
+	}
+
+	if err := expectDelim(decoder, '}'); err != nil {
+		return decodeErr(err)
+	}
+	if !hasOK {
+		return decodeErr(errors.New(`missing "ok" field`))
+	}
+	return nil
+}
+
+// decodeEntries decodes an array of orderbook entries (or null), calling fn
+// with the first maxEntries ones. Decoding errors are *ErrorDecode, errors of
+// fn are returned as is.
+func decodeEntries(decoder *json.Decoder, isBuy bool, maxEntries int, fn func(OrderbookEntry) error) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return &ErrorDecode{Err: err}
+	}
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return &ErrorDecode{Err: fmt.Errorf("expected array of entries, got %v", token)}
+	}
+
+	var entry OrderbookEntry
+	for n := 0; decoder.More(); n++ {
+		entry = OrderbookEntry{}
+		if err := decoder.Decode(&entry); err != nil {
+			return &ErrorDecode{Err: err}
+		}
+		if maxEntries > 0 && n >= maxEntries {
+			continue
+		}
+
+		entry.IsBuy = isBuy
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	if err := expectDelim(decoder, ']'); err != nil {
+		return &ErrorDecode{Err: err}
+	}
+	return nil
+}
+
+// expectDelim reads a delimiter token.
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := token.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %v, got %v", delim, token)
+	}
+	return nil
+}
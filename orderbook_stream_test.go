@@ -0,0 +1,133 @@
+package stockfighter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// deepOrderbook returns an orderbook response with n levels on each side.
+func deepOrderbook(n int) string {
+	var bids, asks []string
+	for i := 0; i < n; i++ {
+		bids = append(bids, fmt.Sprintf(`{"price": %v, "qty": 10, "isBuy": true}`, 5000-i))
+		asks = append(asks, fmt.Sprintf(`{"price": %v, "qty": 10, "isBuy": false}`, 5100+i))
+	}
+	return fmt.Sprintf(`{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR", "bids": [%v], "asks": [%v], "ts": "2015-12-04T09:02:16.680986205Z", "extra": {"a": [1]}}`,
+		strings.Join(bids, ","), strings.Join(asks, ","))
+}
+
+func TestStreamOrderbook(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/venues/TESTEX/stocks/FOOBAR", r.URL.Path)
+		w.Write([]byte(deepOrderbook(1000)))
+	})
+
+	var bids, asks int
+	book, err := client.StreamOrderbook(testVenue, testStock, 0, func(entry OrderbookEntry) error {
+		if entry.IsBuy {
+			bids++
+		} else {
+			asks++
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1000, bids)
+	assert.Equal(t, 1000, asks)
+	assert.Equal(t, "FOOBAR", book.Symbol)
+	assert.Equal(t, "TESTEX", book.Venue)
+	assert.False(t, book.Timestamp.IsZero())
+	assert.Nil(t, book.Bids)
+
+	// the same as GetOrderbook
+	want, err := client.GetOrderbook(testVenue, testStock)
+	assert.Nil(t, err)
+	book = &Orderbook{Bids: make([]OrderbookEntry, 0, 2000)}
+	assert.Nil(t, client.GetOrderbookInto(testVenue, testStock, 0, book))
+	assert.Equal(t, want, book)
+
+	// preallocated slices are reused, up to the maximum depth
+	bidsArray := book.Bids[:cap(book.Bids)]
+	assert.Nil(t, client.GetOrderbookInto(testVenue, testStock, 5, book))
+	assert.Len(t, book.Bids, 5)
+	assert.Len(t, book.Asks, 5)
+	assert.Equal(t, uint64(4996), book.Bids[4].Price)
+	assert.Equal(t, uint64(5104), book.Asks[4].Price)
+	assert.True(t, &bidsArray[0] == &book.Bids[0], "bids not reused")
+
+	// errors of the callback stop decoding
+	errStop := errors.New("stop")
+	n := 0
+	_, err = client.StreamOrderbook(testVenue, testStock, 0, func(entry OrderbookEntry) error {
+		if n++; n == 3 {
+			return errStop
+		}
+		return nil
+	})
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, 3, n)
+}
+
+func TestStreamOrderbookKeys(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"OK": true, "Venue": "TESTEX", "SYMBOL": "FOOBAR", "Bids": [{"Price": 5000, "QTY": 10, "IsBuy": true}], "asks": null, "Ts": "2015-12-04T09:02:16Z"}`))
+	})
+
+	// keys are matched case-insensitively, like GetOrderbook does
+	want, err := client.GetOrderbook(testVenue, testStock)
+	assert.Nil(t, err)
+	book := &Orderbook{}
+	assert.Nil(t, client.GetOrderbookInto(testVenue, testStock, 0, book))
+	assert.Equal(t, want, book)
+	assert.Equal(t, "FOOBAR", book.Symbol)
+	assert.Equal(t, 1, len(book.Bids))
+}
+
+func TestGetOrderbookIntoError(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true, "venue": "TESTEX", "bids": [{"price": 4000, "qty": 1}, {"price": "high"}]}`))
+	})
+
+	bids := make([]OrderbookEntry, 2, 10)
+	book := &Orderbook{Venue: "TESTEX", Symbol: "FOOBAR", Bids: bids}
+	assert.IsType(t, &ErrorDecode{}, client.GetOrderbookInto(testVenue, testStock, 0, book))
+
+	// the book is reset, as its entries were overwritten
+	assert.Equal(t, "", book.Symbol)
+	assert.Equal(t, 0, len(book.Bids))
+	assert.Equal(t, 10, cap(book.Bids))
+	assert.Equal(t, uint64(4000), bids[0].Price)
+}
+
+func TestStreamOrderbookErrors(t *testing.T) {
+	for _, tt := range []struct {
+		status int
+		body   string
+		err    error
+	}{
+		{401, `{"ok": false, "error": "unauthorized"}`, &ErrorUnauthorized{}},
+		{404, `{"ok": false, "error": "No venue exists with the symbol TESTEX"}`, &ErrorVenueNotFound{VenueSymbol: testVenue}},
+		{200, `{"ok": false, "error": "oops"}`, errors.New("oops")},
+		{200, `{"venue": "TESTEX"}`, &ErrorDecode{StatusCode: 200, Err: errors.New(`missing "ok" field`)}},
+		{502, `<html>`, nil},
+		{200, `{"ok": true, "bids": [{"price": "high"}]}`, nil},
+		{200, `{"ok": true, "bids": {}}`, nil},
+	} {
+		client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		})
+
+		_, err := client.StreamOrderbook(testVenue, testStock, 0, func(OrderbookEntry) error { return nil })
+		if tt.err != nil {
+			assert.Equal(t, tt.err, err, tt.body)
+		} else if assert.IsType(t, &ErrorDecode{}, err, tt.body) {
+			assert.Equal(t, tt.status, err.(*ErrorDecode).StatusCode)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Orderbook level change kinds.
+const (
+	LevelAdded   = "added"
+	LevelRemoved = "removed"
+	LevelResized = "resized"
+)
+
+// An OrderbookLevelChange represents a change of a single price level between
+// two orderbook snapshots.
+type OrderbookLevelChange struct {
+	// Change kind (LevelAdded, LevelRemoved, or LevelResized)
+	Kind string
+
+	// Side and price of the level
+	IsBuy bool
+	Price uint64
+
+	// Total quantity at the level before and after the change
+	OldQuantity uint64
+	Quantity    uint64
+}
+
+func (c OrderbookLevelChange) String() string {
+	side := "SELL"
+	if c.IsBuy {
+		side = "BUY "
+	}
+
+	return fmt.Sprintf("%v $%.2f %v -> %v (%v)", side, float64(c.Price)/100.0, c.OldQuantity, c.Quantity, c.Kind)
+}
+
+// DiffOrderbooks returns the level changes needed to turn prev into next.
+// Entries at the same price are aggregated into a single level. A nil prev is
+// treated as an empty orderbook.
+//
+// Bid changes come first, best price first, followed by ask changes.
+func DiffOrderbooks(prev, next *Orderbook) []OrderbookLevelChange {
+	var changes []OrderbookLevelChange
+	for _, isBuy := range []bool{true, false} {
+		oldLevels, newLevels := orderbookLevels(prev, isBuy), orderbookLevels(next, isBuy)
+
+		var sideChanges []OrderbookLevelChange
+		for price, qty := range newLevels {
+			oldQty, ok := oldLevels[price]
+			switch {
+			case !ok:
+				sideChanges = append(sideChanges, OrderbookLevelChange{Kind: LevelAdded, IsBuy: isBuy, Price: price, Quantity: qty})
+			case oldQty != qty:
+				sideChanges = append(sideChanges, OrderbookLevelChange{Kind: LevelResized, IsBuy: isBuy, Price: price, OldQuantity: oldQty, Quantity: qty})
+			}
+		}
+		for price, oldQty := range oldLevels {
+			if _, ok := newLevels[price]; !ok {
+				sideChanges = append(sideChanges, OrderbookLevelChange{Kind: LevelRemoved, IsBuy: isBuy, Price: price, OldQuantity: oldQty})
+			}
+		}
+
+		sort.Slice(sideChanges, func(i, j int) bool {
+			if isBuy {
+				return sideChanges[i].Price > sideChanges[j].Price
+			}
+			return sideChanges[i].Price < sideChanges[j].Price
+		})
+		changes = append(changes, sideChanges...)
+	}
+
+	return changes
+}
+
+func orderbookLevels(orderbook *Orderbook, bids bool) map[uint64]uint64 {
+	levels := make(map[uint64]uint64)
+	if orderbook == nil {
+		return levels
+	}
+
+	entries := orderbook.Asks
+	if bids {
+		entries = orderbook.Bids
+	}
+	for _, entry := range entries {
+		levels[entry.Price] += entry.Quantity
+	}
+
+	return levels
+}
+
+// An OrderbookDelta represents the changes between two consecutive orderbook
+// snapshots delivered by an OrderbookWatcher.
+//
+// Either Err is set, or Orderbook and Changes are.
+type OrderbookDelta struct {
+	// Level changes since the previous snapshot
+	Changes []OrderbookLevelChange
+
+	// New orderbook snapshot
+	Orderbook *Orderbook
+
+	// Error returned by GetOrderbook, if any
+	Err error
+}
+
+// An OrderbookWatcher polls the orderbook of a stock and delivers the changes
+// between snapshots on a channel. Snapshots without any change are not
+// delivered.
+//
+// You can create a new OrderbookWatcher using NewOrderbookWatcher function.
+type OrderbookWatcher struct {
+	client   *Client
+	venue    string
+	stock    string
+	interval time.Duration
+}
+
+// NewOrderbookWatcher creates a new OrderbookWatcher polling the orderbook
+// every interval. This never returns nil.
+func NewOrderbookWatcher(client *Client, venue, stock string, interval time.Duration) *OrderbookWatcher {
+	venue = strings.TrimSpace(venue)
+	if !validSymbol(venue) {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+
+	stock = strings.TrimSpace(stock)
+	if !validSymbol(stock) {
+		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
+	}
+
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	return &OrderbookWatcher{
+		client:   client,
+		venue:    venue,
+		stock:    stock,
+		interval: interval,
+	}
+}
+
+// Start starts polling in a new goroutine and returns the channel deltas are
+// delivered on. The first delta lists every level of the first snapshot as
+// added.
+//
+// Polling stops and the channel is closed when ctx is done.
+func (watcher *OrderbookWatcher) Start(ctx context.Context) <-chan OrderbookDelta {
+	deltas := make(chan OrderbookDelta)
+
+	go func() {
+		defer close(deltas)
+
+		ticker := time.NewTicker(watcher.interval)
+		defer ticker.Stop()
+
+		var prev *Orderbook
+		for {
+			orderbook, err := watcher.client.GetOrderbook(watcher.venue, watcher.stock)
+			delta := OrderbookDelta{Orderbook: orderbook, Err: err}
+			if err == nil {
+				delta.Changes = DiffOrderbooks(prev, orderbook)
+				prev = orderbook
+			}
+
+			if err != nil || len(delta.Changes) > 0 {
+				select {
+				case deltas <- delta:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return deltas
+}
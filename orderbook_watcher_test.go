@@ -0,0 +1,28 @@
+package stockfighter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffOrderbooks(t *testing.T) {
+	prev := &Orderbook{
+		Bids: []OrderbookEntry{{Price: 100, Quantity: 10, IsBuy: true}, {Price: 99, Quantity: 5, IsBuy: true}},
+		Asks: []OrderbookEntry{{Price: 105, Quantity: 7}, {Price: 105, Quantity: 3}},
+	}
+	next := &Orderbook{
+		Bids: []OrderbookEntry{{Price: 101, Quantity: 1, IsBuy: true}, {Price: 100, Quantity: 4, IsBuy: true}},
+		Asks: []OrderbookEntry{{Price: 105, Quantity: 10}, {Price: 106, Quantity: 2}},
+	}
+
+	assert.Equal(t, []OrderbookLevelChange{
+		{Kind: LevelAdded, IsBuy: true, Price: 101, Quantity: 1},
+		{Kind: LevelResized, IsBuy: true, Price: 100, OldQuantity: 10, Quantity: 4},
+		{Kind: LevelRemoved, IsBuy: true, Price: 99, OldQuantity: 5},
+		{Kind: LevelAdded, Price: 106, Quantity: 2},
+	}, DiffOrderbooks(prev, next))
+
+	assert.Len(t, DiffOrderbooks(nil, prev), 3)
+	assert.Empty(t, DiffOrderbooks(next, next))
+}
@@ -0,0 +1,38 @@
+/*
+Package parquetexport writes the tables of a recorded session (see package
+export) as Parquet files:
+
+    tables, err := export.ReadRecording(recording)
+    if err != nil {
+        return err
+    }
+    err = parquetexport.Write(tables, "out")
+*/
+package parquetexport
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/parquet-go/parquet-go"
+	"gpk.io/stockfighter/export"
+)
+
+// Write writes the tables to quotes.parquet, trades.parquet, orders.parquet,
+// and fills.parquet in dir, which is created if needed.
+func Write(tables *export.Tables, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	if err := parquet.WriteFile(filepath.Join(dir, "quotes.parquet"), tables.Quotes); err != nil {
+		return err
+	}
+	if err := parquet.WriteFile(filepath.Join(dir, "trades.parquet"), tables.Trades); err != nil {
+		return err
+	}
+	if err := parquet.WriteFile(filepath.Join(dir, "orders.parquet"), tables.Orders); err != nil {
+		return err
+	}
+	return parquet.WriteFile(filepath.Join(dir, "fills.parquet"), tables.Fills)
+}
@@ -0,0 +1,45 @@
+package parquetexport
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter/export"
+)
+
+func TestWrite(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	bid, bidSize := uint64(5000), uint64(10)
+	tables := &export.Tables{
+		Quotes: []export.QuoteRow{
+			{Time: ts, Venue: "TESTEX", Symbol: "FOOBAR", Bid: &bid, BidSize: &bidSize, BidDepth: 10, Last: 5000, LastSize: 5, LastTrade: ts},
+			{Time: ts.Add(time.Second), Venue: "TESTEX", Symbol: "FOOBAR", Last: 5000, LastSize: 5, LastTrade: ts},
+		},
+		Trades: []export.TradeRow{{Time: ts, Venue: "TESTEX", Symbol: "FOOBAR", Price: 5000, Size: 5}},
+		Orders: []export.OrderRow{{Time: ts, Venue: "TESTEX", Symbol: "FOOBAR", OrderID: 1, Direction: "buy", OrderType: "limit",
+			State: "open", Price: 5000, Quantity: 100}},
+	}
+
+	dir := filepath.Join(t.TempDir(), "out")
+	assert.Nil(t, Write(tables, dir))
+
+	quotes, err := parquet.ReadFile[export.QuoteRow](filepath.Join(dir, "quotes.parquet"))
+	assert.Nil(t, err)
+	assert.Equal(t, tables.Quotes, quotes)
+
+	trades, err := parquet.ReadFile[export.TradeRow](filepath.Join(dir, "trades.parquet"))
+	assert.Nil(t, err)
+	assert.Equal(t, tables.Trades, trades)
+
+	orders, err := parquet.ReadFile[export.OrderRow](filepath.Join(dir, "orders.parquet"))
+	assert.Nil(t, err)
+	assert.Equal(t, tables.Orders, orders)
+
+	// empty tables are written too
+	fills, err := parquet.ReadFile[export.FillRow](filepath.Join(dir, "fills.parquet"))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(fills))
+}
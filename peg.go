@@ -0,0 +1,188 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// A PegResult represents the outcome of a PeggedOrder run.
+type PegResult struct {
+	// Orders placed, in order; all but the last one were replaced
+	Orders []Order
+
+	// Total filled quantity
+	FilledQuantity uint64
+}
+
+// A PeggedOrder works a limit order pegged to the best price on its side of
+// the orderbook (the best bid for buys, the best ask for sells), replacing it
+// as the market moves.
+//
+// You can create a new PeggedOrder using NewPeggedOrder function.
+type PeggedOrder struct {
+	client *Client
+	req    OrderRequest
+
+	// Offset from the best price (in cents) towards the opposite side, e.g. 1
+	// to improve the best bid by one cent
+	Offset int64
+
+	// Maximum distance (in cents) the order price may move away from its first
+	// price in the adverse direction (0 means no limit)
+	MaxChase uint64
+
+	// Orderbook polling interval (DefaultPollInterval if not positive)
+	Interval time.Duration
+}
+
+// NewPeggedOrder creates a new PeggedOrder for the order request. The price of
+// the request is ignored, and the order type is always limit. This never
+// returns nil.
+func NewPeggedOrder(client *Client, req OrderRequest) *PeggedOrder {
+	if req.Direction != OrderDirectionBuy && req.Direction != OrderDirectionSell {
+		panic(fmt.Errorf("Invalid order direction: %v", req.Direction))
+	}
+	req.OrderType = OrderTypeLimit
+
+	return &PeggedOrder{
+		client:   client,
+		req:      req,
+		Interval: DefaultPollInterval,
+	}
+}
+
+// Run works the order until it is completely filled or ctx is done. If ctx is
+// done first, the working order is canceled.
+//
+// The returned result describes the orders placed so far, including when an
+// error is returned.
+func (peg *PeggedOrder) Run(ctx context.Context) (*PegResult, error) {
+	result := &PegResult{}
+	var current *Order
+	var filled uint64
+	var firstPrice uint64
+
+	// closeCurrent records the final status of the working order.
+	closeCurrent := func(status *Order) {
+		result.Orders = append(result.Orders, *status)
+		filled += status.TotalFilled
+		current = nil
+	}
+
+	interval := peg.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if current != nil {
+			status, err := peg.client.GetOrder(peg.req.Venue, peg.req.Stock, current.OrderID)
+			if err != nil {
+				return peg.finish(result, current, filled), err
+			}
+
+			current = status
+			if !current.Open {
+				closeCurrent(current)
+			}
+		}
+
+		if filled >= peg.req.Quantity {
+			return peg.finish(result, current, filled), nil
+		}
+
+		orderbook, err := peg.client.GetOrderbook(peg.req.Venue, peg.req.Stock)
+		if err != nil {
+			return peg.finish(result, current, filled), err
+		}
+
+		if price, ok := peg.price(orderbook, current, firstPrice); ok && (current == nil || current.Price != price) {
+			if current != nil {
+				status, err := peg.client.CancelOrder(peg.req.Venue, peg.req.Stock, current.OrderID)
+				if err != nil {
+					return peg.finish(result, current, filled), err
+				}
+				closeCurrent(status)
+			}
+
+			if filled < peg.req.Quantity {
+				current, err = peg.client.PlaceOrder(peg.req.Venue, peg.req.Stock, peg.req.Account,
+					price, peg.req.Quantity-filled, peg.req.Direction, peg.req.OrderType)
+				if err != nil {
+					return peg.finish(result, nil, filled), err
+				}
+				if firstPrice == 0 {
+					firstPrice = price
+				}
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if current != nil {
+				if status, err := peg.client.CancelOrder(peg.req.Venue, peg.req.Stock, current.OrderID); err == nil {
+					closeCurrent(status)
+				}
+			}
+			return peg.finish(result, current, filled), ctx.Err()
+		}
+	}
+}
+
+func (peg *PeggedOrder) finish(result *PegResult, current *Order, filled uint64) *PegResult {
+	if current != nil {
+		result.Orders = append(result.Orders, *current)
+		filled += current.TotalFilled
+	}
+
+	result.FilledQuantity = filled
+	return result
+}
+
+// price returns the pegged price for the orderbook, ignoring the quantity of
+// the working order itself. It returns false if that side of the orderbook is
+// empty.
+func (peg *PeggedOrder) price(orderbook *Orderbook, current *Order, firstPrice uint64) (uint64, bool) {
+	isBuy := peg.req.Direction == OrderDirectionBuy
+	levels := orderbookLevels(orderbook, isBuy)
+	if current != nil && current.Open {
+		if levels[current.Price] <= current.Quantity {
+			delete(levels, current.Price)
+		} else {
+			levels[current.Price] -= current.Quantity
+		}
+	}
+
+	var best uint64
+	for p := range levels {
+		if best == 0 || (isBuy && p > best) || (!isBuy && p < best) {
+			best = p
+		}
+	}
+	if best == 0 {
+		return 0, false
+	}
+
+	price := int64(best) + peg.Offset
+	if !isBuy {
+		price = int64(best) - peg.Offset
+	}
+	if price < 1 {
+		price = 1
+	}
+
+	if peg.MaxChase > 0 && firstPrice > 0 {
+		if isBuy && price > int64(firstPrice+peg.MaxChase) {
+			price = int64(firstPrice + peg.MaxChase)
+		}
+		if !isBuy && price < int64(firstPrice)-int64(peg.MaxChase) {
+			price = int64(firstPrice) - int64(peg.MaxChase)
+		}
+	}
+
+	return uint64(price), true
+}
@@ -0,0 +1,63 @@
+package stockfighter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeggedOrderPrice(t *testing.T) {
+	peg := NewPeggedOrder(NewClient(testApiKey), OrderRequest{Venue: testVenue, Stock: testStock, Account: testAccount, Quantity: 10, Direction: OrderDirectionBuy})
+	orderbook := &Orderbook{
+		Bids: []OrderbookEntry{{Price: 101, Quantity: 10, IsBuy: true}, {Price: 100, Quantity: 5, IsBuy: true}, {Price: 100, Quantity: 10, IsBuy: true}},
+		Asks: []OrderbookEntry{{Price: 105, Quantity: 7}},
+	}
+
+	price, ok := peg.price(orderbook, nil, 0)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(101), price)
+
+	// the working order alone at the best bid does not hold the peg up
+	price, _ = peg.price(orderbook, &Order{Price: 101, Quantity: 10, Open: true}, 0)
+	assert.Equal(t, uint64(100), price)
+	price, _ = peg.price(orderbook, &Order{Price: 100, Quantity: 10, Open: true}, 0)
+	assert.Equal(t, uint64(101), price)
+
+	// offset and max chase
+	peg.Offset = 2
+	peg.MaxChase = 1
+	price, _ = peg.price(orderbook, nil, 101)
+	assert.Equal(t, uint64(102), price)
+
+	peg = NewPeggedOrder(NewClient(testApiKey), OrderRequest{Venue: testVenue, Stock: testStock, Account: testAccount, Quantity: 10, Direction: OrderDirectionSell})
+	peg.Offset = 1
+	price, _ = peg.price(orderbook, nil, 0)
+	assert.Equal(t, uint64(104), price)
+
+	_, ok = peg.price(&Orderbook{}, nil, 0)
+	assert.False(t, ok)
+}
+
+func TestPeggedOrderRunDefaultInterval(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			w.Write([]byte(`{"ok": true, "id": 1, "price": 101, "qty": 10, "open": true}`))
+		case "DELETE":
+			w.Write([]byte(`{"ok": true, "id": 1, "price": 101, "open": false, "totalFilled": 3}`))
+		default:
+			w.Write([]byte(`{"ok": true, "bids": [{"price": 101, "qty": 10, "isBuy": true}]}`))
+		}
+	})
+	peg := NewPeggedOrder(client, OrderRequest{Venue: testVenue, Stock: testStock, Account: testAccount, Quantity: 10, Direction: OrderDirectionBuy})
+	peg.Interval = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := peg.Run(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Len(t, result.Orders, 1)
+	assert.Equal(t, uint64(3), result.FilledQuantity)
+}
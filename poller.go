@@ -0,0 +1,159 @@
+package stockfighter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultPollInterval is the polling interval used when a poller is created
+// with a non-positive interval.
+const DefaultPollInterval = time.Second
+
+// A QuoteUpdate represents a quote delivered by a QuotePoller.
+//
+// Exactly one of Quote and Err is set.
+type QuoteUpdate struct {
+	// Venue and stock symbols the update is for
+	Venue string
+	Stock string
+
+	// New quote for the stock
+	Quote *Quote
+
+	// Error returned by GetQuote, if any
+	Err error
+}
+
+// A QuotePoller polls quotes for a set of stocks in a venue and delivers
+// changed quotes on a channel.
+//
+// A quote is considered unchanged (and is not delivered again) if its
+// QuoteTime equals the QuoteTime of the last quote delivered for the stock.
+//
+// You can create a new QuotePoller using NewQuotePoller function.
+type QuotePoller struct {
+	client   *Client
+	venue    string
+	stocks   []string
+	interval time.Duration
+}
+
+// NewQuotePoller creates a new QuotePoller polling the given stocks every
+// interval. This never returns nil.
+func NewQuotePoller(client *Client, venue string, stocks []string, interval time.Duration) *QuotePoller {
+	venue = strings.TrimSpace(venue)
+	if !validSymbol(venue) {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+
+	symbols := make([]string, len(stocks))
+	for i, stock := range stocks {
+		symbols[i] = strings.TrimSpace(stock)
+		if !validSymbol(symbols[i]) {
+			panic(fmt.Errorf("Invalid stock symbol: %v", stock))
+		}
+	}
+
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	return &QuotePoller{
+		client:   client,
+		venue:    venue,
+		stocks:   symbols,
+		interval: interval,
+	}
+}
+
+// Start starts polling in a new goroutine and returns the channel updates are
+// delivered on. The first poll happens immediately.
+//
+// Polling stops and the channel is closed when ctx is done. Requests are made
+// with ctx (see Client.WithContext), so that a request in flight is canceled
+// too.
+func (poller *QuotePoller) Start(ctx context.Context) <-chan QuoteUpdate {
+	client := poller.client.WithContext(ctx)
+	updates := make(chan QuoteUpdate)
+
+	go func() {
+		defer close(updates)
+
+		ticker := time.NewTicker(poller.interval)
+		defer ticker.Stop()
+
+		lastQuoteTimes := make(map[string]time.Time, len(poller.stocks))
+		for {
+			for _, stock := range poller.stocks {
+				quote, err := client.GetQuote(poller.venue, stock)
+				if ctx.Err() != nil {
+					return
+				}
+				if err == nil {
+					if last, ok := lastQuoteTimes[stock]; ok && last.Equal(quote.QuoteTime) {
+						continue
+					}
+					lastQuoteTimes[stock] = quote.QuoteTime
+				}
+
+				select {
+				case updates <- QuoteUpdate{Venue: poller.venue, Stock: stock, Quote: quote, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return updates
+}
+
+// SubscribeQuotes calls handler with every new quote of the stocks of a
+// venue, polled every DefaultPollInterval, until handler returns an error or
+// ctx is done. It returns the error of handler, or ctx.Err(). Requests are
+// made with ctx (see Client.WithContext).
+//
+// This is a simpler alternative to a QuotePoller for small bots: quotes are
+// handled one at a time, in the calling goroutine. Polling errors are
+// skipped, except for an unknown venue or API key.
+func (client *Client) SubscribeQuotes(ctx context.Context, venue string, handler func(Quote) error) error {
+	client = client.WithContext(ctx)
+	stocks, err := client.ListStocks(venue)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	symbols := make([]string, len(stocks))
+	for i, stock := range stocks {
+		symbols[i] = stock.Symbol
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for update := range NewQuotePoller(client, venue, symbols, DefaultPollInterval).Start(ctx) {
+		if update.Err != nil {
+			var venueNotFound *ErrorVenueNotFound
+			var unauthorized *ErrorUnauthorized
+			if errors.As(update.Err, &venueNotFound) || errors.As(update.Err, &unauthorized) {
+				return update.Err
+			}
+			continue
+		}
+		if err := handler(*update.Quote); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,81 @@
+package stockfighter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestServer starts an httptest.Server and returns a client using it as the
+// API base URL.
+func newTestServer(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewClient(testApiKey, WithBaseURL(server.URL))
+}
+
+func TestQuotePoller(t *testing.T) {
+	var polls int32
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// the quote time changes every other poll
+		n := atomic.AddInt32(&polls, 1)
+		fmt.Fprintf(w, `{"ok": true, "symbol": "%s", "venue": "%s", "bid": 100, "quoteTime": "2015-12-04T09:02:16.%03dZ"}`,
+			testStock, testVenue, (n+1)/2)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	poller := NewQuotePoller(client, testVenue, []string{testStock}, time.Millisecond)
+	updates := poller.Start(ctx)
+
+	var last time.Time
+	for i := 0; i < 3; i++ {
+		update := <-updates
+		assert.Nil(t, update.Err)
+		assert.Equal(t, testStock, update.Stock)
+		assert.Equal(t, uint64(100), update.Quote.BidPrice)
+		assert.True(t, update.Quote.QuoteTime.After(last))
+		last = update.Quote.QuoteTime
+	}
+
+	cancel()
+	for range updates {
+	}
+}
+
+func TestSubscribeQuotes(t *testing.T) {
+	var polls int32
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/venues/"+testVenue+"/stocks" {
+			fmt.Fprintf(w, `{"ok": true, "symbols": [{"name": "Foobar", "symbol": "%s"}]}`, testStock)
+			return
+		}
+		n := atomic.AddInt32(&polls, 1)
+		fmt.Fprintf(w, `{"ok": true, "symbol": "%s", "venue": "%s", "bid": %d, "quoteTime": "2015-12-04T09:02:16.%03dZ"}`,
+			testStock, testVenue, n, n)
+	})
+
+	var bids []uint64
+	stop := errors.New("stop")
+	err := client.SubscribeQuotes(context.Background(), testVenue, func(quote Quote) error {
+		assert.Equal(t, testStock, quote.Symbol)
+		bids = append(bids, quote.BidPrice)
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []uint64{1}, bids)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = client.SubscribeQuotes(ctx, testVenue, func(quote Quote) error { return nil })
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,41 @@
+package stockfighter
+
+// A Position represents the holdings of an account in a stock, as the result
+// of the fills of its orders.
+type Position struct {
+	// Shares held (negative when short)
+	Shares int64
+
+	// Cash received from sales minus cash spent on purchases, in cents
+	Cash int64
+}
+
+// PositionFromOrders returns the position resulting from the fills of the
+// given orders, which are expected to be for a single stock.
+func PositionFromOrders(orders []Order) Position {
+	var position Position
+	for _, order := range orders {
+		for _, fill := range order.Fills {
+			position.Apply(order.Direction, fill)
+		}
+	}
+	return position
+}
+
+// Apply updates the position with a fill of an order in the given direction.
+func (position *Position) Apply(direction string, fill OrderFillInfo) {
+	qty, value := int64(fill.Quantity), int64(fill.Quantity*fill.Price)
+	if direction == OrderDirectionBuy {
+		position.Shares += qty
+		position.Cash -= value
+	} else {
+		position.Shares -= qty
+		position.Cash += value
+	}
+}
+
+// NAV returns the net asset value of the position, in cents, with shares
+// valued at the given price.
+func (position Position) NAV(price uint64) int64 {
+	return position.Cash + position.Shares*int64(price)
+}
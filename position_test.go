@@ -0,0 +1,18 @@
+package stockfighter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositionFromOrders(t *testing.T) {
+	position := PositionFromOrders([]Order{
+		{Direction: OrderDirectionBuy, Fills: []OrderFillInfo{{Price: 5000, Quantity: 10}, {Price: 5100, Quantity: 5}}},
+		{Direction: OrderDirectionSell, Fills: []OrderFillInfo{{Price: 5200, Quantity: 20}}},
+	})
+	assert.Equal(t, Position{Shares: -5, Cash: -50000 - 25500 + 104000}, position)
+	assert.Equal(t, int64(28500-5*5300), position.NAV(5300))
+
+	assert.Equal(t, Position{}, PositionFromOrders(nil))
+}
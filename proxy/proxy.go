@@ -0,0 +1,207 @@
+/*
+Package proxy provides a local HTTP proxy fronting the Stockfighter API, so
+that several local processes share a single API key and rate budget.
+
+The proxy injects the API key in the requests it forwards, caches successful
+GET responses for a short time, and spaces out the requests it sends to the
+API. Clients use it as their base URLs, without an API key:
+
+    p := proxy.New(apiKey)
+    p.CacheTTL = 250 * time.Millisecond
+    go http.ListenAndServe("localhost:8080", p)
+
+    client := stockfighter.NewClient("",
+        stockfighter.WithBaseURL("http://localhost:8080"+proxy.PathPrefix),
+        stockfighter.WithGMBaseURL("http://localhost:8080"+proxy.GMPathPrefix))
+*/
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// Path prefixes of the requests forwarded to the API and GM API base URLs.
+const (
+	PathPrefix   = "/ob/api"
+	GMPathPrefix = "/gm"
+)
+
+// AuthHeader is the request header holding the API key.
+const AuthHeader = "X-Starfighter-Authorization"
+
+// CacheHeader is the response header telling whether a response came from
+// the cache ("hit") or from the API ("miss").
+const CacheHeader = "X-Proxy-Cache"
+
+type cachedResponse struct {
+	statusCode  int
+	contentType string
+	body        []byte
+	expires     time.Time
+}
+
+// A Proxy is an http.Handler forwarding requests to the Stockfighter API.
+//
+// You can create a new Proxy using New function.
+type Proxy struct {
+	// API and GM API base URLs requests are forwarded to
+	BaseURL   string
+	GMBaseURL string
+
+	// Time-to-live of cached GET responses of the API (0 disables caching).
+	// GM API responses are not cached.
+	CacheTTL time.Duration
+
+	// Minimum interval between requests sent to the API (0 for no limit).
+	// Requests wait for their turn rather than fail.
+	Interval time.Duration
+
+	// HTTP client requests are forwarded with
+	HTTPClient *http.Client
+
+	apiKey string
+
+	mu    sync.Mutex
+	next  time.Time
+	cache map[string]cachedResponse
+}
+
+// New creates a new Proxy forwarding requests to the official API with the
+// given API key. This never returns nil.
+func New(apiKey string) *Proxy {
+	return &Proxy{
+		BaseURL:    stockfighter.DefaultBaseURL,
+		GMBaseURL:  stockfighter.DefaultGMBaseURL,
+		HTTPClient: http.DefaultClient,
+		apiKey:     apiKey,
+		cache:      make(map[string]cachedResponse),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var target string
+	cacheable := false
+	switch {
+	case strings.HasPrefix(r.URL.Path, PathPrefix+"/"):
+		target = p.BaseURL + strings.TrimPrefix(r.URL.Path, PathPrefix)
+		cacheable = r.Method == "GET" && p.CacheTTL > 0
+	case strings.HasPrefix(r.URL.Path, GMPathPrefix+"/"):
+		target = p.GMBaseURL + strings.TrimPrefix(r.URL.Path, GMPathPrefix)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+
+	if cacheable {
+		if resp, ok := p.cached(target); ok {
+			writeResponse(w, resp, "hit")
+			return
+		}
+	}
+
+	if err := p.wait(r); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, target, r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Header.Set(AuthHeader, p.apiKey)
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	httpResp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	resp := cachedResponse{statusCode: httpResp.StatusCode, contentType: httpResp.Header.Get("Content-Type"), body: body}
+	if cacheable && resp.statusCode == http.StatusOK {
+		p.store(target, resp)
+	}
+	writeResponse(w, resp, "miss")
+}
+
+// wait waits for the turn of a request to be sent to the API, or for the
+// request to be canceled.
+func (p *Proxy) wait(r *http.Request) error {
+	if p.Interval <= 0 {
+		return nil
+	}
+
+	p.mu.Lock()
+	now := time.Now()
+	at := p.next
+	if at.Before(now) {
+		at = now
+	}
+	p.next = at.Add(p.Interval)
+	p.mu.Unlock()
+
+	timer := time.NewTimer(at.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-r.Context().Done():
+		return r.Context().Err()
+	}
+}
+
+func (p *Proxy) cached(target string) (cachedResponse, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	resp, ok := p.cache[target]
+	if !ok || !time.Now().Before(resp.expires) {
+		return cachedResponse{}, false
+	}
+	return resp, true
+}
+
+func (p *Proxy) store(target string, resp cachedResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	// expired responses are dropped, so that the cache does not grow with
+	// every order ever looked up
+	for key, cached := range p.cache {
+		if !now.Before(cached.expires) {
+			delete(p.cache, key)
+		}
+	}
+	resp.expires = now.Add(p.CacheTTL)
+	p.cache[target] = resp
+}
+
+func writeResponse(w http.ResponseWriter, resp cachedResponse, cache string) {
+	if resp.contentType != "" {
+		w.Header().Set("Content-Type", resp.contentType)
+	}
+	w.Header().Set(CacheHeader, cache)
+	w.WriteHeader(resp.statusCode)
+	w.Write(resp.body)
+}
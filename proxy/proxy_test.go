@@ -0,0 +1,65 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func TestProxy(t *testing.T) {
+	var requests int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		assert.Equal(t, "KEY", r.Header.Get(AuthHeader))
+		switch r.URL.Path {
+		case "/ob/api/venues/TESTEX/stocks/FOOBAR/quote":
+			w.Write([]byte(`{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR", "bid": 5000}`))
+		case "/ob/api/venues/TESTEX/stocks/FOOBAR/orders":
+			w.Write([]byte(`{"ok": true, "id": 1, "open": true}`))
+		case "/gm/instances/1":
+			w.Write([]byte(`{"ok": true, "id": 1, "state": "open"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer api.Close()
+
+	p := New("KEY")
+	p.BaseURL, p.GMBaseURL = api.URL+"/ob/api", api.URL+"/gm"
+	p.CacheTTL = time.Hour
+	p.Interval = 10 * time.Millisecond
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	// the client has no API key: the proxy injects it
+	client := stockfighter.NewClient("", stockfighter.WithBaseURL(server.URL+PathPrefix), stockfighter.WithGMBaseURL(server.URL+GMPathPrefix))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		quote, err := client.GetQuote("TESTEX", "FOOBAR")
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(5000), quote.BidPrice)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// orders and GM requests are not cached, and are spaced out
+	for i := 0; i < 2; i++ {
+		_, err := client.PlaceOrder("TESTEX", "FOOBAR", "EXB123456", 5000, 10, stockfighter.OrderDirectionBuy, stockfighter.OrderTypeLimit)
+		assert.Nil(t, err)
+		status, err := client.GetLevelStatus(1)
+		assert.Nil(t, err)
+		assert.Equal(t, "open", status.State)
+	}
+	assert.Equal(t, int32(5), atomic.LoadInt32(&requests))
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+
+	resp, err := http.Get(server.URL + "/other")
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,92 @@
+package stockfighter
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithQuoteCache enables caching of quotes: GetQuote returns the last quote
+// of a stock without making an API request if it was fetched less than ttl
+// ago, e.g. when several components of a bot ask for the same quote.
+//
+// The cache is shared by the clients derived with Subsystem. Errors are not
+// cached.
+func WithQuoteCache(ttl time.Duration) ClientOption {
+	return func(client *Client) {
+		if client.quotes == nil {
+			client.quotes = newQuoteCache()
+		}
+		client.quotes.ttl = ttl
+	}
+}
+
+// WithQuoteTTL sets the time-to-live of cached quotes of a stock, overriding
+// the one set by WithQuoteCache for that stock, e.g. to cache the quotes of
+// a stock only traded occasionally for longer. Caching is enabled if it is
+// not already.
+func WithQuoteTTL(stock string, ttl time.Duration) ClientOption {
+	return func(client *Client) {
+		if client.quotes == nil {
+			client.quotes = newQuoteCache()
+		}
+		client.quotes.stockTTLs[strings.TrimSpace(stock)] = ttl
+	}
+}
+
+type cachedQuote struct {
+	quote     Quote
+	fetchedAt time.Time
+}
+
+// quoteCache caches quotes per venue and stock. A nil *quoteCache caches
+// nothing.
+type quoteCache struct {
+	ttl       time.Duration
+	stockTTLs map[string]time.Duration
+	now       func() time.Time
+
+	mu     sync.Mutex
+	quotes map[string]cachedQuote
+}
+
+func newQuoteCache() *quoteCache {
+	return &quoteCache{
+		stockTTLs: make(map[string]time.Duration),
+		now:       time.Now,
+		quotes:    make(map[string]cachedQuote),
+	}
+}
+
+func (cache *quoteCache) get(venue, stock string) (*Quote, bool) {
+	if cache == nil {
+		return nil, false
+	}
+
+	ttl, ok := cache.stockTTLs[stock]
+	if !ok {
+		ttl = cache.ttl
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cached, ok := cache.quotes[venue+"/"+stock]
+	if !ok || cache.now().Sub(cached.fetchedAt) >= ttl {
+		return nil, false
+	}
+	// callers may modify the quote they get
+	quote := cached.quote
+	return &quote, true
+}
+
+func (cache *quoteCache) put(venue, stock string, quote *Quote) {
+	if cache == nil {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.quotes[venue+"/"+stock] = cachedQuote{quote: *quote, fetchedAt: cache.now()}
+}
@@ -0,0 +1,52 @@
+package stockfighter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteCache(t *testing.T) {
+	var requests int
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"ok": true, "symbol": "%s", "venue": "%s", "bid": %d}`, testStock, testVenue, 5000+requests)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	client := NewClient(testApiKey, WithBaseURL(server.URL), WithQuoteCache(time.Second), WithQuoteTTL("SLOW", time.Minute))
+	client.quotes.now = func() time.Time { return ts }
+
+	quote, err := client.GetQuote(testVenue, testStock)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5001), quote.BidPrice)
+	quote.BidPrice = 0 // must not alter the cached quote
+
+	// served from the cache, including to derived clients
+	quote, err = client.Subsystem("other").GetQuote(testVenue, testStock)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5001), quote.BidPrice)
+	assert.Equal(t, 1, requests)
+
+	ts = ts.Add(time.Second)
+	quote, _ = client.GetQuote(testVenue, testStock)
+	assert.Equal(t, uint64(5002), quote.BidPrice)
+	assert.Equal(t, 2, requests)
+
+	client.GetQuote(testVenue, "SLOW")
+	ts = ts.Add(30 * time.Second)
+	client.GetQuote(testVenue, "SLOW")
+	assert.Equal(t, 3, requests)
+
+	// no caching by default
+	client = NewClient(testApiKey, WithBaseURL(server.URL))
+	client.GetQuote(testVenue, testStock)
+	client.GetQuote(testVenue, testStock)
+	assert.Equal(t, 5, requests)
+}
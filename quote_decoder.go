@@ -0,0 +1,454 @@
+package stockfighter
+
+import (
+	"bytes"
+	"errors"
+	"time"
+)
+
+// maxInternedStrings bounds the number of venue and stock symbols a
+// QuoteDecoder keeps, in case a stream sends arbitrary symbols.
+const maxInternedStrings = 1024
+
+// maxSkipDepth bounds the nesting of the unknown values a QuoteDecoder skips,
+// well below the limit of encoding/json.
+const maxSkipDepth = 100
+
+// errSlowPath makes a QuoteDecoder fall back to encoding/json.
+var errSlowPath = errors.New("slow path")
+
+// quoteKeys are the keys of the fields of a tickertape frame and its quote.
+var quoteKeys = []string{
+	"ok", "quote", "venue", "symbol", "bid", "bidSize", "bidDepth", "ask", "askSize", "askDepth",
+	"last", "lastSize", "lastTrade", "quoteTime",
+}
+
+// A QuoteDecoder decodes tickertape frames like DecodeTickertapeFrame, but
+// without allocating: it decodes into a Quote owned by the caller, parses
+// numbers and timestamps by hand, and reuses the strings of the symbols it
+// has already seen. It is meant for quote streams at high message rates.
+//
+//    decoder := stockfighter.NewQuoteDecoder()
+//    var quote stockfighter.Quote
+//    for frame := range frames {
+//        if err := decoder.Decode(frame, &quote); err != nil {
+//            // ...
+//        }
+//        // ...
+//    }
+//
+// Frames the decoder does not handle by itself, e.g. with escaped or non-ASCII
+// strings, or timestamps not in UTC, are decoded with encoding/json instead,
+// so that the result is always the same as DecodeTickertapeFrame.
+//
+// A QuoteDecoder is not safe for concurrent use. You can create a new
+// QuoteDecoder using NewQuoteDecoder function.
+type QuoteDecoder struct {
+	strings map[string]string
+}
+
+// NewQuoteDecoder creates a new QuoteDecoder. This never returns nil.
+func NewQuoteDecoder() *QuoteDecoder {
+	return &QuoteDecoder{strings: make(map[string]string)}
+}
+
+// Decode decodes a frame of a tickertape stream into quote.
+func (d *QuoteDecoder) Decode(data []byte, quote *Quote) error {
+	if err := d.decodeFrame(data, quote); err != nil {
+		decoded, err := decodeTickertapeFrame(JSONCodec{}, data)
+		if err != nil {
+			return err
+		}
+		*quote = *decoded
+	}
+	return nil
+}
+
+// decodeFrame decodes a frame on the fast path, failing with errSlowPath on
+// anything unusual.
+func (d *QuoteDecoder) decodeFrame(data []byte, quote *Quote) error {
+	s := quoteScanner{data: data}
+	ok := false
+	*quote = Quote{}
+
+	err := s.object(func(key []byte) error {
+		switch string(key) {
+		case "ok":
+			ok = true
+			return s.bool()
+		case "quote":
+			// like Quote.UnmarshalJSON, a repeated quote replaces the
+			// previous one
+			*quote = Quote{}
+			return s.object(func(key []byte) error {
+				return d.decodeField(&s, key, quote)
+			})
+		}
+		return s.skipUnknown(key)
+	})
+	if err != nil || !ok {
+		return errSlowPath
+	}
+	if s.ws(); s.i != len(s.data) {
+		return errSlowPath
+	}
+	return nil
+}
+
+func (d *QuoteDecoder) decodeField(s *quoteScanner, key []byte, quote *Quote) error {
+	if s.null() {
+		// null leaves fields unchanged, but for the bid and ask prices,
+		// which it unsets
+		switch string(key) {
+		case "bid":
+			quote.HasBid, quote.BidPrice = false, 0
+		case "ask":
+			quote.HasAsk, quote.AskPrice = false, 0
+		}
+		return nil
+	}
+
+	var err error
+	switch string(key) {
+	case "venue":
+		quote.Venue, err = d.string(s)
+	case "symbol":
+		quote.Symbol, err = d.string(s)
+	case "bid":
+		quote.HasBid = true
+		quote.BidPrice, err = s.uint()
+	case "bidSize":
+		quote.BidSize, err = s.uint()
+	case "bidDepth":
+		quote.BidDepth, err = s.uint()
+	case "ask":
+		quote.HasAsk = true
+		quote.AskPrice, err = s.uint()
+	case "askSize":
+		quote.AskSize, err = s.uint()
+	case "askDepth":
+		quote.AskDepth, err = s.uint()
+	case "last":
+		quote.LastPrice, err = s.uint()
+	case "lastSize":
+		quote.LastSize, err = s.uint()
+	case "lastTrade":
+		quote.LastTradeTime, err = s.time()
+	case "quoteTime":
+		quote.QuoteTime, err = s.time()
+	default:
+		err = s.skipUnknown(key)
+	}
+	return err
+}
+
+// string scans a string and returns it, reusing the string of a previous
+// decode if any.
+func (d *QuoteDecoder) string(s *quoteScanner) (string, error) {
+	b, err := s.str()
+	if err != nil {
+		return "", err
+	}
+	if v, ok := d.strings[string(b)]; ok {
+		return v, nil
+	}
+
+	v := string(b)
+	if len(d.strings) < maxInternedStrings {
+		d.strings[v] = v
+	}
+	return v, nil
+}
+
+// quoteScanner scans the JSON values of a frame.
+type quoteScanner struct {
+	data []byte
+	i    int
+}
+
+// ws skips whitespace.
+func (s *quoteScanner) ws() {
+	for s.i < len(s.data) {
+		switch s.data[s.i] {
+		case ' ', '\t', '\n', '\r':
+			s.i++
+		default:
+			return
+		}
+	}
+}
+
+// expect skips whitespace and the given byte.
+func (s *quoteScanner) expect(c byte) error {
+	s.ws()
+	if s.i >= len(s.data) || s.data[s.i] != c {
+		return errSlowPath
+	}
+	s.i++
+	return nil
+}
+
+// peek skips whitespace and returns the next byte, or 0 at the end.
+func (s *quoteScanner) peek() byte {
+	s.ws()
+	if s.i >= len(s.data) {
+		return 0
+	}
+	return s.data[s.i]
+}
+
+// object scans an object, calling field with each key, positioned on its
+// value, which field must scan.
+func (s *quoteScanner) object(field func(key []byte) error) error {
+	if err := s.expect('{'); err != nil {
+		return err
+	}
+	if s.peek() == '}' {
+		s.i++
+		return nil
+	}
+
+	for {
+		key, err := s.str()
+		if err != nil {
+			return err
+		}
+		if err := s.expect(':'); err != nil {
+			return err
+		}
+		if err := field(key); err != nil {
+			return err
+		}
+
+		switch s.peek() {
+		case ',':
+			s.i++
+		case '}':
+			s.i++
+			return nil
+		default:
+			return errSlowPath
+		}
+	}
+}
+
+// str scans a string without escapes, in ASCII so that it needs no UTF-8
+// validation, and returns its content.
+func (s *quoteScanner) str() ([]byte, error) {
+	if err := s.expect('"'); err != nil {
+		return nil, err
+	}
+	start := s.i
+	for ; s.i < len(s.data); s.i++ {
+		switch c := s.data[s.i]; {
+		case c == '"':
+			s.i++
+			return s.data[start : s.i-1], nil
+		case c == '\\' || c < 0x20 || c >= 0x80:
+			return nil, errSlowPath
+		}
+	}
+	return nil, errSlowPath
+}
+
+// bool scans true or false.
+func (s *quoteScanner) bool() error {
+	switch c := s.peek(); {
+	case c == 't' && bytes.HasPrefix(s.data[s.i:], []byte("true")):
+		s.i += len("true")
+	case c == 'f' && bytes.HasPrefix(s.data[s.i:], []byte("false")):
+		s.i += len("false")
+	default:
+		return errSlowPath
+	}
+	return nil
+}
+
+// null scans null if it is next.
+func (s *quoteScanner) null() bool {
+	if s.peek() == 'n' && bytes.HasPrefix(s.data[s.i:], []byte("null")) {
+		s.i += len("null")
+		return true
+	}
+	return false
+}
+
+// uint scans an unsigned integer.
+func (s *quoteScanner) uint() (uint64, error) {
+	s.ws()
+	start := s.i
+	var n uint64
+	for ; s.i < len(s.data) && s.data[s.i] >= '0' && s.data[s.i] <= '9'; s.i++ {
+		d := uint64(s.data[s.i] - '0')
+		if n > (1<<64-1-d)/10 {
+			return 0, errSlowPath
+		}
+		n = n*10 + d
+	}
+	if s.i == start || (s.i-start > 1 && s.data[start] == '0') {
+		return 0, errSlowPath
+	}
+	if s.i < len(s.data) {
+		// fractions and exponents
+		switch s.data[s.i] {
+		case '.', 'e', 'E':
+			return 0, errSlowPath
+		}
+	}
+	return n, nil
+}
+
+// time scans a RFC 3339 timestamp in UTC, e.g. 2015-12-04T09:02:16.680986205Z.
+func (s *quoteScanner) time() (time.Time, error) {
+	b, err := s.str()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(b) < len("2006-01-02T15:04:05Z") || b[4] != '-' || b[7] != '-' || b[10] != 'T' || b[13] != ':' || b[16] != ':' || b[len(b)-1] != 'Z' {
+		return time.Time{}, errSlowPath
+	}
+
+	year, ok1 := digits(b[0:4])
+	month, ok2 := digits(b[5:7])
+	day, ok3 := digits(b[8:10])
+	hour, ok4 := digits(b[11:13])
+	min, ok5 := digits(b[14:16])
+	sec, ok6 := digits(b[17:19])
+	if !(ok1 && ok2 && ok3 && ok4 && ok5 && ok6) || month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || min > 59 || sec > 59 {
+		return time.Time{}, errSlowPath
+	}
+
+	nsec := 0
+	if frac := b[19 : len(b)-1]; len(frac) > 0 {
+		if frac[0] != '.' || len(frac) < 2 || len(frac) > 10 {
+			return time.Time{}, errSlowPath
+		}
+		n, ok := digits(frac[1:])
+		if !ok {
+			return time.Time{}, errSlowPath
+		}
+		for i := len(frac) - 1; i < 9; i++ {
+			n *= 10
+		}
+		nsec = n
+	} else if len(b) != len("2006-01-02T15:04:05Z") {
+		return time.Time{}, errSlowPath
+	}
+
+	t := time.Date(year, time.Month(month), day, hour, min, sec, nsec, time.UTC)
+	if t.Day() != day {
+		// out of range for the month, e.g. February 30
+		return time.Time{}, errSlowPath
+	}
+	return t, nil
+}
+
+// digits parses decimal digits.
+func digits(b []byte) (int, bool) {
+	n := 0
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, true
+}
+
+// skipUnknown scans the value of an unknown key. encoding/json matches keys
+// case-insensitively, so keys matching a known key that way take the slow
+// path.
+func (s *quoteScanner) skipUnknown(key []byte) error {
+	for _, known := range quoteKeys {
+		if bytes.EqualFold(key, []byte(known)) {
+			return errSlowPath
+		}
+	}
+	return s.skip(0)
+}
+
+// skip scans any value, nested in depth arrays and objects.
+func (s *quoteScanner) skip(depth int) error {
+	if depth > maxSkipDepth {
+		return errSlowPath
+	}
+
+	switch c := s.peek(); {
+	case c == '"':
+		_, err := s.str()
+		return err
+	case c == '{':
+		return s.object(func([]byte) error { return s.skip(depth + 1) })
+	case c == '[':
+		s.i++
+		if s.peek() == ']' {
+			s.i++
+			return nil
+		}
+		for {
+			if err := s.skip(depth + 1); err != nil {
+				return err
+			}
+			switch s.peek() {
+			case ',':
+				s.i++
+			case ']':
+				s.i++
+				return nil
+			default:
+				return errSlowPath
+			}
+		}
+	case c == 't' || c == 'f' || c == 'n':
+		for _, lit := range []string{"true", "false", "null"} {
+			if bytes.HasPrefix(s.data[s.i:], []byte(lit)) {
+				s.i += len(lit)
+				return nil
+			}
+		}
+		return errSlowPath
+	case c == '-' || (c >= '0' && c <= '9'):
+		return s.number()
+	}
+	return errSlowPath
+}
+
+// number scans any number, following the JSON grammar:
+// -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?
+func (s *quoteScanner) number() error {
+	if s.i < len(s.data) && s.data[s.i] == '-' {
+		s.i++
+	}
+	switch {
+	case s.i < len(s.data) && s.data[s.i] == '0':
+		s.i++
+	case s.skipDigits() == 0:
+		return errSlowPath
+	}
+
+	if s.i < len(s.data) && s.data[s.i] == '.' {
+		s.i++
+		if s.skipDigits() == 0 {
+			return errSlowPath
+		}
+	}
+	if s.i < len(s.data) && (s.data[s.i] == 'e' || s.data[s.i] == 'E') {
+		s.i++
+		if s.i < len(s.data) && (s.data[s.i] == '+' || s.data[s.i] == '-') {
+			s.i++
+		}
+		if s.skipDigits() == 0 {
+			return errSlowPath
+		}
+	}
+	return nil
+}
+
+// skipDigits scans decimal digits, and returns how many.
+func (s *quoteScanner) skipDigits() int {
+	start := s.i
+	for s.i < len(s.data) && s.data[s.i] >= '0' && s.data[s.i] <= '9' {
+		s.i++
+	}
+	return s.i - start
+}
@@ -0,0 +1,125 @@
+package stockfighter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTickertapeFrame = `{"ok":true,"quote":{"symbol":"FOOBAR","venue":"TESTEX","bid":5100,"ask":5150,"bidSize":392,"askSize":1085,` +
+	`"bidDepth":2748,"askDepth":2237,"last":5125,"lastSize":52,"lastTrade":"2015-07-13T05:38:17.33640392Z","quoteTime":"2015-07-13T05:38:17.33640392Z"}}`
+
+func TestQuoteDecoder(t *testing.T) {
+	decoder := NewQuoteDecoder()
+
+	for _, frame := range []string{
+		testTickertapeFrame,
+		`{"ok": true, "quote": {"symbol": "FOOBAR", "venue": "TESTEX", "bidDepth": 0, "askDepth": 0, "last": 5125, "quoteTime": "2015-07-13T05:38:17Z"}}`,
+		`{"ok": true, "quote": {"symbol": "FOOBAR", "bid": null, "ask": 0, "extra": [1, {"a": "b"}, -2.5e3, true, null]}, "extra": {}}`,
+		`{"ok": false, "error": "stream closed"}`,
+		`{"quote": {"symbol": "FOOBAR"}, "ok": true}`,
+		`{"ok": true, "quote": {"symbol": "FOOBAR", "bid": 5100, "bid": null, "bidSize": 10, "bidSize": null}}`,
+		`{"ok": true, "quote": {"symbol": "FOOBAR", "bid": 5100}, "quote": {"ask": 5150}}`,
+		`{"ok": true, "extra": [-0, 0.5, 1E+2, -1e-2, 10]}`,
+
+		// slow path
+		`{"ok": true, "quote": {"symbol": "FOO\u0042AR"}}`,
+		`{"ok": true, "quote": {"symbol": "FOOBAR", "quoteTime": "2015-07-13T07:38:17.336+02:00"}}`,
+		`{"ok": true, "quote": {"symbol": "FOOBAR", "Bid": 5100}}`,
+		`{"ok": true, "Quote": {"symbol": "FOOBAR"}}`,
+		"{\"ok\": true, \"quote\": {\"symbol\": \"FOO\xffBAR\"}}",
+		`{"ok": true, "quote": {"symbol": "FOOBÄR"}}`,
+		`{"ok": true, "quote": {"symbol": "FOOBAR", "bid": 18446744073709551615}}`,
+	} {
+		want, err := DecodeTickertapeFrame([]byte(frame))
+		assert.Nil(t, err, frame)
+
+		var quote Quote
+		assert.Nil(t, decoder.Decode([]byte(frame), &quote), frame)
+		assert.Equal(t, *want, quote, frame)
+	}
+
+	for _, frame := range []string{
+		`{"quote": {"symbol": "FOOBAR"}}`,
+		`{"ok": "yes", "quote": {"symbol": "FOOBAR"}}`,
+		`{"ok": true, "quote": {"bid": -1}}`,
+		`{"ok": true, "quote": {"bid": 1.5}}`,
+		`{"ok": true, "quote": {"bid": 18446744073709551616}}`,
+		`{"ok": true, "quote": {"quoteTime": "2015-02-30T05:38:17Z"}}`,
+		`{"ok": true, "quote": {"symbol": "FOOBAR"}`,
+		`{"ok": true} {}`,
+		`{"ok": true, "extra": 1-2}`,
+		`{"ok": true, "extra": 01}`,
+		`{"ok": true, "extra": 1.}`,
+		`{"ok": true, "extra": .5}`,
+		`{"ok": true, "extra": 1e}`,
+		`{"ok": true, "extra": -}`,
+	} {
+		_, want := DecodeTickertapeFrame([]byte(frame))
+		assert.NotNil(t, want, frame)
+
+		var quote Quote
+		assert.Equal(t, want, decoder.Decode([]byte(frame), &quote), frame)
+	}
+}
+
+func FuzzQuoteDecoder(f *testing.F) {
+	f.Add([]byte(testTickertapeFrame))
+	f.Add([]byte(`{"ok": true, "quote": {"symbol": "FOOBAR", "bid": null, "ask": 0, "extra": [1, {"a": "b"}, -2.5e3, true, null]}}`))
+	f.Add([]byte(`{"ok": true, "quote": {"symbol": "FOOBAR", "quoteTime": "2015-07-13T07:38:17.336+02:00"}}`))
+	f.Add([]byte(`{"ok": true, "quote": {"bid": 5100, "bid": null}, "quote": {"ask": 5150}}`))
+	f.Add([]byte(`{"ok": true, "extra": 1-2}`))
+
+	decoder := NewQuoteDecoder()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		want, wantErr := DecodeTickertapeFrame(data)
+
+		var quote Quote
+		err := decoder.Decode(data, &quote)
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("%q: error %v, DecodeTickertapeFrame error %v", data, err, wantErr)
+		}
+		if err == nil && quote != *want {
+			t.Fatalf("%q: decoded %+v, DecodeTickertapeFrame decoded %+v", data, quote, *want)
+		}
+	})
+}
+
+func TestQuoteDecoderAllocs(t *testing.T) {
+	decoder := NewQuoteDecoder()
+	data := []byte(testTickertapeFrame)
+	var quote Quote
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := decoder.Decode(data, &quote); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func BenchmarkQuoteDecoder(b *testing.B) {
+	decoder := NewQuoteDecoder()
+	data := []byte(testTickertapeFrame)
+	var quote Quote
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		if err := decoder.Decode(data, &quote); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeTickertapeFrame(b *testing.B) {
+	data := []byte(testTickertapeFrame)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeTickertapeFrame(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Quoter reference prices.
+const (
+	ReferenceMid        = "mid"
+	ReferenceMicroprice = "microprice"
+)
+
+// A Quoter maintains a two-sided quote (a resting buy and sell limit order) for
+// a stock around a reference price derived from the market quote.
+//
+// Each side is priced at Spread/2 from the reference price, and both sides are
+// shifted by InventorySkew cents per share of the current position, so that a
+// long position lowers the quote (and a short one raises it). A resting order
+// is replaced when its desired price moves by RequoteThreshold cents or more,
+// or when it is closed by a fill.
+//
+// You can create a new Quoter using NewQuoter function.
+type Quoter struct {
+	client  *Client
+	venue   string
+	stock   string
+	account string
+
+	// Reference price (ReferenceMid or ReferenceMicroprice)
+	Reference string
+
+	// Quoted spread (in cents) and size of each side
+	Spread uint64
+	Size   uint64
+
+	// Quote shift per share of position (in cents)
+	InventorySkew float64
+
+	// Minimal price change (in cents) that triggers a requote
+	RequoteThreshold uint64
+
+	// Absolute position beyond which the side growing it is not quoted (0
+	// means no limit)
+	MaxPosition int64
+
+	// Market polling interval (DefaultPollInterval if not positive)
+	Interval time.Duration
+
+	// Optional controller adjusting Spread from the quoter's fills
+	SpreadController *SpreadController
+
+	mu        sync.Mutex
+	bid       *Order
+	ask       *Order
+	seenFills map[int64]int
+	position  int64
+	cash      int64
+}
+
+// NewQuoter creates a new Quoter quoting size shares on each side with the
+// given spread around the mid price. This never returns nil.
+func NewQuoter(client *Client, venue, stock, account string, spread, size uint64) *Quoter {
+	venue = strings.TrimSpace(venue)
+	if !validSymbol(venue) {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+
+	stock = strings.TrimSpace(stock)
+	if !validSymbol(stock) {
+		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
+	}
+
+	account = strings.TrimSpace(account)
+	if !validSymbol(account) {
+		panic(fmt.Errorf("Invalid account name: %v", account))
+	}
+
+	return &Quoter{
+		client:           client,
+		venue:            venue,
+		stock:            stock,
+		account:          account,
+		Reference:        ReferenceMid,
+		Spread:           spread,
+		Size:             size,
+		RequoteThreshold: 1,
+		Interval:         DefaultPollInterval,
+		seenFills:        make(map[int64]int),
+	}
+}
+
+// Position returns the position (in shares) and cash (in cents) accumulated by
+// the quoter's fills.
+func (quoter *Quoter) Position() (position, cash int64) {
+	quoter.mu.Lock()
+	defer quoter.mu.Unlock()
+
+	return quoter.position, quoter.cash
+}
+
+// Run maintains the quote until ctx is done or an API call fails. Resting
+// orders are canceled before Run returns.
+func (quoter *Quoter) Run(ctx context.Context) error {
+	defer quoter.cancelAll()
+
+	interval := quoter.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := quoter.requote(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// requote refreshes the resting orders and replaces them as needed.
+func (quoter *Quoter) requote() error {
+	for _, side := range []**Order{&quoter.bid, &quoter.ask} {
+		if *side == nil {
+			continue
+		}
+
+		status, err := quoter.client.GetOrder(quoter.venue, quoter.stock, (*side).OrderID)
+		if err != nil {
+			return err
+		}
+		quoter.update(side, status)
+	}
+
+	quote, err := quoter.client.GetQuote(quoter.venue, quoter.stock)
+	if err != nil {
+		return err
+	}
+
+	ref, ok := quoter.referencePrice(quote)
+	if !ok {
+		// one-sided market; keep whatever is resting
+		return nil
+	}
+
+	if quoter.SpreadController != nil {
+		quoter.Spread = quoter.SpreadController.Adjust(quoter.Spread, time.Now())
+	}
+
+	position, _ := quoter.Position()
+	bidPrice, askPrice := quoter.prices(ref, position)
+
+	if err := quoter.replace(&quoter.bid, OrderDirectionBuy, bidPrice,
+		quoter.MaxPosition == 0 || position < quoter.MaxPosition); err != nil {
+		return err
+	}
+	return quoter.replace(&quoter.ask, OrderDirectionSell, askPrice,
+		quoter.MaxPosition == 0 || position > -quoter.MaxPosition)
+}
+
+// replace cancels the order of a side if it is away from price and places a
+// new order at price if the side should be quoted.
+func (quoter *Quoter) replace(side **Order, direction string, price int64, quote bool) error {
+	quote = quote && price > 0
+	if *side != nil {
+		if quote && absDiff(int64((*side).Price), price) < int64(quoter.RequoteThreshold) {
+			return nil
+		}
+
+		status, err := quoter.client.CancelOrder(quoter.venue, quoter.stock, (*side).OrderID)
+		if err != nil {
+			return err
+		}
+		quoter.update(side, status)
+	}
+
+	if !quote {
+		return nil
+	}
+
+	order, err := quoter.client.PlaceOrder(quoter.venue, quoter.stock, quoter.account,
+		uint64(price), quoter.Size, direction, OrderTypeLimit)
+	if err != nil {
+		return err
+	}
+	quoter.update(side, order)
+
+	return nil
+}
+
+// update accounts new fills of an order and keeps it as the side's resting
+// order if it is still open.
+func (quoter *Quoter) update(side **Order, status *Order) {
+	quoter.mu.Lock()
+	defer quoter.mu.Unlock()
+
+	seen := quoter.seenFills[status.OrderID]
+	if seen > len(status.Fills) {
+		seen = len(status.Fills)
+	}
+
+	for _, fill := range status.Fills[seen:] {
+		if quoter.SpreadController != nil {
+			quoter.SpreadController.Observe(fill.Quantity, time.Now())
+		}
+
+		if status.Direction == OrderDirectionBuy {
+			quoter.position += int64(fill.Quantity)
+			quoter.cash -= int64(fill.Price * fill.Quantity)
+		} else {
+			quoter.position -= int64(fill.Quantity)
+			quoter.cash += int64(fill.Price * fill.Quantity)
+		}
+	}
+
+	if status.Open {
+		quoter.seenFills[status.OrderID] = len(status.Fills)
+		*side = status
+	} else {
+		delete(quoter.seenFills, status.OrderID)
+		*side = nil
+	}
+}
+
+func (quoter *Quoter) cancelAll() {
+	for _, side := range []**Order{&quoter.bid, &quoter.ask} {
+		if *side == nil {
+			continue
+		}
+
+		if status, err := quoter.client.CancelOrder(quoter.venue, quoter.stock, (*side).OrderID); err == nil {
+			quoter.update(side, status)
+		}
+	}
+}
+
+// referencePrice returns the reference price of a quote, if both sides of the
+// market are quoted.
+func (quoter *Quoter) referencePrice(quote *Quote) (float64, bool) {
+	if !quote.HasBid || !quote.HasAsk {
+		return 0, false
+	}
+
+	if quoter.Reference == ReferenceMicroprice && quote.BidSize+quote.AskSize > 0 {
+		return (float64(quote.BidPrice)*float64(quote.AskSize) + float64(quote.AskPrice)*float64(quote.BidSize)) /
+			float64(quote.BidSize+quote.AskSize), true
+	}
+
+	return float64(quote.BidPrice+quote.AskPrice) / 2, true
+}
+
+// prices returns the bid and ask prices to quote around ref for a position.
+func (quoter *Quoter) prices(ref float64, position int64) (bid, ask int64) {
+	center := ref - quoter.InventorySkew*float64(position)
+	bid = int64(math.Floor(center - float64(quoter.Spread)/2))
+	return bid, bid + int64(quoter.Spread)
+}
+
+func absDiff(a, b int64) int64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
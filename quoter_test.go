@@ -0,0 +1,70 @@
+package stockfighter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoterPrices(t *testing.T) {
+	quoter := NewQuoter(NewClient(testApiKey), testVenue, testStock, testAccount, 10, 100)
+
+	ref, ok := quoter.referencePrice(&Quote{HasBid: true, BidPrice: 100, BidSize: 30, HasAsk: true, AskPrice: 110, AskSize: 10})
+	assert.True(t, ok)
+	assert.Equal(t, 105.0, ref)
+
+	quoter.Reference = ReferenceMicroprice
+	ref, ok = quoter.referencePrice(&Quote{HasBid: true, BidPrice: 100, BidSize: 30, HasAsk: true, AskPrice: 110, AskSize: 10})
+	assert.True(t, ok)
+	assert.Equal(t, 107.5, ref)
+
+	_, ok = quoter.referencePrice(&Quote{HasAsk: true, AskPrice: 110, AskSize: 10})
+	assert.False(t, ok)
+
+	bid, ask := quoter.prices(107.5, 0)
+	assert.Equal(t, int64(102), bid)
+	assert.Equal(t, int64(112), ask)
+
+	quoter.InventorySkew = 0.05
+	bid, ask = quoter.prices(107.5, 100)
+	assert.Equal(t, int64(97), bid)
+	assert.Equal(t, int64(107), ask)
+}
+
+func TestQuoterFillAccounting(t *testing.T) {
+	quoter := NewQuoter(NewClient(testApiKey), testVenue, testStock, testAccount, 10, 100)
+
+	bid := &Order{OrderID: 1, Direction: OrderDirectionBuy, Open: true, Fills: []OrderFillInfo{{Price: 100, Quantity: 10}}}
+	quoter.update(&quoter.bid, bid)
+	assert.Equal(t, bid, quoter.bid)
+
+	// the same fill is not accounted twice
+	quoter.update(&quoter.bid, bid)
+	position, cash := quoter.Position()
+	assert.Equal(t, int64(10), position)
+	assert.Equal(t, int64(-1000), cash)
+
+	closed := &Order{OrderID: 1, Direction: OrderDirectionBuy, Fills: append(bid.Fills, OrderFillInfo{Price: 100, Quantity: 90})}
+	quoter.update(&quoter.bid, closed)
+	assert.Nil(t, quoter.bid)
+
+	ask := &Order{OrderID: 2, Direction: OrderDirectionSell, Fills: []OrderFillInfo{{Price: 110, Quantity: 40}}}
+	quoter.update(&quoter.ask, ask)
+	position, cash = quoter.Position()
+	assert.Equal(t, int64(60), position)
+	assert.Equal(t, int64(-10000+4400), cash)
+}
+
+func TestQuoterRunDefaultInterval(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true, "bid": 100, "bidSize": 10}`))
+	})
+	quoter := NewQuoter(client, testVenue, testStock, testAccount, 10, 100)
+	quoter.Interval = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, quoter.Run(ctx))
+}
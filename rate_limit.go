@@ -0,0 +1,101 @@
+package stockfighter
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the client to n API requests per period: requests are
+// spaced by period / n at least, waiting for their turn unless the context of
+// the client (see Client.WithContext) is done first. A request giving up its
+// turn this way frees it, unless requests have been queued after it. The
+// limit is shared by the clients derived with Subsystem and WithContext. This
+// panics if n or period is not positive.
+func WithRateLimit(n int, period time.Duration) ClientOption {
+	if n <= 0 || period <= 0 {
+		panic(fmt.Errorf("Invalid rate limit: %v per %v", n, period))
+	}
+
+	return func(client *Client) {
+		limiter := &rateLimiter{interval: period / time.Duration(n)}
+		client.transport.limiter = limiter
+		WithMiddleware(limiter.middleware)(client)
+	}
+}
+
+// rateLimitState represents the state of a rate limiter, as published by
+// Client.PublishExpvar.
+type rateLimitState struct {
+	// Minimum interval between requests
+	Interval time.Duration
+
+	// Number of requests waiting for their turn
+	Waiting int
+
+	// Number of requests delayed so far, and their total delay
+	Delayed    uint64
+	TotalDelay time.Duration
+}
+
+// rateLimiter spaces requests by interval at least.
+type rateLimiter struct {
+	interval time.Duration
+
+	mu    sync.Mutex
+	next  time.Time
+	state rateLimitState
+}
+
+func (limiter *rateLimiter) middleware(rt RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		// reserve the next slot
+		limiter.mu.Lock()
+		now := time.Now()
+		if limiter.next.Before(now) {
+			limiter.next = now
+		}
+		slot := limiter.next
+		limiter.next = limiter.next.Add(limiter.interval)
+		wait := slot.Sub(now)
+		if wait > 0 {
+			limiter.state.Waiting++
+			limiter.state.Delayed++
+			limiter.state.TotalDelay += wait
+		}
+		limiter.mu.Unlock()
+
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			var err error
+			select {
+			case <-timer.C:
+			case <-req.Context().Done():
+				timer.Stop()
+				err = req.Context().Err()
+			}
+
+			limiter.mu.Lock()
+			limiter.state.Waiting--
+			if err != nil && limiter.next.Equal(slot.Add(limiter.interval)) {
+				// free the slot, since no request was queued after it
+				limiter.next = slot
+			}
+			limiter.mu.Unlock()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return rt(req)
+	}
+}
+
+func (limiter *rateLimiter) getState() rateLimitState {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	state := limiter.state
+	state.Interval = limiter.interval
+	return state
+}
@@ -0,0 +1,50 @@
+package stockfighter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true}`))
+	})
+	client := NewClient(testApiKey, WithBaseURL(server.transport.baseURL), WithRateLimit(2, 40*time.Millisecond))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, client.Ping())
+	}
+	assert.True(t, time.Since(start) >= 40*time.Millisecond, "requests not spaced")
+
+	state := client.transport.limiter.getState()
+	assert.Equal(t, 20*time.Millisecond, state.Interval)
+	assert.Equal(t, 0, state.Waiting)
+	assert.True(t, state.Delayed > 0 && state.TotalDelay > 0)
+
+	assert.Panics(t, func() { WithRateLimit(0, time.Second) })
+}
+
+func TestRateLimitCanceled(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true}`))
+	})
+	client := NewClient(testApiKey, WithBaseURL(server.transport.baseURL), WithRateLimit(1, time.Hour))
+	limiter := client.transport.limiter
+
+	assert.Nil(t, client.Ping())
+	next := limiter.next
+
+	// a canceled request frees its turn
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := client.WithContext(ctx).Ping()
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, next, limiter.next)
+	assert.Equal(t, 0, limiter.getState().Waiting)
+}
@@ -0,0 +1,196 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of reconciliation actions.
+const (
+	// An open order of the venue is unknown locally: it should be tracked
+	ReconcileAdopt = "adopt"
+
+	// An open order of the venue unknown locally was canceled
+	ReconcileCancel = "cancel"
+
+	// An order open locally is closed or filled further on the venue, or
+	// could not be checked: the local state is stale
+	ReconcileAlert = "alert"
+)
+
+// defaultReconcileInterval is the default interval of a Reconciler.
+const defaultReconcileInterval = 10 * DefaultPollInterval
+
+// A ReconcileAction represents a difference between the local view of the
+// orders of an account and the venue, found by a Reconciler.
+type ReconcileAction struct {
+	Kind string
+
+	// Status of the order on the venue, or local status if it could not be
+	// fetched
+	Order Order
+
+	// Why the action is needed
+	Reason string
+
+	// Error checking or canceling the order, if any
+	Err error
+}
+
+func (action ReconcileAction) String() string {
+	s := fmt.Sprintf("%v order %v: %v", action.Kind, action.Order.OrderID, action.Reason)
+	if action.Err != nil {
+		s += fmt.Sprintf(" (%v)", action.Err)
+	}
+	return s
+}
+
+// A Reconciler periodically compares the local view of the open orders of an
+// account, e.g. strategy.Session.OpenOrders, with the orders of the venue,
+// and finds orphaned venue orders (open orders unknown locally), which are
+// adopted or canceled, and stale local orders (closed or filled further on
+// the venue), which raise alerts.
+//
+// You can create a new Reconciler using NewReconciler function.
+type Reconciler struct {
+	client  *Client
+	venue   string
+	account string
+	local   func() []Order
+
+	// Reconciliation interval (10 times DefaultPollInterval if not positive)
+	Interval time.Duration
+
+	// Whether orphaned venue orders are canceled instead of adopted
+	CancelOrphans bool
+}
+
+// NewReconciler creates a new Reconciler for an account, local returning the
+// orders of the account believed open. This never returns nil.
+func NewReconciler(client *Client, venue, account string, local func() []Order) *Reconciler {
+	venue = strings.TrimSpace(venue)
+	if !validSymbol(venue) {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+
+	account = strings.TrimSpace(account)
+	if !validSymbol(account) {
+		panic(fmt.Errorf("Invalid account name: %v", account))
+	}
+
+	return &Reconciler{
+		client:   client,
+		venue:    venue,
+		account:  account,
+		local:    local,
+		Interval: defaultReconcileInterval,
+	}
+}
+
+// Reconcile compares the local and venue orders once, cancels orphaned orders
+// if CancelOrphans is set, and returns the actions, ordered by order ID. It
+// fails if the orders of the venue cannot be fetched.
+func (reconciler *Reconciler) Reconcile() ([]ReconcileAction, error) {
+	orders, err := reconciler.client.GetAllOrders(reconciler.venue, reconciler.account)
+	if err != nil {
+		return nil, err
+	}
+
+	remote := make(map[int64]Order, len(orders))
+	for _, order := range orders {
+		remote[order.OrderID] = order
+	}
+	local := make(map[int64]Order)
+	for _, order := range reconciler.local() {
+		local[order.OrderID] = order
+	}
+
+	var actions []ReconcileAction
+	for id, order := range remote {
+		if _, ok := local[id]; ok || !order.Open {
+			continue
+		}
+
+		if !reconciler.CancelOrphans {
+			actions = append(actions, ReconcileAction{Kind: ReconcileAdopt, Order: order, Reason: "open on the venue, unknown locally"})
+			continue
+		}
+		action := ReconcileAction{Kind: ReconcileCancel, Order: order, Reason: "open on the venue, unknown locally"}
+		if canceled, err := reconciler.client.CancelOrder(reconciler.venue, order.Symbol, id); err != nil {
+			action.Err = err
+		} else {
+			action.Order = *canceled
+		}
+		actions = append(actions, action)
+	}
+
+	for id, order := range local {
+		status, ok := remote[id]
+		if !ok {
+			// not listed (yet): ask for the order itself
+			fetched, err := reconciler.client.GetOrder(reconciler.venue, order.Symbol, id)
+			if err != nil {
+				actions = append(actions, ReconcileAction{Kind: ReconcileAlert, Order: order, Reason: "not found on the venue", Err: err})
+				continue
+			}
+			status = *fetched
+		}
+
+		switch {
+		case !status.Open:
+			actions = append(actions, ReconcileAction{Kind: ReconcileAlert, Order: status, Reason: "open locally, closed on the venue"})
+		case status.TotalFilled != order.TotalFilled:
+			actions = append(actions, ReconcileAction{Kind: ReconcileAlert, Order: status, Reason: fmt.Sprintf("filled %v locally, %v on the venue", order.TotalFilled, status.TotalFilled)})
+		}
+	}
+
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].Order.OrderID < actions[j].Order.OrderID
+	})
+	return actions, nil
+}
+
+// Start starts reconciling every Interval in a new goroutine and returns the
+// channel actions are delivered on. A failure to fetch the orders of the
+// venue is delivered as an alert action with a zero order.
+//
+// Reconciling stops and the channel is closed when ctx is done.
+func (reconciler *Reconciler) Start(ctx context.Context) <-chan ReconcileAction {
+	out := make(chan ReconcileAction)
+	interval := reconciler.Interval
+	if interval <= 0 {
+		interval = defaultReconcileInterval
+	}
+
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			actions, err := reconciler.Reconcile()
+			if err != nil {
+				actions = []ReconcileAction{{Kind: ReconcileAlert, Reason: "cannot list the venue orders", Err: err}}
+			}
+			for _, action := range actions {
+				select {
+				case out <- action:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
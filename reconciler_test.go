@@ -0,0 +1,92 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newReconcilerTestServer(t *testing.T, canceled *[]string) *Client {
+	return newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/venues/TESTEX/accounts/"+testAccount+"/orders":
+			fmt.Fprint(w, `{"ok": true, "venue": "TESTEX", "orders": [
+				{"id": 1, "symbol": "FOOBAR", "totalFilled": 5, "open": true},
+				{"id": 2, "symbol": "FOOBAR", "open": true},
+				{"id": 3, "symbol": "FOOBAR", "totalFilled": 10},
+				{"id": 4, "symbol": "FOOBAR", "totalFilled": 10, "open": true}
+			]}`)
+		case r.Method == "DELETE":
+			*canceled = append(*canceled, r.URL.Path)
+			fmt.Fprint(w, `{"ok": true, "id": 2, "symbol": "FOOBAR"}`)
+		case r.URL.Path == "/venues/TESTEX/stocks/FOOBAR/orders/5":
+			fmt.Fprint(w, `{"ok": true, "id": 5, "symbol": "FOOBAR"}`)
+		default:
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"ok": false, "error": "No such order"}`)
+		}
+	})
+}
+
+func TestReconciler(t *testing.T) {
+	var canceled []string
+	client := newReconcilerTestServer(t, &canceled)
+
+	local := []Order{
+		{OrderID: 1, Symbol: testStock, TotalFilled: 5, Open: true},
+		{OrderID: 3, Symbol: testStock, Open: true},
+		{OrderID: 4, Symbol: testStock, Open: true},
+		{OrderID: 5, Symbol: testStock, Open: true},
+		{OrderID: 6, Symbol: testStock, Open: true},
+	}
+	reconciler := NewReconciler(client, testVenue, testAccount, func() []Order { return local })
+
+	actions, err := reconciler.Reconcile()
+	assert.Nil(t, err)
+	var s []string
+	for _, action := range actions {
+		s = append(s, action.String())
+	}
+	assert.Equal(t, []string{
+		"adopt order 2: open on the venue, unknown locally",
+		"alert order 3: open locally, closed on the venue",
+		"alert order 4: filled 0 locally, 10 on the venue",
+		"alert order 5: open locally, closed on the venue",
+		"alert order 6: not found on the venue (No such order)",
+	}, s)
+	assert.Empty(t, canceled)
+
+	reconciler.CancelOrphans = true
+	actions, err = reconciler.Reconcile()
+	assert.Nil(t, err)
+	if assert.Len(t, actions, 5) {
+		assert.Equal(t, ReconcileCancel, actions[0].Kind)
+		assert.Nil(t, actions[0].Err)
+	}
+	assert.Equal(t, []string{"/venues/TESTEX/stocks/FOOBAR/orders/2"}, canceled)
+
+	assert.Panics(t, func() { NewReconciler(client, "", testAccount, nil) })
+}
+
+func TestReconcilerStart(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(401)
+		fmt.Fprint(w, `{"ok": false, "error": "unauthorized"}`)
+	})
+	reconciler := NewReconciler(client, testVenue, testAccount, func() []Order { return nil })
+	reconciler.Interval = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	actions := reconciler.Start(ctx)
+
+	action := <-actions
+	assert.Equal(t, ReconcileAlert, action.Kind)
+	assert.IsType(t, &ErrorUnauthorized{}, action.Err)
+
+	cancel()
+	for range actions {
+	}
+}
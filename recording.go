@@ -0,0 +1,76 @@
+package stockfighter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// A RecordedQuote represents a quote of a stock in a recording.
+//
+// Venue and Stock are those the quote was recorded for, and take precedence
+// over the symbols of the quote itself when encoding.
+type RecordedQuote struct {
+	Venue string `json:"venue"`
+	Stock string `json:"symbol"`
+	Quote
+}
+
+// MarshalJSON implements json.Marshaler.
+func (q RecordedQuote) MarshalJSON() ([]byte, error) {
+	quote := q.Quote
+	quote.Venue, quote.Symbol = q.Venue, q.Stock
+	return json.Marshal(quote)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (q *RecordedQuote) UnmarshalJSON(data []byte) error {
+	if err := q.Quote.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	q.Venue, q.Stock = q.Quote.Venue, q.Quote.Symbol
+	return nil
+}
+
+// A QuoteRecorder writes quotes to a recording, as JSON lines.
+//
+// You can create a new QuoteRecorder using NewQuoteRecorder function.
+type QuoteRecorder struct {
+	encoder *json.Encoder
+}
+
+// NewQuoteRecorder creates a new QuoteRecorder writing to w. This never
+// returns nil.
+func NewQuoteRecorder(w io.Writer) *QuoteRecorder {
+	return &QuoteRecorder{encoder: json.NewEncoder(w)}
+}
+
+// Record writes a quote of a stock to the recording.
+func (recorder *QuoteRecorder) Record(venue, stock string, quote *Quote) error {
+	return recorder.encoder.Encode(RecordedQuote{Venue: venue, Stock: stock, Quote: *quote})
+}
+
+// A QuoteReplayer reads quotes back from a recording written by a
+// QuoteRecorder.
+//
+// You can create a new QuoteReplayer using NewQuoteReplayer function.
+type QuoteReplayer struct {
+	decoder *json.Decoder
+}
+
+// NewQuoteReplayer creates a new QuoteReplayer reading from r. This never
+// returns nil.
+func NewQuoteReplayer(r io.Reader) *QuoteReplayer {
+	return &QuoteReplayer{decoder: json.NewDecoder(bufio.NewReader(r))}
+}
+
+// Next returns the next quote of the recording, in the order they were
+// recorded. Next returns io.EOF at the end of the recording.
+func (replayer *QuoteReplayer) Next() (*RecordedQuote, error) {
+	var quote RecordedQuote
+	if err := replayer.decoder.Decode(&quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
@@ -0,0 +1,33 @@
+package stockfighter
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteRecording(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+
+	var buf bytes.Buffer
+	recorder := NewQuoteRecorder(&buf)
+	assert.Nil(t, recorder.Record(testVenue, testStock, &Quote{HasBid: true, BidPrice: 5000, QuoteTime: ts}))
+	assert.Nil(t, recorder.Record(testVenue, "BAZ", &Quote{HasAsk: true, AskPrice: 5100, QuoteTime: ts.Add(time.Second)}))
+	assert.Contains(t, buf.String(), `{"venue":"TESTEX","symbol":"FOOBAR","bid":5000,`)
+
+	replayer := NewQuoteReplayer(&buf)
+	quote, err := replayer.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, &RecordedQuote{Venue: testVenue, Stock: testStock, Quote: Quote{Venue: testVenue, Symbol: testStock, HasBid: true, BidPrice: 5000, QuoteTime: ts}}, quote)
+
+	quote, err = replayer.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "BAZ", quote.Stock)
+	assert.Equal(t, uint64(5100), quote.AskPrice)
+
+	_, err = replayer.Next()
+	assert.Equal(t, io.EOF, err)
+}
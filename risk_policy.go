@@ -0,0 +1,434 @@
+package stockfighter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// A RiskPolicy is a boolean expression over the figures of an order and its
+// account, which orders must satisfy, e.g.
+//
+//     abs(position) <= 1000 && open_orders <= 20 && notional_per_min <= 50000
+//
+// Expressions are made of numbers, the variables below, the function abs,
+// arithmetic operators (+, -, *, /), comparisons (==, !=, <, <=, >, >=),
+// logical operators (&&, ||, !), and parentheses. The variables are:
+//
+//   - position: the shares held in the stock of the order, once the order is
+//     filled (negative when short);
+//   - open_orders: the open orders of the account on the venue, including the
+//     order;
+//   - notional_per_min: the notional of the orders placed in the last minute,
+//     including the order, in cents;
+//   - price and quantity: the price, in cents, and quantity of the order.
+//
+// Policies are evaluated by a RiskGuard.
+//
+// You can create a new RiskPolicy using ParseRiskPolicy function.
+type RiskPolicy struct {
+	expr string
+	eval func(vars *riskVars) float64
+}
+
+// riskVars holds the values of the variables of a policy.
+type riskVars struct {
+	position       float64
+	openOrders     float64
+	notionalPerMin float64
+	price          float64
+	quantity       float64
+}
+
+// riskVariables returns the value of each variable of a policy.
+var riskVariables = map[string]func(vars *riskVars) float64{
+	"position":         func(vars *riskVars) float64 { return vars.position },
+	"open_orders":      func(vars *riskVars) float64 { return vars.openOrders },
+	"notional_per_min": func(vars *riskVars) float64 { return vars.notionalPerMin },
+	"price":            func(vars *riskVars) float64 { return vars.price },
+	"quantity":         func(vars *riskVars) float64 { return vars.quantity },
+}
+
+// ParseRiskPolicy parses a policy expression. It fails if the expression is
+// invalid, or not boolean.
+func ParseRiskPolicy(expr string) (*RiskPolicy, error) {
+	p := &policyParser{input: expr}
+	p.next()
+	node, err := p.or()
+	if err == nil && p.token != "" {
+		err = p.unexpected()
+	}
+	if err == nil && !node.isBool {
+		err = fmt.Errorf("not a boolean expression")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid risk policy %q: %v", expr, err)
+	}
+	return &RiskPolicy{expr: expr, eval: node.eval}, nil
+}
+
+func (policy *RiskPolicy) String() string {
+	return policy.expr
+}
+
+// allows reports whether the policy is satisfied.
+func (policy *RiskPolicy) allows(vars *riskVars) bool {
+	return policy.eval(vars) != 0
+}
+
+// A policyNode is a parsed expression: booleans evaluate to 0 or 1.
+type policyNode struct {
+	eval   func(vars *riskVars) float64
+	isBool bool
+}
+
+// policyParser is a recursive descent parser of policy expressions.
+type policyParser struct {
+	input  string
+	offset int    // offset of the next token
+	token  string // current token, "" at the end
+	at     int    // offset of the current token
+}
+
+// next reads the next token.
+func (p *policyParser) next() {
+	for p.offset < len(p.input) && unicode.IsSpace(rune(p.input[p.offset])) {
+		p.offset++
+	}
+	p.at = p.offset
+	rest := p.input[p.offset:]
+	n := 0
+	switch {
+	case rest == "":
+	case isPolicyIdentByte(rest[0], false):
+		for n < len(rest) && isPolicyIdentByte(rest[n], true) {
+			n++
+		}
+	case rest[0] >= '0' && rest[0] <= '9' || rest[0] == '.':
+		for n < len(rest) && (rest[n] >= '0' && rest[n] <= '9' || rest[n] == '.') {
+			n++
+		}
+	default:
+		n = 1
+		for _, op := range []string{"&&", "||", "==", "!=", "<=", ">="} {
+			if strings.HasPrefix(rest, op) {
+				n = 2
+			}
+		}
+	}
+	p.token = rest[:n]
+	p.offset += n
+}
+
+func isPolicyIdentByte(c byte, digits bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || digits && c >= '0' && c <= '9'
+}
+
+func (p *policyParser) unexpected() error {
+	if p.token == "" {
+		return fmt.Errorf("unexpected end")
+	}
+	return fmt.Errorf("unexpected %q at offset %v", p.token, p.at)
+}
+
+// operands checks the types of the operands of a binary operator.
+func (p *policyParser) operands(op string, a, b policyNode, isBool bool) error {
+	if a.isBool != isBool || b.isBool != isBool {
+		kind := "numbers"
+		if isBool {
+			kind = "booleans"
+		}
+		return fmt.Errorf("operands of %v must be %v", op, kind)
+	}
+	return nil
+}
+
+func (p *policyParser) or() (policyNode, error) {
+	a, err := p.and()
+	for err == nil && p.token == "||" {
+		p.next()
+		var b policyNode
+		if b, err = p.and(); err == nil {
+			err = p.operands("||", a, b, true)
+		}
+		x, y := a.eval, b.eval
+		a = policyNode{eval: func(vars *riskVars) float64 { return boolValue(x(vars) != 0 || y(vars) != 0) }, isBool: true}
+	}
+	return a, err
+}
+
+func (p *policyParser) and() (policyNode, error) {
+	a, err := p.comparison()
+	for err == nil && p.token == "&&" {
+		p.next()
+		var b policyNode
+		if b, err = p.comparison(); err == nil {
+			err = p.operands("&&", a, b, true)
+		}
+		x, y := a.eval, b.eval
+		a = policyNode{eval: func(vars *riskVars) float64 { return boolValue(x(vars) != 0 && y(vars) != 0) }, isBool: true}
+	}
+	return a, err
+}
+
+func (p *policyParser) comparison() (policyNode, error) {
+	a, err := p.sum()
+	if err != nil {
+		return a, err
+	}
+
+	var compare func(x, y float64) bool
+	switch op := p.token; op {
+	case "==":
+		compare = func(x, y float64) bool { return x == y }
+	case "!=":
+		compare = func(x, y float64) bool { return x != y }
+	case "<":
+		compare = func(x, y float64) bool { return x < y }
+	case "<=":
+		compare = func(x, y float64) bool { return x <= y }
+	case ">":
+		compare = func(x, y float64) bool { return x > y }
+	case ">=":
+		compare = func(x, y float64) bool { return x >= y }
+	default:
+		return a, nil
+	}
+
+	op := p.token
+	p.next()
+	b, err := p.sum()
+	if err == nil {
+		err = p.operands(op, a, b, false)
+	}
+	x, y := a.eval, b.eval
+	return policyNode{eval: func(vars *riskVars) float64 { return boolValue(compare(x(vars), y(vars))) }, isBool: true}, err
+}
+
+func (p *policyParser) sum() (policyNode, error) {
+	a, err := p.product()
+	for err == nil && (p.token == "+" || p.token == "-") {
+		op := p.token
+		p.next()
+		var b policyNode
+		if b, err = p.product(); err == nil {
+			err = p.operands(op, a, b, false)
+		}
+		x, y := a.eval, b.eval
+		if op == "+" {
+			a.eval = func(vars *riskVars) float64 { return x(vars) + y(vars) }
+		} else {
+			a.eval = func(vars *riskVars) float64 { return x(vars) - y(vars) }
+		}
+	}
+	return a, err
+}
+
+func (p *policyParser) product() (policyNode, error) {
+	a, err := p.unary()
+	for err == nil && (p.token == "*" || p.token == "/") {
+		op := p.token
+		p.next()
+		var b policyNode
+		if b, err = p.unary(); err == nil {
+			err = p.operands(op, a, b, false)
+		}
+		x, y := a.eval, b.eval
+		if op == "*" {
+			a.eval = func(vars *riskVars) float64 { return x(vars) * y(vars) }
+		} else {
+			a.eval = func(vars *riskVars) float64 { return x(vars) / y(vars) }
+		}
+	}
+	return a, err
+}
+
+func (p *policyParser) unary() (policyNode, error) {
+	switch p.token {
+	case "-":
+		p.next()
+		a, err := p.unary()
+		if err == nil && a.isBool {
+			err = fmt.Errorf("operand of - must be a number")
+		}
+		x := a.eval
+		return policyNode{eval: func(vars *riskVars) float64 { return -x(vars) }}, err
+	case "!":
+		p.next()
+		a, err := p.unary()
+		if err == nil && !a.isBool {
+			err = fmt.Errorf("operand of ! must be a boolean")
+		}
+		x := a.eval
+		return policyNode{eval: func(vars *riskVars) float64 { return boolValue(x(vars) == 0) }, isBool: true}, err
+	}
+	return p.primary()
+}
+
+func (p *policyParser) primary() (policyNode, error) {
+	token := p.token
+	switch {
+	case token == "(":
+		p.next()
+		a, err := p.or()
+		if err != nil {
+			return a, err
+		}
+		if p.token != ")" {
+			return a, p.unexpected()
+		}
+		p.next()
+		return a, nil
+
+	case token != "" && (token[0] >= '0' && token[0] <= '9' || token[0] == '.'):
+		value, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return policyNode{}, fmt.Errorf("invalid number %q at offset %v", token, p.at)
+		}
+		p.next()
+		return policyNode{eval: func(*riskVars) float64 { return value }}, nil
+
+	case token == "abs":
+		p.next()
+		if p.token != "(" {
+			return policyNode{}, p.unexpected()
+		}
+		a, err := p.primary()
+		if err == nil && a.isBool {
+			err = fmt.Errorf("argument of abs must be a number")
+		}
+		x := a.eval
+		return policyNode{eval: func(vars *riskVars) float64 { return math.Abs(x(vars)) }}, err
+
+	case token != "" && isPolicyIdentByte(token[0], false):
+		variable, ok := riskVariables[token]
+		if !ok {
+			return policyNode{}, fmt.Errorf("unknown variable %q at offset %v", token, p.at)
+		}
+		p.next()
+		return policyNode{eval: variable}, nil
+	}
+	return policyNode{}, p.unexpected()
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// A RiskGuard checks the orders placed by a client against a RiskPolicy,
+// with its BeforeOrder and AfterOrder hooks (see WithRiskPolicy).
+//
+// You can create a new RiskGuard using NewRiskGuard function.
+type RiskGuard struct {
+	api    StockfighterAPI
+	policy *RiskPolicy
+
+	mu     sync.Mutex
+	placed []placedNotional
+}
+
+// placedNotional is the notional of an order placed at some time.
+type placedNotional struct {
+	time     time.Time
+	notional uint64
+}
+
+// NewRiskGuard creates a new RiskGuard listing the orders of accounts with
+// api. This never returns nil.
+func NewRiskGuard(api StockfighterAPI, policy *RiskPolicy) *RiskGuard {
+	return &RiskGuard{api: api, policy: policy}
+}
+
+// WithRiskPolicy vetoes the orders which do not satisfy policy, with a
+// RiskGuard listing the orders of accounts with the client itself: every
+// order costs an extra GetAllOrders request.
+func WithRiskPolicy(policy *RiskPolicy) ClientOption {
+	return func(client *Client) {
+		guard := NewRiskGuard(client, policy)
+		client.beforeOrder = append(client.beforeOrder, guard.BeforeOrder)
+		client.afterOrder = append(client.afterOrder, guard.AfterOrder)
+	}
+}
+
+// BeforeOrder is a BeforeOrderHook vetoing the orders which do not satisfy
+// the policy of the guard with an *ErrorInvalidOrder. It lists the orders of
+// the account on the venue of the order, and fails if they cannot be listed.
+func (guard *RiskGuard) BeforeOrder(req *OrderRequest) error {
+	orders, err := guard.api.GetAllOrders(req.Venue, req.Account)
+	if err != nil {
+		return err
+	}
+
+	var position Position
+	openOrders := 1
+	for _, order := range orders {
+		if order.Open {
+			openOrders++
+		}
+		if order.Symbol == req.Stock {
+			for _, fill := range order.Fills {
+				position.Apply(order.Direction, fill)
+			}
+		}
+	}
+	position.Apply(req.Direction, OrderFillInfo{Quantity: req.Quantity})
+
+	vars := &riskVars{
+		position:       float64(position.Shares),
+		openOrders:     float64(openOrders),
+		notionalPerMin: float64(guard.notionalSince(time.Now().Add(-time.Minute)) + req.Price*req.Quantity),
+		price:          float64(req.Price),
+		quantity:       float64(req.Quantity),
+	}
+	if !guard.policy.allows(vars) {
+		return &ErrorInvalidOrder{Reason: "risk policy not satisfied: " + guard.policy.String()}
+	}
+	return nil
+}
+
+// AfterOrder is an AfterOrderHook accounting the notional of the orders
+// placed, for notional_per_min: their price times their quantity, or the
+// notional of their fills for market orders.
+func (guard *RiskGuard) AfterOrder(req OrderRequest, order *Order, err error) {
+	if err != nil {
+		return
+	}
+
+	notional := req.Price * req.Quantity
+	if req.OrderType == OrderTypeMarket {
+		notional = 0
+		for _, fill := range order.Fills {
+			notional += fill.Price * fill.Quantity
+		}
+	}
+
+	guard.mu.Lock()
+	guard.placed = append(guard.placed, placedNotional{time.Now(), notional})
+	guard.mu.Unlock()
+}
+
+// notionalSince returns the notional of the orders placed since t, and
+// forgets the orders placed before.
+func (guard *RiskGuard) notionalSince(t time.Time) uint64 {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	i := 0
+	for i < len(guard.placed) && guard.placed[i].time.Before(t) {
+		i++
+	}
+	guard.placed = guard.placed[i:]
+
+	var notional uint64
+	for _, placed := range guard.placed {
+		notional += placed.notional
+	}
+	return notional
+}
@@ -0,0 +1,90 @@
+package stockfighter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRiskPolicy(t *testing.T) {
+	vars := &riskVars{position: -600, openOrders: 3, notionalPerMin: 40000, price: 100, quantity: 10}
+	for expr, allows := range map[string]bool{
+		"abs(position) <= 1000 && open_orders <= 20 && notional_per_min <= 50000":  true,
+		"abs(position) <= 500 || open_orders < 3":                                  false,
+		"position >= -1000 && !(price * quantity > 1000)":                          true,
+		"-position - 100 == 500 && notional_per_min / 2 != 0.5 * notional_per_min": false,
+		"quantity + 2 * 5 == 20 && (1 + 2) * 3 == 9":                               true,
+		"!!(open_orders > 2)": true,
+	} {
+		policy, err := ParseRiskPolicy(expr)
+		if assert.Nil(t, err, expr) {
+			assert.Equal(t, expr, policy.String())
+			assert.Equal(t, allows, policy.allows(vars), expr)
+		}
+	}
+
+	for expr, msg := range map[string]string{
+		"":                        "unexpected end",
+		"position":                "not a boolean expression",
+		"position <= 10 &&":       "unexpected end",
+		"position <= 10 1":        `unexpected "1" at offset 15`,
+		"(position <= 10":         "unexpected end",
+		"size <= 10":              `unknown variable "size" at offset 0`,
+		"abs position <= 10":      `unexpected "position" at offset 4`,
+		"abs(position <= 10)":     "argument of abs must be a number",
+		"position <= 1.2.3":       `invalid number "1.2.3" at offset 12`,
+		"position && open_orders": "operands of && must be booleans",
+		"(price < 1) + 1 > 0":     "operands of + must be numbers",
+		"!position":               "operand of ! must be a boolean",
+		"-(price < 1)":            "operand of - must be a number",
+		"price < 1 < 2":           `unexpected "<" at offset 10`,
+		"price # 1":               `unexpected "#" at offset 6`,
+	} {
+		_, err := ParseRiskPolicy(expr)
+		assert.EqualError(t, err, "invalid risk policy \""+expr+"\": "+msg, expr)
+	}
+}
+
+func TestRiskPolicy(t *testing.T) {
+	// every order fills at once
+	var orders []Order
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "venue": testVenue, "orders": orders})
+			return
+		}
+		var req OrderRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		order := Order{Venue: req.Venue, Symbol: req.Stock, Account: req.Account, Direction: req.Direction,
+			OriginalQuantity: req.Quantity, Price: req.Price, OrderType: req.OrderType, OrderID: int64(len(orders) + 1),
+			Fills: []OrderFillInfo{{Price: 100, Quantity: req.Quantity}}, TotalFilled: req.Quantity}
+		orders = append(orders, order)
+		json.NewEncoder(w).Encode(struct {
+			OK bool `json:"ok"`
+			Order
+		}{true, order})
+	}))
+	t.Cleanup(server.Close)
+
+	policy, err := ParseRiskPolicy("abs(position) <= 100 && notional_per_min <= 20000")
+	assert.Nil(t, err)
+	client := NewClient(testApiKey, WithBaseURL(server.URL), WithRiskPolicy(policy))
+
+	_, err = client.PlaceOrder(testVenue, testStock, testAccount, 100, 100, OrderDirectionBuy, OrderTypeLimit)
+	assert.Nil(t, err)
+	// the position would exceed 100 shares
+	_, err = client.PlaceOrder(testVenue, testStock, testAccount, 100, 1, OrderDirectionBuy, OrderTypeLimit)
+	assert.IsType(t, &ErrorInvalidOrder{}, err)
+	// market orders count at their fill price
+	_, err = client.PlaceOrder(testVenue, testStock, testAccount, 0, 50, OrderDirectionSell, OrderTypeMarket)
+	assert.Nil(t, err)
+	// the notional per minute would exceed 20000
+	_, err = client.PlaceOrder(testVenue, testStock, testAccount, 100, 60, OrderDirectionSell, OrderTypeLimit)
+	assert.IsType(t, &ErrorInvalidOrder{}, err)
+	_, err = client.PlaceOrder(testVenue, testStock, testAccount, 100, 50, OrderDirectionSell, OrderTypeLimit)
+	assert.Nil(t, err)
+	assert.Len(t, orders, 3)
+}
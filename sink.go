@@ -0,0 +1,109 @@
+package stockfighter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// SinkSchemaVersion is the version of the JSON schema of the events published
+// to sinks. It changes only if the schema changes incompatibly.
+const SinkSchemaVersion = 1
+
+// Sink event kinds.
+const (
+	SinkEventQuote     = "quote"
+	SinkEventExecution = "execution"
+	SinkEventOrder     = "order"
+)
+
+// A SinkEvent is the envelope of the market data and order events published to
+// sinks, e.g. for downstream analytics pipelines. Exactly one of Quote,
+// Execution, and Order is set, depending on Kind.
+type SinkEvent struct {
+	Schema int       `json:"schema"`
+	Kind   string    `json:"kind"`
+	Venue  string    `json:"venue"`
+	Symbol string    `json:"symbol"`
+	Time   time.Time `json:"time"`
+
+	Quote     *Quote        `json:"quote,omitempty"`
+	Execution *Execution    `json:"execution,omitempty"`
+	Order     *BlotterEvent `json:"order,omitempty"`
+}
+
+// QuoteEvent returns the sink event of a quote, at its quote time.
+func QuoteEvent(quote *Quote) SinkEvent {
+	return SinkEvent{Schema: SinkSchemaVersion, Kind: SinkEventQuote, Venue: quote.Venue, Symbol: quote.Symbol, Time: quote.QuoteTime, Quote: quote}
+}
+
+// ExecutionEvent returns the sink event of an execution, at its fill time.
+func ExecutionEvent(execution *Execution) SinkEvent {
+	return SinkEvent{Schema: SinkSchemaVersion, Kind: SinkEventExecution, Venue: execution.Venue, Symbol: execution.Symbol,
+		Time: execution.FilledAt, Execution: execution}
+}
+
+// OrderEvent returns the sink event of an order event or fill of a blotter,
+// e.g. from Blotter.OnEvent.
+func OrderEvent(event BlotterEvent) SinkEvent {
+	return SinkEvent{Schema: SinkSchemaVersion, Kind: SinkEventOrder, Venue: event.Venue, Symbol: event.Symbol, Time: event.Time, Order: &event}
+}
+
+// A Sink publishes events, e.g. to a message broker (see packages kafkasink
+// and natssink).
+type Sink interface {
+	// Publish publishes an event.
+	Publish(ctx context.Context, event SinkEvent) error
+
+	// Close flushes the events published so far and releases the sink.
+	Close() error
+}
+
+// A WriterSink is a Sink writing events to a writer, as JSON lines.
+//
+// You can create a new WriterSink using NewWriterSink function.
+type WriterSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+// NewWriterSink creates a new WriterSink writing to w, which is closed with
+// the sink if it is an io.Closer. This never returns nil.
+func NewWriterSink(w io.Writer) *WriterSink {
+	sink := &WriterSink{encoder: json.NewEncoder(w)}
+	sink.closer, _ = w.(io.Closer)
+	return sink
+}
+
+// Publish implements Sink.
+func (sink *WriterSink) Publish(ctx context.Context, event SinkEvent) error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	return sink.encoder.Encode(event)
+}
+
+// Close implements Sink.
+func (sink *WriterSink) Close() error {
+	if sink.closer == nil {
+		return nil
+	}
+	return sink.closer.Close()
+}
+
+// PublishQuotes publishes the quotes of a stream of updates to a sink until
+// updates is closed or publishing fails. Updates with an error are skipped.
+func PublishQuotes(ctx context.Context, sink Sink, updates <-chan QuoteUpdate) error {
+	for update := range updates {
+		if update.Err != nil {
+			continue
+		}
+		if err := sink.Publish(ctx, QuoteEvent(update.Quote)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package stockfighter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriterSink(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+
+	updates := make(chan QuoteUpdate, 2)
+	updates <- QuoteUpdate{Venue: testVenue, Stock: testStock, Quote: &Quote{Venue: testVenue, Symbol: testStock, LastPrice: 5000, QuoteTime: ts}}
+	updates <- QuoteUpdate{Venue: testVenue, Stock: testStock, Err: errors.New("timeout")}
+	close(updates)
+	assert.Nil(t, PublishQuotes(context.Background(), sink, updates))
+
+	assert.Nil(t, sink.Publish(context.Background(), ExecutionEvent(&Execution{Venue: testVenue, Symbol: testStock, Price: 5000, Filled: 10, FilledAt: ts})))
+	assert.Nil(t, sink.Publish(context.Background(), OrderEvent(BlotterEvent{Time: ts, Kind: BlotterEventFill, Venue: testVenue, Symbol: testStock, OrderID: 1})))
+	assert.Nil(t, sink.Close())
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if assert.Len(t, lines, 3) {
+		assert.Contains(t, string(lines[0]), `{"schema":1,"kind":"quote","venue":"TESTEX","symbol":"FOOBAR","time":"2015-12-04T09:02:16Z","quote":{`)
+		assert.Contains(t, string(lines[1]), `"kind":"execution"`)
+		assert.Contains(t, string(lines[1]), `"execution":{`)
+		assert.Contains(t, string(lines[2]), `"kind":"order"`)
+		assert.Contains(t, string(lines[2]), `"order":{"time":"2015-12-04T09:02:16Z","kind":"fill"`)
+	}
+}
@@ -0,0 +1,78 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A MarketSnapshot represents the state of a stock market as seen by an
+// account at a given time.
+type MarketSnapshot struct {
+	// Quote and orderbook of the stock
+	Quote     *Quote
+	Orderbook *Orderbook
+
+	// Open orders of the account for the stock
+	OpenOrders []Order
+
+	// Time the requests were started at
+	Timestamp time.Time
+}
+
+// GetMarketSnapshot fetches the quote and orderbook of a stock, along with
+// the open orders of an account for it, concurrently.
+//
+// The three requests are started together, so the snapshot is as consistent
+// as the API allows, but not atomic. If any of them fails, GetMarketSnapshot
+// returns the first error (in the order above) and no snapshot. Requests are
+// made with ctx (see Client.WithContext).
+func (client *Client) GetMarketSnapshot(ctx context.Context, venue, stock, account string) (*MarketSnapshot, error) {
+	// validate the arguments before the requests are made concurrently, to
+	// panic in the goroutine of the caller like other methods
+	venue = strings.TrimSpace(venue)
+	if !validSymbol(venue) {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+
+	stock = strings.TrimSpace(stock)
+	if !validSymbol(stock) {
+		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
+	}
+
+	account = strings.TrimSpace(account)
+	if !validSymbol(account) {
+		panic(fmt.Errorf("Invalid account name: %v", account))
+	}
+
+	client = client.WithContext(ctx)
+	snapshot := &MarketSnapshot{Timestamp: time.Now()}
+
+	var orders []Order
+	errs := parallel(ctx, 3, 3, func(i int) error {
+		var err error
+		switch i {
+		case 0:
+			snapshot.Quote, err = client.GetQuote(venue, stock)
+		case 1:
+			snapshot.Orderbook, err = client.GetOrderbook(venue, stock)
+		case 2:
+			orders, err = client.GetStockOrders(venue, account, stock)
+		}
+		return err
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, order := range orders {
+		if order.Open {
+			snapshot.OpenOrders = append(snapshot.OpenOrders, order)
+		}
+	}
+
+	return snapshot, nil
+}
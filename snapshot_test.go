@@ -0,0 +1,61 @@
+package stockfighter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMarketSnapshot(t *testing.T) {
+	var failOrders bool
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/quote"):
+			w.Write([]byte(`{"ok": true, "bid": 100, "ask": 110}`))
+		case strings.HasSuffix(r.URL.Path, "/orders"):
+			if failOrders {
+				w.WriteHeader(401)
+				w.Write([]byte(`{"ok": false, "error": "not authorized"}`))
+				return
+			}
+			w.Write([]byte(`{"ok": true, "orders": [{"id": 1, "open": true}, {"id": 2, "open": false}]}`))
+		default:
+			w.Write([]byte(`{"ok": true, "bids": [{"price": 100, "qty": 5, "isBuy": true}]}`))
+		}
+	})
+
+	snapshot, err := client.GetMarketSnapshot(context.Background(), testVenue, testStock, testAccount)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(110), snapshot.Quote.AskPrice)
+	assert.Equal(t, uint64(5), snapshot.Orderbook.Bids[0].Quantity)
+	assert.Len(t, snapshot.OpenOrders, 1)
+	assert.Equal(t, int64(1), snapshot.OpenOrders[0].OrderID)
+	assert.False(t, snapshot.Timestamp.IsZero())
+
+	failOrders = true
+	snapshot, err = client.GetMarketSnapshot(context.Background(), testVenue, testStock, testAccount)
+	assert.Nil(t, snapshot)
+	assert.IsType(t, &ErrorUnauthorized{}, err)
+
+	assert.Panics(t, func() { client.GetMarketSnapshot(context.Background(), testVenue, "FOO BAR", testAccount) })
+}
+
+func TestGetMarketSnapshotContext(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"ok": true}`))
+	})
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	snapshot, err := client.GetMarketSnapshot(ctx, testVenue, testStock, testAccount)
+	assert.Nil(t, snapshot)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
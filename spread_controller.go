@@ -0,0 +1,116 @@
+package stockfighter
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type timedQuantity struct {
+	quantity uint64
+	at       time.Time
+}
+
+// A SpreadController adjusts a quoted spread so that the fill rate measured
+// over a sliding window approaches a target: the spread widens while fills
+// come faster than the target and tightens while they come slower.
+//
+// Assign it to Quoter.SpreadController to have a Quoter feed it its fills and
+// use the adjusted spread.
+//
+// You can create a new SpreadController using NewSpreadController function.
+type SpreadController struct {
+	// Target fill rate (in shares per minute)
+	TargetFillRate float64
+
+	// Window fills are measured over
+	Window time.Duration
+
+	// Bounds of the adjusted spread (in cents)
+	MinSpread uint64
+	MaxSpread uint64
+
+	// Relative spread change per adjustment at a fill rate twice the target
+	Gain float64
+
+	mu     sync.Mutex
+	fills  []timedQuantity
+	start  time.Time
+	spread float64
+}
+
+// NewSpreadController creates a new SpreadController targeting targetFillRate
+// shares per minute, measured over a one minute window. This never returns
+// nil.
+func NewSpreadController(targetFillRate float64, minSpread, maxSpread uint64) *SpreadController {
+	return &SpreadController{
+		TargetFillRate: targetFillRate,
+		Window:         time.Minute,
+		MinSpread:      minSpread,
+		MaxSpread:      maxSpread,
+		Gain:           0.05,
+	}
+}
+
+// Observe records a fill of quantity shares at the given time.
+func (controller *SpreadController) Observe(quantity uint64, at time.Time) {
+	controller.mu.Lock()
+	defer controller.mu.Unlock()
+
+	controller.fills = append(controller.fills, timedQuantity{quantity: quantity, at: at})
+}
+
+// FillRate returns the fill rate (in shares per minute) over the window ending
+// at now.
+func (controller *SpreadController) FillRate(now time.Time) float64 {
+	controller.mu.Lock()
+	defer controller.mu.Unlock()
+
+	return controller.fillRate(now)
+}
+
+func (controller *SpreadController) fillRate(now time.Time) float64 {
+	since := now.Add(-controller.Window)
+
+	// drop fills that left the window
+	i := 0
+	for i < len(controller.fills) && !controller.fills[i].at.After(since) {
+		i++
+	}
+	controller.fills = controller.fills[i:]
+
+	var filled uint64
+	for _, fill := range controller.fills {
+		filled += fill.quantity
+	}
+
+	return float64(filled) / controller.Window.Minutes()
+}
+
+// Adjust returns the spread to quote at now. The spread passed to the first
+// call seeds the controller, and is returned unchanged until a full window has
+// been observed.
+func (controller *SpreadController) Adjust(spread uint64, now time.Time) uint64 {
+	controller.mu.Lock()
+	defer controller.mu.Unlock()
+
+	if controller.start.IsZero() {
+		controller.start = now
+		controller.spread = float64(spread)
+	}
+	if now.Sub(controller.start) < controller.Window || controller.TargetFillRate <= 0 {
+		return spread
+	}
+
+	// relative fill rate error, bounded to [-1, 1]
+	e := (controller.fillRate(now) - controller.TargetFillRate) / controller.TargetFillRate
+	e = math.Max(-1, math.Min(1, e))
+
+	controller.spread *= 1 + controller.Gain*e
+	controller.spread = math.Max(float64(controller.MinSpread), controller.spread)
+	if controller.MaxSpread > 0 {
+		controller.spread = math.Min(float64(controller.MaxSpread), controller.spread)
+	}
+
+	return uint64(math.Round(controller.spread))
+}
@@ -0,0 +1,31 @@
+package stockfighter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpreadController(t *testing.T) {
+	start := time.Date(2015, 12, 4, 9, 0, 0, 0, time.UTC)
+	controller := NewSpreadController(100, 5, 50)
+	controller.Gain = 0.5
+
+	// no adjustment during the first window
+	assert.Equal(t, uint64(20), controller.Adjust(20, start))
+	controller.Observe(400, start.Add(30*time.Second))
+	assert.Equal(t, uint64(20), controller.Adjust(20, start.Add(59*time.Second)))
+
+	// fills four times faster than the target widen the spread
+	assert.Equal(t, 400.0, controller.FillRate(start.Add(time.Minute)))
+	assert.Equal(t, uint64(30), controller.Adjust(20, start.Add(time.Minute)))
+
+	// no fills in the window tighten it, down to the minimum
+	assert.Equal(t, 0.0, controller.FillRate(start.Add(2*time.Minute)))
+	assert.Equal(t, uint64(15), controller.Adjust(30, start.Add(2*time.Minute)))
+	for i := 0; i < 10; i++ {
+		controller.Adjust(15, start.Add(3*time.Minute))
+	}
+	assert.Equal(t, uint64(5), controller.Adjust(15, start.Add(3*time.Minute)))
+}
@@ -0,0 +1,145 @@
+package stockfighter
+
+import (
+	"sync"
+	"time"
+)
+
+// Classes of failed API requests, keys of Stats.Errors.
+const (
+	// The request could not be made, or the response could not be read
+	ErrorClassNetwork = "network"
+
+	// The response could not be decoded
+	ErrorClassDecode = "decode"
+
+	// The response status is a client error (4xx)
+	ErrorClassClient = "client"
+
+	// The response status is a server error (5xx)
+	ErrorClassServer = "server"
+)
+
+// Stats represents runtime counters of the API requests of a client and all
+// the clients derived from it, since it was created or the stats were reset.
+type Stats struct {
+	// Time counting started at
+	Since time.Time
+
+	// Number of API requests made, per endpoint name (see ParseEndpoint)
+	Requests  uint64
+	Endpoints map[string]uint64
+
+	// Number of failed requests, per error class
+	Errors map[string]uint64
+
+	// Number of HTTP requests made on top of one per API request, e.g. by a
+	// retrying middleware
+	Retries uint64
+
+	// Bytes of request and response bodies
+	BytesSent     uint64
+	BytesReceived uint64
+
+	// Average time from sending requests to receiving full responses, for
+	// the requests that got a response
+	AvgLatency time.Duration
+}
+
+// Stats returns the runtime counters of the client. This is cheap enough to
+// be called often, e.g. to be exported as metrics.
+func (client *Client) Stats() Stats {
+	return client.transport.stats.get()
+}
+
+// ResetStats resets the runtime counters of the client, and of all the
+// clients sharing them.
+func (client *Client) ResetStats() {
+	client.transport.stats.reset()
+}
+
+// statsCounter counts API requests for Stats. It is shared by a Client and all
+// clients derived from it.
+type statsCounter struct {
+	mu        sync.Mutex
+	stats     Stats
+	responses uint64
+	latency   time.Duration
+}
+
+func newStatsCounter() *statsCounter {
+	s := &statsCounter{}
+	s.reset()
+	return s
+}
+
+func (s *statsCounter) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats = Stats{
+		Since:     time.Now(),
+		Endpoints: make(map[string]uint64),
+		Errors:    make(map[string]uint64),
+	}
+	s.responses, s.latency = 0, 0
+}
+
+func (s *statsCounter) get() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	stats.Endpoints = make(map[string]uint64, len(s.stats.Endpoints))
+	for endpoint, n := range s.stats.Endpoints {
+		stats.Endpoints[endpoint] = n
+	}
+	stats.Errors = make(map[string]uint64, len(s.stats.Errors))
+	for class, n := range s.stats.Errors {
+		stats.Errors[class] = n
+	}
+	if s.responses > 0 {
+		stats.AvgLatency = s.latency / time.Duration(s.responses)
+	}
+	return stats
+}
+
+// request counts an API request and the bytes of its body.
+func (s *statsCounter) request(method, path string, sent int) {
+	s.mu.Lock()
+	s.stats.Requests++
+	s.stats.Endpoints[ParseEndpoint(method, path).Name]++
+	s.stats.BytesSent += uint64(sent)
+	s.mu.Unlock()
+}
+
+// retry counts an HTTP request beyond the first of an API request.
+func (s *statsCounter) retry() {
+	s.mu.Lock()
+	s.stats.Retries++
+	s.mu.Unlock()
+}
+
+// response counts a response, its latency, and its error class if any.
+func (s *statsCounter) response(statusCode, received int, latency time.Duration, decodeErr error) {
+	s.mu.Lock()
+	s.stats.BytesReceived += uint64(received)
+	s.responses++
+	s.latency += latency
+	switch {
+	case statusCode >= 500:
+		s.stats.Errors[ErrorClassServer]++
+	case statusCode >= 400:
+		s.stats.Errors[ErrorClassClient]++
+	case decodeErr != nil:
+		s.stats.Errors[ErrorClassDecode]++
+	}
+	s.mu.Unlock()
+}
+
+// failure counts a request that got no response.
+func (s *statsCounter) failure() {
+	s.mu.Lock()
+	s.stats.Errors[ErrorClassNetwork]++
+	s.mu.Unlock()
+}
@@ -0,0 +1,84 @@
+package stockfighter
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/heartbeat":
+			w.Write([]byte(`{"ok": true}`))
+		case "/venues/TESTEX/stocks/FOOBAR/quote":
+			w.WriteHeader(500)
+			w.Write([]byte(`{"ok": false, "error": "oops"}`))
+		default:
+			w.Write([]byte(`not json`))
+		}
+	})
+
+	// a middleware retrying failed requests once
+	var failed bool
+	retry := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if !failed {
+				failed = true
+				return nil, errors.New("connection reset")
+			}
+			return next(req)
+		}
+	}
+	client := NewClient(testApiKey, WithBaseURL(server.transport.baseURL), WithMiddleware(retry))
+
+	stats := client.Stats()
+	assert.Equal(t, uint64(0), stats.Requests)
+	assert.False(t, stats.Since.IsZero())
+
+	assert.NotNil(t, client.Ping())
+	assert.Nil(t, client.Subsystem("poller").Ping())
+	_, err := client.GetQuote(testVenue, testStock)
+	assert.NotNil(t, err)
+	_, err = client.GetOrderbook(testVenue, testStock)
+	assert.NotNil(t, err)
+
+	stats = client.Stats()
+	assert.Equal(t, uint64(4), stats.Requests)
+	assert.Equal(t, map[string]uint64{EndpointHeartbeat: 2, EndpointQuote: 1, EndpointOrderbook: 1}, stats.Endpoints)
+	assert.Equal(t, map[string]uint64{ErrorClassNetwork: 1, ErrorClassServer: 1, ErrorClassDecode: 1}, stats.Errors)
+	assert.Equal(t, uint64(len(`{"ok": true}`)+len(`{"ok": false, "error": "oops"}`)+len(`not json`)), stats.BytesReceived)
+	assert.True(t, stats.AvgLatency > 0)
+
+	client.ResetStats()
+	stats = client.Stats()
+	assert.Equal(t, uint64(0), stats.Requests)
+	assert.Empty(t, stats.Endpoints)
+	assert.Empty(t, stats.Errors)
+}
+
+func TestStatsRetries(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true}`))
+	})
+	retry := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+			return next(req)
+		}
+	}
+	client := NewClient(testApiKey, WithBaseURL(server.transport.baseURL), WithMiddleware(retry))
+
+	_, err := client.PlaceOrder(testVenue, testStock, testAccount, 100, 10, OrderDirectionBuy, OrderTypeLimit)
+	assert.Nil(t, err)
+
+	stats := client.Stats()
+	assert.Equal(t, uint64(1), stats.Requests)
+	assert.Equal(t, uint64(1), stats.Retries)
+	assert.True(t, stats.BytesSent > 0)
+}
@@ -0,0 +1,260 @@
+package stockfightertest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// Accounts the orders of a scenario are placed with: the liquidity it
+// provides, and the trades it makes against it.
+const (
+	LiquidityAccount = "SCENARIOMM"
+	TraderAccount    = "SCENARIOTR"
+)
+
+type scenarioStep struct {
+	at          time.Duration
+	description string
+	action      func() error
+}
+
+type ladder struct {
+	levels int
+	size   uint64
+}
+
+// A Scenario scripts the market of a Venue over time, to reproduce the
+// situations strategies are tested against:
+//
+//     venue := stockfightertest.NewVenue("TESTEX", "FOOBAR")
+//     scenario := stockfightertest.NewScenario(venue, start).
+//         At(0).Liquidity("FOOBAR", 5000, 5010, 5, 100).
+//         At(5*time.Second).Sweep("FOOBAR", stockfighter.OrderDirectionSell, 3).
+//         At(10*time.Second).WidenSpread("FOOBAR", 50)
+//     bot := NewBot(venue.Mock())
+//     for !scenario.Done() {
+//         scenario.Advance(time.Second)
+//         bot.Step()
+//     }
+//
+// Steps run in time order, then in the order they were added. The venue
+// time follows the scenario time.
+//
+// You can create a new Scenario using NewScenario function.
+type Scenario struct {
+	// Price step, in cents, between the levels of liquidity
+	Tick uint64
+
+	venue   *Venue
+	start   time.Time
+	elapsed time.Duration
+	steps   []scenarioStep
+	next    int
+	ladders map[string]ladder
+}
+
+// NewScenario creates a new Scenario of the venue, starting at the given
+// time. This never returns nil.
+func NewScenario(venue *Venue, start time.Time) *Scenario {
+	venue.SetTime(start)
+	return &Scenario{
+		Tick:    1,
+		venue:   venue,
+		start:   start,
+		ladders: make(map[string]ladder),
+	}
+}
+
+// A ScenarioStep adds a step to a scenario, at the time it was created for.
+type ScenarioStep struct {
+	scenario *Scenario
+	at       time.Duration
+}
+
+// At returns a ScenarioStep adding a step at the given time from the start.
+func (s *Scenario) At(at time.Duration) *ScenarioStep {
+	return &ScenarioStep{scenario: s, at: at}
+}
+
+func (step *ScenarioStep) add(description string, action func() error) *Scenario {
+	s := step.scenario
+	s.steps = append(s.steps, scenarioStep{at: step.at, description: description, action: action})
+	sort.SliceStable(s.steps[s.next:], func(i, j int) bool { return s.steps[s.next+i].at < s.steps[s.next+j].at })
+	return s
+}
+
+// Liquidity replaces the liquidity of a stock with levels of orders of the
+// given size on each side, the best bid and ask at the given prices.
+func (step *ScenarioStep) Liquidity(stock string, bid, ask uint64, levels int, size uint64) *Scenario {
+	s := step.scenario
+	return step.add(fmt.Sprintf("liquidity on %v: %v levels of %v, %v/%v", stock, levels, size, bid, ask), func() error {
+		s.ladders[stock] = ladder{levels: levels, size: size}
+		return s.provide(stock, bid, ask)
+	})
+}
+
+// WidenSpread replaces the liquidity of a stock with the same levels around
+// the current mid price, with the given spread in cents.
+func (step *ScenarioStep) WidenSpread(stock string, spread uint64) *Scenario {
+	s := step.scenario
+	return step.add(fmt.Sprintf("spread of %v widens to %v", stock, spread), func() error {
+		quote, err := s.venue.GetQuote(s.venue.Name(), stock)
+		if err != nil {
+			return err
+		}
+		if !quote.HasBid || !quote.HasAsk {
+			return fmt.Errorf("No spread to widen on %v", stock)
+		}
+		mid := (quote.BidPrice + quote.AskPrice) / 2
+		if spread/2 >= mid {
+			return fmt.Errorf("Invalid spread: %v", spread)
+		}
+		return s.provide(stock, mid-spread/2, mid-spread/2+spread)
+	})
+}
+
+// provide replaces the liquidity orders of a stock.
+func (s *Scenario) provide(stock string, bid, ask uint64) error {
+	venue := s.venue.Name()
+	orders, err := s.venue.GetStockOrders(venue, LiquidityAccount, stock)
+	if err != nil {
+		return err
+	}
+	for _, order := range orders {
+		if order.Open {
+			s.venue.CancelOrder(venue, stock, order.OrderID)
+		}
+	}
+
+	l := s.ladders[stock]
+	for i := 0; i < l.levels; i++ {
+		offset := uint64(i) * s.Tick
+		if offset < bid {
+			if _, err := s.venue.PlaceOrder(venue, stock, LiquidityAccount, bid-offset, l.size, stockfighter.OrderDirectionBuy,
+				stockfighter.OrderTypeLimit); err != nil {
+				return err
+			}
+		}
+		if _, err := s.venue.PlaceOrder(venue, stock, LiquidityAccount, ask+offset, l.size, stockfighter.OrderDirectionSell,
+			stockfighter.OrderTypeLimit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sweep places a market order taking the given number of price levels of
+// the other side of the book of a stock (a buy sweeps asks).
+func (step *ScenarioStep) Sweep(stock, direction string, levels int) *Scenario {
+	s := step.scenario
+	return step.add(fmt.Sprintf("%v sweep of %v levels on %v", direction, levels, stock), func() error {
+		venue := s.venue.Name()
+		book, err := s.venue.GetOrderbook(venue, stock)
+		if err != nil {
+			return err
+		}
+		entries := book.Asks
+		if direction == stockfighter.OrderDirectionSell {
+			entries = book.Bids
+		}
+
+		var qty uint64
+		prices := 0
+		for i, entry := range entries {
+			if i == 0 || entry.Price != entries[i-1].Price {
+				if prices == levels {
+					break
+				}
+				prices++
+			}
+			qty += entry.Quantity
+		}
+		if qty == 0 {
+			return fmt.Errorf("Nothing to sweep on %v", stock)
+		}
+		_, err = s.venue.PlaceOrder(venue, stock, TraderAccount, 0, qty, direction, stockfighter.OrderTypeMarket)
+		return err
+	})
+}
+
+// Order places an order with TraderAccount.
+func (step *ScenarioStep) Order(stock string, price, quantity uint64, direction, orderType string) *Scenario {
+	s := step.scenario
+	return step.add(fmt.Sprintf("%v %v order of %v %v at %v", orderType, direction, quantity, stock, price), func() error {
+		_, err := s.venue.PlaceOrder(s.venue.Name(), stock, TraderAccount, price, quantity, direction, orderType)
+		return err
+	})
+}
+
+// Do runs a function on the venue, for situations the other steps do not
+// cover.
+func (step *ScenarioStep) Do(description string, f func(venue *Venue) error) *Scenario {
+	s := step.scenario
+	return step.add(description, func() error { return f(s.venue) })
+}
+
+// Now returns the scenario time.
+func (s *Scenario) Now() time.Time {
+	return s.start.Add(s.elapsed)
+}
+
+// Done returns whether all the steps ran.
+func (s *Scenario) Done() bool {
+	return s.next == len(s.steps)
+}
+
+// Advance moves the scenario time forward by d, running the steps due
+// until then, at their time. Advance stops at the first step failing, and
+// returns its error.
+func (s *Scenario) Advance(d time.Duration) error {
+	end := s.elapsed + d
+	for s.next < len(s.steps) && s.steps[s.next].at <= end {
+		step := s.steps[s.next]
+		s.next++
+		if step.at > s.elapsed {
+			s.elapsed = step.at
+		}
+		s.venue.SetTime(s.Now())
+		if err := step.action(); err != nil {
+			return fmt.Errorf("Scenario step at t+%v (%v) failed: %v", step.at, step.description, err)
+		}
+	}
+	s.elapsed = end
+	s.venue.SetTime(s.Now())
+	return nil
+}
+
+// Run runs the remaining steps in real time, until they all ran, one
+// failed, or the context is done.
+func (s *Scenario) Run(ctx context.Context) error {
+	for !s.Done() {
+		wait := s.steps[s.next].at - s.elapsed
+		if wait < 0 {
+			wait = 0
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if err := s.Advance(wait); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Scenario) String() string {
+	lines := make([]string, len(s.steps))
+	for i, step := range s.steps {
+		lines[i] = fmt.Sprintf("t+%v: %v", step.at, step.description)
+	}
+	return strings.Join(lines, "\n")
+}
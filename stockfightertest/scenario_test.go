@@ -0,0 +1,52 @@
+package stockfightertest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func TestScenario(t *testing.T) {
+	start := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	venue := NewVenue("TESTEX", "FOOBAR")
+	scenario := NewScenario(venue, start).
+		At(10*time.Second).WidenSpread("FOOBAR", 50).
+		At(0).Liquidity("FOOBAR", 5000, 5010, 5, 100).
+		At(5*time.Second).Sweep("FOOBAR", stockfighter.OrderDirectionSell, 3)
+	assert.Equal(t, "t+0s: liquidity on FOOBAR: 5 levels of 100, 5000/5010\n"+
+		"t+5s: sell sweep of 3 levels on FOOBAR\n"+
+		"t+10s: spread of FOOBAR widens to 50", scenario.String())
+
+	assert.Nil(t, scenario.Advance(time.Second))
+	quote, err := venue.GetQuote("TESTEX", "FOOBAR")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5000), quote.BidPrice)
+	assert.Equal(t, uint64(500), quote.BidDepth)
+	assert.Equal(t, start.Add(time.Second), quote.QuoteTime)
+
+	assert.Nil(t, scenario.Advance(5*time.Second))
+	quote, err = venue.GetQuote("TESTEX", "FOOBAR")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4997), quote.BidPrice)
+	assert.Equal(t, uint64(200), quote.BidDepth)
+	assert.Equal(t, uint64(4998), quote.LastPrice)
+	assert.Equal(t, start.Add(5*time.Second), quote.LastTradeTime)
+	assert.False(t, scenario.Done())
+
+	assert.Nil(t, scenario.Advance(5*time.Second))
+	quote, err = venue.GetQuote("TESTEX", "FOOBAR")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4978), quote.BidPrice)
+	assert.Equal(t, uint64(5028), quote.AskPrice)
+	assert.True(t, scenario.Done())
+	assert.Equal(t, start.Add(11*time.Second), scenario.Now())
+
+	scenario = NewScenario(venue, start).At(time.Millisecond).Sweep("FOOBAR", stockfighter.OrderDirectionBuy, 10).
+		At(2 * time.Millisecond).Do("nothing", func(venue *Venue) error { return nil })
+	assert.Nil(t, scenario.Run(context.Background()))
+	assert.EqualError(t, NewScenario(venue, start).At(0).Sweep("FOOBAR", stockfighter.OrderDirectionBuy, 1).Advance(0),
+		"Scenario step at t+0s (buy sweep of 1 levels on FOOBAR) failed: Nothing to sweep on FOOBAR")
+}
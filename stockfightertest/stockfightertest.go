@@ -0,0 +1,286 @@
+/*
+Package stockfightertest provides a mock implementation of the Stockfighter
+API, to unit test code using the API without network access.
+
+Code under test should depend on the stockfighter.StockfighterAPI interface
+rather than on *stockfighter.Client:
+
+    api := &stockfightertest.API{
+        GetQuoteFunc: func(venue, stock string) (*stockfighter.Quote, error) {
+            return &stockfighter.Quote{HasBid: true, BidPrice: 5000, HasAsk: true, AskPrice: 5100}, nil
+        },
+    }
+    bot := NewBot(api)
+    // ...
+    if n := len(api.CallsTo("GetQuote")); n != 1 {
+        t.Errorf("GetQuote called %v times", n)
+    }
+
+Tests needing a market rather than canned responses can use a Venue, which
+matches orders in memory, and script it over time with a Scenario.
+*/
+package stockfightertest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// A Call represents a call of an API method.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// API is a mock implementation of stockfighter.StockfighterAPI. Each method
+// records its call and calls the function of the matching field, e.g. Ping
+// calls PingFunc; methods whose function is nil panic.
+//
+// API is safe for concurrent use, as long as its fields are not modified
+// while methods are called.
+type API struct {
+	PingFunc              func() error
+	PingVenueFunc         func(venue string) error
+	PingRTTFunc           func() (time.Duration, error)
+	PingVenueRTTFunc      func(venue string) (time.Duration, error)
+	ListStocksFunc        func(venue string) ([]stockfighter.StockInfo, error)
+	GetOrderbookFunc      func(venue, stock string) (*stockfighter.Orderbook, error)
+	StreamOrderbookFunc   func(venue, stock string, maxEntries int, fn func(entry stockfighter.OrderbookEntry) error) (*stockfighter.Orderbook, error)
+	GetOrderbookIntoFunc  func(venue, stock string, maxEntries int, book *stockfighter.Orderbook) error
+	GetOrderbookDepthFunc func(venue, stock string, n int) (*stockfighter.Orderbook, error)
+	GetQuoteFunc          func(venue, stock string) (*stockfighter.Quote, error)
+	PlaceOrderFunc        func(venue, stock, account string, price, quantity uint64, direction, orderType string) (*stockfighter.Order, error)
+	GetOrderFunc          func(venue, stock string, orderID int64) (*stockfighter.Order, error)
+	CancelOrderFunc       func(venue, stock string, orderID int64) (*stockfighter.Order, error)
+	GetAllOrdersFunc      func(venue, account string) ([]stockfighter.Order, error)
+	GetStockOrdersFunc    func(venue, account, stock string) ([]stockfighter.Order, error)
+	StartLevelFunc        func(level string) (*stockfighter.Level, error)
+	RestartLevelFunc      func(instanceID int64) (*stockfighter.Level, error)
+	ResumeLevelFunc       func(instanceID int64) (*stockfighter.Level, error)
+	StopLevelFunc         func(instanceID int64) error
+	GetLevelStatusFunc    func(instanceID int64) (*stockfighter.LevelStatus, error)
+	CancelAllOrdersFunc   func(ctx context.Context, venue, account string) ([]stockfighter.CancelResult, error)
+	PlaceOrdersFunc       func(ctx context.Context, reqs []stockfighter.OrderRequest) []stockfighter.OrderResult
+	GetQuotesFunc         func(ctx context.Context, venue string, stocks []string) (map[string]*stockfighter.Quote, error)
+	GetMarketSnapshotFunc func(ctx context.Context, venue, stock, account string) (*stockfighter.MarketSnapshot, error)
+	PlaceOCOFunc          func(ctx context.Context, first, second stockfighter.OrderRequest) (*stockfighter.OCOResult, error)
+	ExecSweepFunc         func(venue, stock, account, direction string, targetQty, limitPrice uint64) (*stockfighter.SweepResult, error)
+	WaitForFillFunc       func(ctx context.Context, venue, stock string, orderID int64) (*stockfighter.Order, error)
+	MeasureLatencyFunc    func(ctx context.Context, venue string, n int) (*stockfighter.LatencyStats, error)
+	SubscribeQuotesFunc   func(ctx context.Context, venue string, handler func(stockfighter.Quote) error) error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ stockfighter.StockfighterAPI = (*API)(nil)
+
+// Calls returns all calls made so far, in order.
+func (api *API) Calls() []Call {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return append([]Call(nil), api.calls...)
+}
+
+// CallsTo returns the calls made so far to a method, in order.
+func (api *API) CallsTo(method string) []Call {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	var calls []Call
+	for _, call := range api.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func (api *API) record(method string, mocked bool, args ...interface{}) {
+	api.mu.Lock()
+	api.calls = append(api.calls, Call{Method: method, Args: args})
+	api.mu.Unlock()
+
+	if !mocked {
+		panic(fmt.Errorf("stockfightertest: API.%vFunc is nil", method))
+	}
+}
+
+// Ping calls PingFunc.
+func (api *API) Ping() error {
+	api.record("Ping", api.PingFunc != nil)
+	return api.PingFunc()
+}
+
+// PingVenue calls PingVenueFunc.
+func (api *API) PingVenue(venue string) error {
+	api.record("PingVenue", api.PingVenueFunc != nil, venue)
+	return api.PingVenueFunc(venue)
+}
+
+// PingRTT calls PingRTTFunc.
+func (api *API) PingRTT() (time.Duration, error) {
+	api.record("PingRTT", api.PingRTTFunc != nil)
+	return api.PingRTTFunc()
+}
+
+// PingVenueRTT calls PingVenueRTTFunc.
+func (api *API) PingVenueRTT(venue string) (time.Duration, error) {
+	api.record("PingVenueRTT", api.PingVenueRTTFunc != nil, venue)
+	return api.PingVenueRTTFunc(venue)
+}
+
+// ListStocks calls ListStocksFunc.
+func (api *API) ListStocks(venue string) ([]stockfighter.StockInfo, error) {
+	api.record("ListStocks", api.ListStocksFunc != nil, venue)
+	return api.ListStocksFunc(venue)
+}
+
+// GetOrderbook calls GetOrderbookFunc.
+func (api *API) GetOrderbook(venue, stock string) (*stockfighter.Orderbook, error) {
+	api.record("GetOrderbook", api.GetOrderbookFunc != nil, venue, stock)
+	return api.GetOrderbookFunc(venue, stock)
+}
+
+// StreamOrderbook calls StreamOrderbookFunc.
+func (api *API) StreamOrderbook(venue, stock string, maxEntries int, fn func(entry stockfighter.OrderbookEntry) error) (*stockfighter.Orderbook, error) {
+	api.record("StreamOrderbook", api.StreamOrderbookFunc != nil, venue, stock, maxEntries)
+	return api.StreamOrderbookFunc(venue, stock, maxEntries, fn)
+}
+
+// GetOrderbookInto calls GetOrderbookIntoFunc.
+func (api *API) GetOrderbookInto(venue, stock string, maxEntries int, book *stockfighter.Orderbook) error {
+	api.record("GetOrderbookInto", api.GetOrderbookIntoFunc != nil, venue, stock, maxEntries, book)
+	return api.GetOrderbookIntoFunc(venue, stock, maxEntries, book)
+}
+
+// GetOrderbookDepth calls GetOrderbookDepthFunc.
+func (api *API) GetOrderbookDepth(venue, stock string, n int) (*stockfighter.Orderbook, error) {
+	api.record("GetOrderbookDepth", api.GetOrderbookDepthFunc != nil, venue, stock, n)
+	return api.GetOrderbookDepthFunc(venue, stock, n)
+}
+
+// GetQuote calls GetQuoteFunc.
+func (api *API) GetQuote(venue, stock string) (*stockfighter.Quote, error) {
+	api.record("GetQuote", api.GetQuoteFunc != nil, venue, stock)
+	return api.GetQuoteFunc(venue, stock)
+}
+
+// PlaceOrder calls PlaceOrderFunc.
+func (api *API) PlaceOrder(venue, stock, account string, price, quantity uint64, direction, orderType string) (*stockfighter.Order, error) {
+	api.record("PlaceOrder", api.PlaceOrderFunc != nil, venue, stock, account, price, quantity, direction, orderType)
+	return api.PlaceOrderFunc(venue, stock, account, price, quantity, direction, orderType)
+}
+
+// GetOrder calls GetOrderFunc.
+func (api *API) GetOrder(venue, stock string, orderID int64) (*stockfighter.Order, error) {
+	api.record("GetOrder", api.GetOrderFunc != nil, venue, stock, orderID)
+	return api.GetOrderFunc(venue, stock, orderID)
+}
+
+// CancelOrder calls CancelOrderFunc.
+func (api *API) CancelOrder(venue, stock string, orderID int64) (*stockfighter.Order, error) {
+	api.record("CancelOrder", api.CancelOrderFunc != nil, venue, stock, orderID)
+	return api.CancelOrderFunc(venue, stock, orderID)
+}
+
+// GetAllOrders calls GetAllOrdersFunc.
+func (api *API) GetAllOrders(venue, account string) ([]stockfighter.Order, error) {
+	api.record("GetAllOrders", api.GetAllOrdersFunc != nil, venue, account)
+	return api.GetAllOrdersFunc(venue, account)
+}
+
+// GetStockOrders calls GetStockOrdersFunc.
+func (api *API) GetStockOrders(venue, account, stock string) ([]stockfighter.Order, error) {
+	api.record("GetStockOrders", api.GetStockOrdersFunc != nil, venue, account, stock)
+	return api.GetStockOrdersFunc(venue, account, stock)
+}
+
+// StartLevel calls StartLevelFunc.
+func (api *API) StartLevel(level string) (*stockfighter.Level, error) {
+	api.record("StartLevel", api.StartLevelFunc != nil, level)
+	return api.StartLevelFunc(level)
+}
+
+// RestartLevel calls RestartLevelFunc.
+func (api *API) RestartLevel(instanceID int64) (*stockfighter.Level, error) {
+	api.record("RestartLevel", api.RestartLevelFunc != nil, instanceID)
+	return api.RestartLevelFunc(instanceID)
+}
+
+// ResumeLevel calls ResumeLevelFunc.
+func (api *API) ResumeLevel(instanceID int64) (*stockfighter.Level, error) {
+	api.record("ResumeLevel", api.ResumeLevelFunc != nil, instanceID)
+	return api.ResumeLevelFunc(instanceID)
+}
+
+// StopLevel calls StopLevelFunc.
+func (api *API) StopLevel(instanceID int64) error {
+	api.record("StopLevel", api.StopLevelFunc != nil, instanceID)
+	return api.StopLevelFunc(instanceID)
+}
+
+// GetLevelStatus calls GetLevelStatusFunc.
+func (api *API) GetLevelStatus(instanceID int64) (*stockfighter.LevelStatus, error) {
+	api.record("GetLevelStatus", api.GetLevelStatusFunc != nil, instanceID)
+	return api.GetLevelStatusFunc(instanceID)
+}
+
+// CancelAllOrders calls CancelAllOrdersFunc.
+func (api *API) CancelAllOrders(ctx context.Context, venue, account string) ([]stockfighter.CancelResult, error) {
+	api.record("CancelAllOrders", api.CancelAllOrdersFunc != nil, ctx, venue, account)
+	return api.CancelAllOrdersFunc(ctx, venue, account)
+}
+
+// PlaceOrders calls PlaceOrdersFunc.
+func (api *API) PlaceOrders(ctx context.Context, reqs []stockfighter.OrderRequest) []stockfighter.OrderResult {
+	api.record("PlaceOrders", api.PlaceOrdersFunc != nil, ctx, reqs)
+	return api.PlaceOrdersFunc(ctx, reqs)
+}
+
+// GetQuotes calls GetQuotesFunc.
+func (api *API) GetQuotes(ctx context.Context, venue string, stocks []string) (map[string]*stockfighter.Quote, error) {
+	api.record("GetQuotes", api.GetQuotesFunc != nil, ctx, venue, stocks)
+	return api.GetQuotesFunc(ctx, venue, stocks)
+}
+
+// GetMarketSnapshot calls GetMarketSnapshotFunc.
+func (api *API) GetMarketSnapshot(ctx context.Context, venue, stock, account string) (*stockfighter.MarketSnapshot, error) {
+	api.record("GetMarketSnapshot", api.GetMarketSnapshotFunc != nil, ctx, venue, stock, account)
+	return api.GetMarketSnapshotFunc(ctx, venue, stock, account)
+}
+
+// PlaceOCO calls PlaceOCOFunc.
+func (api *API) PlaceOCO(ctx context.Context, first, second stockfighter.OrderRequest) (*stockfighter.OCOResult, error) {
+	api.record("PlaceOCO", api.PlaceOCOFunc != nil, ctx, first, second)
+	return api.PlaceOCOFunc(ctx, first, second)
+}
+
+// ExecSweep calls ExecSweepFunc.
+func (api *API) ExecSweep(venue, stock, account, direction string, targetQty, limitPrice uint64) (*stockfighter.SweepResult, error) {
+	api.record("ExecSweep", api.ExecSweepFunc != nil, venue, stock, account, direction, targetQty, limitPrice)
+	return api.ExecSweepFunc(venue, stock, account, direction, targetQty, limitPrice)
+}
+
+// WaitForFill calls WaitForFillFunc.
+func (api *API) WaitForFill(ctx context.Context, venue, stock string, orderID int64) (*stockfighter.Order, error) {
+	api.record("WaitForFill", api.WaitForFillFunc != nil, ctx, venue, stock, orderID)
+	return api.WaitForFillFunc(ctx, venue, stock, orderID)
+}
+
+// MeasureLatency calls MeasureLatencyFunc.
+func (api *API) MeasureLatency(ctx context.Context, venue string, n int) (*stockfighter.LatencyStats, error) {
+	api.record("MeasureLatency", api.MeasureLatencyFunc != nil, ctx, venue, n)
+	return api.MeasureLatencyFunc(ctx, venue, n)
+}
+
+// SubscribeQuotes calls SubscribeQuotesFunc.
+func (api *API) SubscribeQuotes(ctx context.Context, venue string, handler func(stockfighter.Quote) error) error {
+	api.record("SubscribeQuotes", api.SubscribeQuotesFunc != nil, ctx, venue)
+	return api.SubscribeQuotesFunc(ctx, venue, handler)
+}
@@ -0,0 +1,38 @@
+package stockfightertest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func TestAPI(t *testing.T) {
+	api := &API{
+		GetQuoteFunc: func(venue, stock string) (*stockfighter.Quote, error) {
+			return &stockfighter.Quote{BidPrice: 5000}, nil
+		},
+		CancelOrderFunc: func(venue, stock string, orderID int64) (*stockfighter.Order, error) {
+			return nil, errors.New("no such order")
+		},
+	}
+
+	var client stockfighter.StockfighterAPI = api
+	quote, err := client.GetQuote("TESTEX", "FOOBAR")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5000), quote.BidPrice)
+
+	_, err = client.CancelOrder("TESTEX", "FOOBAR", 42)
+	assert.EqualError(t, err, "no such order")
+
+	assert.Panics(t, func() { client.Ping() })
+
+	assert.Equal(t, []Call{
+		{Method: "GetQuote", Args: []interface{}{"TESTEX", "FOOBAR"}},
+		{Method: "CancelOrder", Args: []interface{}{"TESTEX", "FOOBAR", int64(42)}},
+		{Method: "Ping", Args: nil},
+	}, api.Calls())
+	assert.Len(t, api.CallsTo("GetQuote"), 1)
+	assert.Len(t, api.CallsTo("PlaceOrder"), 0)
+}
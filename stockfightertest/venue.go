@@ -0,0 +1,347 @@
+package stockfightertest
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// A Venue is a fake venue matching orders in memory, with price-time
+// priority, to test code trading against a market rather than against
+// canned responses. Use Mock to get an API backed by the venue.
+//
+// Time stands still at the time set with SetTime, if any, so that tests
+// are reproducible.
+//
+// You can create a new Venue using NewVenue function.
+type Venue struct {
+	name string
+
+	mu     sync.Mutex
+	now    time.Time
+	lastID int64
+	books  map[string]*venueBook
+	orders map[int64]*stockfighter.Order
+}
+
+type venueBook struct {
+	// resting orders, in priority order
+	bids []*stockfighter.Order
+	asks []*stockfighter.Order
+
+	lastPrice uint64
+	lastSize  uint64
+	lastTrade time.Time
+}
+
+// NewVenue creates a new Venue trading the given stocks. This never returns
+// nil.
+func NewVenue(name string, stocks ...string) *Venue {
+	v := &Venue{
+		name:   name,
+		books:  make(map[string]*venueBook),
+		orders: make(map[int64]*stockfighter.Order),
+	}
+	for _, stock := range stocks {
+		v.books[stock] = &venueBook{}
+	}
+	return v
+}
+
+// Name returns the venue symbol.
+func (v *Venue) Name() string {
+	return v.name
+}
+
+// SetTime sets the time of the venue, used for order, fill, and quote
+// timestamps.
+func (v *Venue) SetTime(t time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.now = t
+}
+
+func (v *Venue) time() time.Time {
+	if v.now.IsZero() {
+		return time.Now()
+	}
+	return v.now
+}
+
+func (v *Venue) book(venue, stock string) (*venueBook, error) {
+	if venue != v.name {
+		return nil, &stockfighter.ErrorVenueNotFound{VenueSymbol: venue}
+	}
+	book, ok := v.books[stock]
+	if !ok {
+		return nil, &stockfighter.ErrorStockNotFound{VenueSymbol: venue, StockSymbol: stock}
+	}
+	return book, nil
+}
+
+// ListStocks returns the stocks of the venue.
+func (v *Venue) ListStocks(venue string) ([]stockfighter.StockInfo, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if venue != v.name {
+		return nil, &stockfighter.ErrorVenueNotFound{VenueSymbol: venue}
+	}
+	stocks := make([]stockfighter.StockInfo, 0, len(v.books))
+	for stock := range v.books {
+		stocks = append(stocks, stockfighter.StockInfo{Symbol: stock})
+	}
+	sort.Slice(stocks, func(i, j int) bool { return stocks[i].Symbol < stocks[j].Symbol })
+	return stocks, nil
+}
+
+// GetOrderbook returns the orderbook of a stock, with one entry per resting
+// order.
+func (v *Venue) GetOrderbook(venue, stock string) (*stockfighter.Orderbook, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	book, err := v.book(venue, stock)
+	if err != nil {
+		return nil, err
+	}
+	orderbook := &stockfighter.Orderbook{Venue: venue, Symbol: stock, Timestamp: v.time()}
+	for _, order := range book.bids {
+		orderbook.Bids = append(orderbook.Bids, stockfighter.OrderbookEntry{Price: order.Price, Quantity: order.Quantity, IsBuy: true})
+	}
+	for _, order := range book.asks {
+		orderbook.Asks = append(orderbook.Asks, stockfighter.OrderbookEntry{Price: order.Price, Quantity: order.Quantity})
+	}
+	return orderbook, nil
+}
+
+// GetQuote returns the quote of a stock.
+func (v *Venue) GetQuote(venue, stock string) (*stockfighter.Quote, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	book, err := v.book(venue, stock)
+	if err != nil {
+		return nil, err
+	}
+	quote := &stockfighter.Quote{Venue: venue, Symbol: stock, LastPrice: book.lastPrice, LastSize: book.lastSize,
+		LastTradeTime: book.lastTrade, QuoteTime: v.time()}
+	for _, order := range book.bids {
+		if !quote.HasBid {
+			quote.HasBid, quote.BidPrice = true, order.Price
+		}
+		if order.Price == quote.BidPrice {
+			quote.BidSize += order.Quantity
+		}
+		quote.BidDepth += order.Quantity
+	}
+	for _, order := range book.asks {
+		if !quote.HasAsk {
+			quote.HasAsk, quote.AskPrice = true, order.Price
+		}
+		if order.Price == quote.AskPrice {
+			quote.AskSize += order.Quantity
+		}
+		quote.AskDepth += order.Quantity
+	}
+	return quote, nil
+}
+
+// PlaceOrder places an order, matching it against the other side of the
+// book.
+func (v *Venue) PlaceOrder(venue, stock, account string, price, quantity uint64, direction, orderType string) (*stockfighter.Order, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	book, err := v.book(venue, stock)
+	if err != nil {
+		return nil, err
+	}
+	if quantity == 0 {
+		return nil, &stockfighter.ErrorInvalidOrder{Reason: "zero quantity"}
+	}
+	if direction != stockfighter.OrderDirectionBuy && direction != stockfighter.OrderDirectionSell {
+		return nil, &stockfighter.ErrorInvalidOrder{Reason: fmt.Sprintf("unknown direction %q", direction)}
+	}
+
+	now := v.time()
+	v.lastID++
+	order := &stockfighter.Order{Venue: venue, Symbol: stock, Direction: direction, OriginalQuantity: quantity, Quantity: quantity,
+		Price: price, OrderType: orderType, OrderID: v.lastID, Account: account, Timestamp: now, Open: true}
+	v.orders[order.OrderID] = order
+
+	opposite := &book.asks
+	crosses := func(resting uint64) bool { return resting <= price }
+	if direction == stockfighter.OrderDirectionSell {
+		opposite = &book.bids
+		crosses = func(resting uint64) bool { return resting >= price }
+	}
+	if orderType == stockfighter.OrderTypeMarket {
+		crosses = func(uint64) bool { return true }
+	}
+
+	if orderType == stockfighter.OrderTypeFillOrKill {
+		var available uint64
+		for _, resting := range *opposite {
+			if !crosses(resting.Price) {
+				break
+			}
+			available += resting.Quantity
+		}
+		if available < quantity {
+			order.Quantity, order.Open = 0, false
+			return copyOrder(order), nil
+		}
+	}
+
+	for len(*opposite) > 0 && order.Quantity > 0 && crosses((*opposite)[0].Price) {
+		resting := (*opposite)[0]
+		qty := order.Quantity
+		if resting.Quantity < qty {
+			qty = resting.Quantity
+		}
+		fill := stockfighter.OrderFillInfo{Price: resting.Price, Quantity: qty, Timestamp: now}
+		for _, o := range []*stockfighter.Order{order, resting} {
+			o.Fills = append(o.Fills, fill)
+			o.TotalFilled += qty
+			o.Quantity -= qty
+			o.Open = o.Quantity > 0
+		}
+		if !resting.Open {
+			*opposite = (*opposite)[1:]
+		}
+		book.lastPrice, book.lastSize, book.lastTrade = resting.Price, qty, now
+	}
+
+	if order.Quantity > 0 {
+		if orderType == stockfighter.OrderTypeLimit {
+			book.rest(order)
+		} else {
+			order.Quantity, order.Open = 0, false
+		}
+	}
+	return copyOrder(order), nil
+}
+
+// rest adds an order to its side of the book, behind the orders at the same
+// price.
+func (book *venueBook) rest(order *stockfighter.Order) {
+	side := &book.bids
+	better := func(a, b uint64) bool { return a > b }
+	if order.Direction == stockfighter.OrderDirectionSell {
+		side = &book.asks
+		better = func(a, b uint64) bool { return a < b }
+	}
+
+	i := sort.Search(len(*side), func(i int) bool { return better(order.Price, (*side)[i].Price) })
+	*side = append(*side, nil)
+	copy((*side)[i+1:], (*side)[i:])
+	(*side)[i] = order
+}
+
+// GetOrder returns the status of an order.
+func (v *Venue) GetOrder(venue, stock string, orderID int64) (*stockfighter.Order, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	order, err := v.order(venue, stock, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return copyOrder(order), nil
+}
+
+// CancelOrder cancels an order.
+func (v *Venue) CancelOrder(venue, stock string, orderID int64) (*stockfighter.Order, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	order, err := v.order(venue, stock, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Open {
+		book := v.books[stock]
+		for _, side := range []*[]*stockfighter.Order{&book.bids, &book.asks} {
+			for i, resting := range *side {
+				if resting == order {
+					*side = append((*side)[:i], (*side)[i+1:]...)
+					break
+				}
+			}
+		}
+		order.Quantity, order.Open = 0, false
+	}
+	return copyOrder(order), nil
+}
+
+func (v *Venue) order(venue, stock string, orderID int64) (*stockfighter.Order, error) {
+	if _, err := v.book(venue, stock); err != nil {
+		return nil, err
+	}
+	order, ok := v.orders[orderID]
+	if !ok || order.Symbol != stock {
+		return nil, fmt.Errorf("No such order: %v", orderID)
+	}
+	return order, nil
+}
+
+// GetAllOrders returns the orders of an account, in ID order.
+func (v *Venue) GetAllOrders(venue, account string) ([]stockfighter.Order, error) {
+	return v.accountOrders(venue, account, "")
+}
+
+// GetStockOrders returns the orders of an account on a stock, in ID order.
+func (v *Venue) GetStockOrders(venue, account, stock string) ([]stockfighter.Order, error) {
+	return v.accountOrders(venue, account, stock)
+}
+
+func (v *Venue) accountOrders(venue, account, stock string) ([]stockfighter.Order, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if venue != v.name {
+		return nil, &stockfighter.ErrorVenueNotFound{VenueSymbol: venue}
+	}
+	orders := []stockfighter.Order{}
+	for _, order := range v.orders {
+		if order.Account == account && (stock == "" || order.Symbol == stock) {
+			orders = append(orders, *copyOrder(order))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].OrderID < orders[j].OrderID })
+	return orders, nil
+}
+
+// Mock returns an API backed by the venue for the methods it implements:
+// PingVenue, ListStocks, GetOrderbook, GetQuote, PlaceOrder, GetOrder,
+// CancelOrder, GetAllOrders, and GetStockOrders. Other functions can be set
+// on the returned API.
+func (v *Venue) Mock() *API {
+	return &API{
+		PingVenueFunc: func(venue string) error {
+			if venue != v.name {
+				return &stockfighter.ErrorVenueNotFound{VenueSymbol: venue}
+			}
+			return nil
+		},
+		ListStocksFunc:     v.ListStocks,
+		GetOrderbookFunc:   v.GetOrderbook,
+		GetQuoteFunc:       v.GetQuote,
+		PlaceOrderFunc:     v.PlaceOrder,
+		GetOrderFunc:       v.GetOrder,
+		CancelOrderFunc:    v.CancelOrder,
+		GetAllOrdersFunc:   v.GetAllOrders,
+		GetStockOrdersFunc: v.GetStockOrders,
+	}
+}
+
+func copyOrder(order *stockfighter.Order) *stockfighter.Order {
+	result := *order
+	result.Fills = append([]stockfighter.OrderFillInfo{}, order.Fills...)
+	return &result
+}
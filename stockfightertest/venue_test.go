@@ -0,0 +1,66 @@
+package stockfightertest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func TestVenue(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	venue := NewVenue("TESTEX", "FOOBAR")
+	venue.SetTime(ts)
+	api := venue.Mock()
+
+	_, err := api.PlaceOrder("TESTEX", "FOOBAR", "MM", 5010, 100, stockfighter.OrderDirectionSell, stockfighter.OrderTypeLimit)
+	assert.Nil(t, err)
+	_, err = api.PlaceOrder("TESTEX", "FOOBAR", "MM", 5020, 100, stockfighter.OrderDirectionSell, stockfighter.OrderTypeLimit)
+	assert.Nil(t, err)
+	ask, err := api.PlaceOrder("TESTEX", "FOOBAR", "MM2", 5010, 50, stockfighter.OrderDirectionSell, stockfighter.OrderTypeLimit)
+	assert.Nil(t, err)
+	_, err = api.PlaceOrder("TESTEX", "FOOBAR", "MM", 5000, 100, stockfighter.OrderDirectionBuy, stockfighter.OrderTypeLimit)
+	assert.Nil(t, err)
+
+	quote, err := api.GetQuote("TESTEX", "FOOBAR")
+	assert.Nil(t, err)
+	assert.Equal(t, &stockfighter.Quote{Venue: "TESTEX", Symbol: "FOOBAR", HasBid: true, BidPrice: 5000, BidSize: 100, BidDepth: 100,
+		HasAsk: true, AskPrice: 5010, AskSize: 150, AskDepth: 250, QuoteTime: ts}, quote)
+
+	// fills at 5010 in time priority, then at 5020
+	order, err := api.PlaceOrder("TESTEX", "FOOBAR", "BOT", 5020, 170, stockfighter.OrderDirectionBuy, stockfighter.OrderTypeLimit)
+	assert.Nil(t, err)
+	assert.Equal(t, []stockfighter.OrderFillInfo{{Price: 5010, Quantity: 100, Timestamp: ts}, {Price: 5010, Quantity: 50, Timestamp: ts},
+		{Price: 5020, Quantity: 20, Timestamp: ts}}, order.Fills)
+	assert.False(t, order.Open)
+	ask, err = api.GetOrder("TESTEX", "FOOBAR", ask.OrderID)
+	assert.Nil(t, err)
+	assert.False(t, ask.Open)
+
+	order, err = api.PlaceOrder("TESTEX", "FOOBAR", "BOT", 5020, 100, stockfighter.OrderDirectionBuy, stockfighter.OrderTypeFillOrKill)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), order.TotalFilled)
+	order, err = api.PlaceOrder("TESTEX", "FOOBAR", "BOT", 5005, 10, stockfighter.OrderDirectionBuy, stockfighter.OrderTypeLimit)
+	assert.Nil(t, err)
+	assert.True(t, order.Open)
+
+	book, err := api.GetOrderbook("TESTEX", "FOOBAR")
+	assert.Nil(t, err)
+	assert.Equal(t, []stockfighter.OrderbookEntry{{Price: 5005, Quantity: 10, IsBuy: true}, {Price: 5000, Quantity: 100, IsBuy: true}}, book.Bids)
+	assert.Equal(t, []stockfighter.OrderbookEntry{{Price: 5020, Quantity: 80}}, book.Asks)
+
+	order, err = api.CancelOrder("TESTEX", "FOOBAR", order.OrderID)
+	assert.Nil(t, err)
+	assert.False(t, order.Open)
+	orders, err := api.GetAllOrders("TESTEX", "BOT")
+	assert.Nil(t, err)
+	assert.Len(t, orders, 3)
+
+	_, err = api.GetQuote("TESTEX", "BARBAZ")
+	assert.EqualError(t, err, (&stockfighter.ErrorStockNotFound{VenueSymbol: "TESTEX", StockSymbol: "BARBAZ"}).Error())
+	_, err = api.PlaceOrder("TESTEX", "FOOBAR", "BOT", 5005, 0, stockfighter.OrderDirectionBuy, stockfighter.OrderTypeLimit)
+	assert.EqualError(t, err, "Invalid order: zero quantity")
+	_, err = api.GetOrder("TESTEX", "FOOBAR", 42)
+	assert.EqualError(t, err, "No such order: 42")
+}
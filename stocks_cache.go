@@ -0,0 +1,78 @@
+package stockfighter
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStocksTTL is the default time-to-live of the stock lists cached by
+// ListStocks.
+const DefaultStocksTTL = 10 * time.Minute
+
+// WithStocksTTL sets the time-to-live of the stock lists cached by ListStocks
+// (DefaultStocksTTL by default). A non-positive ttl disables caching.
+func WithStocksTTL(ttl time.Duration) ClientOption {
+	return func(client *Client) {
+		client.stocks.ttl = ttl
+	}
+}
+
+// InvalidateStocks drops the cached stock list of a venue, so that the next
+// ListStocks call fetches it again.
+func (client *Client) InvalidateStocks(venue string) {
+	client.stocks.invalidate(strings.TrimSpace(venue))
+}
+
+type cachedStocks struct {
+	stocks    []StockInfo
+	fetchedAt time.Time
+}
+
+// stocksCache caches stock lists per venue. Venue stock lists essentially
+// never change during a level.
+type stocksCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu     sync.Mutex
+	venues map[string]cachedStocks
+}
+
+func newStocksCache() *stocksCache {
+	return &stocksCache{
+		ttl:    DefaultStocksTTL,
+		now:    time.Now,
+		venues: make(map[string]cachedStocks),
+	}
+}
+
+func (cache *stocksCache) get(venue string) ([]StockInfo, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cached, ok := cache.venues[venue]
+	if !ok || cache.now().Sub(cached.fetchedAt) >= cache.ttl {
+		return nil, false
+	}
+	// callers may modify the list they get
+	return append([]StockInfo(nil), cached.stocks...), true
+}
+
+func (cache *stocksCache) put(venue string, stocks []StockInfo) {
+	if cache.ttl <= 0 {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.venues[venue] = cachedStocks{stocks: append([]StockInfo(nil), stocks...), fetchedAt: cache.now()}
+}
+
+func (cache *stocksCache) invalidate(venue string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	delete(cache.venues, venue)
+}
@@ -0,0 +1,44 @@
+package stockfighter
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStocksCache(t *testing.T) {
+	var requests int
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"ok": true, "symbols": [{"name": "Foreign Owned Occluded Bridge Architecture Resources", "symbol": "FOO%d"}]}`, requests)
+	})
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	client.stocks.now = func() time.Time { return ts }
+
+	stocks, err := client.ListStocks(testVenue)
+	assert.Nil(t, err)
+	assert.Equal(t, "FOO1", stocks[0].Symbol)
+	stocks[0].Symbol = "BAR" // must not alter the cached list
+
+	stocks, _ = client.Subsystem("other").ListStocks(testVenue)
+	assert.Equal(t, "FOO1", stocks[0].Symbol)
+	assert.Equal(t, 1, requests)
+
+	client.InvalidateStocks(testVenue)
+	stocks, _ = client.ListStocks(testVenue)
+	assert.Equal(t, "FOO2", stocks[0].Symbol)
+
+	ts = ts.Add(DefaultStocksTTL)
+	stocks, _ = client.ListStocks(testVenue)
+	assert.Equal(t, "FOO3", stocks[0].Symbol)
+	assert.Equal(t, 3, requests)
+
+	// caching disabled
+	WithStocksTTL(0)(client)
+	client.ListStocks(testVenue)
+	client.ListStocks(testVenue)
+	assert.Equal(t, 5, requests)
+}
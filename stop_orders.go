@@ -0,0 +1,237 @@
+package stockfighter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A StopOrder represents an order armed in a StopOrderManager.
+//
+// A sell stop triggers when the last trade price falls to or below
+// TriggerPrice, a buy stop when it rises to or above TriggerPrice. A triggered
+// stop places a market order, or a limit order at LimitPrice if it is set.
+type StopOrder struct {
+	ID           int64  `json:"id"`
+	Stock        string `json:"symbol"`
+	Direction    string `json:"direction"`
+	Quantity     uint64 `json:"qty"`
+	TriggerPrice uint64 `json:"triggerPrice"`
+	LimitPrice   uint64 `json:"limitPrice,omitempty"`
+}
+
+func (s StopOrder) triggered(lastPrice uint64) bool {
+	if s.Direction == OrderDirectionBuy {
+		return lastPrice >= s.TriggerPrice
+	}
+	return lastPrice <= s.TriggerPrice
+}
+
+// A StopEvent represents a stop order triggered by a StopOrderManager.
+//
+// Either Order (the order placed) or Err is set.
+type StopEvent struct {
+	Stop  StopOrder
+	Order *Order
+	Err   error
+}
+
+// A StopOrderManager emulates stop-loss and stop-limit orders, which
+// Stockfighter does not support natively, by polling quotes of the stocks with
+// armed stops.
+//
+// You can create a new StopOrderManager using NewStopOrderManager function.
+type StopOrderManager struct {
+	client  *Client
+	venue   string
+	account string
+
+	// Quote polling interval (DefaultPollInterval if not positive)
+	Interval time.Duration
+
+	mu     sync.Mutex
+	nextID int64
+	stops  map[int64]StopOrder
+}
+
+// NewStopOrderManager creates a new StopOrderManager placing orders for an
+// account. This never returns nil.
+func NewStopOrderManager(client *Client, venue, account string) *StopOrderManager {
+	venue = strings.TrimSpace(venue)
+	if !validSymbol(venue) {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+
+	account = strings.TrimSpace(account)
+	if !validSymbol(account) {
+		panic(fmt.Errorf("Invalid account name: %v", account))
+	}
+
+	return &StopOrderManager{
+		client:   client,
+		venue:    venue,
+		account:  account,
+		Interval: DefaultPollInterval,
+		nextID:   1,
+		stops:    make(map[int64]StopOrder),
+	}
+}
+
+// Arm arms a stop order and returns its ID. A zero limitPrice arms a stop-loss
+// (market) order, otherwise a stop-limit order.
+func (manager *StopOrderManager) Arm(stock, direction string, quantity, triggerPrice, limitPrice uint64) (int64, error) {
+	stock = strings.TrimSpace(stock)
+	if !validSymbol(stock) {
+		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
+	}
+
+	if direction != OrderDirectionBuy && direction != OrderDirectionSell {
+		return 0, fmt.Errorf("Invalid order direction: %v", direction)
+	}
+	if quantity == 0 {
+		return 0, fmt.Errorf("Invalid order quantity: %v", quantity)
+	}
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	id := manager.nextID
+	manager.nextID++
+	manager.stops[id] = StopOrder{
+		ID:           id,
+		Stock:        stock,
+		Direction:    direction,
+		Quantity:     quantity,
+		TriggerPrice: triggerPrice,
+		LimitPrice:   limitPrice,
+	}
+
+	return id, nil
+}
+
+// Cancel disarms a stop order. It returns false if no stop order with the ID
+// is armed (e.g. it was triggered already).
+func (manager *StopOrderManager) Cancel(id int64) bool {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	_, ok := manager.stops[id]
+	delete(manager.stops, id)
+	return ok
+}
+
+// Stops returns the armed stop orders, ordered by ID.
+func (manager *StopOrderManager) Stops() []StopOrder {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	stops := make([]StopOrder, 0, len(manager.stops))
+	for _, stop := range manager.stops {
+		stops = append(stops, stop)
+	}
+	sort.Slice(stops, func(i, j int) bool { return stops[i].ID < stops[j].ID })
+	return stops
+}
+
+// Save writes the armed stop orders to w as JSON.
+func (manager *StopOrderManager) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(manager.Stops())
+}
+
+// Load arms the stop orders previously written by Save, replacing all
+// currently armed stop orders.
+func (manager *StopOrderManager) Load(r io.Reader) error {
+	var stops []StopOrder
+	if err := json.NewDecoder(r).Decode(&stops); err != nil {
+		return err
+	}
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	manager.stops = make(map[int64]StopOrder, len(stops))
+	for _, stop := range stops {
+		manager.stops[stop.ID] = stop
+		if stop.ID >= manager.nextID {
+			manager.nextID = stop.ID + 1
+		}
+	}
+
+	return nil
+}
+
+// Start starts monitoring quotes in a new goroutine and returns the channel
+// triggered stops are delivered on. A triggered stop is disarmed whether or
+// not its order could be placed.
+//
+// Monitoring stops and the channel is closed when ctx is done.
+func (manager *StopOrderManager) Start(ctx context.Context) <-chan StopEvent {
+	events := make(chan StopEvent)
+	interval := manager.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for _, event := range manager.check() {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+// check polls the quotes of stocks with armed stops and fires the stops
+// triggered.
+func (manager *StopOrderManager) check() []StopEvent {
+	stocks := make(map[string]bool)
+	for _, stop := range manager.Stops() {
+		stocks[stop.Stock] = true
+	}
+
+	var events []StopEvent
+	for stock := range stocks {
+		quote, err := manager.client.GetQuote(manager.venue, stock)
+		if err != nil || quote.LastPrice == 0 {
+			continue
+		}
+
+		for _, stop := range manager.Stops() {
+			if stop.Stock != stock || !stop.triggered(quote.LastPrice) || !manager.Cancel(stop.ID) {
+				continue
+			}
+
+			var order *Order
+			if stop.LimitPrice == 0 {
+				order, err = manager.client.PlaceOrder(manager.venue, stock, manager.account, 0, stop.Quantity, stop.Direction, OrderTypeMarket)
+			} else {
+				order, err = manager.client.PlaceOrder(manager.venue, stock, manager.account, stop.LimitPrice, stop.Quantity, stop.Direction, OrderTypeLimit)
+			}
+			events = append(events, StopEvent{Stop: stop, Order: order, Err: err})
+		}
+	}
+
+	return events
+}
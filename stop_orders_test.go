@@ -0,0 +1,69 @@
+package stockfighter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStopOrderManager(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"ok": true, "last": 95}`))
+			return
+		}
+
+		var req map[string]interface{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+		fmt.Fprintf(w, `{"ok": true, "id": 7, "direction": %q, "orderType": %q, "price": %v, "originalQty": %v}`,
+			req["direction"], req["orderType"], req["price"], req["qty"])
+	})
+
+	manager := NewStopOrderManager(client, testVenue, testAccount)
+	manager.Interval = time.Millisecond
+
+	sellStop, err := manager.Arm(testStock, OrderDirectionSell, 10, 96, 0)
+	assert.Nil(t, err)
+	buyStop, err := manager.Arm(testStock, OrderDirectionBuy, 10, 100, 0)
+	assert.Nil(t, err)
+	_, err = manager.Arm(testStock, OrderDirectionSell, 0, 96, 0)
+	assert.NotNil(t, err)
+
+	// save and restore
+	var buf bytes.Buffer
+	assert.Nil(t, manager.Save(&buf))
+	manager = NewStopOrderManager(client, testVenue, testAccount)
+	manager.Interval = time.Millisecond
+	assert.Nil(t, manager.Load(&buf))
+	assert.Len(t, manager.Stops(), 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	event := <-manager.Start(ctx)
+	assert.Nil(t, event.Err)
+	assert.Equal(t, sellStop, event.Stop.ID)
+	assert.Equal(t, OrderTypeMarket, event.Order.OrderType)
+	assert.Equal(t, uint64(10), event.Order.OriginalQuantity)
+
+	assert.Equal(t, []StopOrder{{ID: buyStop, Stock: testStock, Direction: OrderDirectionBuy, Quantity: 10, TriggerPrice: 100}}, manager.Stops())
+	assert.True(t, manager.Cancel(buyStop))
+	assert.False(t, manager.Cancel(buyStop))
+}
+
+func TestStopOrderManagerDefaultInterval(t *testing.T) {
+	manager := NewStopOrderManager(NewClient(testApiKey), testVenue, testAccount)
+	manager.Interval = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range manager.Start(ctx) {
+		// no stops are armed
+	}
+}
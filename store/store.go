@@ -0,0 +1,281 @@
+/*
+Package store persists orders, fills, positions, and blotter events to a SQLite
+database, so that a bot restarted in the middle of a level can resume with an
+accurate position instead of starting blind.
+
+The database is created on first use, and uses the pure-Go SQLite driver, so
+no cgo is needed:
+
+    s, err := store.Open("level.db")
+    if err != nil {
+        log.Fatal(err)
+    }
+    defer s.Close()
+
+    // on every order status retrieved
+    s.SaveOrder(order)
+
+    // on restart
+    orders, err := s.Orders(venue, account, stock)
+    position := stockfighter.PositionFromOrders(orders)
+
+Blotter events are persisted as they are logged by setting Blotter.OnEvent to
+a function calling SaveEvent.
+*/
+package store
+
+import (
+	"database/sql"
+	"time"
+
+	"gpk.io/stockfighter"
+	_ "modernc.org/sqlite"
+)
+
+// DriverName is the database/sql driver name of the SQLite driver.
+const DriverName = "sqlite"
+
+const schema = `
+CREATE TABLE IF NOT EXISTS orders (
+	venue TEXT NOT NULL,
+	id INTEGER NOT NULL,
+	symbol TEXT NOT NULL,
+	account TEXT NOT NULL,
+	direction TEXT NOT NULL,
+	order_type TEXT NOT NULL,
+	price INTEGER NOT NULL,
+	original_qty INTEGER NOT NULL,
+	qty INTEGER NOT NULL,
+	total_filled INTEGER NOT NULL,
+	open INTEGER NOT NULL,
+	ts TEXT NOT NULL,
+	PRIMARY KEY (venue, id)
+);
+CREATE TABLE IF NOT EXISTS fills (
+	venue TEXT NOT NULL,
+	order_id INTEGER NOT NULL,
+	seq INTEGER NOT NULL,
+	price INTEGER NOT NULL,
+	qty INTEGER NOT NULL,
+	ts TEXT NOT NULL,
+	PRIMARY KEY (venue, order_id, seq)
+);
+CREATE TABLE IF NOT EXISTS positions (
+	account TEXT NOT NULL,
+	venue TEXT NOT NULL,
+	symbol TEXT NOT NULL,
+	shares INTEGER NOT NULL,
+	cash INTEGER NOT NULL,
+	PRIMARY KEY (account, venue, symbol)
+);
+CREATE TABLE IF NOT EXISTS blotter (
+	seq INTEGER PRIMARY KEY AUTOINCREMENT,
+	time TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	venue TEXT NOT NULL,
+	symbol TEXT NOT NULL,
+	order_id INTEGER NOT NULL,
+	direction TEXT NOT NULL,
+	order_type TEXT NOT NULL,
+	state TEXT NOT NULL,
+	price INTEGER NOT NULL,
+	qty INTEGER NOT NULL
+);
+`
+
+// A Store is a SQLite database holding trading state. A Store is safe for
+// concurrent use.
+//
+// You can create a new Store using Open or New functions.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the SQLite database at path, creating it if needed. The path
+// ":memory:" opens a new in-memory database.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open(DriverName, path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite serializes writes anyway, and every connection to ":memory:"
+	// opens a distinct database
+	db.SetMaxOpenConns(1)
+
+	s, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// New creates a new Store using an open database, creating the tables that do
+// not exist yet.
+func New(db *sql.DB) (*Store, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveOrder saves a status of an order, replacing any previous status of the
+// order, along with its fills.
+func (s *Store) SaveOrder(order *stockfighter.Order) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT OR REPLACE INTO orders
+		(id, venue, symbol, account, direction, order_type, price, original_qty, qty, total_filled, open, ts)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		order.OrderID, order.Venue, order.Symbol, order.Account, order.Direction, order.OrderType, int64(order.Price),
+		int64(order.OriginalQuantity), int64(order.Quantity), int64(order.TotalFilled), order.Open, formatTime(order.Timestamp))
+	if err != nil {
+		return err
+	}
+
+	for i, fill := range order.Fills {
+		_, err := tx.Exec(`INSERT OR IGNORE INTO fills (venue, order_id, seq, price, qty, ts) VALUES (?, ?, ?, ?, ?, ?)`,
+			order.Venue, order.OrderID, i, int64(fill.Price), int64(fill.Quantity), formatTime(fill.Timestamp))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Orders returns the last saved status of the orders of an account for a
+// stock, with their fills, by order ID.
+func (s *Store) Orders(venue, account, stock string) ([]stockfighter.Order, error) {
+	rows, err := s.db.Query(`SELECT id, direction, order_type, price, original_qty, qty, total_filled, open, ts
+		FROM orders WHERE venue = ? AND account = ? AND symbol = ? ORDER BY id`, venue, account, stock)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []stockfighter.Order
+	for rows.Next() {
+		order := stockfighter.Order{Venue: venue, Symbol: stock, Account: account}
+		var price, originalQty, qty, totalFilled int64
+		var ts string
+		err := rows.Scan(&order.OrderID, &order.Direction, &order.OrderType, &price, &originalQty, &qty, &totalFilled, &order.Open, &ts)
+		if err != nil {
+			return nil, err
+		}
+		order.Price, order.OriginalQuantity, order.Quantity, order.TotalFilled = uint64(price), uint64(originalQty), uint64(qty), uint64(totalFilled)
+		if order.Timestamp, err = parseTime(ts); err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range orders {
+		if orders[i].Fills, err = s.fills(venue, orders[i].OrderID); err != nil {
+			return nil, err
+		}
+	}
+
+	return orders, nil
+}
+
+func (s *Store) fills(venue string, orderID int64) ([]stockfighter.OrderFillInfo, error) {
+	rows, err := s.db.Query(`SELECT price, qty, ts FROM fills WHERE venue = ? AND order_id = ? ORDER BY seq`, venue, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fills []stockfighter.OrderFillInfo
+	for rows.Next() {
+		var price, qty int64
+		var ts string
+		if err := rows.Scan(&price, &qty, &ts); err != nil {
+			return nil, err
+		}
+		t, err := parseTime(ts)
+		if err != nil {
+			return nil, err
+		}
+		fills = append(fills, stockfighter.OrderFillInfo{Price: uint64(price), Quantity: uint64(qty), Timestamp: t})
+	}
+	return fills, rows.Err()
+}
+
+// SavePosition saves the position of an account in a stock.
+func (s *Store) SavePosition(venue, account, stock string, position stockfighter.Position) error {
+	_, err := s.db.Exec(`INSERT OR REPLACE INTO positions (account, venue, symbol, shares, cash) VALUES (?, ?, ?, ?, ?)`,
+		account, venue, stock, position.Shares, position.Cash)
+	return err
+}
+
+// Position returns the saved position of an account in a stock. ok is false
+// if no position was saved.
+func (s *Store) Position(venue, account, stock string) (position stockfighter.Position, ok bool, err error) {
+	err = s.db.QueryRow(`SELECT shares, cash FROM positions WHERE account = ? AND venue = ? AND symbol = ?`, account, venue, stock).
+		Scan(&position.Shares, &position.Cash)
+	if err == sql.ErrNoRows {
+		return stockfighter.Position{}, false, nil
+	} else if err != nil {
+		return stockfighter.Position{}, false, err
+	}
+	return position, true, nil
+}
+
+// SaveEvent appends an event to the saved blotter.
+func (s *Store) SaveEvent(event stockfighter.BlotterEvent) error {
+	_, err := s.db.Exec(`INSERT INTO blotter (time, kind, venue, symbol, order_id, direction, order_type, state, price, qty)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		formatTime(event.Time), event.Kind, event.Venue, event.Symbol, event.OrderID, event.Direction, event.OrderType, event.State,
+		int64(event.Price), int64(event.Quantity))
+	return err
+}
+
+// Events returns the saved blotter events, in the order they were saved.
+func (s *Store) Events() ([]stockfighter.BlotterEvent, error) {
+	rows, err := s.db.Query(`SELECT time, kind, venue, symbol, order_id, direction, order_type, state, price, qty
+		FROM blotter ORDER BY seq`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []stockfighter.BlotterEvent
+	for rows.Next() {
+		var event stockfighter.BlotterEvent
+		var ts string
+		var price, qty int64
+		err := rows.Scan(&ts, &event.Kind, &event.Venue, &event.Symbol, &event.OrderID, &event.Direction, &event.OrderType, &event.State,
+			&price, &qty)
+		if err != nil {
+			return nil, err
+		}
+		if event.Time, err = parseTime(ts); err != nil {
+			return nil, err
+		}
+		event.Price, event.Quantity = uint64(price), uint64(qty)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// Times are stored as RFC 3339 text, which SQLite date functions understand.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, s)
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func TestOrders(t *testing.T) {
+	s, err := Open(":memory:")
+	assert.Nil(t, err)
+	defer s.Close()
+
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 680986000, time.UTC)
+	order := &stockfighter.Order{
+		Venue: "TESTEX", Symbol: "FOOBAR", Account: "EXB123456", OrderID: 1,
+		Direction: stockfighter.OrderDirectionBuy, OrderType: stockfighter.OrderTypeLimit,
+		Price: 5000, OriginalQuantity: 100, Quantity: 100, Open: true, Timestamp: ts,
+	}
+	assert.Nil(t, s.SaveOrder(order))
+
+	// a later status replaces the saved one
+	order.Quantity, order.TotalFilled = 60, 40
+	order.Fills = []stockfighter.OrderFillInfo{{Price: 5000, Quantity: 40, Timestamp: ts.Add(time.Second)}}
+	assert.Nil(t, s.SaveOrder(order))
+
+	// order IDs are only unique within a venue
+	other := *order
+	other.Venue, other.Fills = "OTHEREX", []stockfighter.OrderFillInfo{{Price: 4000, Quantity: 10, Timestamp: ts}}
+	assert.Nil(t, s.SaveOrder(&other))
+
+	orders, err := s.Orders("TESTEX", "EXB123456", "FOOBAR")
+	assert.Nil(t, err)
+	assert.Equal(t, []stockfighter.Order{*order}, orders)
+
+	orders, err = s.Orders("OTHEREX", "EXB123456", "FOOBAR")
+	assert.Nil(t, err)
+	assert.Equal(t, []stockfighter.Order{other}, orders)
+
+	orders, err = s.Orders("TESTEX", "EXB123456", "BARBAZ")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(orders))
+}
+
+func TestPosition(t *testing.T) {
+	s, err := Open(":memory:")
+	assert.Nil(t, err)
+	defer s.Close()
+
+	_, ok, err := s.Position("TESTEX", "EXB123456", "FOOBAR")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, s.SavePosition("TESTEX", "EXB123456", "FOOBAR", stockfighter.Position{Shares: 10, Cash: -50000}))
+	assert.Nil(t, s.SavePosition("TESTEX", "EXB123456", "FOOBAR", stockfighter.Position{Shares: -5, Cash: 25000}))
+
+	position, ok, err := s.Position("TESTEX", "EXB123456", "FOOBAR")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, stockfighter.Position{Shares: -5, Cash: 25000}, position)
+}
+
+func TestEvents(t *testing.T) {
+	s, err := Open(":memory:")
+	assert.Nil(t, err)
+	defer s.Close()
+
+	events := []stockfighter.BlotterEvent{
+		{Time: time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC), Kind: stockfighter.BlotterEventOrder, Venue: "TESTEX", Symbol: "FOOBAR",
+			OrderID: 1, Direction: stockfighter.OrderDirectionBuy, OrderType: stockfighter.OrderTypeLimit, State: "open", Price: 5000, Quantity: 100},
+		{Time: time.Date(2015, 12, 4, 9, 2, 17, 0, time.UTC), Kind: stockfighter.BlotterEventFill, Venue: "TESTEX", Symbol: "FOOBAR",
+			OrderID: 1, Direction: stockfighter.OrderDirectionBuy, OrderType: stockfighter.OrderTypeLimit, State: "filled", Price: 5000, Quantity: 100},
+	}
+	for _, event := range events {
+		assert.Nil(t, s.SaveEvent(event))
+	}
+
+	saved, err := s.Events()
+	assert.Nil(t, err)
+	assert.Equal(t, events, saved)
+}
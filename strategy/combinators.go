@@ -0,0 +1,263 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// ErrDone is returned by a strategy handler when the strategy is done. It
+// ends the current strategy of a Sequence, and stops a Runner without error.
+var ErrDone = errors.New("strategy done")
+
+// A Signal is a strategy telling whether another strategy may trade, e.g. a
+// trend detector (see Gate).
+type Signal interface {
+	Strategy
+
+	// Active reports whether the gated strategy may trade.
+	Active() bool
+}
+
+// A Weighted is a strategy with the weight of its orders in a Blend.
+type Weighted struct {
+	Strategy Strategy
+	Weight   float64
+}
+
+// A component is a strategy composed by a combinator. The orders placed from
+// its handlers are owned by it, and their events are delivered to it only.
+type component struct {
+	strategy Strategy
+	weight   float64 // 0 if not weighted
+	done     bool
+}
+
+// call calls a handler of the strategy of the component, with the component
+// as the owner of the orders placed in the meantime.
+func (c *component) call(session *Session, handler func(Strategy) error) error {
+	session.owners = append(session.owners, c)
+	defer func() { session.owners = session.owners[:len(session.owners)-1] }()
+
+	return handler(c.strategy)
+}
+
+// owner returns the component which placed the order of the order event
+// being delivered, or nil if none of components did, e.g. for orders
+// restored by Runner.Resume.
+func owner(session *Session, components []*component) *component {
+	for _, owner := range session.eventOwners {
+		for _, c := range components {
+			if owner == c {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// combinator implements Strategy for combinators, which only differ in how
+// they deliver events to their components.
+type combinator struct {
+	// event delivers a quote or timer event, and orderEvent an event of an
+	// order
+	event      func(session *Session, handler func(Strategy) error) error
+	orderEvent func(session *Session, handler func(Strategy) error) error
+}
+
+func (c *combinator) OnQuote(session *Session, stock string, quote *stockfighter.Quote) error {
+	return c.event(session, func(s Strategy) error {
+		return s.OnQuote(session, stock, quote)
+	})
+}
+
+func (c *combinator) OnExecution(session *Session, order *stockfighter.Order) error {
+	return c.orderEvent(session, func(s Strategy) error {
+		return s.OnExecution(session, order)
+	})
+}
+
+func (c *combinator) OnFill(session *Session, order *stockfighter.Order, fill stockfighter.OrderFillInfo) error {
+	return c.orderEvent(session, func(s Strategy) error {
+		return s.OnFill(session, order, fill)
+	})
+}
+
+func (c *combinator) OnTimer(session *Session, now time.Time) error {
+	return c.event(session, func(s Strategy) error {
+		return s.OnTimer(session, now)
+	})
+}
+
+// Sequence returns a strategy running strategies one after the other, e.g. a
+// phase accumulating a position followed by a phase distributing it.
+//
+// Quotes and timer ticks are delivered to the current strategy until one of
+// its handlers returns ErrDone; the next strategy takes over from the next
+// event. The events of an order are delivered to the strategy which placed
+// it, even if it is done, so that it can account for the fills of the orders
+// it left open. The sequence is done when its last strategy is.
+func Sequence(strategies ...Strategy) Strategy {
+	phases := make([]*component, len(strategies))
+	for i, strategy := range strategies {
+		phases[i] = &component{strategy: strategy}
+	}
+
+	current := 0
+	event := func(session *Session, handler func(Strategy) error) error {
+		if current == len(phases) {
+			return ErrDone
+		}
+
+		if err := phases[current].call(session, handler); err != ErrDone {
+			return err
+		}
+		phases[current].done = true
+		current++
+		if current == len(phases) {
+			return ErrDone
+		}
+		return nil
+	}
+
+	return &combinator{
+		event: event,
+		orderEvent: func(session *Session, handler func(Strategy) error) error {
+			phase := owner(session, phases)
+			if phase == nil || !phase.done {
+				return event(session, handler)
+			}
+			if err := phase.call(session, handler); err != ErrDone {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+// Gate returns a strategy running strategy only while signal is active.
+//
+// Every quote and timer tick is delivered to signal first, then to strategy
+// if signal is active. The events of the orders of strategy are always
+// delivered to it. A signal returning ErrDone receives no more events and
+// stays in its last state. The gate is done when strategy is.
+func Gate(signal Signal, strategy Strategy) Strategy {
+	components := []*component{{strategy: signal}, {strategy: strategy}}
+	gated := components[1]
+
+	deliver := func(c *component, session *Session, handler func(Strategy) error) error {
+		err := c.call(session, handler)
+		if err != ErrDone {
+			return err
+		}
+		c.done = true
+		if c == gated {
+			return ErrDone
+		}
+		return nil
+	}
+
+	return &combinator{
+		event: func(session *Session, handler func(Strategy) error) error {
+			if gated.done {
+				return ErrDone
+			}
+			if !components[0].done {
+				if err := deliver(components[0], session, handler); err != nil {
+					return err
+				}
+			}
+			if !signal.Active() {
+				return nil
+			}
+			return deliver(gated, session, handler)
+		},
+		orderEvent: func(session *Session, handler func(Strategy) error) error {
+			c := owner(session, components)
+			if c == nil {
+				c = gated
+			}
+			return deliver(c, session, handler)
+		},
+	}
+}
+
+// Blend returns a strategy running strategies side by side, with the
+// quantity of the orders of each scaled by its weight: e.g. with weights 0.7
+// and 0.3, a 100 share order of the first strategy is placed as a 70 share
+// order. Scaled quantities are rounded, and at least 1 share.
+//
+// Every quote and timer tick is delivered to every strategy not done yet, in
+// order, and the events of an order to the strategy which placed it. The
+// blend is done when all its strategies are.
+func Blend(strategies ...Weighted) Strategy {
+	components := make([]*component, len(strategies))
+	for i, strategy := range strategies {
+		if !(strategy.Weight > 0) || math.IsInf(strategy.Weight, 0) {
+			panic(fmt.Errorf("Invalid strategy weight: %v", strategy.Weight))
+		}
+		components[i] = &component{strategy: strategy.Strategy, weight: strategy.Weight}
+	}
+
+	// deliver delivers an event to a component, and returns ErrDone if all
+	// components are done
+	deliver := func(c *component, session *Session, handler func(Strategy) error) error {
+		if err := c.call(session, handler); err == ErrDone {
+			c.done = true
+		} else if err != nil {
+			return err
+		}
+
+		for _, c := range components {
+			if !c.done {
+				return nil
+			}
+		}
+		return ErrDone
+	}
+
+	event := func(session *Session, handler func(Strategy) error) error {
+		err := ErrDone
+		for _, c := range components {
+			if c.done {
+				continue
+			}
+			if err = deliver(c, session, handler); err != nil && err != ErrDone {
+				return err
+			}
+		}
+		return err
+	}
+
+	return &combinator{
+		event: event,
+		orderEvent: func(session *Session, handler func(Strategy) error) error {
+			if c := owner(session, components); c != nil {
+				return deliver(c, session, handler)
+			}
+			return event(session, handler)
+		},
+	}
+}
+
+// scale returns the quantity of an order placed by the owners of a session,
+// scaled by their weights.
+func scale(quantity uint64, owners []*component) uint64 {
+	weight := 1.0
+	for _, owner := range owners {
+		if owner.weight > 0 {
+			weight *= owner.weight
+		}
+	}
+	if weight == 1 || quantity == 0 {
+		return quantity
+	}
+
+	if scaled := uint64(math.Round(float64(quantity) * weight)); scaled > 0 {
+		return scaled
+	}
+	return 1
+}
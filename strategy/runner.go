@@ -0,0 +1,161 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// A Runner runs a strategy against the live Stockfighter API: it polls quotes
+// of a set of stocks and the status of the orders placed by the strategy, and
+// calls the strategy handlers from a single goroutine.
+//
+// You can create a new Runner using NewRunner function.
+type Runner struct {
+	client  *stockfighter.Client
+	venue   string
+	account string
+	stocks  []string
+
+	// Quote polling interval (stockfighter.DefaultPollInterval if not
+	// positive)
+	QuoteInterval time.Duration
+
+	// Order status polling interval (stockfighter.DefaultPollInterval if not
+	// positive)
+	OrderInterval time.Duration
+
+	// Interval of OnTimer calls (not positive disables them)
+	TimerInterval time.Duration
+}
+
+// NewRunner creates a new Runner trading stocks for an account on a venue.
+// This never returns nil.
+func NewRunner(client *stockfighter.Client, venue, account string, stocks []string) *Runner {
+	venue = strings.TrimSpace(venue)
+	if venue == "" {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+
+	account = strings.TrimSpace(account)
+	if account == "" {
+		panic(fmt.Errorf("Invalid account name: %v", account))
+	}
+
+	return &Runner{
+		client:        client,
+		venue:         venue,
+		account:       account,
+		stocks:        stocks,
+		QuoteInterval: stockfighter.DefaultPollInterval,
+		OrderInterval: stockfighter.DefaultPollInterval,
+		TimerInterval: stockfighter.DefaultPollInterval,
+	}
+}
+
+// Run runs a strategy until ctx is done, a handler returns an error, or an
+// API call fails. Quote errors are not fatal; the quote is polled again at the
+// next interval. Run returns nil if a handler returns ErrDone.
+//
+// Orders left open by the strategy are not canceled.
+func (runner *Runner) Run(ctx context.Context, strategy Strategy) error {
+	return runner.run(ctx, strategy, newSession(runner.client, runner.venue, runner.account))
+}
+
+// Resume runs a strategy like Run, with the session restored from a state
+// previously written by Session.Save, e.g. from a strategy handler
+// checkpointing the session periodically.
+func (runner *Runner) Resume(ctx context.Context, strategy Strategy, state io.Reader) error {
+	session := newSession(runner.client, runner.venue, runner.account)
+	if err := session.Load(state); err != nil {
+		return err
+	}
+	return runner.run(ctx, strategy, session)
+}
+
+func (runner *Runner) run(ctx context.Context, strategy Strategy, session *Session) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	quoteInterval, orderInterval := runner.QuoteInterval, runner.OrderInterval
+	if quoteInterval <= 0 {
+		quoteInterval = stockfighter.DefaultPollInterval
+	}
+	if orderInterval <= 0 {
+		orderInterval = stockfighter.DefaultPollInterval
+	}
+
+	quotes := stockfighter.NewQuotePoller(runner.client.Subsystem("strategy"), runner.venue, runner.stocks, quoteInterval).Start(ctx)
+
+	orderTicker := time.NewTicker(orderInterval)
+	defer orderTicker.Stop()
+
+	var timer <-chan time.Time
+	if runner.TimerInterval > 0 {
+		timerTicker := time.NewTicker(runner.TimerInterval)
+		defer timerTicker.Stop()
+		timer = timerTicker.C
+	}
+
+	for {
+		var err error
+		select {
+		case update, ok := <-quotes:
+			if !ok {
+				return ctx.Err()
+			}
+			if update.Err == nil {
+				err = strategy.OnQuote(session, update.Stock, update.Quote)
+			}
+		case <-orderTicker.C:
+			err = runner.pollOrders(session, strategy)
+		case now := <-timer:
+			err = strategy.OnTimer(session, now)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if err == ErrDone {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// pollOrders refreshes the status of the open orders of a session and calls
+// the strategy handlers for the orders that changed.
+func (runner *Runner) pollOrders(session *Session, strategy Strategy) error {
+	defer func() { session.eventOwners = nil }()
+
+	for _, order := range session.OpenOrders() {
+		tracked := session.orders[order.OrderID]
+		status, err := runner.client.GetOrder(runner.venue, tracked.stock, order.OrderID)
+		if err != nil {
+			return err
+		}
+
+		// combinators deliver the events to the strategy which placed the
+		// order, even once it is no longer tracked
+		session.eventOwners = tracked.owners
+
+		fills := session.update(status)
+		for _, fill := range fills {
+			if err := strategy.OnFill(session, status, fill); err != nil {
+				return err
+			}
+		}
+
+		if len(fills) > 0 || !status.Open {
+			if err := strategy.OnExecution(session, status); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package strategy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// trackedOrder is an order placed through a session.
+type trackedOrder struct {
+	stock     string
+	status    *stockfighter.Order
+	seenFills int
+
+	// Components of combinators which placed the order, innermost last (not
+	// saved by Session.Save)
+	owners []*component
+
+	// Time the order is canceled at if still open, and the timer canceling
+	// it (see WithTTL)
+	expires time.Time
+	timer   *time.Timer
+}
+
+// An OrderOption configures an order placed through a session.
+type OrderOption func(*orderOptions)
+
+type orderOptions struct {
+	ttl time.Duration
+}
+
+// WithTTL sets the time-to-live of an order: the order is canceled if it is
+// still open after ttl, protecting against forgotten quotes.
+//
+// The order is canceled even if the strategy stopped in the meantime, as long
+// as the program is running.
+func WithTTL(ttl time.Duration) OrderOption {
+	return func(options *orderOptions) {
+		options.ttl = ttl
+	}
+}
+
+// A Session is the trading context of a strategy: it places orders for an
+// account on a venue, and tracks those orders and the positions their fills
+// add up to.
+//
+// A Session is not safe for concurrent use; it is meant to be used from
+// strategy handlers only.
+type Session struct {
+	client  *stockfighter.Client
+	venue   string
+	account string
+
+	orders    map[int64]*trackedOrder
+	positions map[string]int64
+	cash      int64
+
+	// Components of combinators whose handlers are running, innermost last,
+	// and owners of the order whose events are being delivered
+	owners      []*component
+	eventOwners []*component
+}
+
+func newSession(client *stockfighter.Client, venue, account string) *Session {
+	return &Session{
+		client:    client,
+		venue:     venue,
+		account:   account,
+		orders:    make(map[int64]*trackedOrder),
+		positions: make(map[string]int64),
+	}
+}
+
+// Client returns the client of the session.
+func (session *Session) Client() *stockfighter.Client {
+	return session.client
+}
+
+// Venue returns the venue symbol of the session.
+func (session *Session) Venue() string {
+	return session.venue
+}
+
+// Account returns the trading account of the session.
+func (session *Session) Account() string {
+	return session.account
+}
+
+// Buy places a buy order for a stock and tracks it. Within a Blend, the
+// quantity is scaled by the weight of the strategy.
+func (session *Session) Buy(stock string, price, quantity uint64, orderType string, options ...OrderOption) (*stockfighter.Order, error) {
+	return session.place(stock, price, quantity, stockfighter.OrderDirectionBuy, orderType, options)
+}
+
+// Sell places a sell order for a stock and tracks it.
+func (session *Session) Sell(stock string, price, quantity uint64, orderType string, options ...OrderOption) (*stockfighter.Order, error) {
+	return session.place(stock, price, quantity, stockfighter.OrderDirectionSell, orderType, options)
+}
+
+func (session *Session) place(stock string, price, quantity uint64, direction, orderType string, options []OrderOption) (*stockfighter.Order, error) {
+	var opts orderOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
+	quantity = scale(quantity, session.owners)
+	order, err := session.client.PlaceOrder(session.venue, stock, session.account, price, quantity, direction, orderType)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := &trackedOrder{stock: stock, status: order}
+	if len(session.owners) > 0 {
+		tracked.owners = append([]*component(nil), session.owners...)
+	}
+	if opts.ttl > 0 && order.Open {
+		tracked.expires = time.Now().Add(opts.ttl)
+		session.armTTL(tracked)
+	}
+	session.orders[order.OrderID] = tracked
+	return order, nil
+}
+
+// armTTL starts the timer canceling a tracked order when it expires. The
+// timer only uses the client, which is safe for concurrent use, and not the
+// session.
+func (session *Session) armTTL(tracked *trackedOrder) {
+	client, venue, stock, orderID := session.client, session.venue, tracked.stock, tracked.status.OrderID
+	tracked.timer = time.AfterFunc(time.Until(tracked.expires), func() {
+		// the order may have been closed in the meantime
+		client.CancelOrder(venue, stock, orderID)
+	})
+}
+
+// Cancel cancels an order placed through the session.
+func (session *Session) Cancel(orderID int64) (*stockfighter.Order, error) {
+	tracked, ok := session.orders[orderID]
+	if !ok {
+		return nil, &ErrorUnknownOrder{OrderID: orderID}
+	}
+
+	return session.client.CancelOrder(session.venue, tracked.stock, orderID)
+}
+
+// OpenOrders returns the last known status of the open orders placed through
+// the session, ordered by ID.
+func (session *Session) OpenOrders() []stockfighter.Order {
+	var orders []stockfighter.Order
+	for _, tracked := range session.orders {
+		if tracked.status.Open {
+			orders = append(orders, *tracked.status)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].OrderID < orders[j].OrderID })
+	return orders
+}
+
+// Position returns the position (in shares) in a stock.
+func (session *Session) Position(stock string) int64 {
+	return session.positions[stock]
+}
+
+// Cash returns the cash (in cents) the fills of the session added up to.
+func (session *Session) Cash() int64 {
+	return session.cash
+}
+
+// update records a new status of a tracked order and returns its new fills.
+// Closed orders are no longer tracked.
+func (session *Session) update(status *stockfighter.Order) []stockfighter.OrderFillInfo {
+	tracked, ok := session.orders[status.OrderID]
+	if !ok {
+		return nil
+	}
+
+	var fills []stockfighter.OrderFillInfo
+	if tracked.seenFills < len(status.Fills) {
+		fills = status.Fills[tracked.seenFills:]
+	}
+	for _, fill := range fills {
+		if status.Direction == stockfighter.OrderDirectionBuy {
+			session.positions[tracked.stock] += int64(fill.Quantity)
+			session.cash -= int64(fill.Price * fill.Quantity)
+		} else {
+			session.positions[tracked.stock] -= int64(fill.Quantity)
+			session.cash += int64(fill.Price * fill.Quantity)
+		}
+	}
+
+	tracked.status = status
+	tracked.seenFills = len(status.Fills)
+	if !status.Open {
+		if tracked.timer != nil {
+			tracked.timer.Stop()
+		}
+		delete(session.orders, status.OrderID)
+	}
+
+	return fills
+}
+
+// sessionState is the JSON encoding of the state of a session.
+type sessionState struct {
+	Orders    []trackedOrderState `json:"orders"`
+	Positions map[string]int64    `json:"positions"`
+	Cash      int64               `json:"cash"`
+}
+
+type trackedOrderState struct {
+	Stock     string              `json:"symbol"`
+	Status    *stockfighter.Order `json:"status"`
+	SeenFills int                 `json:"seenFills"`
+	Expires   *time.Time          `json:"expires,omitempty"`
+}
+
+// Save writes the state of the session (the orders it tracks, positions, and
+// cash) to w as JSON, so it can be restored after a crash with Load or
+// Runner.Resume.
+func (session *Session) Save(w io.Writer) error {
+	state := sessionState{Positions: session.positions, Cash: session.cash}
+	for _, tracked := range session.orders {
+		order := trackedOrderState{Stock: tracked.stock, Status: tracked.status, SeenFills: tracked.seenFills}
+		if !tracked.expires.IsZero() {
+			order.Expires = &tracked.expires
+		}
+		state.Orders = append(state.Orders, order)
+	}
+	sort.Slice(state.Orders, func(i, j int) bool { return state.Orders[i].Status.OrderID < state.Orders[j].Status.OrderID })
+
+	return json.NewEncoder(w).Encode(state)
+}
+
+// Load restores the state of the session previously written by Save,
+// replacing its current state. Orders with a time-to-live are canceled when
+// it expires, right away if it already did.
+func (session *Session) Load(r io.Reader) error {
+	var state sessionState
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return err
+	}
+
+	session.orders = make(map[int64]*trackedOrder, len(state.Orders))
+	for _, order := range state.Orders {
+		if order.Status == nil {
+			return errors.New("Invalid session state: order without status")
+		}
+		tracked := &trackedOrder{stock: order.Stock, status: order.Status, seenFills: order.SeenFills}
+		if order.Expires != nil {
+			tracked.expires = *order.Expires
+		}
+		session.orders[order.Status.OrderID] = tracked
+	}
+	session.positions = state.Positions
+	if session.positions == nil {
+		session.positions = make(map[string]int64)
+	}
+	session.cash = state.Cash
+
+	for _, tracked := range session.orders {
+		if !tracked.expires.IsZero() {
+			session.armTTL(tracked)
+		}
+	}
+
+	return nil
+}
+
+// ErrorUnknownOrder is returned for orders not placed through the session.
+type ErrorUnknownOrder struct {
+	OrderID int64
+}
+
+func (e *ErrorUnknownOrder) Error() string {
+	return fmt.Sprintf("Order not placed in this session: %v", e.OrderID)
+}
@@ -0,0 +1,82 @@
+/*
+Package strategy provides an event-driven framework for trading strategies.
+
+A Strategy reacts to events (quotes, order executions, fills, and timer ticks)
+and trades through the Session passed to every handler, which also keeps
+track of its orders and positions. A Runner delivers the events from the
+Stockfighter API, one at a time, so handlers never run concurrently.
+
+    type buyLow struct {
+        strategy.Base
+    }
+
+    func (s *buyLow) OnQuote(session *strategy.Session, stock string, quote *stockfighter.Quote) error {
+        if quote.HasAsk && quote.AskPrice < 5000 && session.Position(stock) < 1000 {
+            _, err := session.Buy(stock, quote.AskPrice, 100, stockfighter.OrderTypeImmediateOrCancel)
+            return err
+        }
+        return nil
+    }
+
+    runner := strategy.NewRunner(client, venue, account, []string{stock})
+    err := runner.Run(ctx, &buyLow{})
+
+Complex strategies can be assembled from simpler ones with combinators, e.g.
+accumulating a position, then distributing it while a trend signal is
+active:
+
+    err := runner.Run(ctx, strategy.Sequence(&accumulate{}, strategy.Gate(trend, &distribute{})))
+*/
+package strategy
+
+import (
+	"time"
+
+	"gpk.io/stockfighter"
+)
+
+// A Strategy handles trading events. Returning an error from any handler stops
+// the Runner running the strategy; returning ErrDone stops it without error.
+//
+// Strategies can be composed with Sequence, Gate, and Blend, which share the
+// session, and thus positions, among the strategies they compose.
+type Strategy interface {
+	// OnQuote is called with every new quote of a stock.
+	OnQuote(session *Session, stock string, quote *stockfighter.Quote) error
+
+	// OnExecution is called when an order placed through the session is
+	// executed (filled or partially filled) or closed, with the new order
+	// status. It is called after OnFill was called for each new fill.
+	OnExecution(session *Session, order *stockfighter.Order) error
+
+	// OnFill is called for every new fill of an order placed through the
+	// session, after positions were updated.
+	OnFill(session *Session, order *stockfighter.Order, fill stockfighter.OrderFillInfo) error
+
+	// OnTimer is called periodically.
+	OnTimer(session *Session, now time.Time) error
+}
+
+// Base implements Strategy with handlers doing nothing. Embed it in a
+// strategy to only implement the handlers it needs.
+type Base struct{}
+
+// OnQuote does nothing.
+func (Base) OnQuote(session *Session, stock string, quote *stockfighter.Quote) error {
+	return nil
+}
+
+// OnExecution does nothing.
+func (Base) OnExecution(session *Session, order *stockfighter.Order) error {
+	return nil
+}
+
+// OnFill does nothing.
+func (Base) OnFill(session *Session, order *stockfighter.Order, fill stockfighter.OrderFillInfo) error {
+	return nil
+}
+
+// OnTimer does nothing.
+func (Base) OnTimer(session *Session, now time.Time) error {
+	return nil
+}
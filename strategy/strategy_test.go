@@ -0,0 +1,319 @@
+package strategy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+type buyOnce struct {
+	Base
+	placed     bool
+	fills      []stockfighter.OrderFillInfo
+	executions int
+}
+
+var errDone = errors.New("done")
+
+func (s *buyOnce) OnQuote(session *Session, stock string, quote *stockfighter.Quote) error {
+	if s.placed {
+		return nil
+	}
+
+	s.placed = true
+	_, err := session.Buy(stock, quote.AskPrice, 10, stockfighter.OrderTypeLimit)
+	return err
+}
+
+func (s *buyOnce) OnFill(session *Session, order *stockfighter.Order, fill stockfighter.OrderFillInfo) error {
+	s.fills = append(s.fills, fill)
+	return nil
+}
+
+func (s *buyOnce) OnExecution(session *Session, order *stockfighter.Order) error {
+	s.executions++
+	if !order.Open {
+		return errDone
+	}
+	return nil
+}
+
+func TestRunner(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/quote"):
+			w.Write([]byte(`{"ok": true, "ask": 100, "quoteTime": "2015-12-04T09:02:16Z"}`))
+		case r.Method == "POST":
+			w.Write([]byte(`{"ok": true, "id": 1, "direction": "buy", "open": true, "fills": []}`))
+		default:
+			// one fill per poll
+			polls++
+			if polls == 1 {
+				w.Write([]byte(`{"ok": true, "id": 1, "direction": "buy", "open": true, "fills": [{"price": 100, "qty": 4}]}`))
+			} else {
+				w.Write([]byte(`{"ok": true, "id": 1, "direction": "buy", "open": false, "fills": [{"price": 100, "qty": 4}, {"price": 99, "qty": 6}]}`))
+			}
+		}
+	}))
+	defer server.Close()
+
+	client := stockfighter.NewClient("KEY", stockfighter.WithBaseURL(server.URL))
+	runner := NewRunner(client, "TESTEX", "EXB123456", []string{"FOOBAR"})
+	runner.OrderInterval = time.Millisecond
+	runner.TimerInterval = 0
+
+	s := &buyOnce{}
+	assert.Equal(t, errDone, runner.Run(context.Background(), s))
+	assert.Len(t, s.fills, 2)
+	assert.Equal(t, 2, s.executions)
+}
+
+func TestSession(t *testing.T) {
+	session := newSession(stockfighter.NewClient("KEY"), "TESTEX", "EXB123456")
+	session.orders[1] = &trackedOrder{stock: "FOOBAR", status: &stockfighter.Order{OrderID: 1, Open: true}}
+	session.orders[2] = &trackedOrder{stock: "FOOBAR", status: &stockfighter.Order{OrderID: 2, Open: true}}
+
+	fills := session.update(&stockfighter.Order{OrderID: 1, Direction: "buy", Open: true, Fills: []stockfighter.OrderFillInfo{{Price: 100, Quantity: 10}}})
+	assert.Len(t, fills, 1)
+	fills = session.update(&stockfighter.Order{OrderID: 2, Direction: "sell", Fills: []stockfighter.OrderFillInfo{{Price: 110, Quantity: 4}}})
+	assert.Len(t, fills, 1)
+
+	assert.Equal(t, int64(6), session.Position("FOOBAR"))
+	assert.Equal(t, int64(-1000+440), session.Cash())
+	assert.Len(t, session.OpenOrders(), 1)
+
+	_, err := session.Cancel(2)
+	_, ok := err.(*ErrorUnknownOrder)
+	assert.True(t, ok)
+}
+
+func TestSessionSaveLoad(t *testing.T) {
+	session := newSession(stockfighter.NewClient("KEY"), "TESTEX", "EXB123456")
+	session.orders[1] = &trackedOrder{stock: "FOOBAR", status: &stockfighter.Order{OrderID: 1, Open: true}}
+	session.update(&stockfighter.Order{OrderID: 1, Direction: "buy", Open: true, Fills: []stockfighter.OrderFillInfo{{Price: 100, Quantity: 10}}})
+
+	var buf bytes.Buffer
+	assert.Nil(t, session.Save(&buf))
+
+	restored := newSession(stockfighter.NewClient("KEY"), "TESTEX", "EXB123456")
+	assert.Nil(t, restored.Load(&buf))
+	assert.Equal(t, session.orders, restored.orders)
+	assert.Equal(t, int64(10), restored.Position("FOOBAR"))
+	assert.Equal(t, int64(-1000), restored.Cash())
+
+	// fills seen before the checkpoint are not counted again
+	fills := restored.update(&stockfighter.Order{OrderID: 1, Direction: "buy", Fills: []stockfighter.OrderFillInfo{{Price: 100, Quantity: 10}, {Price: 101, Quantity: 5}}})
+	assert.Len(t, fills, 1)
+	assert.Equal(t, int64(15), restored.Position("FOOBAR"))
+	assert.Empty(t, restored.OpenOrders())
+}
+
+func TestSessionTTL(t *testing.T) {
+	canceled := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			w.Write([]byte(`{"ok": true, "id": 1, "open": true}`))
+		case "DELETE":
+			canceled <- r.URL.Path
+			w.Write([]byte(`{"ok": true, "id": 1, "open": false}`))
+		}
+	}))
+	defer server.Close()
+
+	session := newSession(stockfighter.NewClient("KEY", stockfighter.WithBaseURL(server.URL)), "TESTEX", "EXB123456")
+	_, err := session.Buy("FOOBAR", 100, 10, stockfighter.OrderTypeLimit, WithTTL(10*time.Millisecond))
+	assert.Nil(t, err)
+	assert.Equal(t, "/venues/TESTEX/stocks/FOOBAR/orders/1", <-canceled)
+
+	// the time-to-live survives a checkpoint, and is expired by now
+	var buf bytes.Buffer
+	assert.Nil(t, session.Save(&buf))
+	assert.Contains(t, buf.String(), `"expires"`)
+	restored := newSession(session.client, "TESTEX", "EXB123456")
+	assert.Nil(t, restored.Load(&buf))
+	assert.Equal(t, "/venues/TESTEX/stocks/FOOBAR/orders/1", <-canceled)
+
+	// closed orders are not canceled
+	_, err = session.Buy("FOOBAR", 100, 10, stockfighter.OrderTypeLimit, WithTTL(10*time.Millisecond))
+	assert.Nil(t, err)
+	session.update(&stockfighter.Order{OrderID: 1})
+	select {
+	case <-canceled:
+		t.Error("closed order canceled")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// recorder records the events it receives, and places an order of 100 shares
+// on quotes if it trades.
+type recorder struct {
+	name   string
+	events *[]string
+	trades bool
+	done   func(event string) bool
+}
+
+func (r *recorder) record(session *Session, event string) error {
+	*r.events = append(*r.events, r.name+":"+event)
+	if r.done != nil && r.done(event) {
+		return ErrDone
+	}
+	return nil
+}
+
+func (r *recorder) OnQuote(session *Session, stock string, quote *stockfighter.Quote) error {
+	if r.trades {
+		if _, err := session.Buy(stock, 100, 100, stockfighter.OrderTypeLimit); err != nil {
+			return err
+		}
+	}
+	return r.record(session, "quote")
+}
+
+func (r *recorder) OnExecution(session *Session, order *stockfighter.Order) error {
+	return r.record(session, "execution")
+}
+
+func (r *recorder) OnFill(session *Session, order *stockfighter.Order, fill stockfighter.OrderFillInfo) error {
+	return r.record(session, "fill")
+}
+
+func (r *recorder) OnTimer(session *Session, now time.Time) error {
+	return r.record(session, "timer")
+}
+
+type signal struct {
+	recorder
+	active bool
+}
+
+func (s *signal) Active() bool {
+	return s.active
+}
+
+// newCombinatorSession returns a session placing orders with increasing IDs,
+// and records their quantities.
+func newCombinatorSession(t *testing.T, quantities *[]string) *Session {
+	id := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var order stockfighter.OrderRequest
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&order))
+		*quantities = append(*quantities, strconv.FormatUint(order.Quantity, 10))
+		id++
+		fmt.Fprintf(w, `{"ok": true, "id": %d, "qty": %d, "open": true}`, id, order.Quantity)
+	}))
+	t.Cleanup(server.Close)
+
+	return newSession(stockfighter.NewClient("KEY", stockfighter.WithBaseURL(server.URL)), "TESTEX", "EXB123456")
+}
+
+// fill delivers the fill of an order to a strategy, as Runner does.
+func fill(session *Session, s Strategy, orderID int64) error {
+	session.eventOwners = session.orders[orderID].owners
+	defer func() { session.eventOwners = nil }()
+	return s.OnFill(session, &stockfighter.Order{OrderID: orderID}, stockfighter.OrderFillInfo{})
+}
+
+func TestSequence(t *testing.T) {
+	var events, quantities []string
+	session := newCombinatorSession(t, &quantities)
+	accumulate := &recorder{name: "a", events: &events, trades: true, done: func(event string) bool { return event == "quote" }}
+	distribute := &recorder{name: "d", events: &events, done: func(event string) bool { return event == "timer" }}
+	s := Sequence(accumulate, distribute)
+
+	assert.Nil(t, s.OnQuote(session, "FOOBAR", &stockfighter.Quote{}))
+	assert.Nil(t, s.OnQuote(session, "FOOBAR", &stockfighter.Quote{}))
+
+	// the fills of the order of the first phase are delivered to it
+	assert.Nil(t, fill(session, s, 1))
+	assert.Equal(t, ErrDone, s.OnTimer(session, time.Now()))
+	assert.Equal(t, ErrDone, s.OnQuote(session, "FOOBAR", &stockfighter.Quote{}))
+	assert.Equal(t, []string{"a:quote", "d:quote", "a:fill", "d:timer"}, events)
+	assert.Equal(t, []string{"100"}, quantities)
+}
+
+func TestGate(t *testing.T) {
+	var events, quantities []string
+	session := newCombinatorSession(t, &quantities)
+	trend := &signal{recorder: recorder{name: "s", events: &events}}
+	trader := &recorder{name: "t", events: &events, trades: true, done: func(event string) bool { return event == "timer" }}
+	s := Gate(trend, trader)
+
+	assert.Nil(t, s.OnQuote(session, "FOOBAR", &stockfighter.Quote{}))
+	trend.active = true
+	assert.Nil(t, s.OnQuote(session, "FOOBAR", &stockfighter.Quote{}))
+	trend.active = false
+
+	// the events of the orders of the gated strategy are delivered even if
+	// the signal is not active
+	assert.Nil(t, fill(session, s, 1))
+	assert.Nil(t, s.OnTimer(session, time.Now()))
+	trend.active = true
+	assert.Equal(t, ErrDone, s.OnTimer(session, time.Now()))
+	assert.Equal(t, ErrDone, s.OnQuote(session, "FOOBAR", &stockfighter.Quote{}))
+	assert.Equal(t, []string{"s:quote", "s:quote", "t:quote", "t:fill", "s:timer", "s:timer", "t:timer"}, events)
+	assert.Equal(t, []string{"100"}, quantities)
+}
+
+func TestBlend(t *testing.T) {
+	var events, quantities []string
+	session := newCombinatorSession(t, &quantities)
+	first := &recorder{name: "1", events: &events, trades: true, done: func(event string) bool { return event == "timer" }}
+	second := &recorder{name: "2", events: &events, trades: true, done: func(event string) bool { return event == "fill" }}
+	s := Blend(Weighted{first, 0.7}, Weighted{Blend(Weighted{second, 0.3}), 0.5})
+
+	assert.Nil(t, s.OnQuote(session, "FOOBAR", &stockfighter.Quote{}))
+	assert.Equal(t, []string{"70", "15"}, quantities)
+
+	// the fills of an order are delivered to the strategy which placed it
+	assert.Nil(t, fill(session, s, 2))
+	assert.Equal(t, ErrDone, s.OnTimer(session, time.Now()))
+
+	// done strategies still receive the events of their orders
+	assert.Equal(t, ErrDone, fill(session, s, 1))
+	assert.Equal(t, []string{"1:quote", "2:quote", "2:fill", "1:timer", "1:fill"}, events)
+
+	assert.Equal(t, uint64(1), scale(1, []*component{{weight: 0.1}}))
+	assert.Panics(t, func() { Blend(Weighted{first, 0}) })
+}
+
+func TestRunnerDone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true, "ask": 100, "quoteTime": "2015-12-04T09:02:16Z"}`))
+	}))
+	defer server.Close()
+
+	var events []string
+	runner := NewRunner(stockfighter.NewClient("KEY", stockfighter.WithBaseURL(server.URL)), "TESTEX", "EXB123456", []string{"FOOBAR"})
+	runner.QuoteInterval = time.Millisecond
+	s := &recorder{name: "r", events: &events, done: func(event string) bool { return event == "quote" }}
+	assert.Nil(t, runner.Run(context.Background(), s))
+}
+
+func TestRunnerDefaultIntervals(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true, "ask": 100, "quoteTime": "2015-12-04T09:02:16Z"}`))
+	}))
+	defer server.Close()
+
+	var events []string
+	runner := NewRunner(stockfighter.NewClient("KEY", stockfighter.WithBaseURL(server.URL)), "TESTEX", "EXB123456", []string{"FOOBAR"})
+	runner.QuoteInterval = 0
+	runner.OrderInterval = 0
+	runner.TimerInterval = -1
+	s := &recorder{name: "r", events: &events, done: func(event string) bool { return event == "quote" }}
+	assert.Nil(t, runner.Run(context.Background(), s))
+}
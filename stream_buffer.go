@@ -0,0 +1,248 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// An OverflowPolicy tells what a stream buffer does when it is full, i.e.
+// when its consumer is slower than its source.
+type OverflowPolicy int
+
+// Overflow policies.
+const (
+	// Stop reading from the source until the consumer catches up: nothing
+	// is dropped, but the source is slowed down (backpressure)
+	OverflowBlock OverflowPolicy = iota
+
+	// Drop the oldest message buffered to make room for the new one
+	OverflowDropOldest
+
+	// Keep only the latest message of each stream key (the stock of quotes,
+	// the order of executions), replacing the one buffered if any; when the
+	// buffer is full of other keys, drop the oldest one
+	OverflowConflate
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropOldest:
+		return "drop-oldest"
+	case OverflowConflate:
+		return "conflate"
+	default:
+		return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+	}
+}
+
+// A QuoteStream is a buffered stream of quote updates.
+type QuoteStream struct {
+	// Channel updates are delivered on, closed when the source is closed and
+	// drained, or when the context of the stream is done
+	C <-chan QuoteUpdate
+
+	buffer *streamBuffer
+}
+
+// Dropped returns the number of updates dropped (or replaced) so far.
+func (s *QuoteStream) Dropped() uint64 {
+	return s.buffer.droppedCount()
+}
+
+// BufferQuotes buffers a stream of quote updates, e.g. of a QuotePoller, in
+// a buffer of the given size with the given overflow policy, so that a slow
+// consumer does not go unnoticed. Conflation keeps the latest update of each
+// stock. This panics if size is not positive.
+func BufferQuotes(ctx context.Context, updates <-chan QuoteUpdate, size int, overflow OverflowPolicy) *QuoteStream {
+	buffer := newStreamBuffer(size, overflow, func(v interface{}) string {
+		update := v.(QuoteUpdate)
+		return update.Venue + "/" + update.Stock
+	})
+
+	c := make(chan QuoteUpdate)
+	go func() {
+		defer close(c)
+
+		for {
+			recv := updates
+			if buffer.full() {
+				recv = nil
+			}
+			var send chan<- QuoteUpdate
+			var next QuoteUpdate
+			if buffer.len() > 0 {
+				send, next = c, buffer.peek().(QuoteUpdate)
+			}
+			if updates == nil && send == nil {
+				return
+			}
+
+			select {
+			case update, ok := <-recv:
+				if !ok {
+					updates = nil
+					continue
+				}
+				buffer.push(update)
+			case send <- next:
+				buffer.pop()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return &QuoteStream{C: c, buffer: buffer}
+}
+
+// An ExecutionStream is a buffered stream of executions.
+type ExecutionStream struct {
+	// Channel executions are delivered on, closed when the source is closed
+	// and drained, or when the context of the stream is done
+	C <-chan *Execution
+
+	buffer *streamBuffer
+}
+
+// Dropped returns the number of executions dropped (or replaced) so far.
+func (s *ExecutionStream) Dropped() uint64 {
+	return s.buffer.droppedCount()
+}
+
+// BufferExecutions buffers a stream of executions like BufferQuotes.
+// Conflation keeps the latest execution of each order, whose status is the
+// most recent, but loses the fills of the executions replaced. This panics if
+// size is not positive.
+func BufferExecutions(ctx context.Context, executions <-chan *Execution, size int, overflow OverflowPolicy) *ExecutionStream {
+	buffer := newStreamBuffer(size, overflow, func(v interface{}) string {
+		execution := v.(*Execution)
+		return fmt.Sprintf("%v/%v", execution.Venue, execution.Order.OrderID)
+	})
+
+	c := make(chan *Execution)
+	go func() {
+		defer close(c)
+
+		for {
+			recv := executions
+			if buffer.full() {
+				recv = nil
+			}
+			var send chan<- *Execution
+			var next *Execution
+			if buffer.len() > 0 {
+				send, next = c, buffer.peek().(*Execution)
+			}
+			if executions == nil && send == nil {
+				return
+			}
+
+			select {
+			case execution, ok := <-recv:
+				if !ok {
+					executions = nil
+					continue
+				}
+				buffer.push(execution)
+			case send <- next:
+				buffer.pop()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return &ExecutionStream{C: c, buffer: buffer}
+}
+
+// streamBuffer buffers the messages of a stream, whatever their type. It is
+// used by a single goroutine, moving messages from the source to the
+// consumer; only the dropped count is read concurrently.
+type streamBuffer struct {
+	size     int
+	overflow OverflowPolicy
+	key      func(interface{}) string
+	dropped  uint64
+
+	// messages buffered, oldest first; when conflating, the keys of the
+	// messages buffered, and the messages by key
+	queue   []interface{}
+	keys    []string
+	pending map[string]interface{}
+}
+
+func newStreamBuffer(size int, overflow OverflowPolicy, key func(interface{}) string) *streamBuffer {
+	if size <= 0 {
+		panic(fmt.Errorf("Invalid stream buffer size: %v", size))
+	}
+
+	return &streamBuffer{
+		size:     size,
+		overflow: overflow,
+		key:      key,
+		pending:  make(map[string]interface{}),
+	}
+}
+
+func (b *streamBuffer) droppedCount() uint64 {
+	return atomic.LoadUint64(&b.dropped)
+}
+
+// full returns whether the source must not be read from, to apply
+// backpressure.
+func (b *streamBuffer) full() bool {
+	return b.overflow == OverflowBlock && len(b.queue) >= b.size
+}
+
+func (b *streamBuffer) len() int {
+	if b.overflow == OverflowConflate {
+		return len(b.keys)
+	}
+	return len(b.queue)
+}
+
+func (b *streamBuffer) push(v interface{}) {
+	switch b.overflow {
+	case OverflowConflate:
+		key := b.key(v)
+		if _, ok := b.pending[key]; ok {
+			b.pending[key] = v
+			atomic.AddUint64(&b.dropped, 1)
+			return
+		}
+		if len(b.keys) >= b.size {
+			delete(b.pending, b.keys[0])
+			b.keys = b.keys[1:]
+			atomic.AddUint64(&b.dropped, 1)
+		}
+		b.keys = append(b.keys, key)
+		b.pending[key] = v
+	case OverflowDropOldest:
+		if len(b.queue) >= b.size {
+			b.queue = b.queue[1:]
+			atomic.AddUint64(&b.dropped, 1)
+		}
+		b.queue = append(b.queue, v)
+	default:
+		b.queue = append(b.queue, v)
+	}
+}
+
+func (b *streamBuffer) peek() interface{} {
+	if b.overflow == OverflowConflate {
+		return b.pending[b.keys[0]]
+	}
+	return b.queue[0]
+}
+
+func (b *streamBuffer) pop() {
+	if b.overflow == OverflowConflate {
+		delete(b.pending, b.keys[0])
+		b.keys = b.keys[1:]
+		return
+	}
+	b.queue = b.queue[1:]
+}
@@ -0,0 +1,77 @@
+package stockfighter
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferQuotes(t *testing.T) {
+	send := func(stocks ...string) <-chan QuoteUpdate {
+		updates := make(chan QuoteUpdate, len(stocks))
+		for i, stock := range stocks {
+			updates <- QuoteUpdate{Venue: testVenue, Stock: stock, Quote: &Quote{LastPrice: uint64(i)}}
+		}
+		close(updates)
+		return updates
+	}
+	receive := func(stream *QuoteStream) []uint64 {
+		var prices []uint64
+		for update := range stream.C {
+			prices = append(prices, update.Quote.LastPrice)
+		}
+		return prices
+	}
+
+	// the source is drained before the consumer reads, as if it were slow
+	drain := func(stream *QuoteStream, updates <-chan QuoteUpdate) *QuoteStream {
+		for len(updates) > 0 {
+			runtime.Gosched()
+		}
+		return stream
+	}
+
+	updates := send("A", "B", "C", "D", "E")
+	stream := BufferQuotes(context.Background(), updates, 2, OverflowDropOldest)
+	assert.Equal(t, []uint64{3, 4}, receive(drain(stream, updates)))
+	assert.Equal(t, uint64(3), stream.Dropped())
+
+	updates = send("A", "B", "A", "C", "A")
+	stream = BufferQuotes(context.Background(), updates, 2, OverflowConflate)
+	assert.Equal(t, []uint64{3, 4}, receive(drain(stream, updates)))
+	assert.Equal(t, uint64(3), stream.Dropped())
+
+	stream = BufferQuotes(context.Background(), send("A", "B", "C", "D", "E"), 2, OverflowBlock)
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4}, receive(stream))
+	assert.Equal(t, uint64(0), stream.Dropped())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream = BufferQuotes(ctx, make(chan QuoteUpdate), 1, OverflowBlock)
+	cancel()
+	_, ok := <-stream.C
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { BufferQuotes(context.Background(), updates, 0, OverflowBlock) })
+	assert.Equal(t, "conflate", OverflowConflate.String())
+}
+
+func TestBufferExecutions(t *testing.T) {
+	executions := make(chan *Execution, 3)
+	for i, id := range []int64{1, 2, 1} {
+		executions <- &Execution{Venue: testVenue, Order: Order{OrderID: id, TotalFilled: uint64(i)}}
+	}
+	close(executions)
+
+	stream := BufferExecutions(context.Background(), executions, 10, OverflowConflate)
+	for len(executions) > 0 {
+		runtime.Gosched()
+	}
+	var filled []uint64
+	for execution := range stream.C {
+		filled = append(filled, execution.Order.TotalFilled)
+	}
+	assert.Equal(t, []uint64{2, 1}, filled)
+	assert.Equal(t, uint64(1), stream.Dropped())
+}
@@ -0,0 +1,161 @@
+package stockfighter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// A JournalFrame represents a raw frame of a WebSocket stream, as received.
+type JournalFrame struct {
+	// Receive time
+	Time time.Time `json:"t"`
+
+	// Stream path, e.g. /ws/:account/venues/:venue/tickertape
+	Stream string `json:"stream"`
+
+	// Frame payload, byte for byte
+	Data []byte `json:"data"`
+}
+
+// A StreamJournal writes the raw frames of WebSocket streams to a journal, as
+// JSON lines, so that a session can be replayed offline exactly as it was
+// received (see ReplayJournal).
+//
+// A StreamJournal is safe for concurrent use, e.g. by the readers of several
+// streams.
+//
+// You can create a new StreamJournal using NewStreamJournal function.
+type StreamJournal struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewStreamJournal creates a new StreamJournal writing to w. This never
+// returns nil.
+func NewStreamJournal(w io.Writer) *StreamJournal {
+	return &StreamJournal{encoder: json.NewEncoder(w)}
+}
+
+// Record writes a frame of a stream, received at the given time, to the
+// journal.
+func (journal *StreamJournal) Record(stream string, received time.Time, data []byte) error {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+
+	return journal.encoder.Encode(JournalFrame{Time: received, Stream: stream, Data: data})
+}
+
+// A JournalReader reads frames back from a journal written by a
+// StreamJournal.
+//
+// You can create a new JournalReader using NewJournalReader function.
+type JournalReader struct {
+	decoder *json.Decoder
+}
+
+// NewJournalReader creates a new JournalReader reading from r. This never
+// returns nil.
+func NewJournalReader(r io.Reader) *JournalReader {
+	return &JournalReader{decoder: json.NewDecoder(bufio.NewReader(r))}
+}
+
+// Next returns the next frame of the journal, in the order they were
+// recorded. Next returns io.EOF at the end of the journal.
+func (reader *JournalReader) Next() (*JournalFrame, error) {
+	var frame JournalFrame
+	if err := reader.decoder.Decode(&frame); err != nil {
+		return nil, err
+	}
+	return &frame, nil
+}
+
+// ReplayJournal calls handler with the frames of a journal, in order, paced
+// by their receive times divided by speed (e.g. 2 replays twice as fast;
+// 0 does not wait), until the end of the journal, an error of handler, or ctx
+// is done. It returns nil at the end of the journal.
+//
+// Handlers decode frames with DecodeTickertapeFrame and
+// DecodeExecutionFrame, like live streams do, so that a session replays
+// exactly as it happened.
+func ReplayJournal(ctx context.Context, r io.Reader, speed float64, handler func(*JournalFrame) error) error {
+	reader := NewJournalReader(r)
+
+	var first time.Time
+	start := time.Now()
+	for {
+		frame, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if speed > 0 {
+			if first.IsZero() {
+				first = frame.Time
+			}
+			due := start.Add(time.Duration(float64(frame.Time.Sub(first)) / speed))
+			if wait := time.Until(due); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := handler(frame); err != nil {
+			return err
+		}
+	}
+}
+
+// DecodeTickertapeFrame decodes a frame of a tickertape stream.
+func DecodeTickertapeFrame(data []byte) (*Quote, error) {
+	return decodeTickertapeFrame(JSONCodec{}, data)
+}
+
+// DecodeExecutionFrame decodes a frame of an executions stream.
+func DecodeExecutionFrame(data []byte) (*Execution, error) {
+	return decodeExecutionFrame(JSONCodec{}, data)
+}
+
+// DecodeTickertapeFrame decodes a frame of a tickertape stream with the codec
+// of the client (see WithCodec).
+func (client *Client) DecodeTickertapeFrame(data []byte) (*Quote, error) {
+	return decodeTickertapeFrame(client.transport.codec, data)
+}
+
+// DecodeExecutionFrame decodes a frame of an executions stream with the codec
+// of the client (see WithCodec).
+func (client *Client) DecodeExecutionFrame(data []byte) (*Execution, error) {
+	return decodeExecutionFrame(client.transport.codec, data)
+}
+
+func decodeTickertapeFrame(codec Codec, data []byte) (*Quote, error) {
+	var resp struct {
+		Quote Quote `json:"quote"`
+	}
+	if err := decodeResponse(codec, 0, data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Quote, nil
+}
+
+func decodeExecutionFrame(codec Codec, data []byte) (*Execution, error) {
+	var execution Execution
+	if err := decodeResponse(codec, 0, data, &execution); err != nil {
+		return nil, err
+	}
+	return &execution, nil
+}
@@ -0,0 +1,59 @@
+package stockfighter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamJournal(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	tickertape := "/ws/" + testAccount + "/venues/" + testVenue + "/tickertape"
+	executions := "/ws/" + testAccount + "/venues/" + testVenue + "/executions"
+	quoteFrame := []byte(`{"ok":true,"quote":{"symbol":"FOOBAR","venue":"TESTEX","bid":5000,"bidSize":10,"quoteTime":"2015-12-04T09:02:16Z"}}`)
+	executionFrame := []byte(`{"ok":true,"account":"EXB123456","venue":"TESTEX","symbol":"FOOBAR","order":{"id":42},` +
+		"\n" + `"standingId":42,"incomingId":43,"price":5000,"filled":10,"filledAt":"2015-12-04T09:02:16.5Z"}`)
+
+	var buf bytes.Buffer
+	journal := NewStreamJournal(&buf)
+	assert.Nil(t, journal.Record(tickertape, ts, quoteFrame))
+	assert.Nil(t, journal.Record(executions, ts.Add(20*time.Millisecond), executionFrame))
+	assert.Nil(t, journal.Record(tickertape, ts.Add(40*time.Millisecond), []byte("not json")))
+	recorded := buf.Bytes()
+
+	var frames []*JournalFrame
+	start := time.Now()
+	err := ReplayJournal(context.Background(), bytes.NewReader(recorded), 2, func(frame *JournalFrame) error {
+		frames = append(frames, frame)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+	assert.Len(t, frames, 3)
+	assert.Equal(t, executions, frames[1].Stream)
+	assert.Equal(t, ts.Add(20*time.Millisecond), frames[1].Time)
+	assert.Equal(t, executionFrame, frames[1].Data)
+
+	quote, err := DecodeTickertapeFrame(frames[0].Data)
+	assert.Nil(t, err)
+	assert.Equal(t, &Quote{Venue: testVenue, Symbol: testStock, HasBid: true, BidPrice: 5000, BidSize: 10, QuoteTime: ts}, quote)
+	execution, err := DecodeExecutionFrame(frames[1].Data)
+	assert.Nil(t, err)
+	assert.False(t, execution.Aggressive())
+	assert.Equal(t, uint64(10), execution.Filled)
+	_, err = DecodeTickertapeFrame(frames[2].Data)
+	var decodeErr *ErrorDecode
+	assert.True(t, errors.As(err, &decodeErr))
+
+	stop := errors.New("stop")
+	err = ReplayJournal(context.Background(), bytes.NewReader(recorded), 0, func(frame *JournalFrame) error { return stop })
+	assert.Equal(t, stop, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = ReplayJournal(ctx, bytes.NewReader(recorded), 0, func(frame *JournalFrame) error { return nil })
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,215 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Kinds of stream warnings.
+const (
+	StreamWarningLag     = "lag"
+	StreamWarningStalled = "stalled"
+	StreamWarningResumed = "resumed"
+)
+
+// A StreamWarning represents a problem with a stream of quotes, raised by a
+// StreamWatchdog.
+type StreamWarning struct {
+	Kind string
+
+	// Local time the warning was raised at
+	Time time.Time
+
+	// Stock of the late quote, for lag warnings
+	Stock string
+
+	// Gap between the quote time and its receipt for lag warnings, or time
+	// since the last update for stall and resume warnings
+	Lag time.Duration
+
+	// Whether updates are delivered from the fallback
+	Fallback bool
+}
+
+func (w StreamWarning) String() string {
+	if w.Kind == StreamWarningLag {
+		return fmt.Sprintf("%v quote received %v late", w.Stock, w.Lag)
+	}
+	return fmt.Sprintf("stream %v after %v (fallback: %v)", w.Kind, w.Lag, w.Fallback)
+}
+
+// A StreamStatus represents the state of a stream watched by a
+// StreamWatchdog.
+type StreamStatus struct {
+	// Local time of the last update of the stream, zero if none
+	LastUpdate time.Time
+
+	// Gap between the quote time and the receipt of the last quote
+	Lag time.Duration
+
+	// Whether the stream is stalled
+	Stalled bool
+}
+
+// A StreamWatchdog watches a stream of quotes for lag (quotes received long
+// after their quote time) and stalls (no update at all), and can switch to a
+// fallback source, typically a QuotePoller, while the stream is stalled:
+//
+//    watchdog := NewStreamWatchdog(5 * time.Second)
+//    watchdog.Fallback = NewQuotePoller(client, venue, stocks, time.Second).Start
+//    for update := range watchdog.Start(ctx, stream) {
+//        // ...
+//    }
+//
+// Note that a stream of a quiet market may be silent for a while: the stall
+// timeout should be set accordingly.
+//
+// You can create a new StreamWatchdog using NewStreamWatchdog function.
+type StreamWatchdog struct {
+	// Largest gap between the quote time and the receipt of a quote before a
+	// lag warning is raised (0 disables lag warnings)
+	MaxLag time.Duration
+
+	// Source started when the stream stalls, and stopped when the stream
+	// delivers again (nil to only raise warnings)
+	Fallback func(ctx context.Context) <-chan QuoteUpdate
+
+	// Function called with each warning raised, if not nil
+	OnWarning func(StreamWarning)
+
+	stallTimeout time.Duration
+
+	mu       sync.Mutex
+	warnings []StreamWarning
+	status   StreamStatus
+}
+
+// NewStreamWatchdog creates a new StreamWatchdog considering the stream
+// stalled after the given timeout without update. This never returns nil.
+func NewStreamWatchdog(stallTimeout time.Duration) *StreamWatchdog {
+	if stallTimeout <= 0 {
+		panic(fmt.Errorf("Invalid stall timeout: %v", stallTimeout))
+	}
+
+	return &StreamWatchdog{stallTimeout: stallTimeout}
+}
+
+// Warnings returns the warnings raised so far, in order.
+func (watchdog *StreamWatchdog) Warnings() []StreamWarning {
+	watchdog.mu.Lock()
+	defer watchdog.mu.Unlock()
+
+	return append([]StreamWarning(nil), watchdog.warnings...)
+}
+
+// Status returns the current state of the stream.
+func (watchdog *StreamWatchdog) Status() StreamStatus {
+	watchdog.mu.Lock()
+	defer watchdog.mu.Unlock()
+
+	return watchdog.status
+}
+
+// Start starts watching updates in a new goroutine and returns the channel
+// the updates, and those of the fallback while the stream is stalled, are
+// forwarded on.
+//
+// Watching stops and the channel is closed when ctx is done or updates is
+// closed.
+func (watchdog *StreamWatchdog) Start(ctx context.Context, updates <-chan QuoteUpdate) <-chan QuoteUpdate {
+	out := make(chan QuoteUpdate)
+
+	go func() {
+		defer close(out)
+
+		timer := time.NewTimer(watchdog.stallTimeout)
+		defer timer.Stop()
+
+		last := time.Now()
+		stalled := false
+		var fallback <-chan QuoteUpdate
+		stopFallback := func() {}
+		defer func() { stopFallback() }()
+
+		for {
+			var update QuoteUpdate
+			select {
+			case u, ok := <-updates:
+				if !ok {
+					return
+				}
+				update = u
+
+				now := time.Now()
+				if stalled {
+					// the timer fired already
+					stalled = false
+					stopFallback()
+					stopFallback, fallback = func() {}, nil
+					watchdog.warn(StreamWarning{Kind: StreamWarningResumed, Time: now, Lag: now.Sub(last)})
+				} else if !timer.Stop() {
+					<-timer.C
+				}
+				timer.Reset(watchdog.stallTimeout)
+				last = now
+
+				var lag time.Duration
+				if update.Quote != nil && !update.Quote.QuoteTime.IsZero() {
+					lag = now.Sub(update.Quote.QuoteTime)
+				}
+				watchdog.setStatus(StreamStatus{LastUpdate: now, Lag: lag})
+				if watchdog.MaxLag > 0 && lag > watchdog.MaxLag {
+					watchdog.warn(StreamWarning{Kind: StreamWarningLag, Time: now, Stock: update.Stock, Lag: lag})
+				}
+
+			case u, ok := <-fallback:
+				if !ok {
+					fallback = nil
+					continue
+				}
+				update = u
+
+			case now := <-timer.C:
+				stalled = true
+				watchdog.mu.Lock()
+				watchdog.status.Stalled = true
+				watchdog.mu.Unlock()
+				if watchdog.Fallback != nil {
+					fallbackCtx, cancel := context.WithCancel(ctx)
+					stopFallback, fallback = cancel, watchdog.Fallback(fallbackCtx)
+				}
+				watchdog.warn(StreamWarning{Kind: StreamWarningStalled, Time: now, Lag: now.Sub(last), Fallback: fallback != nil})
+				continue
+
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case out <- update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func (watchdog *StreamWatchdog) setStatus(status StreamStatus) {
+	watchdog.mu.Lock()
+	watchdog.status = status
+	watchdog.mu.Unlock()
+}
+
+func (watchdog *StreamWatchdog) warn(warning StreamWarning) {
+	watchdog.mu.Lock()
+	watchdog.warnings = append(watchdog.warnings, warning)
+	watchdog.mu.Unlock()
+
+	if watchdog.OnWarning != nil {
+		watchdog.OnWarning(warning)
+	}
+}
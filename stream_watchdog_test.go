@@ -0,0 +1,64 @@
+package stockfighter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamWatchdog(t *testing.T) {
+	watchdog := NewStreamWatchdog(50 * time.Millisecond)
+	watchdog.MaxLag = time.Minute
+
+	fallbackStopped := make(chan struct{})
+	watchdog.Fallback = func(ctx context.Context) <-chan QuoteUpdate {
+		updates := make(chan QuoteUpdate, 1)
+		updates <- QuoteUpdate{Stock: "POLLED", Quote: &Quote{}}
+		go func() {
+			<-ctx.Done()
+			close(fallbackStopped)
+		}()
+		return updates
+	}
+
+	stream := make(chan QuoteUpdate)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := watchdog.Start(ctx, stream)
+	assert.True(t, watchdog.Status().LastUpdate.IsZero())
+
+	// a fresh quote, then a late one
+	stream <- QuoteUpdate{Stock: testStock, Quote: &Quote{QuoteTime: time.Now()}}
+	assert.Equal(t, testStock, (<-updates).Stock)
+	stream <- QuoteUpdate{Stock: testStock, Quote: &Quote{QuoteTime: time.Now().Add(-time.Hour)}}
+	<-updates
+	status := watchdog.Status()
+	assert.False(t, status.LastUpdate.IsZero())
+	assert.True(t, status.Lag >= time.Hour)
+
+	// the stream stalls: updates come from the fallback until it resumes
+	assert.Equal(t, "POLLED", (<-updates).Stock)
+	assert.True(t, watchdog.Status().Stalled)
+	stream <- QuoteUpdate{Stock: testStock, Quote: &Quote{QuoteTime: time.Now()}}
+	assert.Equal(t, testStock, (<-updates).Stock)
+	assert.False(t, watchdog.Status().Stalled)
+	<-fallbackStopped
+
+	close(stream)
+	_, ok := <-updates
+	assert.False(t, ok)
+
+	warnings := watchdog.Warnings()
+	if assert.Len(t, warnings, 3) {
+		assert.Equal(t, StreamWarningLag, warnings[0].Kind)
+		assert.True(t, warnings[0].Lag >= time.Hour)
+		assert.Equal(t, StreamWarningStalled, warnings[1].Kind)
+		assert.True(t, warnings[1].Fallback)
+		assert.True(t, warnings[1].Lag >= 50*time.Millisecond)
+		assert.Equal(t, StreamWarningResumed, warnings[2].Kind)
+	}
+
+	assert.Panics(t, func() { NewStreamWatchdog(0) })
+}
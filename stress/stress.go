@@ -0,0 +1,319 @@
+/*
+Package stress runs strategies against perturbed market data (latency
+jitter, price shocks, widened spreads, dropped quotes) many times, to get
+the distribution of their outcomes and flag fragile parameterizations
+before a scored run.
+
+The market data is a quote recording (see stockfighter.QuoteReplayer); a Run
+replays the quotes it is given through a strategy and measures how it did,
+like an optimize.Backtest:
+
+    h := stress.NewHarness(stress.Perturbation{
+        Jitter:         200 * time.Millisecond,
+        ShockRate:      0.001,
+        ShockSize:      0.05,
+        SpreadWidening: 10,
+        DropRate:       0.05,
+    })
+    reports, err := h.Screen(ctx, quotes, trials, runQuoter)
+    if err != nil {
+        return err
+    }
+    for _, report := range reports {
+        if report.Fragile {
+            log.Printf("%v is fragile: %v", report.Params, report.Distribution)
+        }
+    }
+*/
+package stress
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"gpk.io/stockfighter"
+	"gpk.io/stockfighter/optimize"
+)
+
+// A Perturbation describes how market data is perturbed. Zero values leave
+// the data as is.
+type Perturbation struct {
+	// Maximum delay added to the arrival of each quote; quotes are
+	// reordered by arrival (their QuoteTime is unchanged)
+	Jitter time.Duration
+
+	// Probability, for each quote, of a price shock, and shock size as a
+	// fraction of the price. A shock moves the prices of the stock up or
+	// down for the rest of the data.
+	ShockRate float64
+	ShockSize float64
+
+	// Maximum number of cents the best bid and ask are each moved away from
+	// the other
+	SpreadWidening uint64
+
+	// Probability of each quote being dropped
+	DropRate float64
+}
+
+// Apply returns a perturbed copy of the quotes, using rng.
+func (p Perturbation) Apply(quotes []stockfighter.RecordedQuote, rng *rand.Rand) []stockfighter.RecordedQuote {
+	type arrival struct {
+		quote stockfighter.RecordedQuote
+		at    time.Time
+	}
+
+	factors := make(map[string]float64)
+	arrivals := make([]arrival, 0, len(quotes))
+	for _, quote := range quotes {
+		key := quote.Venue + "/" + quote.Stock
+		if _, ok := factors[key]; !ok {
+			factors[key] = 1
+		}
+		if p.ShockRate > 0 && rng.Float64() < p.ShockRate {
+			if rng.Intn(2) == 0 {
+				factors[key] *= 1 + p.ShockSize
+			} else {
+				factors[key] *= 1 - p.ShockSize
+			}
+		}
+		if p.DropRate > 0 && rng.Float64() < p.DropRate {
+			continue
+		}
+
+		factor := factors[key]
+		quote.BidPrice = scale(quote.BidPrice, factor)
+		quote.AskPrice = scale(quote.AskPrice, factor)
+		quote.LastPrice = scale(quote.LastPrice, factor)
+		if p.SpreadWidening > 0 {
+			if quote.HasBid {
+				widening := uint64(rng.Int63n(int64(p.SpreadWidening) + 1))
+				if widening >= quote.BidPrice {
+					widening = quote.BidPrice - 1
+				}
+				quote.BidPrice -= widening
+			}
+			if quote.HasAsk {
+				quote.AskPrice += uint64(rng.Int63n(int64(p.SpreadWidening) + 1))
+			}
+		}
+
+		at := quote.QuoteTime
+		if p.Jitter > 0 {
+			at = at.Add(time.Duration(rng.Int63n(int64(p.Jitter))))
+		}
+		arrivals = append(arrivals, arrival{quote: quote, at: at})
+	}
+
+	sort.SliceStable(arrivals, func(i, j int) bool { return arrivals[i].at.Before(arrivals[j].at) })
+	perturbed := make([]stockfighter.RecordedQuote, len(arrivals))
+	for i, arrival := range arrivals {
+		perturbed[i] = arrival.quote
+	}
+	return perturbed
+}
+
+func scale(price uint64, factor float64) uint64 {
+	if price == 0 || factor == 1 {
+		return price
+	}
+	scaled := uint64(math.Round(float64(price) * factor))
+	if scaled < 1 {
+		scaled = 1
+	}
+	return scaled
+}
+
+// A Run runs a strategy with the given parameters against the given quotes.
+// It must be safe for concurrent use.
+type Run func(ctx context.Context, params optimize.Params, quotes []stockfighter.RecordedQuote) (optimize.Result, error)
+
+// A Distribution represents the outcomes of the runs of a strategy.
+type Distribution struct {
+	// Results of the successful runs, and number of failed runs
+	Results []optimize.Result
+	Errors  int
+
+	// P&L statistics over the successful runs, in cents
+	MeanPnL   float64
+	StdDevPnL float64
+	MinPnL    int64
+	MedianPnL int64
+	P5PnL     int64
+
+	// Worst maximum drawdown, in cents
+	MaxDrawdown int64
+
+	// Share of the successful runs losing money, between 0 and 1
+	LossRate float64
+}
+
+func (d *Distribution) String() string {
+	return fmt.Sprintf("%v runs (%v failed): P&L $%.2f ± $%.2f, median $%.2f, 5th percentile $%.2f, min $%.2f; %.0f%% losing",
+		len(d.Results)+d.Errors, d.Errors, d.MeanPnL/100.0, d.StdDevPnL/100.0, float64(d.MedianPnL)/100.0, float64(d.P5PnL)/100.0,
+		float64(d.MinPnL)/100.0, 100*d.LossRate)
+}
+
+func newDistribution(results []optimize.Result, errors int) *Distribution {
+	d := &Distribution{Results: results, Errors: errors}
+	if len(results) == 0 {
+		return d
+	}
+
+	pnls := make([]int64, len(results))
+	var sum float64
+	var losses int
+	for i, result := range results {
+		pnls[i] = result.PnL
+		sum += float64(result.PnL)
+		if result.PnL < 0 {
+			losses++
+		}
+		if result.MaxDrawdown > d.MaxDrawdown {
+			d.MaxDrawdown = result.MaxDrawdown
+		}
+	}
+	sort.Slice(pnls, func(i, j int) bool { return pnls[i] < pnls[j] })
+
+	d.MeanPnL = sum / float64(len(pnls))
+	var variance float64
+	for _, pnl := range pnls {
+		variance += (float64(pnl) - d.MeanPnL) * (float64(pnl) - d.MeanPnL)
+	}
+	d.StdDevPnL = math.Sqrt(variance / float64(len(pnls)))
+	d.MinPnL = pnls[0]
+	d.MedianPnL = pnls[len(pnls)/2]
+	d.P5PnL = pnls[len(pnls)*5/100]
+	d.LossRate = float64(losses) / float64(len(pnls))
+	return d
+}
+
+// A Report represents the outcomes of a parameterization under stress.
+type Report struct {
+	Params       optimize.Params
+	Distribution *Distribution
+
+	// Whether the parameterization lost money in too many runs, or failed
+	Fragile bool
+}
+
+// A Harness runs strategies repeatedly against perturbed market data.
+//
+// You can create a new Harness using NewHarness function.
+type Harness struct {
+	Perturbation Perturbation
+
+	// Runs per parameterization
+	Runs int
+
+	// Seed of the perturbations: run i of every parameterization uses the
+	// same perturbed data, seeded with Seed+i
+	Seed int64
+
+	// Maximum share of runs losing money of a robust parameterization
+	MaxLossRate float64
+
+	// Maximum number of runs run concurrently
+	Parallelism int
+}
+
+// NewHarness creates a new Harness with the given perturbation, running
+// strategies 100 times. This never returns nil.
+func NewHarness(perturbation Perturbation) *Harness {
+	return &Harness{
+		Perturbation: perturbation,
+		Runs:         100,
+		MaxLossRate:  0.25,
+		Parallelism:  runtime.GOMAXPROCS(0),
+	}
+}
+
+// Stress runs the strategy with the given parameters against perturbed
+// copies of the quotes, and returns the distribution of the outcomes.
+//
+// Stress returns early with ctx.Err() if the context is done.
+func (h *Harness) Stress(ctx context.Context, quotes []stockfighter.RecordedQuote, params optimize.Params, run Run) (*Distribution, error) {
+	reports, err := h.Screen(ctx, quotes, []optimize.Params{params}, run)
+	if err != nil {
+		return nil, err
+	}
+	return reports[0].Distribution, nil
+}
+
+// Screen stresses the strategy with each of the parameter sets, and returns
+// their reports, in the same order.
+//
+// Screen returns early with ctx.Err() if the context is done.
+func (h *Harness) Screen(ctx context.Context, quotes []stockfighter.RecordedQuote, paramSets []optimize.Params, run Run) ([]Report, error) {
+	// perturbed data is shared by the parameterizations so they are compared
+	// on equal terms
+	datasets := make([][]stockfighter.RecordedQuote, h.Runs)
+	for i := range datasets {
+		datasets[i] = h.Perturbation.Apply(quotes, rand.New(rand.NewSource(h.Seed+int64(i))))
+	}
+
+	type outcome struct {
+		result optimize.Result
+		err    error
+	}
+	outcomes := make([][]outcome, len(paramSets))
+	for i := range outcomes {
+		outcomes[i] = make([]outcome, h.Runs)
+	}
+
+	parallelism := h.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	type job struct{ params, run int }
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				result, err := run(ctx, paramSets[job.params], datasets[job.run])
+				outcomes[job.params][job.run] = outcome{result, err}
+			}
+		}()
+	}
+
+feed:
+	for i := range paramSets {
+		for j := 0; j < h.Runs; j++ {
+			select {
+			case jobs <- job{i, j}:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	reports := make([]Report, len(paramSets))
+	for i, params := range paramSets {
+		var results []optimize.Result
+		var errors int
+		for _, outcome := range outcomes[i] {
+			if outcome.err != nil {
+				errors++
+				continue
+			}
+			results = append(results, outcome.result)
+		}
+		d := newDistribution(results, errors)
+		reports[i] = Report{Params: params, Distribution: d, Fragile: errors > 0 || d.LossRate > h.MaxLossRate}
+	}
+	return reports, nil
+}
@@ -0,0 +1,83 @@
+package stress
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+	"gpk.io/stockfighter/optimize"
+)
+
+func testQuotes() []stockfighter.RecordedQuote {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	quotes := make([]stockfighter.RecordedQuote, 100)
+	for i := range quotes {
+		quotes[i] = stockfighter.RecordedQuote{Venue: "TESTEX", Stock: "FOOBAR", Quote: stockfighter.Quote{HasBid: true, BidPrice: 5000,
+			HasAsk: true, AskPrice: 5010, LastPrice: 5005, QuoteTime: ts.Add(time.Duration(i) * time.Second)}}
+	}
+	return quotes
+}
+
+func TestPerturbation(t *testing.T) {
+	quotes := testQuotes()
+	assert.Equal(t, quotes, Perturbation{}.Apply(quotes, rand.New(rand.NewSource(1))))
+
+	perturbed := Perturbation{DropRate: 0.5}.Apply(quotes, rand.New(rand.NewSource(1)))
+	assert.True(t, len(perturbed) > 25 && len(perturbed) < 75)
+
+	perturbed = Perturbation{ShockRate: 1, ShockSize: 0.1}.Apply(quotes[:1], rand.New(rand.NewSource(1)))
+	assert.Contains(t, []uint64{4500, 5500}, perturbed[0].BidPrice)
+	assert.Equal(t, uint64(5005), quotes[0].LastPrice)
+
+	for _, quote := range (Perturbation{SpreadWidening: 10}).Apply(quotes, rand.New(rand.NewSource(1))) {
+		assert.True(t, quote.BidPrice >= 4990 && quote.BidPrice <= 5000)
+		assert.True(t, quote.AskPrice >= 5010 && quote.AskPrice <= 5020)
+	}
+
+	perturbed = Perturbation{Jitter: 10 * time.Second}.Apply(quotes, rand.New(rand.NewSource(1)))
+	assert.Len(t, perturbed, 100)
+	var reordered bool
+	for i := 1; i < len(perturbed); i++ {
+		reordered = reordered || perturbed[i].QuoteTime.Before(perturbed[i-1].QuoteTime)
+	}
+	assert.True(t, reordered)
+}
+
+func TestHarness(t *testing.T) {
+	// a strategy earning the spread, losing when it is wide
+	run := func(ctx context.Context, params optimize.Params, quotes []stockfighter.RecordedQuote) (optimize.Result, error) {
+		var pnl int64
+		for _, quote := range quotes {
+			if int64(quote.AskPrice-quote.BidPrice) > int64(params["maxSpread"]) {
+				pnl -= 10
+			} else {
+				pnl++
+			}
+		}
+		return optimize.Result{PnL: pnl}, nil
+	}
+
+	h := NewHarness(Perturbation{SpreadWidening: 10})
+	h.Runs = 20
+	reports, err := h.Screen(context.Background(), testQuotes(), []optimize.Params{{"maxSpread": 30}, {"maxSpread": 15}}, run)
+	assert.Nil(t, err)
+	assert.Len(t, reports, 2)
+	assert.False(t, reports[0].Fragile)
+	assert.Equal(t, float64(100), reports[0].Distribution.MeanPnL)
+	assert.Equal(t, float64(0), reports[0].Distribution.LossRate)
+	assert.True(t, reports[1].Fragile)
+	assert.Equal(t, float64(1), reports[1].Distribution.LossRate)
+	assert.Len(t, reports[1].Distribution.Results, 20)
+
+	d, err := h.Stress(context.Background(), testQuotes(), optimize.Params{"maxSpread": 30}, run)
+	assert.Nil(t, err)
+	assert.Equal(t, "20 runs (0 failed): P&L $1.00 ± $0.00, median $1.00, 5th percentile $1.00, min $1.00; 0% losing", d.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = h.Stress(ctx, testQuotes(), optimize.Params{}, run)
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,251 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Stream kinds of the WebSocket API.
+const (
+	StreamTickertape = "tickertape"
+	StreamExecutions = "executions"
+)
+
+// A Subscription represents a stream of the WebSocket API: the tickertape or
+// the executions of an account in a venue, optionally of a single stock.
+type Subscription struct {
+	Kind    string
+	Account string
+	Venue   string
+	Stock   string
+}
+
+// Path returns the path of the stream, relative to the WebSocket base URL.
+func (sub Subscription) Path() string {
+	path := fmt.Sprintf("/ws/%v/venues/%v/%v", sub.Account, sub.Venue, sub.Kind)
+	if sub.Stock != "" {
+		path += "/stocks/" + sub.Stock
+	}
+	return path
+}
+
+// A StreamConn is an open stream connection, e.g. a WebSocket.
+type StreamConn interface {
+	// ReadFrame blocks until the next frame is received, or the connection
+	// fails or is closed.
+	ReadFrame() ([]byte, error)
+
+	Close() error
+}
+
+// A StreamDialFunc opens a connection to the stream with the given path.
+type StreamDialFunc func(ctx context.Context, path string) (StreamConn, error)
+
+// Connection states of a subscription.
+const (
+	// The stream is connected (or reconnected)
+	StreamConnected = "connected"
+
+	// State was resynced after connecting: Orderbook or Orders is set, or
+	// Err if resyncing failed
+	StreamResynced = "resynced"
+
+	// The stream connection failed, or could not be opened: Err is set. It
+	// is reopened after the reconnect delay.
+	StreamDisconnected = "disconnected"
+)
+
+// A StreamEvent represents a change of the connection state of a
+// subscription.
+type StreamEvent struct {
+	Subscription Subscription
+	State        string
+	Time         time.Time
+
+	// Fresh orderbook of the stock of a tickertape subscription, or orders
+	// of the account (of the stock, if any) of an executions subscription,
+	// after a resync
+	Orderbook *Orderbook
+	Orders    []Order
+
+	// Error of the connection or of the resync, if any
+	Err error
+}
+
+// A SubscriptionManager keeps the desired WebSocket subscriptions open,
+// reopening them after failures and resyncing state when they connect:
+// orderbook snapshots for tickertape subscriptions of a stock, and the orders
+// of the account for executions subscriptions, so that the messages missed
+// while disconnected do not matter.
+//
+// You can create a new SubscriptionManager using NewSubscriptionManager
+// function.
+type SubscriptionManager struct {
+	// Delay before reopening a failed stream
+	ReconnectDelay time.Duration
+
+	client  *Client
+	dial    StreamDialFunc
+	handler func(Subscription, []byte)
+
+	mu      sync.Mutex
+	ctx     context.Context
+	events  chan StreamEvent
+	cancels map[Subscription]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewSubscriptionManager creates a new SubscriptionManager opening streams
+// with dial, resyncing state with client, and calling handler with each frame
+// received. handler is called concurrently by the streams. This never returns
+// nil.
+func NewSubscriptionManager(client *Client, dial StreamDialFunc, handler func(sub Subscription, frame []byte)) *SubscriptionManager {
+	return &SubscriptionManager{
+		ReconnectDelay: time.Second,
+		client:         client,
+		dial:           dial,
+		handler:        handler,
+		cancels:        make(map[Subscription]context.CancelFunc),
+	}
+}
+
+// Subscribe adds a subscription, opened right away if the manager is
+// started. Subscribing twice to the same stream does nothing.
+func (m *SubscriptionManager) Subscribe(sub Subscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.cancels[sub]; ok {
+		return
+	}
+	m.cancels[sub] = nil
+	if m.ctx != nil {
+		m.open(sub)
+	}
+}
+
+// Unsubscribe removes a subscription, closing its stream.
+func (m *SubscriptionManager) Unsubscribe(sub Subscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cancel := m.cancels[sub]; cancel != nil {
+		cancel()
+	}
+	delete(m.cancels, sub)
+}
+
+// Subscriptions returns the subscriptions of the manager.
+func (m *SubscriptionManager) Subscriptions() []Subscription {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	subs := make([]Subscription, 0, len(m.cancels))
+	for sub := range m.cancels {
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+// Start opens the subscriptions, and returns the channel the connection
+// events of all the subscriptions are delivered on. Streams wait for events
+// to be received: the channel must be drained.
+//
+// Streams are closed and the channel is closed when ctx is done. This panics
+// if the manager is already started.
+func (m *SubscriptionManager) Start(ctx context.Context) <-chan StreamEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ctx != nil {
+		panic(fmt.Errorf("SubscriptionManager already started"))
+	}
+	m.ctx = ctx
+	m.events = make(chan StreamEvent)
+	for sub := range m.cancels {
+		m.open(sub)
+	}
+
+	go func() {
+		<-ctx.Done()
+		m.wg.Wait()
+		close(m.events)
+	}()
+	return m.events
+}
+
+// open starts the goroutine keeping a subscription open. m.mu must be held.
+func (m *SubscriptionManager) open(sub Subscription) {
+	if m.ctx.Err() != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancels[sub] = cancel
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.run(ctx, sub)
+	}()
+}
+
+// run keeps a subscription open until ctx is done.
+func (m *SubscriptionManager) run(ctx context.Context, sub Subscription) {
+	for ctx.Err() == nil {
+		conn, err := m.dial(ctx, sub.Path())
+		if err == nil {
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			if m.emit(ctx, StreamEvent{Subscription: sub, State: StreamConnected}) {
+				m.emit(ctx, m.resync(sub))
+			}
+			for {
+				var frame []byte
+				frame, err = conn.ReadFrame()
+				if err != nil {
+					break
+				}
+				m.handler(sub, frame)
+			}
+			stop()
+			conn.Close()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		m.emit(ctx, StreamEvent{Subscription: sub, State: StreamDisconnected, Err: err})
+
+		timer := time.NewTimer(m.ReconnectDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+}
+
+// resync fetches the state a subscription may have missed updates of.
+func (m *SubscriptionManager) resync(sub Subscription) StreamEvent {
+	event := StreamEvent{Subscription: sub, State: StreamResynced}
+	switch {
+	case sub.Kind == StreamTickertape && sub.Stock != "":
+		event.Orderbook, event.Err = m.client.GetOrderbook(sub.Venue, sub.Stock)
+	case sub.Kind == StreamExecutions && sub.Stock != "":
+		event.Orders, event.Err = m.client.GetStockOrders(sub.Venue, sub.Account, sub.Stock)
+	case sub.Kind == StreamExecutions:
+		event.Orders, event.Err = m.client.GetAllOrders(sub.Venue, sub.Account)
+	}
+	return event
+}
+
+// emit delivers an event, unless ctx is done first.
+func (m *SubscriptionManager) emit(ctx context.Context, event StreamEvent) bool {
+	event.Time = time.Now()
+	select {
+	case m.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,140 @@
+package stockfighter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testStreamConn is a StreamConn reading frames from a channel.
+type testStreamConn struct {
+	frames chan []byte
+	once   sync.Once
+	closed chan struct{}
+}
+
+func newTestStreamConn() *testStreamConn {
+	return &testStreamConn{frames: make(chan []byte), closed: make(chan struct{})}
+}
+
+func (conn *testStreamConn) ReadFrame() ([]byte, error) {
+	select {
+	case frame, ok := <-conn.frames:
+		if !ok {
+			return nil, errors.New("connection reset")
+		}
+		return frame, nil
+	case <-conn.closed:
+		return nil, errors.New("connection closed")
+	}
+}
+
+func (conn *testStreamConn) Close() error {
+	conn.once.Do(func() { close(conn.closed) })
+	return nil
+}
+
+func TestSubscriptionPath(t *testing.T) {
+	sub := Subscription{Kind: StreamTickertape, Account: testAccount, Venue: testVenue}
+	assert.Equal(t, "/ws/"+testAccount+"/venues/TESTEX/tickertape", sub.Path())
+
+	sub = Subscription{Kind: StreamExecutions, Account: testAccount, Venue: testVenue, Stock: testStock}
+	assert.Equal(t, "/ws/"+testAccount+"/venues/TESTEX/executions/stocks/FOOBAR", sub.Path())
+}
+
+func TestSubscriptionManager(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"ok": true, "venue": "%s", "symbol": "%s", "bids": [{"price": 100, "qty": 10, "isBuy": true}]}`, testVenue, testStock)
+	})
+
+	conns := make(chan *testStreamConn, 2)
+	dial := func(ctx context.Context, path string) (StreamConn, error) {
+		conn := newTestStreamConn()
+		conns <- conn
+		return conn, nil
+	}
+	frames := make(chan string, 1)
+	manager := NewSubscriptionManager(client, dial, func(sub Subscription, frame []byte) {
+		frames <- string(frame)
+	})
+	manager.ReconnectDelay = time.Millisecond
+
+	sub := Subscription{Kind: StreamTickertape, Account: testAccount, Venue: testVenue, Stock: testStock}
+	manager.Subscribe(sub)
+	manager.Subscribe(sub)
+	assert.Equal(t, []Subscription{sub}, manager.Subscriptions())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := manager.Start(ctx)
+
+	event := <-events
+	assert.Equal(t, StreamConnected, event.State)
+	assert.Equal(t, sub, event.Subscription)
+	event = <-events
+	assert.Equal(t, StreamResynced, event.State)
+	assert.Nil(t, event.Err)
+	if assert.NotNil(t, event.Orderbook) {
+		assert.Len(t, event.Orderbook.Bids, 1)
+	}
+
+	conn := <-conns
+	conn.frames <- []byte(`{"ok": true}`)
+	assert.Equal(t, `{"ok": true}`, <-frames)
+
+	// the stream is reopened and resynced after a failure
+	close(conn.frames)
+	event = <-events
+	assert.Equal(t, StreamDisconnected, event.State)
+	assert.EqualError(t, event.Err, "connection reset")
+	assert.Equal(t, StreamConnected, (<-events).State)
+	assert.Equal(t, StreamResynced, (<-events).State)
+
+	conn = <-conns
+	manager.Unsubscribe(sub)
+	<-conn.closed
+	assert.Empty(t, manager.Subscriptions())
+
+	cancel()
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestSubscriptionManagerDialError(t *testing.T) {
+	var dials int
+	dial := func(ctx context.Context, path string) (StreamConn, error) {
+		dials++
+		if dials == 1 {
+			return nil, errors.New("dial failed")
+		}
+		return newTestStreamConn(), nil
+	}
+	manager := NewSubscriptionManager(nil, dial, func(Subscription, []byte) {})
+	manager.ReconnectDelay = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// subscriptions made after starting are opened right away
+	events := manager.Start(ctx)
+	sub := Subscription{Kind: StreamTickertape, Account: testAccount, Venue: testVenue}
+	manager.Subscribe(sub)
+
+	event := <-events
+	assert.Equal(t, StreamDisconnected, event.State)
+	assert.EqualError(t, event.Err, "dial failed")
+	assert.Equal(t, StreamConnected, (<-events).State)
+
+	// nothing to resync for the tickertape of a whole venue
+	event = <-events
+	assert.Equal(t, StreamResynced, event.State)
+	assert.Nil(t, event.Orderbook)
+	assert.Nil(t, event.Err)
+
+	assert.Panics(t, func() { manager.Start(ctx) })
+}
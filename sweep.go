@@ -0,0 +1,108 @@
+package stockfighter
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A SweepResult represents the outcome of ExecSweep.
+type SweepResult struct {
+	// Immediate-or-cancel orders placed, one per price level
+	Orders []Order
+
+	// Total filled quantity and its average price (in cents)
+	FilledQuantity uint64
+	AveragePrice   float64
+
+	// Best opposite price in the orderbook when the sweep started
+	ArrivalPrice uint64
+
+	// Average price paid over the arrival price (in cents per share). Positive
+	// values mean a worse price than the arrival price, for either direction.
+	Slippage float64
+}
+
+// Fills returns the fills of all orders placed by the sweep.
+func (r *SweepResult) Fills() []OrderFillInfo {
+	var fills []OrderFillInfo
+	for _, order := range r.Orders {
+		fills = append(fills, order.Fills...)
+	}
+	return fills
+}
+
+// ExecSweep walks the current orderbook of a stock and places
+// immediate-or-cancel orders level by level until targetQty is filled, the
+// next level is beyond limitPrice, or the orderbook is exhausted.
+//
+// On error, the returned result describes the orders placed so far.
+func (client *Client) ExecSweep(venue, stock, account, direction string, targetQty, limitPrice uint64) (*SweepResult, error) {
+	if direction != OrderDirectionBuy && direction != OrderDirectionSell {
+		return nil, fmt.Errorf("Invalid order direction: %v", direction)
+	}
+
+	orderbook, err := client.GetOrderbook(venue, stock)
+	if err != nil {
+		return nil, err
+	}
+
+	isBuy := direction == OrderDirectionBuy
+	levels := orderbookLevels(orderbook, !isBuy)
+	prices := make([]uint64, 0, len(levels))
+	for price := range levels {
+		prices = append(prices, price)
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		if isBuy {
+			return prices[i] < prices[j]
+		}
+		return prices[i] > prices[j]
+	})
+
+	result := &SweepResult{}
+	if len(prices) > 0 {
+		result.ArrivalPrice = prices[0]
+	}
+
+	var notional uint64
+	for _, price := range prices {
+		if result.FilledQuantity >= targetQty {
+			break
+		}
+		if (isBuy && price > limitPrice) || (!isBuy && price < limitPrice) {
+			break
+		}
+
+		qty := targetQty - result.FilledQuantity
+		if levels[price] < qty {
+			qty = levels[price]
+		}
+
+		order, err := client.PlaceOrder(venue, stock, account, price, qty, direction, OrderTypeImmediateOrCancel)
+		if err != nil {
+			result.finish(notional, isBuy)
+			return result, err
+		}
+
+		result.Orders = append(result.Orders, *order)
+		for _, fill := range order.Fills {
+			result.FilledQuantity += fill.Quantity
+			notional += fill.Price * fill.Quantity
+		}
+	}
+
+	result.finish(notional, isBuy)
+	return result, nil
+}
+
+func (r *SweepResult) finish(notional uint64, isBuy bool) {
+	if r.FilledQuantity == 0 {
+		return
+	}
+
+	r.AveragePrice = float64(notional) / float64(r.FilledQuantity)
+	r.Slippage = r.AveragePrice - float64(r.ArrivalPrice)
+	if !isBuy {
+		r.Slippage = -r.Slippage
+	}
+}
@@ -0,0 +1,150 @@
+package stockfighter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A SweepEvent represents an aggressive order that swept several price levels
+// of the book, or traded a block of shares.
+type SweepEvent struct {
+	Venue  string
+	Symbol string
+
+	// ID and direction of the aggressive (incoming) order
+	AggressorID int64
+	Direction   string
+
+	// Price levels swept, and shares traded across them
+	Levels int
+	Size   uint64
+
+	// Price of the first and last fills, and the difference between them
+	// (positive when the sweep lifted prices)
+	FirstPrice uint64
+	LastPrice  uint64
+	Impact     int64
+
+	// Timestamps of the first and last fills
+	Start time.Time
+	End   time.Time
+}
+
+func (e SweepEvent) String() string {
+	return fmt.Sprintf("%v %v sweep of %v levels by order %v: %v shares, $%.2f to $%.2f (%+d)", e.Symbol, e.Direction, e.Levels,
+		e.AggressorID, e.Size, float64(e.FirstPrice)/100.0, float64(e.LastPrice)/100.0, e.Impact)
+}
+
+type aggressor struct {
+	event    SweepEvent
+	prices   map[uint64]bool
+	standing map[int64]bool
+}
+
+// A SweepDetector identifies, from the executions of a venue, aggressive
+// orders sweeping several levels of the book or trading large blocks.
+//
+// Fills are grouped by incoming order. An aggressor is complete when its
+// order is, or when it did not fill for the window duration (see Flush).
+//
+// You can create a new SweepDetector using NewSweepDetector function.
+type SweepDetector struct {
+	// Price levels an aggressor must fill at to be reported
+	MinLevels int
+
+	// Shares an aggressor must trade to be reported even at a single level
+	// (0 disables block trades)
+	BlockSize uint64
+
+	mu         sync.Mutex
+	window     time.Duration
+	aggressors map[int64]*aggressor
+}
+
+// NewSweepDetector creates a new SweepDetector grouping the fills of an
+// aggressor within the given window. This never returns nil.
+func NewSweepDetector(window time.Duration) *SweepDetector {
+	if window <= 0 {
+		panic(fmt.Errorf("Invalid sweep window: %v", window))
+	}
+
+	return &SweepDetector{
+		MinLevels:  2,
+		window:     window,
+		aggressors: make(map[int64]*aggressor),
+	}
+}
+
+// Add adds an execution. It returns the sweep of the aggressor of the
+// execution if the execution completes it and it is to be reported.
+//
+// The same fill may be reported once per account involved; duplicates are
+// ignored.
+func (d *SweepDetector) Add(execution *Execution) (SweepEvent, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a, ok := d.aggressors[execution.IncomingID]
+	if !ok {
+		direction := execution.Order.Direction
+		if !execution.Aggressive() {
+			direction = oppositeDirection(direction)
+		}
+		a = &aggressor{
+			event: SweepEvent{Venue: execution.Venue, Symbol: execution.Symbol, AggressorID: execution.IncomingID, Direction: direction,
+				FirstPrice: execution.Price, Start: execution.FilledAt},
+			prices:   make(map[uint64]bool),
+			standing: make(map[int64]bool),
+		}
+		d.aggressors[execution.IncomingID] = a
+	}
+
+	if !a.standing[execution.StandingID] {
+		a.standing[execution.StandingID] = true
+		a.prices[execution.Price] = true
+		a.event.Levels = len(a.prices)
+		a.event.Size += execution.Filled
+		a.event.LastPrice = execution.Price
+		a.event.Impact = int64(execution.Price) - int64(a.event.FirstPrice)
+		a.event.End = execution.FilledAt
+	}
+
+	if !execution.IncomingComplete {
+		return SweepEvent{}, false
+	}
+	delete(d.aggressors, execution.IncomingID)
+	return a.event, d.reported(a.event)
+}
+
+// Flush completes the aggressors that did not fill within the window before
+// now, and returns the sweeps to be reported, in start order.
+func (d *SweepDetector) Flush(now time.Time) []SweepEvent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var events []SweepEvent
+	for id, a := range d.aggressors {
+		if now.Sub(a.event.End) < d.window {
+			continue
+		}
+		delete(d.aggressors, id)
+		if d.reported(a.event) {
+			events = append(events, a.event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events
+}
+
+func (d *SweepDetector) reported(event SweepEvent) bool {
+	return event.Levels >= d.MinLevels || (d.BlockSize > 0 && event.Size >= d.BlockSize)
+}
+
+func oppositeDirection(direction string) string {
+	if direction == OrderDirectionBuy {
+		return OrderDirectionSell
+	}
+	return OrderDirectionBuy
+}
@@ -0,0 +1,47 @@
+package stockfighter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSweepDetector(t *testing.T) {
+	ts := time.Date(2015, 12, 4, 9, 2, 16, 0, time.UTC)
+	d := NewSweepDetector(time.Second)
+	d.BlockSize = 1000
+
+	// a buy order sweeping three ask levels, seen from the accounts resting
+	// on the book (sell orders)
+	fill := func(standingID, incomingID int64, price, filled uint64, complete bool) *Execution {
+		return &Execution{Venue: testVenue, Symbol: testStock, Order: Order{OrderID: standingID, Direction: OrderDirectionSell},
+			StandingID: standingID, IncomingID: incomingID, Price: price, Filled: filled, FilledAt: ts, IncomingComplete: complete}
+	}
+	_, ok := d.Add(fill(1, 10, 5000, 100, false))
+	assert.False(t, ok)
+	_, ok = d.Add(fill(1, 10, 5000, 100, false)) // duplicate
+	assert.False(t, ok)
+	_, ok = d.Add(fill(2, 10, 5010, 200, false))
+	assert.False(t, ok)
+	event, ok := d.Add(fill(3, 10, 5025, 50, true))
+	assert.True(t, ok)
+	assert.Equal(t, SweepEvent{Venue: testVenue, Symbol: testStock, AggressorID: 10, Direction: OrderDirectionBuy, Levels: 3, Size: 350,
+		FirstPrice: 5000, LastPrice: 5025, Impact: 25, Start: ts, End: ts}, event)
+
+	// a single-level fill is not a sweep
+	_, ok = d.Add(fill(4, 11, 5025, 10, true))
+	assert.False(t, ok)
+
+	// a block trade, completed by the window running out
+	_, ok = d.Add(fill(5, 12, 5025, 1500, false))
+	assert.False(t, ok)
+	assert.Empty(t, d.Flush(ts.Add(time.Second/2)))
+	events := d.Flush(ts.Add(time.Second))
+	assert.Len(t, events, 1)
+	assert.Equal(t, uint64(1500), events[0].Size)
+	assert.Equal(t, 1, events[0].Levels)
+	assert.Empty(t, d.Flush(ts.Add(time.Hour)))
+
+	assert.Panics(t, func() { NewSweepDetector(0) })
+}
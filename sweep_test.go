@@ -0,0 +1,49 @@
+package stockfighter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecSweep(t *testing.T) {
+	var placed []uint64
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"ok": true, "asks": [
+				{"price": 100, "qty": 5}, {"price": 101, "qty": 5}, {"price": 101, "qty": 5}, {"price": 110, "qty": 50}
+			]}`))
+			return
+		}
+
+		var req struct {
+			Price     uint64 `json:"price"`
+			Qty       uint64 `json:"qty"`
+			OrderType string `json:"orderType"`
+		}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, OrderTypeImmediateOrCancel, req.OrderType)
+		placed = append(placed, req.Price)
+		fmt.Fprintf(w, `{"ok": true, "price": %d, "originalQty": %d, "fills": [{"price": %d, "qty": %d}]}`,
+			req.Price, req.Qty, req.Price, req.Qty)
+	})
+
+	result, err := client.ExecSweep(testVenue, testStock, testAccount, OrderDirectionBuy, 12, 105)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{100, 101}, placed)
+	assert.Equal(t, uint64(12), result.FilledQuantity)
+	assert.Equal(t, uint64(100), result.ArrivalPrice)
+	assert.InDelta(t, (5*100+7*101)/12.0, result.AveragePrice, 1e-9)
+	assert.InDelta(t, 7/12.0, result.Slippage, 1e-9)
+	assert.Len(t, result.Fills(), 2)
+
+	// the limit price stops the sweep before the target quantity is filled
+	placed = nil
+	result, err = client.ExecSweep(testVenue, testStock, testAccount, OrderDirectionBuy, 100, 101)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{100, 101}, placed)
+	assert.Equal(t, uint64(15), result.FilledQuantity)
+}
@@ -0,0 +1,80 @@
+package stockfighter
+
+import "fmt"
+
+// A Slippage represents the difference between the average fill price of an
+// order and a benchmark price.
+type Slippage struct {
+	// Benchmark price, in cents
+	Benchmark float64
+
+	// Slippage per share in cents, and in basis points of the benchmark.
+	// Slippage is positive when the fills were worse than the benchmark:
+	// higher when buying, lower when selling.
+	PerShare    float64
+	BasisPoints float64
+}
+
+func newSlippage(direction string, averagePrice, benchmark float64) *Slippage {
+	perShare := averagePrice - benchmark
+	if direction == OrderDirectionSell {
+		perShare = -perShare
+	}
+	return &Slippage{Benchmark: benchmark, PerShare: perShare, BasisPoints: 10000 * perShare / benchmark}
+}
+
+// A TCAReport represents the execution quality of a parent order worked by
+// child orders.
+type TCAReport struct {
+	// Filled quantity and its average price (in cents)
+	Filled       uint64
+	AveragePrice float64
+
+	// Slippage versus the mid price when the parent order arrived, the market
+	// VWAP over the execution window, and the limit price of the parent
+	// order. Each is nil if the benchmark is unknown.
+	Arrival *Slippage
+	VWAP    *Slippage
+	Limit   *Slippage
+}
+
+// TCA reports the execution quality of a parent order in the given direction
+// from the fills of its child orders, against three benchmarks:
+//
+//   - the mid price of the arrival quote (nil, or with an empty side, if
+//     unknown)
+//   - the market VWAP over the execution window, e.g. VWAPResult.MarketVWAP
+//     (0 if unknown)
+//   - the limit price of the parent order (0 if none)
+//
+// It returns false if the child orders have no fills.
+func TCA(direction string, limitPrice uint64, arrival *Quote, marketVWAP float64, children []Order) (*TCAReport, bool) {
+	if direction != OrderDirectionBuy && direction != OrderDirectionSell {
+		panic(fmt.Errorf("Invalid order direction: %v", direction))
+	}
+
+	report := &TCAReport{}
+	var notional uint64
+	for _, order := range children {
+		for _, fill := range order.Fills {
+			report.Filled += fill.Quantity
+			notional += fill.Price * fill.Quantity
+		}
+	}
+	if report.Filled == 0 {
+		return nil, false
+	}
+	report.AveragePrice = float64(notional) / float64(report.Filled)
+
+	if arrival != nil && arrival.HasBid && arrival.HasAsk {
+		report.Arrival = newSlippage(direction, report.AveragePrice, float64(arrival.BidPrice+arrival.AskPrice)/2)
+	}
+	if marketVWAP > 0 {
+		report.VWAP = newSlippage(direction, report.AveragePrice, marketVWAP)
+	}
+	if limitPrice > 0 {
+		report.Limit = newSlippage(direction, report.AveragePrice, float64(limitPrice))
+	}
+
+	return report, true
+}
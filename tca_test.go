@@ -0,0 +1,37 @@
+package stockfighter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTCA(t *testing.T) {
+	children := []Order{
+		{Fills: []OrderFillInfo{{Price: 5010, Quantity: 10}, {Price: 5020, Quantity: 10}}},
+		{Fills: []OrderFillInfo{{Price: 5030, Quantity: 20}}},
+	}
+	arrival := &Quote{HasBid: true, BidPrice: 4990, HasAsk: true, AskPrice: 5010}
+
+	report, ok := TCA(OrderDirectionBuy, 5050, arrival, 5020, children)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(40), report.Filled)
+	assert.InDelta(t, 5022.5, report.AveragePrice, 1e-9)
+	assert.InDelta(t, 5000, report.Arrival.Benchmark, 1e-9)
+	assert.InDelta(t, 22.5, report.Arrival.PerShare, 1e-9)
+	assert.InDelta(t, 45, report.Arrival.BasisPoints, 1e-9)
+	assert.InDelta(t, 2.5, report.VWAP.PerShare, 1e-9)
+	assert.InDelta(t, -27.5, report.Limit.PerShare, 1e-9)
+
+	// selling below the benchmark is slippage
+	report, ok = TCA(OrderDirectionSell, 0, &Quote{HasAsk: true, AskPrice: 5010}, 5030, children)
+	assert.True(t, ok)
+	assert.Nil(t, report.Arrival)
+	assert.InDelta(t, 7.5, report.VWAP.PerShare, 1e-9)
+	assert.Nil(t, report.Limit)
+
+	_, ok = TCA(OrderDirectionBuy, 0, nil, 0, []Order{{}})
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { TCA("short", 0, nil, 0, children) })
+}
@@ -0,0 +1,94 @@
+/*
+Package tracing instruments Stockfighter API calls with OpenTelemetry spans.
+
+Every API call made through a client using the instrumented transport creates
+a client span named after the API endpoint (e.g. "stockfighter.quote"), with
+the venue, stock, account, and order ID of the call as attributes:
+
+    client := stockfighter.NewClient(apiKey, stockfighter.WithHTTPClient(&http.Client{
+        Transport: tracing.RoundTripper(otel.GetTracerProvider(), nil),
+    }))
+
+API call spans are children of the span of the client context, if any (see
+stockfighter.Client.WithContext), and root spans otherwise.
+*/
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gpk.io/stockfighter"
+)
+
+// TracerName is the name of the tracer spans are created with.
+const TracerName = "gpk.io/stockfighter"
+
+// Span attribute keys.
+const (
+	AttributeEndpoint = "stockfighter.endpoint"
+	AttributeVenue    = "stockfighter.venue"
+	AttributeStock    = "stockfighter.stock"
+	AttributeAccount  = "stockfighter.account"
+	AttributeOrderID  = "stockfighter.order_id"
+)
+
+// RoundTripper returns an http.RoundTripper creating a span for every API
+// request made through next (http.DefaultTransport if nil).
+func RoundTripper(provider trace.TracerProvider, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	tracer := provider.Tracer(TracerName)
+
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		endpoint := stockfighter.ParseEndpoint(req.Method, req.URL.Path)
+
+		ctx, span := tracer.Start(req.Context(), "stockfighter."+endpoint.Name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(Attributes(endpoint)...),
+			trace.WithAttributes(attribute.String("http.method", req.Method)))
+		defer span.End()
+
+		resp, err := next.RoundTrip(req.WithContext(ctx))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return resp, err
+		}
+
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+
+		return resp, nil
+	})
+}
+
+// Attributes returns the span attributes of an API endpoint. Empty symbols
+// are omitted.
+func Attributes(endpoint stockfighter.Endpoint) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String(AttributeEndpoint, endpoint.Name)}
+	if endpoint.Venue != "" {
+		attrs = append(attrs, attribute.String(AttributeVenue, endpoint.Venue))
+	}
+	if endpoint.Stock != "" {
+		attrs = append(attrs, attribute.String(AttributeStock, endpoint.Stock))
+	}
+	if endpoint.Account != "" {
+		attrs = append(attrs, attribute.String(AttributeAccount, endpoint.Account))
+	}
+	if endpoint.OrderID != 0 {
+		attrs = append(attrs, attribute.Int64(AttributeOrderID, endpoint.OrderID))
+	}
+	return attrs
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
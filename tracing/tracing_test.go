@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gpk.io/stockfighter"
+)
+
+func TestRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"ok": false, "error": "Stock NOPE does not trade on venue TESTEX"}`))
+			return
+		}
+		w.Write([]byte(`{"ok": true, "venue": "TESTEX", "symbol": "FOOBAR", "id": 42}`))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := stockfighter.NewClient("KEY", stockfighter.WithBaseURL(server.URL), stockfighter.WithHTTPClient(&http.Client{
+		Transport: RoundTripper(provider, nil),
+	}))
+
+	// spans are children of the span of the client context
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	_, err := client.WithContext(ctx).GetOrder("TESTEX", "FOOBAR", 42)
+	assert.Nil(t, err)
+	parent.End()
+	_, err = client.CancelOrder("TESTEX", "NOPE", 42)
+	assert.NotNil(t, err)
+
+	spans := recorder.Ended()
+	assert.Equal(t, 3, len(spans))
+
+	span := spans[0]
+	assert.Equal(t, "stockfighter.order_status", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Equal(t, codes.Unset, span.Status().Code)
+	assert.Equal(t, map[attribute.Key]attribute.Value{
+		AttributeEndpoint:  attribute.StringValue(stockfighter.EndpointOrderStatus),
+		AttributeVenue:     attribute.StringValue("TESTEX"),
+		AttributeStock:     attribute.StringValue("FOOBAR"),
+		AttributeOrderID:   attribute.Int64Value(42),
+		"http.method":      attribute.StringValue("GET"),
+		"http.status_code": attribute.IntValue(200),
+	}, attributes(span))
+
+	span = spans[2]
+	assert.Equal(t, "stockfighter.cancel_order", span.Name())
+	assert.False(t, span.Parent().IsValid())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Equal(t, attribute.IntValue(404), attributes(span)["http.status_code"])
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
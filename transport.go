@@ -0,0 +1,343 @@
+package stockfighter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// transport makes API requests on behalf of a Client. It is shared by the
+// client and all clients derived from it, and is not modified after NewClient
+// returns.
+type transport struct {
+	apiKey      string
+	baseURL     string
+	gmBaseURL   string
+	httpClient  http.Client
+	logger      *slog.Logger
+	middlewares []Middleware
+	stats       *statsCounter
+	limiter     *rateLimiter
+	codec       Codec
+
+	// Header values, shared by all requests (see newRequest)
+	authHeader []string
+}
+
+// jsonContentType is the Content-Type header value of requests with a body.
+// Its capacity is its length, so that appending to it copies it.
+var jsonContentType = []string{"application/json"}[:1:1]
+
+// An apiRequest describes a single API request.
+type apiRequest struct {
+	// Context of the request (context.Background() if nil)
+	ctx context.Context
+
+	method string
+	path   string
+
+	// Base URL the path is relative to, if not the API base URL (e.g. the GM
+	// base URL)
+	baseURL string
+
+	// Request body, encoded as JSON unless nil
+	body interface{}
+
+	// Subsystem the request is made from (only used for logging)
+	subsystem string
+}
+
+// An apiResponse is the envelope of an API response, independent of the
+// endpoint it comes from.
+type apiResponse struct {
+	statusCode int
+	header     http.Header
+
+	// Raw response body, as received
+	raw []byte
+}
+
+// do makes an API request and decodes the JSON response body into respBody.
+//
+// The returned error is only about making the request or decoding the
+// response; status codes are left to the caller. The response is returned
+// along with decoding errors, so that callers can still look at the status
+// and raw body.
+func (t *transport) do(apiReq apiRequest, respBody interface{}) (*apiResponse, error) {
+	req, httpResp, start, err := t.send(apiReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		t.stats.failure()
+		return nil, err
+	}
+
+	latency := time.Since(start)
+	t.logDebug("stockfighter: response", "method", req.Method, "path", apiReq.path, "status", httpResp.StatusCode, "duration", latency)
+
+	resp := &apiResponse{
+		statusCode: httpResp.StatusCode,
+		header:     httpResp.Header,
+		raw:        raw,
+	}
+	err = decodeResponse(t.codec, httpResp.StatusCode, raw, respBody)
+	t.stats.response(httpResp.StatusCode, len(raw), latency, err)
+	return resp, err
+}
+
+// stream makes an API request like do, but passes the response body to
+// decode as it is received, instead of reading it whole first, e.g. for
+// large responses. It returns the response status along with the error of
+// decode, which should be an *ErrorDecode when the body cannot be decoded.
+func (t *transport) stream(apiReq apiRequest, decode func(statusCode int, body io.Reader) error) (int, error) {
+	req, httpResp, start, err := t.send(apiReq)
+	if err != nil {
+		return 0, err
+	}
+	defer httpResp.Body.Close()
+
+	body := &countingReader{r: httpResp.Body}
+	err = decode(httpResp.StatusCode, body)
+
+	latency := time.Since(start)
+	t.logDebug("stockfighter: response", "method", req.Method, "path", apiReq.path, "status", httpResp.StatusCode, "duration", latency)
+
+	var decodeErr *ErrorDecode
+	if errors.As(err, &decodeErr) {
+		t.stats.response(httpResp.StatusCode, body.n, latency, decodeErr)
+	} else {
+		t.stats.response(httpResp.StatusCode, body.n, latency, nil)
+	}
+	return httpResp.StatusCode, err
+}
+
+// send sends an API request, and returns the HTTP request and response, and
+// the time the request was sent at.
+func (t *transport) send(apiReq apiRequest) (*http.Request, *http.Response, time.Time, error) {
+	req, sent, err := t.newRequest(apiReq)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	if body, ok := req.Body.(*pooledBody); ok {
+		// middlewares may retry the request until the round trip returns
+		defer body.release()
+	}
+
+	t.logDebug("stockfighter: request", "method", req.Method, "path", apiReq.path, "subsystem", apiReq.subsystem)
+	t.stats.request(req.Method, apiReq.path, sent)
+
+	start := time.Now()
+	httpResp, err := t.roundTrip(req)
+	if err != nil {
+		t.logDebug("stockfighter: request failed", "method", req.Method, "path", apiReq.path, "error", err)
+		t.stats.failure()
+		return nil, nil, time.Time{}, err
+	}
+	return req, httpResp, start, nil
+}
+
+// newRequest returns the HTTP request of an API request, and the size of its
+// body.
+//
+// Header values are shared by all requests instead of allocated for each
+// one, and bodies encoded with JSONCodec use pooled buffers, which send
+// returns to the pool once the round trip is over.
+func (t *transport) newRequest(apiReq apiRequest) (*http.Request, int, error) {
+	var reqBody io.Reader
+	var getBody func() (io.ReadCloser, error)
+	var sent int
+	if apiReq.body != nil {
+		if _, ok := t.codec.(JSONCodec); ok {
+			body, err := newPooledBody(apiReq.body)
+			if err != nil {
+				return nil, 0, err
+			}
+			reqBody, getBody, sent = body, body.getBody, body.r.Len()
+		} else {
+			encoded, err := t.codec.Marshal(apiReq.body)
+			if err != nil {
+				return nil, 0, err
+			}
+			reqBody, sent = bytes.NewReader(encoded), len(encoded)
+		}
+	}
+
+	baseURL := t.baseURL
+	if apiReq.baseURL != "" {
+		baseURL = apiReq.baseURL
+	}
+
+	ctx := apiReq.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(apiReq.method), baseURL+apiReq.path, reqBody)
+	if err != nil {
+		if body, ok := reqBody.(*pooledBody); ok {
+			body.release()
+		}
+		return nil, 0, err
+	}
+	if getBody != nil {
+		req.ContentLength, req.GetBody = int64(sent), getBody
+	}
+
+	// the keys are in canonical form already
+	req.Header["X-Starfighter-Authorization"] = t.authHeader
+	if reqBody != nil {
+		req.Header["Content-Type"] = jsonContentType
+	}
+	return req, sent, nil
+}
+
+// bodyBuffer is a pooled buffer encoding request bodies.
+type bodyBuffer struct {
+	buf     bytes.Buffer
+	encoder *json.Encoder
+}
+
+var bodyBuffers = sync.Pool{
+	New: func() interface{} {
+		b := &bodyBuffer{}
+		b.encoder = json.NewEncoder(&b.buf)
+		return b
+	},
+}
+
+// pooledBody is a request body encoded in a pooled buffer. Releasing it
+// returns the buffer to the pool, after which it reads nothing.
+type pooledBody struct {
+	mu     sync.Mutex
+	buffer *bodyBuffer
+	r      bytes.Reader
+}
+
+func newPooledBody(v interface{}) (*pooledBody, error) {
+	buffer := bodyBuffers.Get().(*bodyBuffer)
+	buffer.buf.Reset()
+	if err := buffer.encoder.Encode(v); err != nil {
+		bodyBuffers.Put(buffer)
+		return nil, err
+	}
+
+	body := &pooledBody{buffer: buffer}
+	body.r.Reset(buffer.buf.Bytes())
+	return body, nil
+}
+
+func (body *pooledBody) Read(p []byte) (int, error) {
+	body.mu.Lock()
+	defer body.mu.Unlock()
+
+	if body.buffer == nil {
+		return 0, http.ErrBodyReadAfterClose
+	}
+	return body.r.Read(p)
+}
+
+// Close rewinds the body, since middlewares may retry the request.
+func (body *pooledBody) Close() error {
+	body.mu.Lock()
+	defer body.mu.Unlock()
+
+	if body.buffer != nil {
+		body.r.Reset(body.buffer.buf.Bytes())
+	}
+	return nil
+}
+
+func (body *pooledBody) release() {
+	body.mu.Lock()
+	defer body.mu.Unlock()
+
+	if body.buffer != nil {
+		body.r.Reset(nil)
+		bodyBuffers.Put(body.buffer)
+		body.buffer = nil
+	}
+}
+
+// getBody returns a copy of the body, as http.Request.GetBody does, e.g. to
+// follow redirects.
+func (body *pooledBody) getBody() (io.ReadCloser, error) {
+	body.mu.Lock()
+	defer body.mu.Unlock()
+
+	if body.buffer == nil {
+		return nil, http.ErrBodyReadAfterClose
+	}
+	return io.NopCloser(bytes.NewReader(append([]byte(nil), body.buffer.buf.Bytes()...))), nil
+}
+
+// countingReader counts the bytes read from a reader.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += n
+	return n, err
+}
+
+// decodeResponse decodes a JSON response body into respBody with codec.
+// Unknown fields are ignored and absent fields are left zero, but the body
+// must be a JSON object with an "ok" field, as every API response is.
+func decodeResponse(codec Codec, statusCode int, raw []byte, respBody interface{}) error {
+	var envelope struct {
+		OK *bool `json:"ok"`
+	}
+	if err := codec.Unmarshal(raw, &envelope); err != nil {
+		return &ErrorDecode{StatusCode: statusCode, Payload: raw, Err: err}
+	}
+	if envelope.OK == nil {
+		return &ErrorDecode{StatusCode: statusCode, Payload: raw, Err: errors.New(`missing "ok" field`)}
+	}
+
+	if err := codec.Unmarshal(raw, respBody); err != nil {
+		return &ErrorDecode{StatusCode: statusCode, Payload: raw, Err: err}
+	}
+	return nil
+}
+
+// roundTrip makes an HTTP request through the middlewares. HTTP requests
+// beyond the first one (made by middlewares) are counted as retries.
+func (t *transport) roundTrip(req *http.Request) (*http.Response, error) {
+	var attempts int32
+	next := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if atomic.AddInt32(&attempts, 1) > 1 {
+			t.stats.retry()
+		}
+		return t.httpClient.Do(req)
+	})
+	for i := len(t.middlewares) - 1; i >= 0; i-- {
+		next = t.middlewares[i](next)
+	}
+
+	return next(req)
+}
+
+func (t *transport) logDebug(msg string, args ...interface{}) {
+	if t.logger != nil {
+		t.logger.Debug(msg, args...)
+	}
+}
+
+func (t *transport) logInfo(msg string, args ...interface{}) {
+	if t.logger != nil {
+		t.logger.Info(msg, args...)
+	}
+}
@@ -0,0 +1,105 @@
+package stockfighter
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransportDo(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, testApiKey, r.Header.Get("X-Starfighter-Authorization"))
+
+		if r.Method == "POST" {
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			var req OrderRequest
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, testStock, req.Stock)
+			w.Write([]byte(`{"ok": true}`))
+			return
+		}
+
+		w.Header().Set("X-Test", "yes")
+		w.WriteHeader(502)
+		w.Write([]byte(`<html>Bad Gateway</html>`))
+	})
+
+	var body apiRespHeartbeat
+	resp, err := client.transport.do(apiRequest{method: "post", path: "/orders", body: OrderRequest{Stock: testStock}}, &body)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, resp.statusCode)
+	assert.True(t, body.OK)
+
+	// the envelope is returned along with decoding errors
+	resp, err = client.transport.do(apiRequest{method: "GET", path: "/heartbeat"}, &body)
+	assert.IsType(t, &ErrorDecode{}, err)
+	assert.Equal(t, 502, resp.statusCode)
+	assert.Equal(t, "yes", resp.header.Get("X-Test"))
+	assert.Equal(t, "<html>Bad Gateway</html>", string(resp.raw))
+}
+
+func TestTransportPooledBody(t *testing.T) {
+	var bodies []string
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(r.Body)
+		assert.Nil(t, err)
+		bodies = append(bodies, string(raw))
+		w.Write([]byte(`{"ok": true}`))
+	})
+	client.transport.middlewares = append(client.transport.middlewares, func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+			return next(req)
+		}
+	})
+
+	// retries send the whole body again
+	var body apiRespHeartbeat
+	_, err := client.transport.do(apiRequest{method: "POST", path: "/orders", body: OrderRequest{Stock: testStock}}, &body)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(bodies))
+	assert.Equal(t, bodies[0], bodies[1])
+	var sentReq OrderRequest
+	assert.Nil(t, json.Unmarshal([]byte(bodies[0]), &sentReq))
+	assert.Equal(t, testStock, sentReq.Stock)
+
+	// released bodies read nothing
+	req, sent, err := client.transport.newRequest(apiRequest{method: "POST", path: "/orders", body: OrderRequest{Stock: testStock}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(sent), req.ContentLength)
+	copied, err := req.GetBody()
+	assert.Nil(t, err)
+	req.Body.(*pooledBody).release()
+	_, err = req.Body.Read(make([]byte, 1))
+	assert.Equal(t, http.ErrBodyReadAfterClose, err)
+	_, err = req.GetBody()
+	assert.Equal(t, http.ErrBodyReadAfterClose, err)
+
+	raw, err := io.ReadAll(copied)
+	assert.Nil(t, err)
+	assert.Equal(t, bodies[0], string(raw))
+}
+
+func BenchmarkNewRequest(b *testing.B) {
+	client := NewClient(testApiKey)
+	apiReq := apiRequest{
+		method: "POST",
+		path:   "/venues/TESTEX/stocks/FOOBAR/orders",
+		body:   OrderRequest{Venue: testVenue, Stock: testStock, Account: testAccount, Price: 5000, Quantity: 100, Direction: OrderDirectionBuy, OrderType: OrderTypeLimit},
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		req, _, err := client.transport.newRequest(apiReq)
+		if err != nil {
+			b.Fatal(err)
+		}
+		req.Body.(*pooledBody).release()
+	}
+}
@@ -1,6 +1,7 @@
 package stockfighter
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -33,13 +34,24 @@ func (s StockInfo) String() string {
 }
 
 // A Quote represents a stock quote.
+//
+// The API omits the best bid (or ask) when there are no bids (or asks), so
+// HasBid (or HasAsk) must be checked before using BidPrice (or AskPrice): a
+// zero price means an empty side, not a free stock. In JSON, the bid and ask
+// fields are likewise omitted when there is no bid or ask.
 type Quote struct {
-	// Bid best price, size, and depth
+	// Venue and stock symbols
+	Venue  string `json:"venue"`
+	Symbol string `json:"symbol"`
+
+	// Whether there are any bids, and bid best price, size, and depth
+	HasBid   bool   `json:"-"`
 	BidPrice uint64 `json:"bid"`
 	BidSize  uint64 `json:"bidSize"`
 	BidDepth uint64 `json:"bidDepth"`
 
-	// Ask best price, size, and depth
+	// Whether there are any asks, and ask best price, size, and depth
+	HasAsk   bool   `json:"-"`
 	AskPrice uint64 `json:"ask"`
 	AskSize  uint64 `json:"askSize"`
 	AskDepth uint64 `json:"askDepth"`
@@ -53,6 +65,64 @@ type Quote struct {
 	QuoteTime time.Time `json:"quoteTime"`
 }
 
+// MarshalJSON implements json.Marshaler.
+func (q Quote) MarshalJSON() ([]byte, error) {
+	v := struct {
+		Venue         string    `json:"venue"`
+		Symbol        string    `json:"symbol"`
+		BidPrice      *uint64   `json:"bid,omitempty"`
+		BidSize       *uint64   `json:"bidSize,omitempty"`
+		BidDepth      uint64    `json:"bidDepth"`
+		AskPrice      *uint64   `json:"ask,omitempty"`
+		AskSize       *uint64   `json:"askSize,omitempty"`
+		AskDepth      uint64    `json:"askDepth"`
+		LastPrice     uint64    `json:"last"`
+		LastSize      uint64    `json:"lastSize"`
+		LastTradeTime time.Time `json:"lastTrade"`
+		QuoteTime     time.Time `json:"quoteTime"`
+	}{
+		Venue:         q.Venue,
+		Symbol:        q.Symbol,
+		BidDepth:      q.BidDepth,
+		AskDepth:      q.AskDepth,
+		LastPrice:     q.LastPrice,
+		LastSize:      q.LastSize,
+		LastTradeTime: q.LastTradeTime,
+		QuoteTime:     q.QuoteTime,
+	}
+	if q.HasBid {
+		v.BidPrice, v.BidSize = &q.BidPrice, &q.BidSize
+	}
+	if q.HasAsk {
+		v.AskPrice, v.AskSize = &q.AskPrice, &q.AskSize
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (q *Quote) UnmarshalJSON(data []byte) error {
+	// quoteJSON is Quote without its JSON methods
+	type quoteJSON Quote
+	var v struct {
+		quoteJSON
+		BidPrice *uint64 `json:"bid"`
+		AskPrice *uint64 `json:"ask"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*q = Quote(v.quoteJSON)
+	if v.BidPrice != nil {
+		q.HasBid, q.BidPrice = true, *v.BidPrice
+	}
+	if v.AskPrice != nil {
+		q.HasAsk, q.AskPrice = true, *v.AskPrice
+	}
+	return nil
+}
+
 // An OrderbookEntry represents an entry in orderbook.
 type OrderbookEntry struct {
 	Price    uint64 `json:"price"`
@@ -70,6 +140,10 @@ func (oe OrderbookEntry) String() string {
 
 // An Orderbook represents an orderbook for a stock.
 type Orderbook struct {
+	// Venue and stock symbols
+	Venue  string `json:"venue"`
+	Symbol string `json:"symbol"`
+
 	// Bid entries in the orderbook
 	Bids []OrderbookEntry `json:"bids"`
 
@@ -87,8 +161,13 @@ type OrderFillInfo struct {
 	Timestamp time.Time `json:"ts"`
 }
 
-// An OrderStatus represents the status of an open or closed order.
+// An Order represents the status of an open or closed order, as returned by
+// all the calls placing, canceling, or looking up orders.
 type Order struct {
+	// Venue and stock symbols
+	Venue  string `json:"venue"`
+	Symbol string `json:"symbol"`
+
 	Direction        string          `json:"direction"`
 	OriginalQuantity uint64          `json:"originalQty"`
 	Quantity         uint64          `json:"qty"`
@@ -101,3 +180,44 @@ type Order struct {
 	TotalFilled      uint64          `json:"totalFilled"`
 	Open             bool            `json:"open"`
 }
+
+// An OrderRequest represents an order to be placed.
+type OrderRequest struct {
+	Venue     string `json:"venue"`
+	Stock     string `json:"stock"`
+	Account   string `json:"account"`
+	Price     uint64 `json:"price"`
+	Quantity  uint64 `json:"qty"`
+	Direction string `json:"direction"`
+	OrderType string `json:"orderType"`
+}
+
+// An Execution represents a fill of an order of an account, as reported by
+// the executions stream of a venue.
+type Execution struct {
+	Account string `json:"account"`
+	Venue   string `json:"venue"`
+	Symbol  string `json:"symbol"`
+
+	// Status of the order of the account after the fill
+	Order Order `json:"order"`
+
+	// IDs of the order resting on the book and of the order that crossed it
+	StandingID int64 `json:"standingId"`
+	IncomingID int64 `json:"incomingId"`
+
+	// Fill price, size, and timestamp
+	Price    uint64    `json:"price"`
+	Filled   uint64    `json:"filled"`
+	FilledAt time.Time `json:"filledAt"`
+
+	// Whether the standing and incoming orders are now complete
+	StandingComplete bool `json:"standingComplete"`
+	IncomingComplete bool `json:"incomingComplete"`
+}
+
+// Aggressive reports whether the order of the account crossed the book, as
+// opposed to resting on it.
+func (e *Execution) Aggressive() bool {
+	return e.Order.OrderID == e.IncomingID
+}
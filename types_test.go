@@ -0,0 +1,31 @@
+package stockfighter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteJSON(t *testing.T) {
+	var quote Quote
+	assert.Nil(t, json.Unmarshal([]byte(`{"ask": 0, "askSize": 5, "bidSize": 0, "last": 5000}`), &quote))
+	assert.Equal(t, Quote{HasAsk: true, AskSize: 5, LastPrice: 5000}, quote)
+
+	data, err := json.Marshal(quote)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"venue":"","symbol":"","bidDepth":0,"ask":0,"askSize":5,"askDepth":0,"last":5000,"lastSize":0,`+
+		`"lastTrade":"0001-01-01T00:00:00Z","quoteTime":"0001-01-01T00:00:00Z"}`, string(data))
+
+	var decoded Quote
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, quote, decoded)
+}
+
+func TestExecutionAggressive(t *testing.T) {
+	e := &Execution{Order: Order{OrderID: 2}, StandingID: 1, IncomingID: 2}
+	assert.True(t, e.Aggressive())
+
+	e.StandingID, e.IncomingID = 2, 3
+	assert.False(t, e.Aggressive())
+}
@@ -0,0 +1,45 @@
+package stockfighter
+
+import "sync"
+
+// DefaultSubsystem is the subsystem name API requests are accounted to when
+// made from a Client not returned by Client.Subsystem.
+const DefaultSubsystem = "default"
+
+// usageCounter counts API requests per subsystem. It is shared by a Client and
+// all clients derived from it.
+type usageCounter struct {
+	mu       sync.Mutex
+	requests map[string]uint64
+}
+
+func (u *usageCounter) add(subsystem string) {
+	u.mu.Lock()
+	u.requests[subsystem]++
+	u.mu.Unlock()
+}
+
+// Subsystem returns a client which accounts its API requests to the named
+// subsystem (e.g. "poller" or "strategy"). The returned client shares the
+// API key, HTTP client, and usage counters with the original client.
+//
+//     poller := stockfighter.NewQuotePoller(client.Subsystem("poller"), venue, stocks, time.Second)
+func (client *Client) Subsystem(name string) *Client {
+	derived := *client
+	derived.subsystem = name
+	return &derived
+}
+
+// Usage returns the number of API requests made so far per subsystem,
+// including requests that failed. They are also published by PublishExpvar,
+// and exported to Prometheus by package metrics (see its UsageCollector).
+func (client *Client) Usage() map[string]uint64 {
+	client.usage.mu.Lock()
+	defer client.usage.mu.Unlock()
+
+	usage := make(map[string]uint64, len(client.usage.requests))
+	for subsystem, n := range client.usage.requests {
+		usage[subsystem] = n
+	}
+	return usage
+}
@@ -0,0 +1,22 @@
+package stockfighter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsage(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true}`))
+	})
+	poller := client.Subsystem("poller")
+
+	assert.Nil(t, client.Ping())
+	assert.Nil(t, poller.Ping())
+	assert.Nil(t, poller.PingVenue(testVenue))
+
+	assert.Equal(t, map[string]uint64{DefaultSubsystem: 1, "poller": 2}, client.Usage())
+	assert.Equal(t, client.Usage(), poller.Usage())
+}
@@ -0,0 +1,221 @@
+/*
+Package vcr records Stockfighter API interactions to cassette files and
+replays them, so that tests written against the live API can run offline and
+deterministically.
+
+A Recorder wraps the HTTP transport of the client. In record mode, requests go
+to the API and the interactions are saved to the cassette on Stop; in replay
+mode, responses come from the cassette and nothing is sent:
+
+    recorder, err := vcr.New("testdata/session.json", vcr.ModeReplay, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer recorder.Stop()
+
+    client := stockfighter.NewClient(apiKey, stockfighter.WithHTTPClient(&http.Client{
+        Transport: recorder,
+    }))
+
+The API key is scrubbed from recorded requests, so cassettes can be
+committed.
+*/
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Recorder modes.
+const (
+	// Requests are sent to the API and recorded
+	ModeRecord = "record"
+
+	// Responses are replayed from the cassette
+	ModeReplay = "replay"
+
+	// The cassette is replayed if it exists, and recorded otherwise
+	ModeAuto = "auto"
+)
+
+// AuthHeader is the request header holding the API key.
+const AuthHeader = "X-Starfighter-Authorization"
+
+// Scrubbed replaces the API key in recorded requests.
+const Scrubbed = "SCRUBBED"
+
+// An Interaction represents a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// A Request represents a recorded request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// A Response represents a recorded response.
+type Response struct {
+	StatusCode int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// A Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// A Recorder is an http.RoundTripper recording or replaying API
+// interactions.
+//
+// In replay mode, each request is answered with the first interaction not
+// replayed yet with the same method, URL, and body, so the same request made
+// twice gets the two recorded responses in order.
+//
+// You can create a new Recorder using New function.
+type Recorder struct {
+	path string
+	mode string
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	replayed []bool
+}
+
+// New creates a new Recorder for the cassette at path. In record mode,
+// requests are made with next (http.DefaultTransport if nil). In replay mode,
+// the cassette is read immediately.
+func New(path, mode string, next http.RoundTripper) (*Recorder, error) {
+	if mode == ModeAuto {
+		mode = ModeReplay
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			mode = ModeRecord
+		}
+	}
+	if mode != ModeRecord && mode != ModeReplay {
+		return nil, fmt.Errorf("Invalid recorder mode: %v", mode)
+	}
+
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	recorder := &Recorder{path: path, mode: mode, next: next}
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &recorder.cassette); err != nil {
+			return nil, fmt.Errorf("%v: %v", path, err)
+		}
+		recorder.replayed = make([]bool, len(recorder.cassette.Interactions))
+	}
+
+	return recorder, nil
+}
+
+// Mode returns the mode of the recorder (ModeRecord or ModeReplay).
+func (recorder *Recorder) Mode() string {
+	return recorder.mode
+}
+
+// RoundTrip implements http.RoundTripper.
+func (recorder *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if recorder.mode == ModeReplay {
+		return recorder.replay(req, string(body))
+	}
+
+	resp, err := recorder.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := req.Header.Clone()
+	if header.Get(AuthHeader) != "" {
+		header.Set(AuthHeader, Scrubbed)
+	}
+
+	recorder.mu.Lock()
+	recorder.cassette.Interactions = append(recorder.cassette.Interactions, Interaction{
+		Request:  Request{Method: req.Method, URL: req.URL.String(), Header: header, Body: string(body)},
+		Response: Response{StatusCode: resp.StatusCode, Header: resp.Header.Clone(), Body: string(respBody)},
+	})
+	recorder.mu.Unlock()
+
+	return resp, nil
+}
+
+func (recorder *Recorder) replay(req *http.Request, body string) (*http.Response, error) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	url := req.URL.String()
+	for i, interaction := range recorder.cassette.Interactions {
+		if recorder.replayed[i] || interaction.Request.Method != req.Method || interaction.Request.URL != url || interaction.Request.Body != body {
+			continue
+		}
+		recorder.replayed[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("vcr: no recorded interaction for %v %v", req.Method, url)
+}
+
+// Stop writes the cassette in record mode. It does nothing in replay mode.
+func (recorder *Recorder) Stop() error {
+	if recorder.mode != ModeRecord {
+		return nil
+	}
+
+	recorder.mu.Lock()
+	data, err := json.MarshalIndent(recorder.cassette, "", "  ")
+	recorder.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(recorder.path, append(data, '\n'), 0644)
+}
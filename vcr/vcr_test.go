@@ -0,0 +1,76 @@
+package vcr
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gpk.io/stockfighter"
+)
+
+func TestRecorder(t *testing.T) {
+	var bid uint64 = 5000
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			w.Write([]byte(`{"ok": true, "id": 42, "open": true}`))
+		default:
+			fmt.Fprintf(w, `{"ok": true, "bid": %d}`, bid)
+			bid += 100
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	newClient := func(recorder *Recorder) *stockfighter.Client {
+		return stockfighter.NewClient("SECRET_KEY", stockfighter.WithBaseURL(server.URL), stockfighter.WithHTTPClient(&http.Client{Transport: recorder}))
+	}
+
+	recorder, err := New(path, ModeAuto, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ModeRecord, recorder.Mode())
+	client := newClient(recorder)
+	_, err = client.GetQuote("TESTEX", "FOOBAR")
+	assert.Nil(t, err)
+	_, err = client.GetQuote("TESTEX", "FOOBAR")
+	assert.Nil(t, err)
+	_, err = client.PlaceOrder("TESTEX", "FOOBAR", "EXB123456", 5000, 10, stockfighter.OrderDirectionBuy, stockfighter.OrderTypeLimit)
+	assert.Nil(t, err)
+	assert.Nil(t, recorder.Stop())
+
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "SECRET_KEY")
+	assert.Contains(t, string(data), Scrubbed)
+
+	server.Close()
+	recorder, err = New(path, ModeAuto, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ModeReplay, recorder.Mode())
+	client = newClient(recorder)
+
+	quote, err := client.GetQuote("TESTEX", "FOOBAR")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5000), quote.BidPrice)
+	quote, err = client.GetQuote("TESTEX", "FOOBAR")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5100), quote.BidPrice)
+	order, err := client.PlaceOrder("TESTEX", "FOOBAR", "EXB123456", 5000, 10, stockfighter.OrderDirectionBuy, stockfighter.OrderTypeLimit)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), order.OrderID)
+
+	// replayed interactions are used once, and bodies must match
+	_, err = client.GetQuote("TESTEX", "FOOBAR")
+	assert.NotNil(t, err)
+	_, err = client.PlaceOrder("TESTEX", "FOOBAR", "EXB123456", 5000, 10, stockfighter.OrderDirectionBuy, stockfighter.OrderTypeLimit)
+	assert.NotNil(t, err)
+
+	_, err = New(path, "rewind", nil)
+	assert.NotNil(t, err)
+	_, err = New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil)
+	assert.NotNil(t, err)
+}
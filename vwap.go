@@ -0,0 +1,200 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A VWAPResult represents the outcome of a VWAPExecutor run.
+type VWAPResult struct {
+	// Child orders placed
+	Orders []Order
+
+	// Total filled quantity and its average price (in cents)
+	FilledQuantity uint64
+	AveragePrice   float64
+
+	// Traded volume observed in the market during the run and its VWAP (in
+	// cents)
+	MarketVolume uint64
+	MarketVWAP   float64
+}
+
+// A VWAPExecutor works a parent order by sending immediate-or-cancel child
+// orders sized as a fraction (the participation rate) of the traded volume
+// observed in the market, so the average fill price tracks the session VWAP.
+//
+// Traded volume is observed from the last trade of polled quotes. Whenever
+// participation alone would not complete the parent order by the deadline,
+// child orders follow a linear time schedule towards the deadline instead.
+//
+// You can create a new VWAPExecutor using NewVWAPExecutor function.
+type VWAPExecutor struct {
+	client    *Client
+	venue     string
+	stock     string
+	account   string
+	direction string
+	quantity  uint64
+
+	// Fraction of observed market volume to trade, in (0, 1]
+	ParticipationRate float64
+
+	// Time by which the parent order should be complete
+	Deadline time.Time
+
+	// Worst price child orders may be sent at (0 means no limit)
+	LimitPrice uint64
+
+	// Quote polling interval (DefaultPollInterval if not positive)
+	Interval time.Duration
+}
+
+// NewVWAPExecutor creates a new VWAPExecutor for a parent order of quantity
+// shares. This never returns nil.
+func NewVWAPExecutor(client *Client, venue, stock, account, direction string, quantity uint64, participationRate float64, deadline time.Time) *VWAPExecutor {
+	venue = strings.TrimSpace(venue)
+	if !validSymbol(venue) {
+		panic(fmt.Errorf("Invalid venue symbol: %v", venue))
+	}
+
+	stock = strings.TrimSpace(stock)
+	if !validSymbol(stock) {
+		panic(fmt.Errorf("Invalid stock symbol: %v", stock))
+	}
+
+	if direction != OrderDirectionBuy && direction != OrderDirectionSell {
+		panic(fmt.Errorf("Invalid order direction: %v", direction))
+	}
+
+	if participationRate <= 0 || participationRate > 1 {
+		panic(fmt.Errorf("Invalid participation rate: %v", participationRate))
+	}
+
+	return &VWAPExecutor{
+		client:            client,
+		venue:             venue,
+		stock:             stock,
+		account:           account,
+		direction:         direction,
+		quantity:          quantity,
+		ParticipationRate: participationRate,
+		Deadline:          deadline,
+		Interval:          DefaultPollInterval,
+	}
+}
+
+// Run works the parent order until it is completely filled or ctx is done.
+//
+// The returned result describes the orders placed so far, including when an
+// error is returned.
+func (executor *VWAPExecutor) Run(ctx context.Context) (*VWAPResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := &VWAPResult{}
+	start := time.Now()
+	var notional, marketNotional uint64
+	var lastTrade time.Time
+
+	interval := executor.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	updates := NewQuotePoller(executor.client, executor.venue, []string{executor.stock}, interval).Start(ctx)
+	var quote *Quote
+	for result.FilledQuantity < executor.quantity {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return result, ctx.Err()
+			}
+			if update.Err != nil {
+				return result, update.Err
+			}
+
+			quote = update.Quote
+			if quote.LastSize > 0 && quote.LastTradeTime.After(lastTrade) {
+				lastTrade = quote.LastTradeTime
+				result.MarketVolume += quote.LastSize
+				marketNotional += quote.LastPrice * quote.LastSize
+				result.MarketVWAP = float64(marketNotional) / float64(result.MarketVolume)
+			}
+		case <-ticker.C:
+		case <-ctx.Done():
+			return result, ctx.Err()
+		}
+
+		if quote == nil {
+			continue
+		}
+
+		target := executor.target(result.MarketVolume, start, time.Now())
+		if target <= result.FilledQuantity {
+			continue
+		}
+
+		price, ok := executor.price(quote)
+		if !ok {
+			continue
+		}
+
+		order, err := executor.client.PlaceOrder(executor.venue, executor.stock, executor.account,
+			price, target-result.FilledQuantity, executor.direction, OrderTypeImmediateOrCancel)
+		if err != nil {
+			return result, err
+		}
+
+		result.Orders = append(result.Orders, *order)
+		for _, fill := range order.Fills {
+			result.FilledQuantity += fill.Quantity
+			notional += fill.Price * fill.Quantity
+		}
+		if result.FilledQuantity > 0 {
+			result.AveragePrice = float64(notional) / float64(result.FilledQuantity)
+		}
+	}
+
+	return result, nil
+}
+
+// target returns the cumulative quantity that should be filled by now.
+func (executor *VWAPExecutor) target(marketVolume uint64, start, now time.Time) uint64 {
+	target := uint64(float64(marketVolume) * executor.ParticipationRate)
+
+	if now.Before(executor.Deadline) {
+		elapsed, total := now.Sub(start), executor.Deadline.Sub(start)
+		schedule := uint64(float64(executor.quantity) * float64(elapsed) / float64(total))
+		if schedule > target {
+			target = schedule
+		}
+	} else {
+		target = executor.quantity
+	}
+
+	if target > executor.quantity {
+		target = executor.quantity
+	}
+	return target
+}
+
+// price returns the price to send a child order at, taking liquidity at the
+// best opposite price within the limit price.
+func (executor *VWAPExecutor) price(quote *Quote) (uint64, bool) {
+	if executor.direction == OrderDirectionBuy {
+		if !quote.HasAsk || (executor.LimitPrice > 0 && quote.AskPrice > executor.LimitPrice) {
+			return 0, false
+		}
+		return quote.AskPrice, true
+	}
+
+	if !quote.HasBid || (executor.LimitPrice > 0 && quote.BidPrice < executor.LimitPrice) {
+		return 0, false
+	}
+	return quote.BidPrice, true
+}
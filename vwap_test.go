@@ -0,0 +1,45 @@
+package stockfighter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVWAPExecutorTarget(t *testing.T) {
+	start := time.Date(2015, 12, 4, 9, 0, 0, 0, time.UTC)
+	executor := NewVWAPExecutor(NewClient(testApiKey), testVenue, testStock, testAccount, OrderDirectionBuy, 1000, 0.1, start.Add(100*time.Second))
+
+	// participation
+	assert.Equal(t, uint64(300), executor.target(3000, start, start.Add(10*time.Second)))
+
+	// schedule catch-up
+	assert.Equal(t, uint64(500), executor.target(3000, start, start.Add(50*time.Second)))
+
+	// capped at parent quantity, and everything is due past the deadline
+	assert.Equal(t, uint64(1000), executor.target(20000, start, start.Add(10*time.Second)))
+	assert.Equal(t, uint64(1000), executor.target(0, start, start.Add(101*time.Second)))
+
+	ask := executor.price
+	_, ok := ask(&Quote{HasBid: true, BidPrice: 100})
+	assert.False(t, ok)
+	price, ok := ask(&Quote{HasBid: true, BidPrice: 100, HasAsk: true, AskPrice: 105})
+	assert.True(t, ok)
+	assert.Equal(t, uint64(105), price)
+	executor.LimitPrice = 104
+	_, ok = ask(&Quote{HasBid: true, BidPrice: 100, HasAsk: true, AskPrice: 105})
+	assert.False(t, ok)
+}
+
+func TestVWAPExecutorRunDefaultInterval(t *testing.T) {
+	executor := NewVWAPExecutor(NewClient(testApiKey), testVenue, testStock, testAccount, OrderDirectionBuy, 1000, 0.1, time.Now().Add(time.Minute))
+	executor.Interval = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := executor.Run(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, uint64(0), result.FilledQuantity)
+}
@@ -0,0 +1,41 @@
+package stockfighter
+
+import (
+	"context"
+	"time"
+)
+
+// WaitForFill polls the status of an order until it is closed (fully filled or
+// canceled) and returns its final status.
+//
+// Requests are made with ctx (see Client.WithContext). If ctx is done before
+// the order closes, WaitForFill returns the last status retrieved (or nil if
+// none was) along with ctx.Err(). Any other error returned by GetOrder stops
+// the wait and is returned as is.
+func (client *Client) WaitForFill(ctx context.Context, venue, stock string, orderID int64) (*Order, error) {
+	client = client.WithContext(ctx)
+	ticker := time.NewTicker(DefaultPollInterval)
+	defer ticker.Stop()
+
+	var order *Order
+	for {
+		status, err := client.GetOrder(venue, stock, orderID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return order, ctx.Err()
+			}
+			return nil, err
+		}
+
+		order = status
+		if !order.Open {
+			return order, nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return order, ctx.Err()
+		}
+	}
+}
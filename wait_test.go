@@ -0,0 +1,56 @@
+package stockfighter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForFill(t *testing.T) {
+	var polls int
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		fmt.Fprintf(w, `{"ok": true, "id": 42, "originalQty": 10, "qty": 0, "totalFilled": 10, "open": %v}`, polls > 1)
+	})
+
+	order, err := client.WaitForFill(context.Background(), testVenue, testStock, 42)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), order.OrderID)
+	assert.False(t, order.Open)
+	assert.Equal(t, 1, polls)
+}
+
+func TestWaitForFillTimeout(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true, "id": 42, "originalQty": 10, "qty": 10, "open": true}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	order, err := client.WaitForFill(ctx, testVenue, testStock, 42)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.NotNil(t, order)
+	assert.True(t, order.Open)
+}
+
+func TestWaitForFillCanceled(t *testing.T) {
+	release := make(chan struct{})
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"ok": true, "id": 42, "open": true}`))
+	})
+	defer close(release)
+
+	// the poll in flight is canceled
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	order, err := client.WaitForFill(ctx, testVenue, testStock, 42)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Nil(t, order)
+}